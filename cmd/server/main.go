@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"html/template"
 	"net/http"
 	"os"
@@ -45,15 +46,14 @@ func main() {
 	userService := services.NewUserService(userRepo)
 	projectRepo := repositories.NewProjectRepository(database.DB)
 	scoreSettingsRepo := repositories.NewScoreSettingsRepository(database.DB)
-	scoreSettingsService := services.NewScoreSettingsService(scoreSettingsRepo)
 	excludedExtensionRepo := repositories.NewExcludedExtensionRepository(database.DB)
 	excludedExtensionService := services.NewExcludedExtensionService(excludedExtensionRepo)
 	excludedFolderRepo := repositories.NewExcludedFolderRepository(database.DB)
 	excludedFolderService := services.NewExcludedFolderService(excludedFolderRepo)
 	githubRepoRepo := repositories.NewGitHubRepositoryRepository(database.DB)
 	projectRepoRepo := repositories.NewProjectRepositoryRepository(database.DB)
-	githubRepoService := services.NewGitHubRepositoryService(githubRepoRepo, projectRepoRepo)
-	projectService := services.NewProjectService(projectRepo, scoreSettingsService)
+	githubRateBudget := services.NewGitHubRateBudget(config.AppConfig.GitHub.RateLimitReserve)
+	githubRepoService := services.NewGitHubRepositoryService(githubRepoRepo, projectRepoRepo, githubRateBudget)
 
 	// Job and worker services
 	jobRepo := repositories.NewJobRepository(database.DB)
@@ -61,13 +61,45 @@ func main() {
 	commitFileRepo := repositories.NewCommitFileRepository(database.DB)
 	personRepo := repositories.NewPersonRepository(database.DB)
 	jobService := services.NewJobService(jobRepo)
-	cloneService := services.NewCloneService(projectRepo, userRepo, githubRepoRepo, projectRepoRepo)
+
+	// Score settings changes queue a stats job, so they need the job service
+	scoreSettingsService := services.NewScoreSettingsService(scoreSettingsRepo, jobService)
+	projectService := services.NewProjectService(projectRepo, scoreSettingsService)
+
+	// GitHub App service resolves the tokens background jobs run with
+	projectCollaboratorRepo := repositories.NewProjectCollaboratorRepository(database.DB)
+	projectGitHubInstallationRepo := repositories.NewProjectGitHubInstallationRepository(database.DB)
+	githubAppService := services.NewGitHubAppService(
+		config.AppConfig.GitHub.AppID,
+		config.AppConfig.GitHub.AppSlug,
+		config.AppConfig.GitHub.AppPrivateKey,
+		projectGitHubInstallationRepo,
+		projectRepo,
+		userRepo,
+		projectCollaboratorRepo,
+	)
+	githubHTTPCacheRepo := repositories.NewGitHubHTTPCacheRepository(database.DB)
+	githubClientPool := services.NewGitHubClientPool(githubAppService, githubRateBudget, githubHTTPCacheRepo)
+	jobGitHubStatsRepo := repositories.NewJobGitHubStatsRepository(database.DB)
+
+	cloneService := services.NewCloneService(githubAppService, githubRepoRepo, projectRepoRepo)
 
 	// Pull request related services
 	pullRequestRepo := repositories.NewPullRequestRepository(database.DB)
 	pullRequestService := services.NewPullRequestService(pullRequestRepo)
 	prReviewRepo := repositories.NewPRReviewRepository(database.DB)
 	prReviewService := services.NewPRReviewService(prReviewRepo)
+	prReviewCommentRepo := repositories.NewPRReviewCommentRepository(database.DB)
+	prIssueCommentRepo := repositories.NewPRIssueCommentRepository(database.DB)
+	prDraftEventRepo := repositories.NewPRDraftEventRepository(database.DB)
+	prFileRepo := repositories.NewPRFileRepository(database.DB)
+	prCommitRepo := repositories.NewPRCommitRepository(database.DB)
+	githubTeamRepo := repositories.NewGithubTeamRepository(database.DB)
+	prReviewRequestRepo := repositories.NewPRReviewRequestRepository(database.DB)
+	issueRepo := repositories.NewIssueRepository(database.DB)
+	issueCommentRepo := repositories.NewIssueCommentRepository(database.DB)
+	ciRunRepo := repositories.NewCIRunRepository(database.DB)
+	deploymentRepo := repositories.NewDeploymentRepository(database.DB)
 	githubPersonRepo := repositories.NewGithubPersonRepository(database.DB)
 	githubPersonService := services.NewGithubPersonService(githubPersonRepo)
 	emailMergeRepo := repositories.NewEmailMergeRepository(database.DB)
@@ -82,7 +114,13 @@ func main() {
 	workingHoursSettingsRepo := repositories.NewWorkingHoursSettingsRepository(database.DB)
 	workingHoursSettingsService := services.NewWorkingHoursSettingsService(workingHoursSettingsRepo)
 
+	// Bot detection service
+	botSettingsRepo := repositories.NewBotSettingsRepository(database.DB)
+	botService := services.NewBotService(botSettingsRepo)
+
 	// People statistics service
+	repositoryScoreSettingsRepo := repositories.NewRepositoryScoreSettingsRepository(database.DB)
+	scoreFormulaRepo := repositories.NewScoreFormulaRepository(database.DB)
 	peopleStatsRepo := repositories.NewPeopleStatisticsRepository(database.DB)
 	projectRepositoryRepo := repositories.NewProjectRepositoryRepository(database.DB)
 	peopleStatsService := services.NewPeopleStatisticsService(
@@ -102,22 +140,61 @@ func main() {
 		projectRepositoryRepo,
 		workingHoursSettingsService,
 		projectGithubPersonService,
+		prReviewCommentRepo,
+		prIssueCommentRepo,
+		issueRepo,
+		botService,
+		repositoryScoreSettingsRepo,
+		scoreFormulaRepo,
 	)
 
+	// Pull request cycle-time metrics service
+	prCycleMetricsRepo := repositories.NewPRCycleMetricsRepository(database.DB)
+	prCycleMetricsService := services.NewPRCycleMetricsService(pullRequestRepo, prReviewRepo, prDraftEventRepo, prCycleMetricsRepo, githubPersonRepo, githubRepoRepo)
+	reviewerReportService := services.NewReviewerReportService(pullRequestRepo, prReviewRepo, prReviewRequestRepo, githubPersonRepo, botService)
+	collaborationService := services.NewCollaborationService(pullRequestRepo, prReviewRepo, githubPersonRepo)
+	reviewCoverageService := services.NewReviewCoverageService(pullRequestRepo, prReviewRepo, commitRepo, githubRepoRepo)
+	stalePRSettingsRepo := repositories.NewStalePRSettingsRepository(database.DB)
+	stalePullRequestService := services.NewStalePullRequestService(stalePRSettingsRepo, pullRequestRepo, prReviewRepo, prReviewRequestRepo, prDraftEventRepo, githubPersonRepo, githubRepoRepo)
+	issueReportService := services.NewIssueReportService(issueRepo, issueCommentRepo, githubPersonRepo)
+	ciReportService := services.NewCIReportService(ciRunRepo, pullRequestRepo, prCommitRepo, commitRepo, githubPersonRepo, githubRepoRepo)
+	doraSettingsRepo := repositories.NewDORASettingsRepository(database.DB)
+	doraService := services.NewDORAService(doraSettingsRepo, deploymentRepo, pullRequestRepo, prCommitRepo, commitRepo, githubRepoRepo)
+	webhookService := services.NewWebhookService(githubRepoRepo, projectRepoRepo, githubRepoService, jobService, projectRepo)
+
 	// Project update settings service
 	projectUpdateSettingsRepo := repositories.NewProjectUpdateSettingsRepository(database.DB)
 	projectUpdateSettingsService := services.NewProjectUpdateSettingsService(projectUpdateSettingsRepo)
 
 	// Project collaborator service
-	projectCollaboratorRepo := repositories.NewProjectCollaboratorRepository(database.DB)
 	projectCollaboratorService := services.NewProjectCollaboratorService(projectCollaboratorRepo, userRepo, projectRepo)
 
 	// LLM API key service
 	llmAPIKeyRepo := repositories.NewLLMAPIKeyRepository(database.DB)
 	llmAPIKeyService := services.NewLLMAPIKeyService(llmAPIKeyRepo, projectCollaboratorService)
 
+	// Repository discovery service
+	repositoryDiscoveryRepo := repositories.NewRepositoryDiscoveryRepository(database.DB)
+	repositoryDiscoveryService := services.NewRepositoryDiscoveryService(repositoryDiscoveryRepo, githubRepoService)
+
+	// Repository group service
+	repositoryGroupService := services.NewRepositoryGroupService(projectRepoRepo, githubRepoRepo)
+
+	// Repository score weighting service
+	repositoryScoreSettingsService := services.NewRepositoryScoreSettingsService(repositoryScoreSettingsRepo, scoreSettingsRepo, projectRepoRepo, githubRepoRepo, jobService)
+
+	// Score formula service
+	scoreFormulaService := services.NewScoreFormulaService(scoreFormulaRepo, peopleStatsService, scoreSettingsService)
+
+	// Team sync service
+	projectTeamRepo := repositories.NewProjectTeamRepository(database.DB)
+	teamService := services.NewTeamService(projectTeamRepo, githubTeamRepo, githubPersonRepo, githubRepoRepo, githubRepoService, repositoryDiscoveryService, peopleStatsRepo)
+
 	// Scheduler service
-	schedulerService := services.NewSchedulerService(projectUpdateSettingsRepo, jobRepo, githubRepoService)
+	schedulerService := services.NewSchedulerService(
+		projectUpdateSettingsRepo, jobRepo, githubRepoService, repositoryDiscoveryService, githubClientPool, teamService,
+		githubHTTPCacheRepo, config.AppConfig.GitHub.CacheRetentionDays, projectRepo,
+	)
 
 	// Initialize GitHub client
 	githubClient := github.NewClient(nil)
@@ -126,7 +203,9 @@ func main() {
 	workerManager := workers.NewWorkerManager(
 		jobRepo, cloneService, projectRepoRepo, commitRepo, commitFileRepo, personRepo, githubRepoRepo,
 		githubRepoService, pullRequestService, prReviewService, githubPersonService, peopleStatsService, githubClient,
-		projectRepo, userRepo, projectGithubPersonService, pullRequestRepo,
+		githubClientPool, projectGithubPersonService, pullRequestRepo, jobGitHubStatsRepo, projectRepo,
+		prReviewCommentRepo, prIssueCommentRepo, prDraftEventRepo, prCycleMetricsService,
+		prFileRepo, prCommitRepo, githubTeamRepo, prReviewRequestRepo, issueRepo, issueCommentRepo, ciRunRepo, deploymentRepo,
 	)
 
 	// Initialize router
@@ -139,7 +218,7 @@ func main() {
 	router.Static("/static", "./web/static")
 
 	// Setup routes
-	setupRoutes(router, userService, projectService, scoreSettingsService, excludedExtensionService, excludedFolderService, githubRepoService, jobService, jobRepo, commitRepo, commitFileRepo, pullRequestRepo, prReviewRepo, githubPersonRepo, personRepo, emailMergeService, githubPersonEmailService, textSimilarityService, peopleStatsService, projectUpdateSettingsService, projectCollaboratorService, workingHoursSettingsService, projectGithubPersonService, llmAPIKeyService)
+	setupRoutes(router, userService, projectService, scoreSettingsService, excludedExtensionService, excludedFolderService, githubRepoService, jobService, jobRepo, commitRepo, commitFileRepo, pullRequestRepo, prReviewRepo, githubPersonRepo, personRepo, emailMergeService, githubPersonEmailService, textSimilarityService, peopleStatsService, projectUpdateSettingsService, projectCollaboratorService, workingHoursSettingsService, projectGithubPersonService, llmAPIKeyService, repositoryDiscoveryService, githubAppService, githubClientPool, jobGitHubStatsRepo, prCycleMetricsService, reviewerReportService, collaborationService, reviewCoverageService, stalePullRequestService, issueReportService, ciReportService, doraService, webhookService, botService, teamService, repositoryGroupService, repositoryScoreSettingsService, scoreFormulaService)
 	loadTemplates(router)
 
 	// Start workers
@@ -196,14 +275,15 @@ func main() {
 	logger.Info("Server stopped")
 }
 
-func setupRoutes(router *gin.Engine, userService *services.UserService, projectService *services.ProjectService, scoreSettingsService *services.ScoreSettingsService, excludedExtensionService *services.ExcludedExtensionService, excludedFolderService *services.ExcludedFolderService, githubRepoService *services.GitHubRepositoryService, jobService *services.JobService, jobRepo *repositories.JobRepository, commitRepo *repositories.CommitRepository, commitFileRepo *repositories.CommitFileRepository, pullRequestRepo *repositories.PullRequestRepository, prReviewRepo *repositories.PRReviewRepository, githubPersonRepo *repositories.GithubPersonRepository, personRepo *repositories.PersonRepository, emailMergeService *services.EmailMergeService, githubPersonEmailService *services.GitHubPersonEmailService, textSimilarityService *services.TextSimilarityService, peopleStatsService *services.PeopleStatisticsService, projectUpdateSettingsService *services.ProjectUpdateSettingsService, projectCollaboratorService *services.ProjectCollaboratorService, workingHoursSettingsService *services.WorkingHoursSettingsService, projectGithubPersonService *services.ProjectGithubPersonService, llmAPIKeyService *services.LLMAPIKeyService) {
+func setupRoutes(router *gin.Engine, userService *services.UserService, projectService *services.ProjectService, scoreSettingsService *services.ScoreSettingsService, excludedExtensionService *services.ExcludedExtensionService, excludedFolderService *services.ExcludedFolderService, githubRepoService *services.GitHubRepositoryService, jobService *services.JobService, jobRepo *repositories.JobRepository, commitRepo *repositories.CommitRepository, commitFileRepo *repositories.CommitFileRepository, pullRequestRepo *repositories.PullRequestRepository, prReviewRepo *repositories.PRReviewRepository, githubPersonRepo *repositories.GithubPersonRepository, personRepo *repositories.PersonRepository, emailMergeService *services.EmailMergeService, githubPersonEmailService *services.GitHubPersonEmailService, textSimilarityService *services.TextSimilarityService, peopleStatsService *services.PeopleStatisticsService, projectUpdateSettingsService *services.ProjectUpdateSettingsService, projectCollaboratorService *services.ProjectCollaboratorService, workingHoursSettingsService *services.WorkingHoursSettingsService, projectGithubPersonService *services.ProjectGithubPersonService, llmAPIKeyService *services.LLMAPIKeyService, repositoryDiscoveryService *services.RepositoryDiscoveryService, githubAppService *services.GitHubAppService, githubClientPool *services.GitHubClientPool, jobGitHubStatsRepo *repositories.JobGitHubStatsRepository, prCycleMetricsService *services.PRCycleMetricsService, reviewerReportService *services.ReviewerReportService, collaborationService *services.CollaborationService, reviewCoverageService *services.ReviewCoverageService, stalePullRequestService *services.StalePullRequestService, issueReportService *services.IssueReportService, ciReportService *services.CIReportService, doraService *services.DORAService, webhookService *services.WebhookService, botService *services.BotService, teamService *services.TeamService, repositoryGroupService *services.RepositoryGroupService, repositoryScoreSettingsService *services.RepositoryScoreSettingsService, scoreFormulaService *services.ScoreFormulaService) {
 	// Initialize handlers
 	homeHandler := handlers.NewHomeHandler(userService)
 	authHandler := handlers.NewAuthHandler(userService)
 	dashboardHandler := handlers.NewDashboardHandler(userService, projectService, projectCollaboratorService)
-	projectHandler := handlers.NewProjectHandler(projectService, userService, scoreSettingsService, excludedExtensionService, excludedFolderService, githubRepoService, jobService, jobRepo, commitRepo, commitFileRepo, pullRequestRepo, prReviewRepo, githubPersonRepo, personRepo, emailMergeService, githubPersonEmailService, textSimilarityService, peopleStatsService, projectUpdateSettingsService, projectCollaboratorService, workingHoursSettingsService, projectGithubPersonService, llmAPIKeyService)
+	projectHandler := handlers.NewProjectHandler(projectService, userService, scoreSettingsService, excludedExtensionService, excludedFolderService, githubRepoService, jobService, jobRepo, commitRepo, commitFileRepo, pullRequestRepo, prReviewRepo, githubPersonRepo, personRepo, emailMergeService, githubPersonEmailService, textSimilarityService, peopleStatsService, projectUpdateSettingsService, projectCollaboratorService, workingHoursSettingsService, projectGithubPersonService, llmAPIKeyService, repositoryDiscoveryService, githubAppService, githubClientPool, jobGitHubStatsRepo, prCycleMetricsService, reviewerReportService, collaborationService, reviewCoverageService, stalePullRequestService, issueReportService, ciReportService, doraService, botService, teamService, repositoryGroupService, repositoryScoreSettingsService, scoreFormulaService)
 	workingHoursSettingsHandler := handlers.NewWorkingHoursSettingsHandler(workingHoursSettingsService)
 	llmAPIKeyHandler := handlers.NewLLMAPIKeyHandler(llmAPIKeyService, projectService, projectCollaboratorService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService, config.AppConfig.GitHub.WebhookSecret)
 	healthHandler := handlers.NewHealthHandler()
 	notFoundHandler := handlers.NewNotFoundHandler()
 
@@ -234,11 +314,18 @@ func setupRoutes(router *gin.Engine, userService *services.UserService, projectS
 		projects.POST("/:id/emails/detach", projectHandler.DetachEmailMerge)
 		projects.GET("/:id/people", projectHandler.ViewProjectPeople)
 		projects.GET("/:id/people/:person_id", projectHandler.ViewPersonStats)
+		projects.GET("/:id/collaboration/export", projectHandler.ExportCollaborationGraph)
 		projects.GET("/:id/repositories/:repository_id", projectHandler.ViewRepository)
 		projects.POST("/:id/people/associate", projectHandler.CreateGitHubPersonEmailAssociation)
 		projects.POST("/:id/people/detach", projectHandler.DeleteGitHubPersonEmailAssociation)
 		projects.POST("/:id/people/remove", projectHandler.SoftDeletePerson)
 		projects.POST("/:id/people/restore", projectHandler.RestorePerson)
+		projects.POST("/:id/people/teams/sync", projectHandler.SyncProjectTeams)
+		projects.POST("/:id/people/teams", projectHandler.CreateProjectTeam)
+		projects.POST("/:id/people/teams/:team_id/delete", projectHandler.DeleteProjectTeam)
+		projects.POST("/:id/people/teams/:team_id/members", projectHandler.AddProjectTeamMember)
+		projects.POST("/:id/people/teams/:team_id/members/:member_id", projectHandler.UpdateProjectTeamMember)
+		projects.POST("/:id/people/teams/:team_id/members/:member_id/delete", projectHandler.RemoveProjectTeamMember)
 		projects.GET("/:id/reports", projectHandler.ViewProjectReports)
 		projects.GET("/:id/reports/daily", projectHandler.ViewProjectReportsDaily)
 		projects.GET("/:id/reports/weekly", projectHandler.ViewProjectReportsWeekly)
@@ -246,6 +333,8 @@ func setupRoutes(router *gin.Engine, userService *services.UserService, projectS
 		projects.GET("/:id/reports/monthly/export", projectHandler.ExportMonthlyReportsToExcel)
 		projects.GET("/:id/reports/yearly", projectHandler.ViewProjectReportsYearly)
 		projects.GET("/:id/reports/yearly/export", projectHandler.ExportYearlyReportsToExcel)
+		projects.GET("/:id/stale-pull-requests", projectHandler.ViewStalePullRequests)
+		projects.GET("/:id/stale-pull-requests/json", projectHandler.GetStalePullRequestsJSON)
 		projects.POST("/:id/fetch-repositories", projectHandler.FetchRepositories)
 		projects.POST("/:id/analyze", projectHandler.CreateAnalyzeJobs)
 		projects.POST("/:id/repositories/:repository_id/clone", projectHandler.CreateCloneJob)
@@ -257,14 +346,32 @@ func setupRoutes(router *gin.Engine, userService *services.UserService, projectS
 		projects.POST("/:id/analyze-all", projectHandler.AnalyzeAllRepositories)
 		projects.POST("/:id/update-all", projectHandler.UpdateAllRepositories)
 		projects.POST("/:id/repositories/:repository_id/toggle-track", projectHandler.ToggleRepositoryTracking)
+		projects.POST("/:id/repositories/:repository_id/groups", projectHandler.UpdateRepositoryGroups)
 		projects.GET("/:id/settings", projectHandler.ProjectSettings)
 		projects.POST("/:id/settings/name", projectHandler.UpdateProjectName)
 		projects.POST("/:id/settings/scores", projectHandler.UpdateScoreSettings)
+		projects.POST("/:id/settings/repository-scores", projectHandler.UpdateRepositoryScoreSettings)
+		projects.POST("/:id/settings/repository-scores/:repository_id/delete", projectHandler.ResetRepositoryScoreSettings)
+		projects.POST("/:id/settings/score-formula", projectHandler.SaveScoreFormula)
+		projects.POST("/:id/settings/score-formula/preview", projectHandler.PreviewScoreFormula)
+		projects.POST("/:id/settings/score-formula/deactivate", projectHandler.DeactivateScoreFormula)
+		projects.POST("/:id/settings/score-formula/:formula_id/activate", projectHandler.ActivateScoreFormula)
 		projects.POST("/:id/settings/extensions", projectHandler.AddExcludedExtension)
 		projects.POST("/:id/settings/extensions/:extension_id/delete", projectHandler.DeleteExcludedExtension)
 		projects.POST("/:id/settings/folders", projectHandler.AddExcludedFolder)
 		projects.POST("/:id/settings/folders/:folder_id/delete", projectHandler.DeleteExcludedFolder)
 		projects.POST("/:id/settings/update-settings", projectHandler.UpdateProjectUpdateSettings)
+		projects.POST("/:id/settings/stale-pull-requests", projectHandler.UpdateStalePRSettings)
+		projects.POST("/:id/settings/dora", projectHandler.UpdateDORASettings)
+		projects.POST("/:id/settings/bots", projectHandler.UpdateBotSettings)
+		projects.POST("/:id/settings/discovery/sources", projectHandler.AddDiscoverySource)
+		projects.POST("/:id/settings/discovery/sources/:source_id/delete", projectHandler.DeleteDiscoverySource)
+		projects.POST("/:id/settings/discovery/rules", projectHandler.AddDiscoveryRule)
+		projects.POST("/:id/settings/discovery/rules/:rule_id/delete", projectHandler.DeleteDiscoveryRule)
+		projects.POST("/:id/settings/github-app", projectHandler.SetGitHubAppInstallation)
+		projects.POST("/:id/settings/github-app/delete", projectHandler.RemoveGitHubAppInstallation)
+		projects.POST("/:id/settings/github-ingestion", projectHandler.UpdateGitHubIngestion)
+		projects.POST("/:id/settings/github-sync", projectHandler.UpdateGitHubSync)
 		projects.GET("/:id/working-hours-settings", workingHoursSettingsHandler.WorkingHoursSettingsForm)
 		projects.POST("/:id/working-hours-settings", workingHoursSettingsHandler.UpdateWorkingHoursSettings)
 
@@ -276,9 +383,13 @@ func setupRoutes(router *gin.Engine, userService *services.UserService, projectS
 		projects.GET("/:id/collaborators", projectHandler.ViewProjectCollaborators)
 		projects.POST("/:id/collaborators/add", projectHandler.AddProjectCollaborator)
 		projects.POST("/:id/collaborators/remove", projectHandler.RemoveProjectCollaborator)
+		projects.POST("/:id/collaborators/share-token", projectHandler.ShareGitHubToken)
 		projects.POST("/jobs/:job_id/retry", projectHandler.RetryFailedJob)
 	}
 
+	// GitHub webhooks, authenticated by their signature
+	router.POST("/webhooks/github", webhookHandler.GitHubWebhook)
+
 	// Health check endpoint
 	router.GET("/health", healthHandler.HealthCheck)
 
@@ -305,6 +416,22 @@ func loadTemplates(router *gin.Engine) {
 			}
 			return result
 		},
+		"percent": func(fraction float64) string {
+			return fmt.Sprintf("%.0f%%", fraction*100)
+		},
+		"formatDuration": func(seconds float64) string {
+			switch {
+			case seconds < 3600:
+				return fmt.Sprintf("%dm", int(seconds/60))
+			case seconds < 48*3600:
+				return fmt.Sprintf("%.1fh", seconds/3600)
+			default:
+				return fmt.Sprintf("%.1fd", seconds/86400)
+			}
+		},
+		"formatRate": func(rate float64) string {
+			return fmt.Sprintf("%.1f", rate)
+		},
 	})
 
 	router.LoadHTMLFiles(
@@ -317,6 +444,7 @@ func loadTemplates(router *gin.Engine) {
 		filepath.Join(cwd, "web/templates/projects/view.html"),
 		filepath.Join(cwd, "web/templates/projects/view_ajax.html"),
 		filepath.Join(cwd, "web/templates/projects/settings.html"),
+		filepath.Join(cwd, "web/templates/projects/score_formula_preview.html"),
 		filepath.Join(cwd, "web/templates/projects/emails.html"),
 		filepath.Join(cwd, "web/templates/projects/people.html"),
 		filepath.Join(cwd, "web/templates/projects/reports.html"),
@@ -324,6 +452,16 @@ func loadTemplates(router *gin.Engine) {
 		filepath.Join(cwd, "web/templates/projects/reports_weekly.html"),
 		filepath.Join(cwd, "web/templates/projects/reports_monthly.html"),
 		filepath.Join(cwd, "web/templates/projects/reports_yearly.html"),
+		filepath.Join(cwd, "web/templates/projects/pr_cycle_metrics.html"),
+		filepath.Join(cwd, "web/templates/projects/reviewer_report.html"),
+		filepath.Join(cwd, "web/templates/projects/collaboration_graph.html"),
+		filepath.Join(cwd, "web/templates/projects/review_coverage.html"),
+		filepath.Join(cwd, "web/templates/projects/stale_pull_requests.html"),
+		filepath.Join(cwd, "web/templates/projects/issue_report.html"),
+		filepath.Join(cwd, "web/templates/projects/ci_report.html"),
+		filepath.Join(cwd, "web/templates/projects/dora_report.html"),
+		filepath.Join(cwd, "web/templates/projects/teams.html"),
+		filepath.Join(cwd, "web/templates/projects/repository_filter.html"),
 		filepath.Join(cwd, "web/templates/projects/collaborators.html"),
 		filepath.Join(cwd, "web/templates/projects/person_stats.html"),
 		filepath.Join(cwd, "web/templates/projects/repository.html"),
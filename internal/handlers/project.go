@@ -17,34 +17,52 @@ import (
 	"github.com/alimgiray/gscope/internal/repositories"
 	"github.com/alimgiray/gscope/internal/services"
 	"github.com/gin-gonic/gin"
+	"github.com/google/go-github/v57/github"
 	"github.com/google/uuid"
 	"github.com/xuri/excelize/v2"
 )
 
 type ProjectHandler struct {
-	projectService               *services.ProjectService
-	userService                  *services.UserService
-	scoreSettingsService         *services.ScoreSettingsService
-	excludedExtensionService     *services.ExcludedExtensionService
-	excludedFolderService        *services.ExcludedFolderService
-	githubRepoService            *services.GitHubRepositoryService
-	jobService                   *services.JobService
-	jobRepo                      *repositories.JobRepository
-	commitRepo                   *repositories.CommitRepository
-	commitFileRepo               *repositories.CommitFileRepository
-	pullRequestRepo              *repositories.PullRequestRepository
-	prReviewRepo                 *repositories.PRReviewRepository
-	githubPersonRepo             *repositories.GithubPersonRepository
-	personRepo                   *repositories.PersonRepository
-	emailMergeService            *services.EmailMergeService
-	githubPersonEmailService     *services.GitHubPersonEmailService
-	textSimilarityService        *services.TextSimilarityService
-	peopleStatsService           *services.PeopleStatisticsService
-	projectUpdateSettingsService *services.ProjectUpdateSettingsService
-	projectCollaboratorService   *services.ProjectCollaboratorService
-	workingHoursSettingsService  *services.WorkingHoursSettingsService
-	projectGithubPersonService   *services.ProjectGithubPersonService
-	llmAPIKeyService             *services.LLMAPIKeyService
+	projectService                 *services.ProjectService
+	userService                    *services.UserService
+	scoreSettingsService           *services.ScoreSettingsService
+	excludedExtensionService       *services.ExcludedExtensionService
+	excludedFolderService          *services.ExcludedFolderService
+	githubRepoService              *services.GitHubRepositoryService
+	jobService                     *services.JobService
+	jobRepo                        *repositories.JobRepository
+	commitRepo                     *repositories.CommitRepository
+	commitFileRepo                 *repositories.CommitFileRepository
+	pullRequestRepo                *repositories.PullRequestRepository
+	prReviewRepo                   *repositories.PRReviewRepository
+	githubPersonRepo               *repositories.GithubPersonRepository
+	personRepo                     *repositories.PersonRepository
+	emailMergeService              *services.EmailMergeService
+	githubPersonEmailService       *services.GitHubPersonEmailService
+	textSimilarityService          *services.TextSimilarityService
+	peopleStatsService             *services.PeopleStatisticsService
+	projectUpdateSettingsService   *services.ProjectUpdateSettingsService
+	projectCollaboratorService     *services.ProjectCollaboratorService
+	workingHoursSettingsService    *services.WorkingHoursSettingsService
+	projectGithubPersonService     *services.ProjectGithubPersonService
+	llmAPIKeyService               *services.LLMAPIKeyService
+	repositoryDiscoveryService     *services.RepositoryDiscoveryService
+	githubAppService               *services.GitHubAppService
+	githubClientPool               *services.GitHubClientPool
+	jobGitHubStatsRepo             *repositories.JobGitHubStatsRepository
+	prCycleMetricsService          *services.PRCycleMetricsService
+	reviewerReportService          *services.ReviewerReportService
+	collaborationService           *services.CollaborationService
+	reviewCoverageService          *services.ReviewCoverageService
+	stalePullRequestService        *services.StalePullRequestService
+	issueReportService             *services.IssueReportService
+	ciReportService                *services.CIReportService
+	doraService                    *services.DORAService
+	botService                     *services.BotService
+	teamService                    *services.TeamService
+	repositoryGroupService         *services.RepositoryGroupService
+	repositoryScoreSettingsService *services.RepositoryScoreSettingsService
+	scoreFormulaService            *services.ScoreFormulaService
 }
 
 func NewProjectHandler(projectService *services.ProjectService, userService *services.UserService,
@@ -54,31 +72,53 @@ func NewProjectHandler(projectService *services.ProjectService, userService *ser
 	prReviewRepo *repositories.PRReviewRepository, githubPersonRepo *repositories.GithubPersonRepository,
 	personRepo *repositories.PersonRepository, emailMergeService *services.EmailMergeService,
 	githubPersonEmailService *services.GitHubPersonEmailService, textSimilarityService *services.TextSimilarityService,
-	peopleStatsService *services.PeopleStatisticsService, projectUpdateSettingsService *services.ProjectUpdateSettingsService, projectCollaboratorService *services.ProjectCollaboratorService, workingHoursSettingsService *services.WorkingHoursSettingsService, projectGithubPersonService *services.ProjectGithubPersonService, llmAPIKeyService *services.LLMAPIKeyService) *ProjectHandler {
+	peopleStatsService *services.PeopleStatisticsService, projectUpdateSettingsService *services.ProjectUpdateSettingsService, projectCollaboratorService *services.ProjectCollaboratorService, workingHoursSettingsService *services.WorkingHoursSettingsService, projectGithubPersonService *services.ProjectGithubPersonService, llmAPIKeyService *services.LLMAPIKeyService,
+	repositoryDiscoveryService *services.RepositoryDiscoveryService, githubAppService *services.GitHubAppService, githubClientPool *services.GitHubClientPool, jobGitHubStatsRepo *repositories.JobGitHubStatsRepository,
+	prCycleMetricsService *services.PRCycleMetricsService, reviewerReportService *services.ReviewerReportService,
+	collaborationService *services.CollaborationService, reviewCoverageService *services.ReviewCoverageService,
+	stalePullRequestService *services.StalePullRequestService, issueReportService *services.IssueReportService, ciReportService *services.CIReportService,
+	doraService *services.DORAService, botService *services.BotService, teamService *services.TeamService, repositoryGroupService *services.RepositoryGroupService, repositoryScoreSettingsService *services.RepositoryScoreSettingsService, scoreFormulaService *services.ScoreFormulaService) *ProjectHandler {
 	return &ProjectHandler{
-		projectService:               projectService,
-		userService:                  userService,
-		scoreSettingsService:         scoreSettingsService,
-		excludedExtensionService:     excludedExtensionService,
-		excludedFolderService:        excludedFolderService,
-		githubRepoService:            githubRepoService,
-		jobService:                   jobService,
-		jobRepo:                      jobRepo,
-		commitRepo:                   commitRepo,
-		commitFileRepo:               commitFileRepo,
-		pullRequestRepo:              pullRequestRepo,
-		prReviewRepo:                 prReviewRepo,
-		githubPersonRepo:             githubPersonRepo,
-		personRepo:                   personRepo,
-		emailMergeService:            emailMergeService,
-		githubPersonEmailService:     githubPersonEmailService,
-		textSimilarityService:        textSimilarityService,
-		peopleStatsService:           peopleStatsService,
-		projectUpdateSettingsService: projectUpdateSettingsService,
-		projectCollaboratorService:   projectCollaboratorService,
-		workingHoursSettingsService:  workingHoursSettingsService,
-		projectGithubPersonService:   projectGithubPersonService,
-		llmAPIKeyService:             llmAPIKeyService,
+		projectService:                 projectService,
+		userService:                    userService,
+		scoreSettingsService:           scoreSettingsService,
+		excludedExtensionService:       excludedExtensionService,
+		excludedFolderService:          excludedFolderService,
+		githubRepoService:              githubRepoService,
+		jobService:                     jobService,
+		jobRepo:                        jobRepo,
+		commitRepo:                     commitRepo,
+		commitFileRepo:                 commitFileRepo,
+		pullRequestRepo:                pullRequestRepo,
+		prReviewRepo:                   prReviewRepo,
+		githubPersonRepo:               githubPersonRepo,
+		personRepo:                     personRepo,
+		emailMergeService:              emailMergeService,
+		githubPersonEmailService:       githubPersonEmailService,
+		textSimilarityService:          textSimilarityService,
+		peopleStatsService:             peopleStatsService,
+		projectUpdateSettingsService:   projectUpdateSettingsService,
+		projectCollaboratorService:     projectCollaboratorService,
+		workingHoursSettingsService:    workingHoursSettingsService,
+		projectGithubPersonService:     projectGithubPersonService,
+		llmAPIKeyService:               llmAPIKeyService,
+		repositoryDiscoveryService:     repositoryDiscoveryService,
+		githubAppService:               githubAppService,
+		githubClientPool:               githubClientPool,
+		jobGitHubStatsRepo:             jobGitHubStatsRepo,
+		prCycleMetricsService:          prCycleMetricsService,
+		reviewerReportService:          reviewerReportService,
+		collaborationService:           collaborationService,
+		reviewCoverageService:          reviewCoverageService,
+		stalePullRequestService:        stalePullRequestService,
+		issueReportService:             issueReportService,
+		ciReportService:                ciReportService,
+		doraService:                    doraService,
+		botService:                     botService,
+		teamService:                    teamService,
+		repositoryGroupService:         repositoryGroupService,
+		repositoryScoreSettingsService: repositoryScoreSettingsService,
+		scoreFormulaService:            scoreFormulaService,
 	}
 }
 
@@ -394,6 +434,12 @@ func (h *ProjectHandler) ViewProject(c *gin.Context) {
 	// Get message from query parameter
 	message := c.Query("message")
 
+	// Get remaining GitHub API quota of the project's tokens
+	githubQuota, err := h.githubClientPool.ProjectQuota(projectID)
+	if err != nil {
+		githubQuota = []services.GitHubTokenQuota{}
+	}
+
 	data := gin.H{
 		"Title":        project.Name,
 		"User":         session,
@@ -401,6 +447,8 @@ func (h *ProjectHandler) ViewProject(c *gin.Context) {
 		"Owner":        owner,
 		"Repositories": repositories,
 		"Message":      message,
+		"GitHubQuota":  githubQuota,
+		"AccessType":   accessType,
 	}
 
 	// Check if this is an AJAX request
@@ -458,6 +506,10 @@ func (h *ProjectHandler) ProjectSettings(c *gin.Context) {
 		// If no score settings found, create default ones
 		scoreSettings = models.NewScoreSettings(projectID)
 	}
+	scoreSettingsVersions, err := h.scoreSettingsService.GetScoreSettingsVersions(projectID)
+	if err != nil {
+		log.Printf("Error getting score settings versions: %v", err)
+	}
 
 	// Get excluded extensions
 	excludedExtensions, err := h.excludedExtensionService.GetExcludedExtensionsByProjectID(projectID)
@@ -478,6 +530,39 @@ func (h *ProjectHandler) ProjectSettings(c *gin.Context) {
 		log.Printf("Error getting project update settings: %v", err)
 		updateSettings = nil
 	}
+	repositoryGroups, err := h.repositoryGroupService.GetGroups(projectID)
+	if err != nil {
+		log.Printf("Error getting repository groups: %v", err)
+	}
+
+	// Get the repositories and their score weighting
+	repositoryOptions, err := h.repositoryGroupService.GetFilterOptions(projectID, "", "")
+	if err != nil {
+		log.Printf("Error getting project repositories: %v", err)
+		repositoryOptions = &models.RepositoryFilterOptions{}
+	}
+	repositoryScoreSettings, err := h.repositoryScoreSettingsService.GetByProjectID(projectID)
+	if err != nil {
+		log.Printf("Error getting repository score settings: %v", err)
+	}
+
+	// Get the versions of the score formula
+	scoreFormulas, err := h.scoreFormulaService.GetFormulas(projectID)
+	if err != nil {
+		log.Printf("Error getting score formulas: %v", err)
+	}
+	var activeScoreFormula *models.ScoreFormula
+	for _, formula := range scoreFormulas {
+		if formula.IsActive {
+			activeScoreFormula = formula
+		}
+	}
+
+	// The latest version tells over which days the active formula scores
+	var currentScoreSettingsVersion *models.ScoreSettingsVersion
+	if len(scoreSettingsVersions) > 0 {
+		currentScoreSettingsVersion = scoreSettingsVersions[0]
+	}
 
 	// Get working hours settings
 	workingHoursSettings, err := h.workingHoursSettingsService.GetByProjectID(projectID)
@@ -497,6 +582,27 @@ func (h *ProjectHandler) ProjectSettings(c *gin.Context) {
 		}
 	}
 
+	// Get stale pull request settings
+	stalePRSettings, err := h.stalePullRequestService.GetSettings(projectID)
+	if err != nil {
+		log.Printf("Error getting stale pull request settings: %v", err)
+		stalePRSettings = models.NewStalePRSettings(projectID)
+	}
+
+	// Get bot settings
+	botSettings, err := h.botService.GetSettings(projectID)
+	if err != nil {
+		log.Printf("Error getting bot settings: %v", err)
+		botSettings = models.NewBotSettings(projectID)
+	}
+
+	// Get DORA settings
+	doraSettings, err := h.doraService.GetSettings(projectID)
+	if err != nil {
+		log.Printf("Error getting DORA settings: %v", err)
+		doraSettings = models.NewDORASettings(projectID)
+	}
+
 	// Get LLM API key
 	userUUID, _ := uuid.Parse(session.UserID)
 	projectUUID, _ := uuid.Parse(projectID)
@@ -508,17 +614,62 @@ func (h *ProjectHandler) ProjectSettings(c *gin.Context) {
 		apiKey = nil
 	}
 
+	// Get repository discovery configuration
+	discoverySources, err := h.repositoryDiscoveryService.GetSources(projectID)
+	if err != nil {
+		discoverySources = []*models.RepositoryDiscoverySource{}
+	}
+	discoveryRules, err := h.repositoryDiscoveryService.GetRules(projectID)
+	if err != nil {
+		discoveryRules = []*models.RepositoryDiscoveryRule{}
+	}
+
+	// Get GitHub App installation
+	githubAppInstallation, err := h.githubAppService.GetProjectInstallation(projectID)
+	if err != nil {
+		log.Printf("Error getting GitHub App installation: %v", err)
+	}
+	var githubAppInstallations []*github.Installation
+	if h.githubAppService.IsConfigured() && accessType == "owner" {
+		githubAppInstallations, err = h.githubAppService.ListUserInstallations(session.UserID)
+		if err != nil {
+			log.Printf("Error listing GitHub App installations: %v", err)
+		}
+	}
+
 	data := gin.H{
-		"Title":                "Project Settings",
-		"User":                 session,
-		"Project":              project,
-		"ScoreSettings":        scoreSettings,
-		"ExcludedExtensions":   excludedExtensions,
-		"ExcludedFolders":      excludedFolders,
-		"UpdateSettings":       updateSettings,
-		"WorkingHoursSettings": workingHoursSettings,
-		"AccessType":           accessType,
-		"APIKey":               apiKey,
+		"Title":                   "Project Settings",
+		"User":                    session,
+		"Project":                 project,
+		"ScoreSettings":           scoreSettings,
+		"ScoreSettingsVersions":   scoreSettingsVersions,
+		"CurrentScoreVersion":     currentScoreSettingsVersion,
+		"Today":                   time.Now().UTC().Format("2006-01-02"),
+		"Repositories":            repositoryOptions.Repositories,
+		"RepositoryScores":        repositoryScoreSettings,
+		"ScoreFormulas":           scoreFormulas,
+		"ActiveScoreFormula":      activeScoreFormula,
+		"ScoreFormulaVariables":   models.ScoreFormulaVariables,
+		"ScoreFormulaFunctions":   models.ScoreFormulaFunctions,
+		"ScoreFormulaPreviewDays": services.DefaultScoreFormulaPreviewDays,
+		"ExcludedExtensions":      excludedExtensions,
+		"ExcludedFolders":         excludedFolders,
+		"UpdateSettings":          updateSettings,
+		"RepositoryGroups":        repositoryGroups,
+		"WorkingHoursSettings":    workingHoursSettings,
+		"StalePRSettings":         stalePRSettings,
+		"DORASettings":            doraSettings,
+		"BotSettings":             botSettings,
+		"AccessType":              accessType,
+		"APIKey":                  apiKey,
+		"DiscoverySources":        discoverySources,
+		"DiscoveryRules":          discoveryRules,
+		"GitHubApp": gin.H{
+			"Configured":    h.githubAppService.IsConfigured(),
+			"InstallURL":    h.githubAppService.InstallURL(),
+			"Installation":  githubAppInstallation,
+			"Installations": githubAppInstallations,
+		},
 	}
 
 	c.HTML(http.StatusOK, "project_settings", data)
@@ -595,6 +746,10 @@ func (h *ProjectHandler) UpdateScoreSettings(c *gin.Context) {
 	commits, _ := strconv.Atoi(c.PostForm("commits"))
 	pullRequests, _ := strconv.Atoi(c.PostForm("pull_requests"))
 	comments, _ := strconv.Atoi(c.PostForm("comments"))
+	reviewComments, _ := strconv.Atoi(c.PostForm("review_comments"))
+	issueComments, _ := strconv.Atoi(c.PostForm("issue_comments"))
+	issuesOpened, _ := strconv.Atoi(c.PostForm("issues_opened"))
+	issuesClosed, _ := strconv.Atoi(c.PostForm("issues_closed"))
 
 	// Validate project ownership
 	project, err := h.projectService.GetProjectByID(projectID)
@@ -629,9 +784,27 @@ func (h *ProjectHandler) UpdateScoreSettings(c *gin.Context) {
 	scoreSettings.Commits = commits
 	scoreSettings.PullRequests = pullRequests
 	scoreSettings.Comments = comments
+	scoreSettings.ReviewComments = reviewComments
+	scoreSettings.IssueComments = issueComments
+	scoreSettings.IssuesOpened = issuesOpened
+	scoreSettings.IssuesClosed = issuesClosed
 
-	if err := h.scoreSettingsService.UpdateScoreSettings(scoreSettings); err != nil {
-		c.HTML(http.StatusInternalServerError, "error", gin.H{
+	effectiveFrom, err := scoreEffectiveFrom(c)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to update score settings: " + err.Error(),
+		})
+		return
+	}
+
+	if _, err := h.scoreSettingsService.UpdateScoreSettings(scoreSettings, effectiveFrom, session.UserID); err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*models.ValidationError); ok {
+			status = http.StatusBadRequest
+		}
+		c.HTML(status, "error", gin.H{
 			"Title": "Error",
 			"User":  session,
 			"Error": "Failed to update score settings: " + err.Error(),
@@ -642,6 +815,259 @@ func (h *ProjectHandler) UpdateScoreSettings(c *gin.Context) {
 	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
 }
 
+// scoreEffectiveFrom parses whether a change of the scoring rescores all of history, returning nil, or
+// only applies from the posted effective date on
+func scoreEffectiveFrom(c *gin.Context) (*time.Time, error) {
+	if c.PostForm("apply_to") == "history" {
+		return nil, nil
+	}
+	parsed, err := time.Parse("2006-01-02", c.PostForm("effective_from"))
+	if err != nil {
+		return nil, fmt.Errorf("effective date must be in YYYY-MM-DD format")
+	}
+	return &parsed, nil
+}
+
+// UpdateRepositoryScoreSettings handles the score weighting of a repository and queues the
+// recalculation of its statistics
+func (h *ProjectHandler) UpdateRepositoryScoreSettings(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	settings := h.repositoryScoreSettingsService.NewSettings(projectID, c.PostForm("project_repository_id"))
+	var err error
+	settings.Multiplier, err = strconv.ParseFloat(strings.TrimSpace(c.PostForm("multiplier")), 64)
+	if err != nil {
+		err = &models.ValidationError{Field: "multiplier", Message: "Score multiplier must be a number"}
+	}
+	settings.OverrideWeights = c.PostForm("override_weights") == "on"
+	if settings.OverrideWeights {
+		settings.Additions, _ = strconv.Atoi(c.PostForm("additions"))
+		settings.Deletions, _ = strconv.Atoi(c.PostForm("deletions"))
+		settings.Commits, _ = strconv.Atoi(c.PostForm("commits"))
+		settings.PullRequests, _ = strconv.Atoi(c.PostForm("pull_requests"))
+		settings.Comments, _ = strconv.Atoi(c.PostForm("comments"))
+		settings.ReviewComments, _ = strconv.Atoi(c.PostForm("review_comments"))
+		settings.IssueComments, _ = strconv.Atoi(c.PostForm("issue_comments"))
+		settings.IssuesOpened, _ = strconv.Atoi(c.PostForm("issues_opened"))
+		settings.IssuesClosed, _ = strconv.Atoi(c.PostForm("issues_closed"))
+	}
+
+	if err == nil {
+		err = h.repositoryScoreSettingsService.UpdateSettings(projectID, settings)
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*models.ValidationError); ok {
+			status = http.StatusBadRequest
+		}
+		c.HTML(status, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to update repository score settings: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// ResetRepositoryScoreSettings scores a repository with the project's weights again and queues the
+// recalculation of its statistics
+func (h *ProjectHandler) ResetRepositoryScoreSettings(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	if err := h.repositoryScoreSettingsService.ResetSettings(projectID, c.Param("repository_id")); err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*models.ValidationError); ok {
+			status = http.StatusBadRequest
+		}
+		c.HTML(status, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to reset repository score settings: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// PreviewScoreFormula compares the recent scores of a project's people with their scores under a formula
+func (h *ProjectHandler) PreviewScoreFormula(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	// Check if the user has access to the project (owners and collaborators can access settings)
+	accessType, err := h.projectCollaboratorService.GetProjectAccessType(projectID, session.UserID)
+	if err != nil || (accessType != "owner" && accessType != "collaborator") {
+		c.HTML(http.StatusForbidden, "error", gin.H{
+			"Title": "Access Denied",
+			"User":  session,
+			"Error": "Only project owners and collaborators can access project settings.",
+		})
+		return
+	}
+
+	project, err := h.projectService.GetProjectByID(projectID)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error", gin.H{
+			"Title": "Project Not Found",
+			"User":  session,
+			"Error": "The requested project could not be found.",
+		})
+		return
+	}
+
+	expression := strings.TrimSpace(c.PostForm("expression"))
+	days, err := strconv.Atoi(c.PostForm("days"))
+	if err != nil {
+		days = services.DefaultScoreFormulaPreviewDays
+	}
+
+	data := gin.H{
+		"Title":                 "Score Formula Preview",
+		"User":                  session,
+		"Project":               project,
+		"AccessType":            accessType,
+		"Expression":            expression,
+		"Days":                  days,
+		"Today":                 time.Now().UTC().Format("2006-01-02"),
+		"ScoreFormulaVariables": models.ScoreFormulaVariables,
+		"ScoreFormulaFunctions": models.ScoreFormulaFunctions,
+	}
+
+	previews, err := h.scoreFormulaService.PreviewFormula(projectID, expression, days)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*models.ValidationError); ok {
+			status = http.StatusBadRequest
+		}
+		data["FormulaError"] = err.Error()
+		c.HTML(status, "score_formula_preview", data)
+		return
+	}
+	data["Previews"] = previews
+
+	c.HTML(http.StatusOK, "score_formula_preview", data)
+}
+
+// SaveScoreFormula stores a new version of the score formula of a project, optionally activating it
+func (h *ProjectHandler) SaveScoreFormula(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	activate := c.PostForm("activate") == "on"
+	effectiveFrom, err := scoreEffectiveFrom(c)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to save score formula: " + err.Error(),
+		})
+		return
+	}
+
+	if _, err := h.scoreFormulaService.SaveFormula(projectID, session.UserID, c.PostForm("expression"), c.PostForm("note"), activate, effectiveFrom); err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*models.ValidationError); ok {
+			status = http.StatusBadRequest
+		}
+		c.HTML(status, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to save score formula: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// ActivateScoreFormula makes a version of the score formula active, from a date on or over all of history,
+// and queues the recalculation of the project's statistics
+func (h *ProjectHandler) ActivateScoreFormula(c *gin.Context) {
+	h.setActiveScoreFormula(c, c.Param("formula_id"))
+}
+
+// DeactivateScoreFormula goes back to scoring with the weights of the score settings, from a date on or
+// over all of history, and queues the recalculation of the project's statistics
+func (h *ProjectHandler) DeactivateScoreFormula(c *gin.Context) {
+	h.setActiveScoreFormula(c, "")
+}
+
+func (h *ProjectHandler) setActiveScoreFormula(c *gin.Context, formulaID string) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	effectiveFrom, err := scoreEffectiveFrom(c)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to change the active score formula: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.scoreFormulaService.ActivateFormula(projectID, formulaID, session.UserID, effectiveFrom); err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*models.ValidationError); ok {
+			status = http.StatusBadRequest
+		}
+		c.HTML(status, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to change the active score formula: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
 // AddExcludedExtension handles adding excluded extensions
 func (h *ProjectHandler) AddExcludedExtension(c *gin.Context) {
 	session := middleware.GetSession(c)
@@ -835,28 +1261,83 @@ func (h *ProjectHandler) FetchRepositories(c *gin.Context) {
 		return
 	}
 
-	// Get GitHub token from database
-	user, err := h.userService.GetUserByID(session.UserID)
+	// Projects connected to a GitHub App installation fetch with the installation's token
+	installation, err := h.githubAppService.GetProjectInstallation(projectID)
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error", gin.H{
 			"Title": "Error",
 			"User":  session,
-			"Error": "Failed to retrieve user data.",
+			"Error": "Failed to load GitHub App settings.",
 		})
 		return
 	}
 
-	if user.GitHubAccessToken == "" {
-		c.HTML(http.StatusBadRequest, "error", gin.H{
-			"Title": "GitHub Token Required",
+	var token string
+	if installation != nil {
+		token, err = h.githubAppService.GetInstallationToken(installation.InstallationID)
+		if err != nil {
+			c.HTML(http.StatusInternalServerError, "error", gin.H{
+				"Title": "Error Fetching Repositories",
+				"User":  session,
+				"Error": "Failed to authenticate as GitHub App installation: " + err.Error(),
+			})
+			return
+		}
+	} else {
+		// Get GitHub token from database
+		user, err := h.userService.GetUserByID(session.UserID)
+		if err != nil {
+			c.HTML(http.StatusInternalServerError, "error", gin.H{
+				"Title": "Error",
+				"User":  session,
+				"Error": "Failed to retrieve user data.",
+			})
+			return
+		}
+
+		if user.GitHubAccessToken == "" {
+			c.HTML(http.StatusBadRequest, "error", gin.H{
+				"Title": "GitHub Token Required",
+				"User":  session,
+				"Error": "GitHub token not found. Please login with GitHub again.",
+			})
+			return
+		}
+		token = user.GitHubAccessToken
+	}
+
+	// Projects bound to organizations or teams only discover repositories from those sources
+	hasSources, err := h.repositoryDiscoveryService.HasSources(projectID)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error", gin.H{
+			"Title": "Error",
 			"User":  session,
-			"Error": "GitHub token not found. Please login with GitHub again.",
+			"Error": "Failed to load repository discovery settings.",
 		})
 		return
 	}
 
+	if hasSources {
+		if _, err := h.repositoryDiscoveryService.DiscoverProjectRepositories(projectID, token); err != nil {
+			c.HTML(http.StatusInternalServerError, "error", gin.H{
+				"Title": "Error Fetching Repositories",
+				"User":  session,
+				"Error": "Failed to discover repositories from GitHub: " + err.Error(),
+			})
+			return
+		}
+
+		c.Redirect(http.StatusFound, "/projects/"+projectID)
+		return
+	}
+
 	// Fetch repositories from GitHub
-	if err := h.githubRepoService.FetchUserRepositories(projectID, user.GitHubAccessToken); err != nil {
+	if installation != nil {
+		err = h.githubRepoService.FetchInstallationRepositories(projectID, token)
+	} else {
+		err = h.githubRepoService.FetchUserRepositories(projectID, token)
+	}
+	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error", gin.H{
 			"Title": "Error Fetching Repositories",
 			"User":  session,
@@ -966,8 +1447,8 @@ func (h *ProjectHandler) DeleteExcludedFolder(c *gin.Context) {
 	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
 }
 
-// ToggleRepositoryTracking toggles the tracking status of a project repository
-func (h *ProjectHandler) ToggleRepositoryTracking(c *gin.Context) {
+// AddDiscoverySource binds the project to a GitHub organization or team
+func (h *ProjectHandler) AddDiscoverySource(c *gin.Context) {
 	session := middleware.GetSession(c)
 	if session == nil {
 		c.Redirect(http.StatusFound, "/login")
@@ -975,13 +1456,307 @@ func (h *ProjectHandler) ToggleRepositoryTracking(c *gin.Context) {
 	}
 
 	projectID := c.Param("id")
-	repositoryID := c.Param("repository_id")
+	organization := strings.TrimSpace(c.PostForm("organization"))
+	teamSlug := strings.TrimSpace(c.PostForm("team_slug"))
+	autoTrack := c.PostForm("auto_track") == "on"
 
-	// Validate project access (owner or collaborator)
-	project, err := h.projectService.GetProjectByID(projectID)
-	if err != nil {
-		c.HTML(http.StatusNotFound, "error", gin.H{
-			"Title": "Project Not Found",
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	if _, err := h.repositoryDiscoveryService.AddSource(projectID, organization, teamSlug, autoTrack); err != nil {
+		c.HTML(http.StatusBadRequest, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to add discovery source: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// DeleteDiscoverySource removes an organization or team from the project
+func (h *ProjectHandler) DeleteDiscoverySource(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+	sourceID := c.Param("source_id")
+
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	if err := h.repositoryDiscoveryService.DeleteSource(projectID, sourceID); err != nil {
+		c.HTML(http.StatusInternalServerError, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to delete discovery source: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// AddDiscoveryRule adds an include or exclude rule for repository discovery
+func (h *ProjectHandler) AddDiscoveryRule(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+	action := c.PostForm("action")
+	field := c.PostForm("field")
+	value := c.PostForm("value")
+
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	if _, err := h.repositoryDiscoveryService.AddRule(projectID, action, field, value); err != nil {
+		c.HTML(http.StatusBadRequest, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to add discovery rule: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// DeleteDiscoveryRule removes a repository discovery rule
+func (h *ProjectHandler) DeleteDiscoveryRule(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+	ruleID := c.Param("rule_id")
+
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	if err := h.repositoryDiscoveryService.DeleteRule(projectID, ruleID); err != nil {
+		c.HTML(http.StatusInternalServerError, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to delete discovery rule: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// SetGitHubAppInstallation connects the project to a GitHub App installation
+func (h *ProjectHandler) SetGitHubAppInstallation(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	installationID, err := strconv.ParseInt(strings.TrimSpace(c.PostForm("installation_id")), 10, 64)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Invalid installation ID.",
+		})
+		return
+	}
+
+	if _, err := h.githubAppService.SetProjectInstallation(projectID, session.UserID, installationID); err != nil {
+		c.HTML(http.StatusBadRequest, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to connect GitHub App installation: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// RemoveGitHubAppInstallation disconnects the project from its GitHub App installation
+func (h *ProjectHandler) RemoveGitHubAppInstallation(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	if err := h.githubAppService.RemoveProjectInstallation(projectID); err != nil {
+		c.HTML(http.StatusInternalServerError, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to disconnect GitHub App installation: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// UpdateGitHubIngestion selects whether pull requests of the project are fetched with the REST or GraphQL API
+func (h *ProjectHandler) UpdateGitHubIngestion(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	if err := h.projectService.SetGitHubIngestion(projectID, c.PostForm("github_ingestion")); err != nil {
+		c.HTML(http.StatusBadRequest, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to update pull request ingestion: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// UpdateGitHubSync selects which GitHub data of the project is fetched with its updates
+func (h *ProjectHandler) UpdateGitHubSync(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	project, err := h.projectService.GetProjectByID(projectID)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Project not found",
+		})
+		return
+	}
+
+	project.SyncIssues = c.PostForm("sync_issues") == "on"
+	project.SyncCI = c.PostForm("sync_ci") == "on"
+	project.SyncDeployments = c.PostForm("sync_deployments") == "on"
+	if err := h.projectService.SetGitHubSync(project); err != nil {
+		c.HTML(http.StatusInternalServerError, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to update synced data: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// requireProjectOwner renders an error page and returns false unless the session user owns the project
+func (h *ProjectHandler) requireProjectOwner(c *gin.Context, session *middleware.SessionData, projectID string) bool {
+	project, err := h.projectService.GetProjectByID(projectID)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error", gin.H{
+			"Title": "Project Not Found",
+			"User":  session,
+			"Error": "The requested project could not be found.",
+		})
+		return false
+	}
+
+	userID, err := uuid.Parse(session.UserID)
+	if err != nil || project.OwnerID != userID {
+		c.HTML(http.StatusForbidden, "error", gin.H{
+			"Title": "Access Denied",
+			"User":  session,
+			"Error": "You don't have permission to modify this project.",
+		})
+		return false
+	}
+
+	return true
+}
+
+// UpdateRepositoryGroups replaces the repository groups of a project repository
+func (h *ProjectHandler) UpdateRepositoryGroups(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	if err := h.repositoryGroupService.SetRepositoryGroups(projectID, c.Param("repository_id"), c.PostForm("groups")); err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*models.ValidationError); ok {
+			status = http.StatusBadRequest
+		}
+		c.HTML(status, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to update repository groups: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID)
+}
+
+// ToggleRepositoryTracking toggles the tracking status of a project repository
+func (h *ProjectHandler) ToggleRepositoryTracking(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+	repositoryID := c.Param("repository_id")
+
+	// Validate project access (owner or collaborator)
+	project, err := h.projectService.GetProjectByID(projectID)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error", gin.H{
+			"Title": "Project Not Found",
 			"User":  session,
 			"Error": "The requested project could not be found.",
 		})
@@ -1171,7 +1946,7 @@ func (h *ProjectHandler) CreateFetchGithubJob(c *gin.Context) {
 	}
 
 	// Check if project exists
-	_, err := h.projectService.GetProjectByID(projectID)
+	project, err := h.projectService.GetProjectByID(projectID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"success": false,
@@ -1190,8 +1965,8 @@ func (h *ProjectHandler) CreateFetchGithubJob(c *gin.Context) {
 		return
 	}
 
-	// Create only the pull_request job
-	err = h.jobService.CreatePullRequestJob(projectID, projectRepositoryID)
+	// Create the pull_request job, followed by the issue, ci and deployment jobs
+	err = h.jobService.CreateGitHubFetchJobs(project, projectRepositoryID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -1359,8 +2134,8 @@ func (h *ProjectHandler) CloneAllRepositories(c *gin.Context) {
 
 	createdCount := 0
 	for _, projectRepo := range projectRepos {
-		// Only create jobs for tracked repositories
-		if !projectRepo.IsTracked {
+		// Only create jobs for tracked repositories that are neither archived nor deleted
+		if !projectRepo.IsTracked || !h.githubRepoService.IsRepositoryActive(projectRepo) {
 			continue
 		}
 
@@ -1617,20 +2392,72 @@ func (h *ProjectHandler) ViewProjectPeople(c *gin.Context) {
 		projectGithubPeople = []*models.ProjectGithubPerson{}
 	}
 
-	// Get GitHub people details
+	botSettings, err := h.botService.GetSettings(projectID)
+	if err != nil {
+		log.Printf("Error getting bot settings: %v", err)
+		botSettings = models.NewBotSettings(projectID)
+	}
+
+	// Get GitHub people details, with bots hidden, marked or listed separately by the bot policy
 	var people []*models.GithubPerson
 	var deletedPeople []*models.GithubPerson
+	var bots []*models.GithubPerson
+	botIDs := make(map[string]bool)
 	for _, pgp := range projectGithubPeople {
 		person, err := h.githubPersonRepo.GetByID(pgp.GithubPersonID)
 		if err == nil && person != nil {
-			if pgp.IsDeleted {
+			isBot := botSettings.IsBotPerson(person)
+			if isBot {
+				botIDs[person.ID] = true
+			}
+			switch {
+			case pgp.IsDeleted:
 				deletedPeople = append(deletedPeople, person)
-			} else {
+			case isBot && botSettings.Policy == models.BotPolicyHide:
+			case isBot && botSettings.Policy == models.BotPolicySeparate:
+				bots = append(bots, person)
+			default:
 				people = append(people, person)
 			}
 		}
 	}
 
+	// Teams of the project, with the people list narrowed down to the current members of the selected
+	// team. Everyone the project knows can be added to a team.
+	teams, err := h.teamService.GetTeams(projectID)
+	if err != nil {
+		log.Printf("Error getting teams: %v", err)
+		teams = []*models.ProjectTeam{}
+	}
+	peopleByID := make(map[string]*models.GithubPerson)
+	for _, person := range append(append(append([]*models.GithubPerson{}, people...), bots...), deletedPeople...) {
+		peopleByID[person.ID] = person
+	}
+	teamCandidates := people
+	personTeams := make(map[string][]string)
+	var selectedTeam *models.ProjectTeam
+	for _, team := range teams {
+		if team.ID == c.Query("team") {
+			selectedTeam = team
+		}
+		for _, memberID := range team.CurrentMemberIDs() {
+			personTeams[memberID] = append(personTeams[memberID], team.Name)
+		}
+	}
+	if selectedTeam != nil {
+		current := make(map[string]bool)
+		for _, memberID := range selectedTeam.CurrentMemberIDs() {
+			current[memberID] = true
+		}
+		var members []*models.GithubPerson
+		for _, person := range people {
+			if current[person.ID] {
+				members = append(members, person)
+			}
+		}
+		people = members
+	}
+
 	// Get email associations for this project
 	emailAssociations, err := h.githubPersonEmailService.GetGitHubPersonEmailsByProjectID(projectID)
 	if err != nil {
@@ -1649,13 +2476,23 @@ func (h *ProjectHandler) ViewProjectPeople(c *gin.Context) {
 		emails = []*models.EmailStats{}
 	}
 
-	// Filter out merged emails from the dropdown options
+	// Filter out merged emails from the dropdown options, and bot emails unless bots are listed as people
 	var filteredEmails []*models.EmailStats
 	for _, email := range emails {
 		// Check if this email is a source email (merged into another)
-		if _, isMerged := mergedEmails[email.Email]; !isMerged {
-			filteredEmails = append(filteredEmails, email)
+		if _, isMerged := mergedEmails[email.Email]; isMerged {
+			continue
 		}
+		if botSettings.Policy != models.BotPolicyUnscored {
+			name := ""
+			if email.Name != nil {
+				name = *email.Name
+			}
+			if botSettings.IsBotAuthor(name, email.Email) {
+				continue
+			}
+		}
+		filteredEmails = append(filteredEmails, email)
 	}
 
 	// Create a map of GitHub person ID to associated email
@@ -1701,6 +2538,13 @@ func (h *ProjectHandler) ViewProjectPeople(c *gin.Context) {
 		"Project":            project,
 		"People":             people,
 		"DeletedPeople":      deletedPeople,
+		"Bots":               bots,
+		"BotIDs":             botIDs,
+		"Teams":              teams,
+		"SelectedTeam":       selectedTeam,
+		"PersonTeams":        personTeams,
+		"PeopleByID":         peopleByID,
+		"TeamCandidates":     teamCandidates,
 		"Emails":             filteredEmails,
 		"PersonEmailMap":     personEmailMap,
 		"AssociatedEmails":   associatedEmails,
@@ -1708,7 +2552,370 @@ func (h *ProjectHandler) ViewProjectPeople(c *gin.Context) {
 		"AccessType":         accessType,
 	}
 
-	c.HTML(http.StatusOK, "project_people", data)
+	// Collaboration graph for the selected date range
+	from, to, err := collaborationDateRange(c)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": err.Error(),
+		})
+		return
+	}
+	collaboration, err := h.collaborationService.GetGraphByProject(projectID, from, to)
+	if err != nil {
+		collaboration = &models.CollaborationGraph{From: from, To: to}
+	}
+	data["Collaboration"] = collaboration
+	data["CollaborationFrom"] = from.Format("2006-01-02")
+	data["CollaborationTo"] = to.AddDate(0, 0, -1).Format("2006-01-02")
+
+	c.HTML(http.StatusOK, "project_people", data)
+}
+
+// SyncProjectTeams imports the project's GitHub organization teams and their members right away
+func (h *ProjectHandler) SyncProjectTeams(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	token, err := h.githubClientPool.ProjectToken(projectID)
+	if err != nil {
+		c.HTML(http.StatusBadRequest, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to get a GitHub token: " + err.Error(),
+		})
+		return
+	}
+
+	if _, err := h.teamService.SyncProjectTeams(projectID, token); err != nil {
+		c.HTML(http.StatusInternalServerError, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to sync teams: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/people")
+}
+
+// CreateProjectTeam creates a team defined in the project
+func (h *ProjectHandler) CreateProjectTeam(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	if _, err := h.teamService.CreateTeam(projectID, c.PostForm("name")); err != nil {
+		h.renderTeamError(c, session, "create team", err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/people")
+}
+
+// DeleteProjectTeam deletes a team defined in the project
+func (h *ProjectHandler) DeleteProjectTeam(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	if err := h.teamService.DeleteTeam(projectID, c.Param("team_id")); err != nil {
+		h.renderTeamError(c, session, "delete team", err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/people")
+}
+
+// AddProjectTeamMember adds a person to a team defined in the project for a period
+func (h *ProjectHandler) AddProjectTeamMember(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	_, err := h.teamService.AddMember(projectID, c.Param("team_id"), c.PostForm("github_person_id"), c.PostForm("start_date"), c.PostForm("end_date"))
+	if err != nil {
+		h.renderTeamError(c, session, "add team member", err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/people")
+}
+
+// UpdateProjectTeamMember changes the period of a team membership
+func (h *ProjectHandler) UpdateProjectTeamMember(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	err := h.teamService.UpdateMember(projectID, c.Param("team_id"), c.Param("member_id"), c.PostForm("start_date"), c.PostForm("end_date"))
+	if err != nil {
+		h.renderTeamError(c, session, "update team member", err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/people")
+}
+
+// RemoveProjectTeamMember deletes a team membership
+func (h *ProjectHandler) RemoveProjectTeamMember(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+	if !h.requireProjectOwner(c, session, projectID) {
+		return
+	}
+
+	if err := h.teamService.RemoveMember(projectID, c.Param("team_id"), c.Param("member_id")); err != nil {
+		h.renderTeamError(c, session, "remove team member", err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/people")
+}
+
+// renderTeamError renders the error page of a failed team change, with a 400 for invalid input
+func (h *ProjectHandler) renderTeamError(c *gin.Context, session *middleware.SessionData, action string, err error) {
+	status := http.StatusInternalServerError
+	if _, ok := err.(*models.ValidationError); ok {
+		status = http.StatusBadRequest
+	}
+	c.HTML(status, "error", gin.H{
+		"Title": "Error",
+		"User":  session,
+		"Error": "Failed to " + action + ": " + err.Error(),
+	})
+}
+
+// collaborationDateRange parses the inclusive from and to dates (YYYY-MM-DD) of the collaboration graph,
+// defaulting to the last 90 days. The returned end is exclusive.
+func collaborationDateRange(c *gin.Context) (time.Time, time.Time, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -89)
+	to := today
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from date, expected YYYY-MM-DD")
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to date, expected YYYY-MM-DD")
+		}
+		to = parsed
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from date must not be after to date")
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+// ExportCollaborationGraph exports the collaboration graph of a project as JSON or GraphML
+func (h *ProjectHandler) ExportCollaborationGraph(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Authentication required",
+		})
+		return
+	}
+
+	projectID := c.Param("id")
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "graphml" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Format must be json or graphml",
+		})
+		return
+	}
+
+	if _, err := h.projectService.GetProjectByID(projectID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Project not found",
+		})
+		return
+	}
+
+	accessType, err := h.projectCollaboratorService.GetProjectAccessType(projectID, session.UserID)
+	if err != nil || accessType == "none" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"message": "Access denied",
+		})
+		return
+	}
+
+	from, to, err := collaborationDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	graph, err := h.collaborationService.GetGraphByProject(projectID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to build collaboration graph: " + err.Error(),
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("collaboration-%s-%s", from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02"))
+	if format == "graphml" {
+		output, err := services.CollaborationGraphML(graph)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Failed to generate GraphML: " + err.Error(),
+			})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.graphml", filename))
+		c.Data(http.StatusOK, "application/graphml+xml", output)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.json", filename))
+	c.JSON(http.StatusOK, graph)
+}
+
+// ViewStalePullRequests displays the open pull requests of a project that are past the stale thresholds
+func (h *ProjectHandler) ViewStalePullRequests(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+	project, err := h.projectService.GetProjectByID(projectID)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error", gin.H{
+			"Title": "Project Not Found",
+			"User":  session,
+			"Error": "The requested project could not be found.",
+		})
+		return
+	}
+
+	accessType, err := h.projectCollaboratorService.GetProjectAccessType(projectID, session.UserID)
+	if err != nil || accessType == "none" {
+		c.HTML(http.StatusForbidden, "error", gin.H{
+			"Title": "Access Denied",
+			"User":  session,
+			"Error": "You don't have permission to view this project.",
+		})
+		return
+	}
+
+	report, err := h.stalePullRequestService.GetReportByProject(projectID)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to load stale pull requests: " + err.Error(),
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "project_stale_pull_requests", gin.H{
+		"Title":      "Stale Pull Requests",
+		"User":       session,
+		"Project":    project,
+		"AccessType": accessType,
+		"Report":     report,
+	})
+}
+
+// GetStalePullRequestsJSON returns the stale pull requests of a project as JSON
+func (h *ProjectHandler) GetStalePullRequestsJSON(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Authentication required",
+		})
+		return
+	}
+
+	projectID := c.Param("id")
+	if _, err := h.projectService.GetProjectByID(projectID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Project not found",
+		})
+		return
+	}
+
+	accessType, err := h.projectCollaboratorService.GetProjectAccessType(projectID, session.UserID)
+	if err != nil || accessType == "none" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"message": "Access denied",
+		})
+		return
+	}
+
+	report, err := h.stalePullRequestService.GetReportByProject(projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to load stale pull requests: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
 }
 
 // ViewProjectReports displays the reports page for a project
@@ -1750,17 +2957,68 @@ func (h *ProjectHandler) ViewProjectReports(c *gin.Context) {
 		return
 	}
 
+	// Narrow the reports down to a repository group or a single repository
+	repositoryFilter, err := h.repositoryGroupService.GetFilterOptions(projectID, c.Query("group"), c.Query("repository"))
+	if err != nil {
+		log.Printf("Error building repository filter for project %s: %v", projectID, err)
+		repositoryFilter = &models.RepositoryFilterOptions{}
+	}
+	filter := repositoryFilter.Filter
+
 	// Get all-time statistics for all GitHub people in this project
-	allTimeStats, err := h.peopleStatsService.GetAllTimeStatisticsByProject(projectID)
+	allTimeStats, err := h.peopleStatsService.GetAllTimeStatisticsByProject(projectID, filter)
 	if err != nil {
 		allTimeStats = []*models.GitHubPersonStats{}
 	}
+	teamReport, allTimeStats, err := h.teamService.GetAllTimeTeamReport(projectID, c.Query("team"), allTimeStats, filter)
+	if err != nil {
+		log.Printf("Error building team report for project %s: %v", projectID, err)
+		teamReport = &models.TeamReport{}
+	}
+
+	cycleReport, err := h.prCycleMetricsService.GetAllTimeReportByProject(projectID, filter)
+	if err != nil {
+		cycleReport = &models.PRCycleReport{}
+	}
+
+	reviewerReport, err := h.reviewerReportService.GetAllTimeReportByProject(projectID, filter)
+	if err != nil {
+		reviewerReport = &models.ReviewerReport{}
+	}
+
+	reviewCoverage, err := h.reviewCoverageService.GetAllTimeReportByProject(projectID, filter)
+	if err != nil {
+		reviewCoverage = &models.ReviewCoverageReport{}
+	}
+
+	issueReport, err := h.issueReportService.GetAllTimeReportByProject(projectID, filter)
+	if err != nil {
+		issueReport = &models.IssueReport{}
+	}
+
+	ciReport, err := h.ciReportService.GetAllTimeReportByProject(projectID, filter)
+	if err != nil {
+		ciReport = &models.CIReport{}
+	}
+
+	doraReport, err := h.doraService.GetAllTimeReportByProject(projectID, filter)
+	if err != nil {
+		doraReport = &models.DORAReport{}
+	}
 
 	data := gin.H{
-		"Title":        "Reports - " + project.Name,
-		"User":         session,
-		"Project":      project,
-		"AllTimeStats": allTimeStats,
+		"Title":            "Reports - " + project.Name,
+		"User":             session,
+		"Project":          project,
+		"AllTimeStats":     allTimeStats,
+		"CycleReport":      cycleReport,
+		"ReviewerReport":   reviewerReport,
+		"ReviewCoverage":   reviewCoverage,
+		"IssueReport":      issueReport,
+		"CIReport":         ciReport,
+		"DORAReport":       doraReport,
+		"TeamReport":       teamReport,
+		"RepositoryFilter": repositoryFilter,
 	}
 
 	c.HTML(http.StatusOK, "project_reports", data)
@@ -1826,19 +3084,70 @@ func (h *ProjectHandler) ViewProjectReportsDaily(c *gin.Context) {
 		selectedDate = time.Now()
 	}
 
+	// Narrow the reports down to a repository group or a single repository
+	repositoryFilter, err := h.repositoryGroupService.GetFilterOptions(projectID, c.Query("group"), c.Query("repository"))
+	if err != nil {
+		log.Printf("Error building repository filter for project %s: %v", projectID, err)
+		repositoryFilter = &models.RepositoryFilterOptions{}
+	}
+	filter := repositoryFilter.Filter
+
 	// Get daily statistics for the selected day
-	dailyStats, err := h.peopleStatsService.GetDailyStatisticsByProject(projectID, selectedDate)
+	dailyStats, err := h.peopleStatsService.GetDailyStatisticsByProject(projectID, selectedDate, filter)
 	if err != nil {
 		dailyStats = []*models.GitHubPersonStats{}
 	}
+	teamReport, dailyStats, err := h.teamService.GetDailyTeamReport(projectID, c.Query("team"), selectedDate, dailyStats, filter)
+	if err != nil {
+		log.Printf("Error building team report for project %s: %v", projectID, err)
+		teamReport = &models.TeamReport{}
+	}
+
+	cycleReport, err := h.prCycleMetricsService.GetDailyReportByProject(projectID, selectedDate, filter)
+	if err != nil {
+		cycleReport = &models.PRCycleReport{}
+	}
+
+	reviewerReport, err := h.reviewerReportService.GetDailyReportByProject(projectID, selectedDate, filter)
+	if err != nil {
+		reviewerReport = &models.ReviewerReport{}
+	}
+
+	reviewCoverage, err := h.reviewCoverageService.GetDailyReportByProject(projectID, selectedDate, filter)
+	if err != nil {
+		reviewCoverage = &models.ReviewCoverageReport{}
+	}
+
+	issueReport, err := h.issueReportService.GetDailyReportByProject(projectID, selectedDate, filter)
+	if err != nil {
+		issueReport = &models.IssueReport{}
+	}
+
+	ciReport, err := h.ciReportService.GetDailyReportByProject(projectID, selectedDate, filter)
+	if err != nil {
+		ciReport = &models.CIReport{}
+	}
+
+	doraReport, err := h.doraService.GetDailyReportByProject(projectID, selectedDate, filter)
+	if err != nil {
+		doraReport = &models.DORAReport{}
+	}
 
 	data := gin.H{
-		"Title":       "Daily Reports - " + project.Name,
-		"User":        session,
-		"Project":     project,
-		"Days":        availableDays,
-		"SelectedDay": selectedDay,
-		"DailyStats":  dailyStats,
+		"Title":            "Daily Reports - " + project.Name,
+		"User":             session,
+		"Project":          project,
+		"Days":             availableDays,
+		"SelectedDay":      selectedDay,
+		"DailyStats":       dailyStats,
+		"CycleReport":      cycleReport,
+		"ReviewerReport":   reviewerReport,
+		"ReviewCoverage":   reviewCoverage,
+		"IssueReport":      issueReport,
+		"CIReport":         ciReport,
+		"DORAReport":       doraReport,
+		"TeamReport":       teamReport,
+		"RepositoryFilter": repositoryFilter,
 	}
 
 	c.HTML(http.StatusOK, "project_reports_daily", data)
@@ -1909,23 +3218,74 @@ func (h *ProjectHandler) ViewProjectReportsWeekly(c *gin.Context) {
 		selectedWeekInt = week
 	}
 
+	// Narrow the reports down to a repository group or a single repository
+	repositoryFilter, err := h.repositoryGroupService.GetFilterOptions(projectID, c.Query("group"), c.Query("repository"))
+	if err != nil {
+		log.Printf("Error building repository filter for project %s: %v", projectID, err)
+		repositoryFilter = &models.RepositoryFilterOptions{}
+	}
+	filter := repositoryFilter.Filter
+
 	// Get weekly statistics for the selected week
-	weeklyStats, err := h.peopleStatsService.GetWeeklyStatisticsByProject(projectID, selectedYear, selectedWeekInt)
+	weeklyStats, err := h.peopleStatsService.GetWeeklyStatisticsByProject(projectID, selectedYear, selectedWeekInt, filter)
 	if err != nil {
 		weeklyStats = []*models.GitHubPersonStats{}
 	}
+	teamReport, weeklyStats, err := h.teamService.GetWeeklyTeamReport(projectID, c.Query("team"), selectedYear, selectedWeekInt, weeklyStats, filter)
+	if err != nil {
+		log.Printf("Error building team report for project %s: %v", projectID, err)
+		teamReport = &models.TeamReport{}
+	}
 
 	// Calculate date range for the selected week
 	weekDateRange := h.calculateWeekDateRange(selectedYear, selectedWeekInt)
 
+	cycleReport, err := h.prCycleMetricsService.GetWeeklyReportByProject(projectID, selectedYear, selectedWeekInt, filter)
+	if err != nil {
+		cycleReport = &models.PRCycleReport{}
+	}
+
+	reviewerReport, err := h.reviewerReportService.GetWeeklyReportByProject(projectID, selectedYear, selectedWeekInt, filter)
+	if err != nil {
+		reviewerReport = &models.ReviewerReport{}
+	}
+
+	reviewCoverage, err := h.reviewCoverageService.GetWeeklyReportByProject(projectID, selectedYear, selectedWeekInt, filter)
+	if err != nil {
+		reviewCoverage = &models.ReviewCoverageReport{}
+	}
+
+	issueReport, err := h.issueReportService.GetWeeklyReportByProject(projectID, selectedYear, selectedWeekInt, filter)
+	if err != nil {
+		issueReport = &models.IssueReport{}
+	}
+
+	ciReport, err := h.ciReportService.GetWeeklyReportByProject(projectID, selectedYear, selectedWeekInt, filter)
+	if err != nil {
+		ciReport = &models.CIReport{}
+	}
+
+	doraReport, err := h.doraService.GetWeeklyReportByProject(projectID, selectedYear, selectedWeekInt, filter)
+	if err != nil {
+		doraReport = &models.DORAReport{}
+	}
+
 	data := gin.H{
-		"Title":         "Weekly Reports - " + project.Name,
-		"User":          session,
-		"Project":       project,
-		"Weeks":         availableWeeks,
-		"SelectedWeek":  selectedWeek,
-		"WeeklyStats":   weeklyStats,
-		"WeekDateRange": weekDateRange,
+		"Title":            "Weekly Reports - " + project.Name,
+		"User":             session,
+		"Project":          project,
+		"Weeks":            availableWeeks,
+		"SelectedWeek":     selectedWeek,
+		"WeeklyStats":      weeklyStats,
+		"WeekDateRange":    weekDateRange,
+		"CycleReport":      cycleReport,
+		"ReviewerReport":   reviewerReport,
+		"ReviewCoverage":   reviewCoverage,
+		"IssueReport":      issueReport,
+		"CIReport":         ciReport,
+		"DORAReport":       doraReport,
+		"TeamReport":       teamReport,
+		"RepositoryFilter": repositoryFilter,
 	}
 
 	c.HTML(http.StatusOK, "project_reports_weekly", data)
@@ -2017,19 +3377,70 @@ func (h *ProjectHandler) ViewProjectReportsMonthly(c *gin.Context) {
 		selectedMonthInt = int(time.Now().Month())
 	}
 
+	// Narrow the reports down to a repository group or a single repository
+	repositoryFilter, err := h.repositoryGroupService.GetFilterOptions(projectID, c.Query("group"), c.Query("repository"))
+	if err != nil {
+		log.Printf("Error building repository filter for project %s: %v", projectID, err)
+		repositoryFilter = &models.RepositoryFilterOptions{}
+	}
+	filter := repositoryFilter.Filter
+
 	// Get monthly statistics for the selected month
-	monthlyStats, err := h.peopleStatsService.GetMonthlyStatisticsByProject(projectID, selectedYear, selectedMonthInt)
+	monthlyStats, err := h.peopleStatsService.GetMonthlyStatisticsByProject(projectID, selectedYear, selectedMonthInt, filter)
+	if err != nil {
+		monthlyStats = []*models.GitHubPersonStats{}
+	}
+	teamReport, monthlyStats, err := h.teamService.GetMonthlyTeamReport(projectID, c.Query("team"), selectedYear, selectedMonthInt, monthlyStats, filter)
+	if err != nil {
+		log.Printf("Error building team report for project %s: %v", projectID, err)
+		teamReport = &models.TeamReport{}
+	}
+
+	cycleReport, err := h.prCycleMetricsService.GetMonthlyReportByProject(projectID, selectedYear, selectedMonthInt, filter)
+	if err != nil {
+		cycleReport = &models.PRCycleReport{}
+	}
+
+	reviewerReport, err := h.reviewerReportService.GetMonthlyReportByProject(projectID, selectedYear, selectedMonthInt, filter)
+	if err != nil {
+		reviewerReport = &models.ReviewerReport{}
+	}
+
+	reviewCoverage, err := h.reviewCoverageService.GetMonthlyReportByProject(projectID, selectedYear, selectedMonthInt, filter)
+	if err != nil {
+		reviewCoverage = &models.ReviewCoverageReport{}
+	}
+
+	issueReport, err := h.issueReportService.GetMonthlyReportByProject(projectID, selectedYear, selectedMonthInt, filter)
+	if err != nil {
+		issueReport = &models.IssueReport{}
+	}
+
+	ciReport, err := h.ciReportService.GetMonthlyReportByProject(projectID, selectedYear, selectedMonthInt, filter)
+	if err != nil {
+		ciReport = &models.CIReport{}
+	}
+
+	doraReport, err := h.doraService.GetMonthlyReportByProject(projectID, selectedYear, selectedMonthInt, filter)
 	if err != nil {
-		monthlyStats = []*models.GitHubPersonStats{}
+		doraReport = &models.DORAReport{}
 	}
 
 	data := gin.H{
-		"Title":         "Monthly Reports - " + project.Name,
-		"User":          session,
-		"Project":       project,
-		"Months":        availableMonths,
-		"SelectedMonth": selectedMonth,
-		"MonthlyStats":  monthlyStats,
+		"Title":            "Monthly Reports - " + project.Name,
+		"User":             session,
+		"Project":          project,
+		"Months":           availableMonths,
+		"SelectedMonth":    selectedMonth,
+		"MonthlyStats":     monthlyStats,
+		"CycleReport":      cycleReport,
+		"ReviewerReport":   reviewerReport,
+		"ReviewCoverage":   reviewCoverage,
+		"IssueReport":      issueReport,
+		"CIReport":         ciReport,
+		"DORAReport":       doraReport,
+		"TeamReport":       teamReport,
+		"RepositoryFilter": repositoryFilter,
 	}
 
 	c.HTML(http.StatusOK, "project_reports_monthly", data)
@@ -2091,19 +3502,70 @@ func (h *ProjectHandler) ViewProjectReportsYearly(c *gin.Context) {
 		selectedYear = availableYears[0]
 	}
 
+	// Narrow the reports down to a repository group or a single repository
+	repositoryFilter, err := h.repositoryGroupService.GetFilterOptions(projectID, c.Query("group"), c.Query("repository"))
+	if err != nil {
+		log.Printf("Error building repository filter for project %s: %v", projectID, err)
+		repositoryFilter = &models.RepositoryFilterOptions{}
+	}
+	filter := repositoryFilter.Filter
+
 	// Get yearly statistics for the selected year
-	yearlyStats, err := h.peopleStatsService.GetYearlyStatisticsByProject(projectID, selectedYear)
+	yearlyStats, err := h.peopleStatsService.GetYearlyStatisticsByProject(projectID, selectedYear, filter)
 	if err != nil {
 		yearlyStats = []*models.GitHubPersonStats{}
 	}
+	teamReport, yearlyStats, err := h.teamService.GetYearlyTeamReport(projectID, c.Query("team"), selectedYear, yearlyStats, filter)
+	if err != nil {
+		log.Printf("Error building team report for project %s: %v", projectID, err)
+		teamReport = &models.TeamReport{}
+	}
+
+	cycleReport, err := h.prCycleMetricsService.GetYearlyReportByProject(projectID, selectedYear, filter)
+	if err != nil {
+		cycleReport = &models.PRCycleReport{}
+	}
+
+	reviewerReport, err := h.reviewerReportService.GetYearlyReportByProject(projectID, selectedYear, filter)
+	if err != nil {
+		reviewerReport = &models.ReviewerReport{}
+	}
+
+	reviewCoverage, err := h.reviewCoverageService.GetYearlyReportByProject(projectID, selectedYear, filter)
+	if err != nil {
+		reviewCoverage = &models.ReviewCoverageReport{}
+	}
+
+	issueReport, err := h.issueReportService.GetYearlyReportByProject(projectID, selectedYear, filter)
+	if err != nil {
+		issueReport = &models.IssueReport{}
+	}
+
+	ciReport, err := h.ciReportService.GetYearlyReportByProject(projectID, selectedYear, filter)
+	if err != nil {
+		ciReport = &models.CIReport{}
+	}
+
+	doraReport, err := h.doraService.GetYearlyReportByProject(projectID, selectedYear, filter)
+	if err != nil {
+		doraReport = &models.DORAReport{}
+	}
 
 	data := gin.H{
-		"Title":        "Yearly Reports - " + project.Name,
-		"User":         session,
-		"Project":      project,
-		"Years":        availableYears,
-		"SelectedYear": selectedYear,
-		"YearlyStats":  yearlyStats,
+		"Title":            "Yearly Reports - " + project.Name,
+		"User":             session,
+		"Project":          project,
+		"Years":            availableYears,
+		"SelectedYear":     selectedYear,
+		"YearlyStats":      yearlyStats,
+		"CycleReport":      cycleReport,
+		"ReviewerReport":   reviewerReport,
+		"ReviewCoverage":   reviewCoverage,
+		"IssueReport":      issueReport,
+		"CIReport":         ciReport,
+		"DORAReport":       doraReport,
+		"TeamReport":       teamReport,
+		"RepositoryFilter": repositoryFilter,
 	}
 
 	c.HTML(http.StatusOK, "project_reports_yearly", data)
@@ -2127,6 +3589,15 @@ type Statistics struct {
 
 // calculateStatistics calculates mean, median, and standard deviation for all metrics
 func calculateStatistics(stats []*models.GitHubPersonStats) Statistics {
+	// Bots stay out of team statistics
+	var people []*models.GitHubPersonStats
+	for _, stat := range stats {
+		if !stat.IsBot && !stat.IsAutomation {
+			people = append(people, stat)
+		}
+	}
+	stats = people
+
 	if len(stats) == 0 {
 		return Statistics{}
 	}
@@ -2251,8 +3722,16 @@ func (h *ProjectHandler) ExportMonthlyReportsToExcel(c *gin.Context) {
 		return
 	}
 
+	// Narrow the reports down to a repository group or a single repository
+	repositoryFilter, err := h.repositoryGroupService.GetFilterOptions(projectID, c.Query("group"), c.Query("repository"))
+	if err != nil {
+		log.Printf("Error building repository filter for project %s: %v", projectID, err)
+		repositoryFilter = &models.RepositoryFilterOptions{}
+	}
+	filter := repositoryFilter.Filter
+
 	// Get monthly statistics for the selected month
-	monthlyStats, err := h.peopleStatsService.GetMonthlyStatisticsByProject(projectID, selectedYear, selectedMonthInt)
+	monthlyStats, err := h.peopleStatsService.GetMonthlyStatisticsByProject(projectID, selectedYear, selectedMonthInt, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -2261,6 +3740,13 @@ func (h *ProjectHandler) ExportMonthlyReportsToExcel(c *gin.Context) {
 		return
 	}
 
+	// Narrow down to the selected team, if any
+	teamReport, monthlyStats, err := h.teamService.GetMonthlyTeamReport(projectID, c.Query("team"), selectedYear, selectedMonthInt, monthlyStats, filter)
+	if err != nil {
+		log.Printf("Error building team report for project %s: %v", projectID, err)
+		teamReport = &models.TeamReport{}
+	}
+
 	// Filter out people with zero score
 	var filteredStats []*models.GitHubPersonStats
 	for _, stat := range monthlyStats {
@@ -2399,6 +3885,17 @@ func (h *ProjectHandler) ExportMonthlyReportsToExcel(c *gin.Context) {
 	f.SetCellValue(sheetName, fmt.Sprintf("B%d", infoStartRow+2), selectedMonth)
 	f.SetCellValue(sheetName, fmt.Sprintf("A%d", infoStartRow+3), "Generated:")
 	f.SetCellValue(sheetName, fmt.Sprintf("B%d", infoStartRow+3), time.Now().Format("2006-01-02 15:04:05"))
+	if teamReport.SelectedTeam != nil {
+		f.SetCellValue(sheetName, fmt.Sprintf("A%d", infoStartRow+4), "Team:")
+		f.SetCellValue(sheetName, fmt.Sprintf("B%d", infoStartRow+4), teamReport.SelectedTeam.Name)
+	}
+	if filter != nil {
+		f.SetCellValue(sheetName, fmt.Sprintf("A%d", infoStartRow+5), "Repositories:")
+		f.SetCellValue(sheetName, fmt.Sprintf("B%d", infoStartRow+5), filter.Label)
+	}
+
+	// Add team rollups on their own sheet
+	writeTeamSheet(f, teamReport.TeamStats)
 
 	// Set response headers
 	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
@@ -2465,8 +3962,16 @@ func (h *ProjectHandler) ExportYearlyReportsToExcel(c *gin.Context) {
 		return
 	}
 
+	// Narrow the reports down to a repository group or a single repository
+	repositoryFilter, err := h.repositoryGroupService.GetFilterOptions(projectID, c.Query("group"), c.Query("repository"))
+	if err != nil {
+		log.Printf("Error building repository filter for project %s: %v", projectID, err)
+		repositoryFilter = &models.RepositoryFilterOptions{}
+	}
+	filter := repositoryFilter.Filter
+
 	// Get yearly statistics for the selected year
-	yearlyStats, err := h.peopleStatsService.GetYearlyStatisticsByProject(projectID, selectedYearInt)
+	yearlyStats, err := h.peopleStatsService.GetYearlyStatisticsByProject(projectID, selectedYearInt, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
@@ -2475,6 +3980,13 @@ func (h *ProjectHandler) ExportYearlyReportsToExcel(c *gin.Context) {
 		return
 	}
 
+	// Narrow down to the selected team, if any
+	teamReport, yearlyStats, err := h.teamService.GetYearlyTeamReport(projectID, c.Query("team"), selectedYearInt, yearlyStats, filter)
+	if err != nil {
+		log.Printf("Error building team report for project %s: %v", projectID, err)
+		teamReport = &models.TeamReport{}
+	}
+
 	// Filter out people with zero score
 	var filteredStats []*models.GitHubPersonStats
 	for _, stat := range yearlyStats {
@@ -2613,6 +4125,17 @@ func (h *ProjectHandler) ExportYearlyReportsToExcel(c *gin.Context) {
 	f.SetCellValue(sheetName, fmt.Sprintf("B%d", infoStartRow+2), selectedYear)
 	f.SetCellValue(sheetName, fmt.Sprintf("A%d", infoStartRow+3), "Generated:")
 	f.SetCellValue(sheetName, fmt.Sprintf("B%d", infoStartRow+3), time.Now().Format("2006-01-02 15:04:05"))
+	if teamReport.SelectedTeam != nil {
+		f.SetCellValue(sheetName, fmt.Sprintf("A%d", infoStartRow+4), "Team:")
+		f.SetCellValue(sheetName, fmt.Sprintf("B%d", infoStartRow+4), teamReport.SelectedTeam.Name)
+	}
+	if filter != nil {
+		f.SetCellValue(sheetName, fmt.Sprintf("A%d", infoStartRow+5), "Repositories:")
+		f.SetCellValue(sheetName, fmt.Sprintf("B%d", infoStartRow+5), filter.Label)
+	}
+
+	// Add team rollups on their own sheet
+	writeTeamSheet(f, teamReport.TeamStats)
 
 	// Set response headers
 	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
@@ -2628,6 +4151,38 @@ func (h *ProjectHandler) ExportYearlyReportsToExcel(c *gin.Context) {
 	}
 }
 
+// writeTeamSheet adds a sheet with the team rollups of a report, if the project has teams
+func writeTeamSheet(f *excelize.File, teamStats []*models.TeamStats) {
+	if len(teamStats) == 0 {
+		return
+	}
+
+	sheetName := "Teams"
+	if _, err := f.NewSheet(sheetName); err != nil {
+		log.Printf("Error creating teams sheet: %v", err)
+		return
+	}
+
+	headers := []string{"Team", "Active Members", "Score", "Average Score", "Commits", "Additions", "Deletions", "Pull Requests", "Comments"}
+	for i, header := range headers {
+		f.SetCellValue(sheetName, fmt.Sprintf("%c1", 'A'+i), header)
+		f.SetColWidth(sheetName, string(rune('A'+i)), string(rune('A'+i)), 15)
+	}
+
+	for i, stat := range teamStats {
+		row := i + 2
+		f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), stat.Team.Name)
+		f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), stat.ActiveMembers)
+		f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), stat.TotalScore)
+		f.SetCellValue(sheetName, fmt.Sprintf("D%d", row), fmt.Sprintf("%.1f", stat.AverageScore))
+		f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), stat.TotalCommits)
+		f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), stat.TotalAdditions)
+		f.SetCellValue(sheetName, fmt.Sprintf("G%d", row), stat.TotalDeletions)
+		f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), stat.TotalPullRequests)
+		f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), stat.TotalComments)
+	}
+}
+
 // CreateEmailMerge creates an email merge
 func (h *ProjectHandler) CreateEmailMerge(c *gin.Context) {
 	session := middleware.GetSession(c)
@@ -2997,10 +4552,10 @@ func (h *ProjectHandler) FetchAllRepositories(c *gin.Context) {
 		return
 	}
 
-	// Filter to only tracked repositories
+	// Filter to only tracked repositories that are neither archived nor deleted
 	var trackedRepos []*models.ProjectRepository
 	for _, repo := range repositories {
-		if repo.IsTracked {
+		if repo.IsTracked && h.githubRepoService.IsRepositoryActive(repo) {
 			trackedRepos = append(trackedRepos, repo)
 		}
 	}
@@ -3013,10 +4568,10 @@ func (h *ProjectHandler) FetchAllRepositories(c *gin.Context) {
 		return
 	}
 
-	// Create pull_request jobs for all tracked repositories
+	// Create pull_request, issue, ci and deployment jobs for all tracked repositories
 	createdJobs := 0
 	for _, repo := range trackedRepos {
-		err := h.jobService.CreatePullRequestJob(projectID, repo.ID)
+		err := h.jobService.CreateGitHubFetchJobs(project, repo.ID)
 		if err != nil {
 			// Continue with other repos even if one fails
 			continue
@@ -3168,6 +4723,10 @@ func (h *ProjectHandler) UpdateAllRepositories(c *gin.Context) {
 	var trackedRepos []*models.ProjectRepository
 	var untrackedRepos []*models.ProjectRepository
 	for _, repo := range repositories {
+		// Archived and deleted repositories keep their history but get no new jobs
+		if !h.githubRepoService.IsRepositoryActive(repo) {
+			continue
+		}
 		if repo.IsTracked {
 			trackedRepos = append(trackedRepos, repo)
 		} else {
@@ -3203,63 +4762,33 @@ func (h *ProjectHandler) UpdateAllRepositories(c *gin.Context) {
 	}
 
 	// Create jobs in the correct order with dependencies
-	// Each repository gets its own chain: clone -> commit -> pull_request -> stats
-
-	// Step 1: Create clone jobs for all tracked repositories
+	// Each repository gets its own chain: clone -> commit -> pull_request -> issue -> ci -> deployment -> stats,
+	// leaving out the jobs the project doesn't sync
+	jobTypes := project.SyncedJobTypes(
+		models.JobTypeClone, models.JobTypeCommit, models.JobTypePullRequest,
+		models.JobTypeIssue, models.JobTypeCI, models.JobTypeDeployment, models.JobTypeStats,
+	)
 	for _, repo := range trackedRepos {
-		cloneJob := models.NewJob(projectID, models.JobTypeClone)
-		cloneJob.ProjectRepositoryID = &repo.ID
-		if err := h.jobRepo.Create(cloneJob); err != nil {
-			c.HTML(http.StatusInternalServerError, "error", gin.H{
-				"Title": "Error",
-				"User":  session,
-				"Error": "Failed to create clone job: " + err.Error(),
-			})
-			return
-		}
-		log.Printf("Created clone job %s for repository %s", cloneJob.ID, repo.ID)
-
-		// Step 2: Create commit job that depends on this clone job
-		commitJob := models.NewJob(projectID, models.JobTypeCommit)
-		commitJob.ProjectRepositoryID = &repo.ID
-		commitJob.DependsOn = &cloneJob.ID
-		if err := h.jobRepo.Create(commitJob); err != nil {
-			c.HTML(http.StatusInternalServerError, "error", gin.H{
-				"Title": "Error",
-				"User":  session,
-				"Error": "Failed to create commit job: " + err.Error(),
-			})
-			return
-		}
-		log.Printf("Created commit job %s for repository %s (depends on %s)", commitJob.ID, repo.ID, cloneJob.ID)
-
-		// Step 3: Create pull request job that depends on this commit job
-		pullRequestJob := models.NewJob(projectID, models.JobTypePullRequest)
-		pullRequestJob.ProjectRepositoryID = &repo.ID
-		pullRequestJob.DependsOn = &commitJob.ID
-		if err := h.jobRepo.Create(pullRequestJob); err != nil {
-			c.HTML(http.StatusInternalServerError, "error", gin.H{
-				"Title": "Error",
-				"User":  session,
-				"Error": "Failed to create pull request job: " + err.Error(),
-			})
-			return
-		}
-		log.Printf("Created pull request job %s for repository %s (depends on %s)", pullRequestJob.ID, repo.ID, commitJob.ID)
-
-		// Step 4: Create stats job that depends on this pull request job
-		statsJob := models.NewJob(projectID, models.JobTypeStats)
-		statsJob.ProjectRepositoryID = &repo.ID
-		statsJob.DependsOn = &pullRequestJob.ID
-		if err := h.jobRepo.Create(statsJob); err != nil {
-			c.HTML(http.StatusInternalServerError, "error", gin.H{
-				"Title": "Error",
-				"User":  session,
-				"Error": "Failed to create stats job: " + err.Error(),
-			})
-			return
+		var dependsOn *string
+		for _, jobType := range jobTypes {
+			job := models.NewJob(projectID, jobType)
+			job.ProjectRepositoryID = &repo.ID
+			job.DependsOn = dependsOn
+			if err := h.jobRepo.Create(job); err != nil {
+				c.HTML(http.StatusInternalServerError, "error", gin.H{
+					"Title": "Error",
+					"User":  session,
+					"Error": fmt.Sprintf("Failed to create %s job: %s", jobType, err.Error()),
+				})
+				return
+			}
+			if dependsOn != nil {
+				log.Printf("Created %s job %s for repository %s (depends on %s)", jobType, job.ID, repo.ID, *dependsOn)
+			} else {
+				log.Printf("Created %s job %s for repository %s", jobType, job.ID, repo.ID)
+			}
+			dependsOn = &job.ID
 		}
-		log.Printf("Created stats job %s for repository %s (depends on %s)", statsJob.ID, repo.ID, pullRequestJob.ID)
 	}
 
 	c.Redirect(http.StatusFound, "/projects/"+projectID)
@@ -3324,8 +4853,23 @@ func (h *ProjectHandler) UpdateProjectUpdateSettings(c *gin.Context) {
 		return
 	}
 
+	// Scheduled updates may be limited to a repository group
+	repositoryGroup := strings.TrimSpace(c.PostForm("repository_group"))
+	if err := h.repositoryGroupService.ValidateGroup(projectID, repositoryGroup); err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*models.ValidationError); ok {
+			status = http.StatusBadRequest
+		}
+		c.HTML(status, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to update project update settings: " + err.Error(),
+		})
+		return
+	}
+
 	// Update project update settings
-	_, err = h.projectUpdateSettingsService.UpsertProjectUpdateSettings(projectID, isEnabled, hour)
+	_, err = h.projectUpdateSettingsService.UpsertProjectUpdateSettings(projectID, isEnabled, hour, repositoryGroup)
 	if err != nil {
 		c.HTML(http.StatusInternalServerError, "error", gin.H{
 			"Title": "Error",
@@ -3338,6 +4882,157 @@ func (h *ProjectHandler) UpdateProjectUpdateSettings(c *gin.Context) {
 	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
 }
 
+// UpdateStalePRSettings handles updating the stale pull request thresholds of a project
+func (h *ProjectHandler) UpdateStalePRSettings(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	// Validate project ownership
+	project, err := h.projectService.GetProjectByID(projectID)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error", gin.H{
+			"Title": "Project Not Found",
+			"User":  session,
+			"Error": "The requested project could not be found.",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(session.UserID)
+	if err != nil || project.OwnerID != userID {
+		c.HTML(http.StatusForbidden, "error", gin.H{
+			"Title": "Access Denied",
+			"User":  session,
+			"Error": "You don't have permission to modify this project.",
+		})
+		return
+	}
+
+	// Parse form values, missing or invalid values are rejected by validation
+	maxAgeDays, _ := strconv.Atoi(c.PostForm("max_age_days"))
+	reviewSLAHours, _ := strconv.Atoi(c.PostForm("review_sla_hours"))
+	draftIdleDays, _ := strconv.Atoi(c.PostForm("draft_idle_days"))
+
+	if _, err := h.stalePullRequestService.UpdateSettings(projectID, maxAgeDays, reviewSLAHours, draftIdleDays); err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*models.ValidationError); ok {
+			status = http.StatusBadRequest
+		}
+		c.HTML(status, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to update stale pull request settings: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// UpdateDORASettings handles updating what counts as a deploy and a failure for the DORA metrics of a project
+func (h *ProjectHandler) UpdateDORASettings(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	// Validate project ownership
+	project, err := h.projectService.GetProjectByID(projectID)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error", gin.H{
+			"Title": "Project Not Found",
+			"User":  session,
+			"Error": "The requested project could not be found.",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(session.UserID)
+	if err != nil || project.OwnerID != userID {
+		c.HTML(http.StatusForbidden, "error", gin.H{
+			"Title": "Access Denied",
+			"User":  session,
+			"Error": "You don't have permission to modify this project.",
+		})
+		return
+	}
+
+	// Unchecked checkboxes are not submitted
+	revertIsFailure := c.PostForm("revert_is_failure") == "on"
+	failedStatusIsFailure := c.PostForm("failed_status_is_failure") == "on"
+
+	if _, err := h.doraService.UpdateSettings(projectID, c.PostForm("deploy_source"), c.PostForm("environment"), c.PostForm("tag_pattern"),
+		revertIsFailure, c.PostForm("failure_labels"), failedStatusIsFailure); err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*models.ValidationError); ok {
+			status = http.StatusBadRequest
+		}
+		c.HTML(status, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to update DORA settings: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
+// UpdateBotSettings handles updating how the bots of a project are detected and treated
+func (h *ProjectHandler) UpdateBotSettings(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	projectID := c.Param("id")
+
+	// Validate project ownership
+	project, err := h.projectService.GetProjectByID(projectID)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error", gin.H{
+			"Title": "Project Not Found",
+			"User":  session,
+			"Error": "The requested project could not be found.",
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(session.UserID)
+	if err != nil || project.OwnerID != userID {
+		c.HTML(http.StatusForbidden, "error", gin.H{
+			"Title": "Access Denied",
+			"User":  session,
+			"Error": "You don't have permission to modify this project.",
+		})
+		return
+	}
+
+	if _, err := h.botService.UpdateSettings(projectID, c.PostForm("policy"), c.PostForm("patterns")); err != nil {
+		status := http.StatusInternalServerError
+		if _, ok := err.(*models.ValidationError); ok {
+			status = http.StatusBadRequest
+		}
+		c.HTML(status, "error", gin.H{
+			"Title": "Error",
+			"User":  session,
+			"Error": "Failed to update bot settings: " + err.Error(),
+		})
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/projects/"+projectID+"/settings")
+}
+
 // RetryFailedJob retries a specific failed job
 func (h *ProjectHandler) RetryFailedJob(c *gin.Context) {
 	session := middleware.GetSession(c)
@@ -3447,12 +5142,22 @@ func (h *ProjectHandler) ViewProjectCollaborators(c *gin.Context) {
 		return
 	}
 
+	sharingGitHubToken := false
+	if accessType == "collaborator" {
+		sharingGitHubToken, err = h.projectCollaboratorService.IsSharingGitHubToken(projectID, session.UserID)
+		if err != nil {
+			log.Printf("Error checking GitHub token sharing: %v", err)
+		}
+	}
+
 	data := gin.H{
-		"Title":         "Project Collaborators",
-		"User":          session,
-		"Project":       project,
-		"Collaborators": collaborators,
-		"AccessType":    accessType,
+		"Title":              "Project Collaborators",
+		"User":               session,
+		"Project":            project,
+		"Collaborators":      collaborators,
+		"AccessType":         accessType,
+		"SharingGitHubToken": sharingGitHubToken,
+		"GitHubAppEnabled":   h.githubAppService.IsConfigured(),
 	}
 
 	c.HTML(http.StatusOK, "project_collaborators", data)
@@ -3498,6 +5203,33 @@ func (h *ProjectHandler) AddProjectCollaborator(c *gin.Context) {
 	})
 }
 
+// ShareGitHubToken lets the current collaborator opt in or out of sharing their GitHub token with background jobs
+func (h *ProjectHandler) ShareGitHubToken(c *gin.Context) {
+	session := middleware.GetSession(c)
+	if session == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "Unauthorized"})
+		return
+	}
+
+	projectID := c.Param("id")
+	if projectID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "Project ID is required"})
+		return
+	}
+
+	share := c.PostForm("share") == "true"
+	if err := h.projectCollaboratorService.SetShareGitHubToken(projectID, session.UserID, share); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+
+	message := "Your GitHub token is no longer used for this project's background jobs"
+	if share {
+		message = "Your GitHub token will be used for this project's background jobs"
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": message})
+}
+
 // RemoveProjectCollaborator removes a collaborator from a project
 func (h *ProjectHandler) RemoveProjectCollaborator(c *gin.Context) {
 	session := middleware.GetSession(c)
@@ -3591,8 +5323,16 @@ func (h *ProjectHandler) ViewPersonStats(c *gin.Context) {
 		return
 	}
 
+	// Narrow the reports down to a repository group or a single repository
+	repositoryFilter, err := h.repositoryGroupService.GetFilterOptions(projectID, c.Query("group"), c.Query("repository"))
+	if err != nil {
+		log.Printf("Error building repository filter for project %s: %v", projectID, err)
+		repositoryFilter = &models.RepositoryFilterOptions{}
+	}
+	filter := repositoryFilter.Filter
+
 	// Get weekly averages for this person
-	weeklyAverages, err := h.peopleStatsService.GetPersonWeeklyAverages(projectID, personID)
+	weeklyAverages, err := h.peopleStatsService.GetPersonWeeklyAverages(projectID, personID, filter)
 	if err != nil {
 		// Log error but continue with zero averages
 		log.Printf("Error getting weekly averages for person %s: %v", personID, err)
@@ -3606,7 +5346,7 @@ func (h *ProjectHandler) ViewPersonStats(c *gin.Context) {
 	}
 
 	// Get score history for this person
-	scoreHistory, err := h.peopleStatsService.GetPersonScoreHistory(projectID, personID)
+	scoreHistory, err := h.peopleStatsService.GetPersonScoreHistory(projectID, personID, filter)
 	if err != nil {
 		// Log error but continue with empty history
 		log.Printf("Error getting score history for person %s: %v", personID, err)
@@ -3614,7 +5354,7 @@ func (h *ProjectHandler) ViewPersonStats(c *gin.Context) {
 	}
 
 	// Get detailed statistics for this person
-	detailedStats, err := h.peopleStatsService.GetPersonDetailedStats(projectID, personID)
+	detailedStats, err := h.peopleStatsService.GetPersonDetailedStats(projectID, personID, filter)
 	if err != nil {
 		// Log error but continue with empty stats
 		log.Printf("Error getting detailed stats for person %s: %v", personID, err)
@@ -3636,7 +5376,7 @@ func (h *ProjectHandler) ViewPersonStats(c *gin.Context) {
 	}
 
 	// Get top repositories and languages for this person
-	topReposAndLanguages, err := h.peopleStatsService.GetPersonTopReposAndLanguages(projectID, personID)
+	topReposAndLanguages, err := h.peopleStatsService.GetPersonTopReposAndLanguages(projectID, personID, filter)
 	if err != nil {
 		// Log error but continue with empty data
 		log.Printf("Error getting top repos and languages for person %s: %v", personID, err)
@@ -3647,7 +5387,7 @@ func (h *ProjectHandler) ViewPersonStats(c *gin.Context) {
 	}
 
 	// Get top commits and PRs for this person
-	topCommitsAndPRs, err := h.peopleStatsService.GetPersonTopCommitsAndPRs(projectID, personID)
+	topCommitsAndPRs, err := h.peopleStatsService.GetPersonTopCommitsAndPRs(projectID, personID, filter)
 	if err != nil {
 		// Log error but continue with empty data
 		log.Printf("Error getting top commits and PRs for person %s: %v", personID, err)
@@ -3658,7 +5398,7 @@ func (h *ProjectHandler) ViewPersonStats(c *gin.Context) {
 	}
 
 	// Get overtime statistics for this person
-	overtimeStats, err := h.peopleStatsService.GetPersonOvertimeStats(projectID, personID)
+	overtimeStats, err := h.peopleStatsService.GetPersonOvertimeStats(projectID, personID, filter)
 	if err != nil {
 		// Log error but continue with empty data
 		log.Printf("Error getting overtime stats for person %s: %v", personID, err)
@@ -3692,6 +5432,7 @@ func (h *ProjectHandler) ViewPersonStats(c *gin.Context) {
 		"TopReposAndLanguages": topReposAndLanguages,
 		"TopCommitsAndPRs":     topCommitsAndPRs,
 		"OvertimeStats":        overtimeStats,
+		"RepositoryFilter":     repositoryFilter,
 	}
 
 	c.HTML(http.StatusOK, "person_stats", data)
@@ -4032,6 +5773,19 @@ func (h *ProjectHandler) ViewRepository(c *gin.Context) {
 		}
 	}
 
+	// Get GitHub request stats of the latest fetch
+	githubStats, err := h.jobGitHubStatsRepo.GetLatestByProjectRepositoryID(projectRepo.ID)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Error getting GitHub request stats: %v", err)
+	}
+
+	// Get review coverage of the repository per month
+	reviewCoverage, reviewCoverageMonths, err := h.reviewCoverageService.GetMonthlyReportByRepository(githubRepo.ID)
+	if err != nil {
+		log.Printf("Error getting review coverage: %v", err)
+		reviewCoverage = &models.ReviewCoverageStats{}
+	}
+
 	data := gin.H{
 		"Title":                       "Repository Details",
 		"User":                        session,
@@ -4048,6 +5802,9 @@ func (h *ProjectHandler) ViewRepository(c *gin.Context) {
 		"RepositoryStats":             repositoryStats,
 		"TopModifiedFiles":            topModifiedFiles,
 		"TopContributors":             topContributors,
+		"GitHubStats":                 githubStats,
+		"ReviewCoverage":              reviewCoverage,
+		"ReviewCoverageMonths":        reviewCoverageMonths,
 	}
 
 	c.HTML(http.StatusOK, "repository_view", data)
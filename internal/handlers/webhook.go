@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/google/go-github/v57/github"
+)
+
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+	secret         string
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService, secret string) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		secret:         secret,
+	}
+}
+
+// GitHubWebhook handles GitHub webhook deliveries signed with the configured secret
+func (h *WebhookHandler) GitHubWebhook(c *gin.Context) {
+	if h.secret == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhooks are not configured"})
+		return
+	}
+
+	payload, err := github.ValidatePayload(c.Request, []byte(h.secret))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid signature"})
+		return
+	}
+
+	eventType := github.WebHookType(c.Request)
+	created, err := h.webhookService.HandleEvent(eventType, payload)
+	if err != nil {
+		log.Printf("Failed to handle %s webhook %s: %v", eventType, github.DeliveryID(c.Request), err)
+		// Bad payloads are GitHub's to fix; anything else failed here and is worth redelivering
+		if _, ok := err.(*models.ValidationError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to handle webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"event": eventType,
+		"jobs":  created,
+	})
+}
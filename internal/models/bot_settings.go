@@ -0,0 +1,110 @@
+package models
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BotPolicyHide     = "hide"     // Bots are left out of people lists and reports
+	BotPolicyUnscored = "unscored" // Bots are listed but get no score and stay out of team statistics
+	BotPolicySeparate = "separate" // Bots are combined into one automation row, outside team statistics
+)
+
+// BotSettings decides which accounts of a project are bots and how they are treated
+type BotSettings struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	Policy    string `json:"policy"`
+	// Glob patterns, one per line, matched against usernames, author names and emails on top of the
+	// built-in detection
+	Patterns  string    `json:"patterns"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewBotSettings creates bot settings with the default policy of keeping bots out of scoring
+func NewBotSettings(projectID string) *BotSettings {
+	return &BotSettings{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		Policy:    BotPolicyUnscored,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Validate validates the BotSettings fields
+func (s *BotSettings) Validate() error {
+	if s.ProjectID == "" {
+		return &ValidationError{Field: "project_id", Message: "Project ID is required"}
+	}
+	switch s.Policy {
+	case BotPolicyHide, BotPolicyUnscored, BotPolicySeparate:
+	default:
+		return &ValidationError{Field: "policy", Message: "Bot policy must be hide, unscored or separate"}
+	}
+	for _, pattern := range s.PatternList() {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return &ValidationError{Field: "patterns", Message: "Bot pattern " + pattern + " is not a valid glob"}
+		}
+	}
+	return nil
+}
+
+// PatternList returns the lowercase bot patterns
+func (s *BotSettings) PatternList() []string {
+	var patterns []string
+	for _, line := range strings.Split(s.Patterns, "\n") {
+		if pattern := strings.ToLower(strings.TrimSpace(line)); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// IsBotPerson tells whether a GitHub account is a bot: GitHub says so, its login ends with [bot] or it
+// matches one of the patterns
+func (s *BotSettings) IsBotPerson(person *GithubPerson) bool {
+	if person.Type != nil && strings.EqualFold(*person.Type, "Bot") {
+		return true
+	}
+	if strings.HasSuffix(strings.ToLower(person.Username), "[bot]") {
+		return true
+	}
+	return s.matchesPattern(person.Username)
+}
+
+// IsBotAuthor tells whether a commit author is a bot: the name or email contains [bot], the email is a
+// noreply address of a service rather than a user's GitHub noreply address, or it matches one of the
+// patterns
+func (s *BotSettings) IsBotAuthor(name, email string) bool {
+	name, email = strings.ToLower(name), strings.ToLower(email)
+	if strings.Contains(name, "[bot]") || strings.Contains(email, "[bot]") {
+		return true
+	}
+	local, _, _ := strings.Cut(email, "@")
+	switch local {
+	case "noreply", "no-reply", "donotreply", "do-not-reply":
+		return true
+	}
+	return s.matchesPattern(name, email)
+}
+
+// matchesPattern tells whether any of the values matches one of the patterns, ignoring case
+func (s *BotSettings) matchesPattern(values ...string) bool {
+	for _, pattern := range s.PatternList() {
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			if matched, err := path.Match(pattern, strings.ToLower(value)); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+const (
+	CIRunKindCheckRun    = "check_run"
+	CIRunKindWorkflowRun = "workflow_run"
+)
+
+// CIRun is one attempt of a GitHub check run or GitHub Actions workflow run
+type CIRun struct {
+	ID              string     `json:"id"`
+	RepositoryID    string     `json:"repository_id"`
+	Kind            string     `json:"kind"`
+	GithubID        int64      `json:"github_id"`
+	Attempt         int        `json:"attempt"` // Always 1 for check runs, which get a new ID when re-run
+	Name            string     `json:"name"`
+	HeadSHA         string     `json:"head_sha"`
+	HeadBranch      *string    `json:"head_branch"`
+	Event           *string    `json:"event"`
+	Status          string     `json:"status"`
+	Conclusion      *string    `json:"conclusion"`
+	HTMLURL         *string    `json:"html_url"`
+	GithubCreatedAt *time.Time `json:"github_created_at"`
+	StartedAt       *time.Time `json:"started_at"`
+	CompletedAt     *time.Time `json:"completed_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Completed tells whether the run finished with a conclusion
+func (r *CIRun) Completed() bool {
+	return r.Status == "completed" && r.Conclusion != nil
+}
+
+// Passed tells whether the run completed without failing. Skipped and neutral runs pass.
+func (r *CIRun) Passed() bool {
+	if !r.Completed() {
+		return false
+	}
+	switch *r.Conclusion {
+	case "success", "neutral", "skipped":
+		return true
+	}
+	return false
+}
+
+// Failed tells whether the run completed with a failure. Cancelled runs, which are usually
+// superseded by a newer push, and runs waiting on an action don't count as failures.
+func (r *CIRun) Failed() bool {
+	if !r.Completed() {
+		return false
+	}
+	switch *r.Conclusion {
+	case "failure", "timed_out", "startup_failure":
+		return true
+	}
+	return false
+}
+
+// CIPassStats counts the commits whose CI passed or failed on the first attempt, for an author or a repository
+type CIPassStats struct {
+	GithubPersonID string   `json:"github_person_id,omitempty"`
+	Name           string   `json:"name"`
+	Builds         int      `json:"builds"`
+	Passed         int      `json:"passed"`
+	Failed         int      `json:"failed"`
+	PassRate       *float64 `json:"pass_rate"`
+}
+
+// CIFlakyCheck counts the commits on which a check or workflow failed and then passed on a re-run
+type CIFlakyCheck struct {
+	Repository string    `json:"repository"`
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	Flaky      int       `json:"flaky"`
+	Commits    int       `json:"commits"` // Commits the check completed on in the period
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// CIReport holds the CI results of a project for a report period. Waiting on CI is measured on the
+// final head commit of pull requests merged in the period, from the first check run starting to the
+// last one completing, next to the time from opening to merge.
+type CIReport struct {
+	Builds       int             `json:"builds"`
+	Passed       int             `json:"passed"`
+	Failed       int             `json:"failed"`
+	PassRate     *float64        `json:"pass_rate"`
+	Authors      []*CIPassStats  `json:"authors"`
+	Repositories []*CIPassStats  `json:"repositories"`
+	CIWait       MetricSummary   `json:"ci_wait"`
+	CycleTime    MetricSummary   `json:"cycle_time"`
+	CIWaitShare  *float64        `json:"ci_wait_share"` // Total CI wait over total cycle time of the same pull requests
+	Flaky        []*CIFlakyCheck `json:"flaky"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+// CollaborationGraph is the network of who reviews whom in a project over a date range
+type CollaborationGraph struct {
+	From  time.Time            `json:"from"`
+	To    time.Time            `json:"to"` // Exclusive
+	Nodes []*CollaborationNode `json:"nodes"`
+	Edges []*CollaborationEdge `json:"edges"`
+}
+
+// CollaborationNode is a pull request author or reviewer of the graph
+type CollaborationNode struct {
+	ID              string `json:"id"`
+	GithubPersonID  string `json:"github_person_id,omitempty"`
+	Username        string `json:"username"`
+	PullRequests    int    `json:"pull_requests"` // Pull requests opened in the range
+	ReviewsGiven    int    `json:"reviews_given"`
+	ReviewsReceived int    `json:"reviews_received"`
+	// Isolated contributors opened pull requests in the range without reviewing or being reviewed
+	Isolated bool `json:"isolated"`
+
+	// Position of the node in the rendered graph
+	X float64 `json:"-"`
+	Y float64 `json:"-"`
+}
+
+// CollaborationEdge goes from a pull request author to a reviewer, weighted by the number of reviews
+type CollaborationEdge struct {
+	Source  string `json:"source"`
+	Target  string `json:"target"`
+	Reviews int    `json:"reviews"`
+	// Reciprocal-only pairs review each other and nobody else, and nobody else reviews them
+	ReciprocalOnly bool `json:"reciprocal_only"`
+
+	SourceNode *CollaborationNode `json:"-"`
+	TargetNode *CollaborationNode `json:"-"`
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DeploymentSourceRelease    = "release"
+	DeploymentSourceDeployment = "deployment"
+	DeploymentSourceTag        = "tag"
+)
+
+// Deployment is a GitHub release, GitHub deployment or git tag of a repository
+type Deployment struct {
+	ID           string    `json:"id"`
+	RepositoryID string    `json:"repository_id"`
+	Source       string    `json:"source"`
+	ExternalID   string    `json:"external_id"` // GitHub ID of releases and deployments, name of tags
+	Name         string    `json:"name"`        // Tag of releases and tags, ref of deployments
+	Environment  *string   `json:"environment"` // Only for deployments
+	SHA          *string   `json:"sha"`
+	Status       string    `json:"status"` // published or prerelease for releases, the latest deployment status, created for tags
+	URL          *string   `json:"url"`
+	DeployedAt   time.Time `json:"deployed_at"`
+	// Whether the commits shipped by the deployment were linked from the clone
+	CommitsLinked bool      `json:"commits_linked"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// DeploymentCommit links a deployment to a commit it shipped
+type DeploymentCommit struct {
+	DeploymentID string `json:"deployment_id"`
+	CommitSHA    string `json:"commit_sha"`
+}
+
+// DORASettings decides what counts as a deploy and as a failed change for the DORA metrics of a project
+type DORASettings struct {
+	ID           string `json:"id"`
+	ProjectID    string `json:"project_id"`
+	DeploySource string `json:"deploy_source"` // release, deployment or tag
+	Environment  string `json:"environment"`   // Environment of GitHub deployments, empty for all
+	TagPattern   string `json:"tag_pattern"`   // Glob the tag of releases and tags must match, empty for all
+	// A deploy failed when the next one ships a revert commit or a pull request with one of the failure
+	// labels, or when its GitHub deployment status is failure or error
+	RevertIsFailure       bool      `json:"revert_is_failure"`
+	FailureLabels         string    `json:"failure_labels"` // Comma separated
+	FailedStatusIsFailure bool      `json:"failed_status_is_failure"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// NewDORASettings creates DORA settings with the defaults: releases are deploys, and reverts and hotfix
+// pull requests mark the previous deploy as failed
+func NewDORASettings(projectID string) *DORASettings {
+	return &DORASettings{
+		ID:                    uuid.New().String(),
+		ProjectID:             projectID,
+		DeploySource:          DeploymentSourceRelease,
+		Environment:           "production",
+		RevertIsFailure:       true,
+		FailureLabels:         "hotfix",
+		FailedStatusIsFailure: true,
+		CreatedAt:             time.Now(),
+		UpdatedAt:             time.Now(),
+	}
+}
+
+// Validate validates the DORASettings fields
+func (s *DORASettings) Validate() error {
+	if s.ProjectID == "" {
+		return &ValidationError{Field: "project_id", Message: "Project ID is required"}
+	}
+	switch s.DeploySource {
+	case DeploymentSourceRelease, DeploymentSourceDeployment, DeploymentSourceTag:
+	default:
+		return &ValidationError{Field: "deploy_source", Message: "Deploy source must be release, deployment or tag"}
+	}
+	if _, err := path.Match(s.TagPattern, ""); err != nil {
+		return &ValidationError{Field: "tag_pattern", Message: "Tag pattern is not a valid glob"}
+	}
+	return nil
+}
+
+// FailureLabelList returns the failure labels, lower case
+func (s *DORASettings) FailureLabelList() []string {
+	var labels []string
+	for _, label := range strings.Split(s.FailureLabels, ",") {
+		if label = strings.ToLower(strings.TrimSpace(label)); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// DORAStats holds the DORA metrics of a repository or a whole project for a report period
+type DORAStats struct {
+	RepositoryID string   `json:"repository_id,omitempty"`
+	Name         string   `json:"name"`
+	Deployments  int      `json:"deployments"`
+	PerWeek      *float64 `json:"per_week"` // Deployment frequency
+	// Lead time for changes, from commit to the deploy that shipped it
+	LeadTime          MetricSummary `json:"lead_time"`
+	Failures          int           `json:"failures"`
+	ChangeFailureRate *float64      `json:"change_failure_rate"`
+	// Time to restore, from a failed deploy to the next one that didn't fail
+	TimeToRestore MetricSummary `json:"time_to_restore"`
+	Unrestored    int           `json:"unrestored"` // Failed deploys not followed by a successful one yet
+}
+
+// DORAReport holds the DORA metrics of a project and its repositories for a report period
+type DORAReport struct {
+	Settings     *DORASettings `json:"settings"`
+	Project      *DORAStats    `json:"project"`
+	Repositories []*DORAStats  `json:"repositories"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// GitHubHTTPCacheEntry is a cached GitHub API response that is revalidated with its ETag or Last-Modified date
+type GitHubHTTPCacheEntry struct {
+	CacheKey     string    `json:"cache_key"`
+	URL          string    `json:"url"`
+	ETag         *string   `json:"etag"`
+	LastModified *string   `json:"last_modified"`
+	Header       string    `json:"header"`
+	Body         []byte    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// JobGitHubStats records how many GitHub API requests a job made and how many were answered from the cache
+type JobGitHubStats struct {
+	JobID               string    `json:"job_id"`
+	ProjectID           string    `json:"project_id"`
+	ProjectRepositoryID *string   `json:"project_repository_id"`
+	Requests            int64     `json:"requests"`
+	CacheHits           int64     `json:"cache_hits"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// CacheHitRate returns the percentage of requests answered from the cache
+func (s *JobGitHubStats) CacheHitRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.CacheHits) / float64(s.Requests) * 100
+}
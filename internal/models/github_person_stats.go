@@ -9,4 +9,6 @@ type GitHubPersonStats struct {
 	TotalComments     int           `json:"total_comments"`
 	TotalPullRequests int           `json:"total_pull_requests"`
 	TotalScore        int           `json:"total_score"`
+	IsBot             bool          `json:"is_bot"`
+	IsAutomation      bool          `json:"is_automation"` // Combined row of the bots of a project
 }
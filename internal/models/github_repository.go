@@ -19,6 +19,9 @@ type GitHubRepository struct {
 	Stars           int        `json:"stars"`
 	Forks           int        `json:"forks"`
 	Private         bool       `json:"private"`
+	IsArchived      bool       `json:"is_archived"`
+	IsFork          bool       `json:"is_fork"`
+	Topics          []string   `json:"topics"`
 	DefaultBranch   *string    `json:"default_branch"`
 	LocalPath       *string    `json:"local_path"`
 	IsCloned        bool       `json:"is_cloned"`
@@ -26,6 +29,14 @@ type GitHubRepository struct {
 	GithubCreatedAt *time.Time `json:"github_created_at"`
 	GithubUpdatedAt *time.Time `json:"github_updated_at"`
 	GithubPushedAt  *time.Time `json:"github_pushed_at"`
+	// GithubDeletedAt is set once the repository is known to be deleted on GitHub
+	GithubDeletedAt *time.Time `json:"github_deleted_at"`
+	// GithubInaccessibleAt is set once GitHub answers 404 for the repository, which it does both for
+	// deleted repositories and for ones the token can no longer see
+	GithubInaccessibleAt *time.Time `json:"github_inaccessible_at"`
+	PreviousFullName     *string    `json:"previous_full_name"`
+	// StatusCheckedAt is when the status was last refreshed from GitHub
+	StatusCheckedAt *time.Time `json:"status_checked_at"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
 }
@@ -45,3 +56,20 @@ func NewGitHubRepository(githubID int64, name, fullName, url, cloneURL string) *
 		IsCloned: false,
 	}
 }
+
+// IsDeleted reports whether the repository no longer exists on GitHub
+func (r *GitHubRepository) IsDeleted() bool {
+	return r.GithubDeletedAt != nil
+}
+
+// IsInaccessible reports whether GitHub stopped showing the repository to the token, without saying
+// whether it was deleted
+func (r *GitHubRepository) IsInaccessible() bool {
+	return r.GithubInaccessibleAt != nil
+}
+
+// IsActive reports whether jobs should still be scheduled for the repository.
+// Archived, deleted and inaccessible repositories keep their history but receive no new jobs.
+func (r *GitHubRepository) IsActive() bool {
+	return !r.IsArchived && !r.IsDeleted() && !r.IsInaccessible()
+}
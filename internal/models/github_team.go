@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// GithubTeam represents a GitHub organization team
+type GithubTeam struct {
+	ID           string    `json:"id" db:"id"`
+	GithubTeamID int64     `json:"github_team_id" db:"github_team_id"`
+	Slug         string    `json:"slug" db:"slug"`
+	Name         *string   `json:"name" db:"name"`
+	HTMLURL      *string   `json:"html_url" db:"html_url"`
+	Organization *string   `json:"organization" db:"organization"` // Set once the team is synced from its organization
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"time"
+)
+
+// Issue event types that are stored
+const (
+	IssueEventClosed   = "closed"
+	IssueEventReopened = "reopened"
+)
+
+// Issue represents a GitHub issue of a tracked repository
+type Issue struct {
+	ID              string     `json:"id" db:"id"`
+	RepositoryID    string     `json:"repository_id" db:"repository_id"`
+	GithubIssueID   int64      `json:"github_issue_id" db:"github_issue_id"`
+	Number          int        `json:"number" db:"number"`
+	Title           string     `json:"title" db:"title"`
+	Body            *string    `json:"body" db:"body"`
+	State           string     `json:"state" db:"state"`
+	StateReason     *string    `json:"state_reason" db:"state_reason"`
+	AuthorID        *string    `json:"author_id" db:"author_id"`
+	ClosedByID      *string    `json:"closed_by_id" db:"closed_by_id"` // Actor of the latest close event
+	HTMLURL         *string    `json:"html_url" db:"html_url"`
+	GithubCreatedAt *time.Time `json:"github_created_at" db:"github_created_at"`
+	GithubUpdatedAt *time.Time `json:"github_updated_at" db:"github_updated_at"`
+	ClosedAt        *time.Time `json:"closed_at" db:"closed_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IssueLabel represents a label currently set on an issue
+type IssueLabel struct {
+	ID      string  `json:"id" db:"id"`
+	IssueID string  `json:"issue_id" db:"issue_id"`
+	Name    string  `json:"name" db:"name"`
+	Color   *string `json:"color" db:"color"`
+}
+
+// IssueAssignee represents a person currently assigned to an issue
+type IssueAssignee struct {
+	ID             string `json:"id" db:"id"`
+	IssueID        string `json:"issue_id" db:"issue_id"`
+	GithubPersonID string `json:"github_person_id" db:"github_person_id"`
+}
+
+// IssueEvent represents an issue being closed or reopened
+type IssueEvent struct {
+	ID            string    `json:"id" db:"id"`
+	RepositoryID  string    `json:"repository_id" db:"repository_id"`
+	IssueID       string    `json:"issue_id" db:"issue_id"`
+	GithubEventID int64     `json:"github_event_id" db:"github_event_id"`
+	Event         string    `json:"event" db:"event"`
+	ActorID       *string   `json:"actor_id" db:"actor_id"`
+	OccurredAt    time.Time `json:"occurred_at" db:"occurred_at"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// IssueComment represents a comment on a GitHub issue
+type IssueComment struct {
+	ID              string     `json:"id" db:"id"`
+	RepositoryID    string     `json:"repository_id" db:"repository_id"`
+	IssueID         string     `json:"issue_id" db:"issue_id"`
+	GithubCommentID int64      `json:"github_comment_id" db:"github_comment_id"`
+	AuthorID        int64      `json:"author_id" db:"author_id"`
+	AuthorLogin     string     `json:"author_login" db:"author_login"`
+	Body            *string    `json:"body" db:"body"`
+	HTMLURL         *string    `json:"html_url" db:"html_url"`
+	GithubCreatedAt *time.Time `json:"github_created_at" db:"github_created_at"`
+	GithubUpdatedAt *time.Time `json:"github_updated_at" db:"github_updated_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// IssuePersonStats counts the issues a person opened and closed and the comments they wrote in a period
+type IssuePersonStats struct {
+	GithubPersonID string `json:"github_person_id,omitempty"`
+	Username       string `json:"username"`
+	Opened         int    `json:"opened"`
+	Closed         int    `json:"closed"`
+	Comments       int    `json:"comments"`
+}
+
+// IssueLabelStats summarizes the time to close of the issues with a label closed in a period, in seconds
+type IssueLabelStats struct {
+	Label       string        `json:"label"`
+	Closed      int           `json:"closed"`
+	TimeToClose MetricSummary `json:"time_to_close"`
+}
+
+// IssueFlow counts the bugs opened and closed in a part of a period
+type IssueFlow struct {
+	Period string `json:"period"`
+	Opened int    `json:"opened"`
+	Closed int    `json:"closed"`
+}
+
+// IssueReport holds the issue flow of a project for a report period
+type IssueReport struct {
+	Opened      int                 `json:"opened"`
+	Closed      int                 `json:"closed"`
+	TimeToClose MetricSummary       `json:"time_to_close"`
+	People      []*IssuePersonStats `json:"people"`
+	Labels      []*IssueLabelStats  `json:"labels"`
+
+	// Bugs are issues with a bug label; open bugs are counted at the end of the period
+	BugsOpened int          `json:"bugs_opened"`
+	BugsClosed int          `json:"bugs_closed"`
+	OpenBugs   int          `json:"open_bugs"`
+	BugFlow    []*IssueFlow `json:"bug_flow"`
+}
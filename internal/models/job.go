@@ -13,6 +13,9 @@ const (
 	JobTypeClone       JobType = "clone"
 	JobTypeCommit      JobType = "commit"
 	JobTypePullRequest JobType = "pull_request"
+	JobTypeIssue       JobType = "issue"
+	JobTypeCI          JobType = "ci"
+	JobTypeDeployment  JobType = "deployment"
 	JobTypeStats       JobType = "stats"
 )
 
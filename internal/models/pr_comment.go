@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+)
+
+// PRReviewComment represents an inline comment on the diff of a GitHub pull request
+type PRReviewComment struct {
+	ID                string     `json:"id" db:"id"`
+	RepositoryID      string     `json:"repository_id" db:"repository_id"`
+	PullRequestID     string     `json:"pull_request_id" db:"pull_request_id"`
+	GithubCommentID   int64      `json:"github_comment_id" db:"github_comment_id"`
+	GithubReviewID    *int64     `json:"github_review_id" db:"github_review_id"`
+	InReplyToGithubID *int64     `json:"in_reply_to_github_id" db:"in_reply_to_github_id"` // First comment of the thread, nil for thread starters
+	AuthorID          int64      `json:"author_id" db:"author_id"`
+	AuthorLogin       string     `json:"author_login" db:"author_login"`
+	Body              *string    `json:"body" db:"body"`
+	Path              *string    `json:"path" db:"path"`
+	Line              *int       `json:"line" db:"line"`
+	OriginalLine      *int       `json:"original_line" db:"original_line"`
+	StartLine         *int       `json:"start_line" db:"start_line"`
+	Side              *string    `json:"side" db:"side"`
+	CommitID          *string    `json:"commit_id" db:"commit_id"`
+	HTMLURL           *string    `json:"html_url" db:"html_url"`
+	GithubCreatedAt   *time.Time `json:"github_created_at" db:"github_created_at"`
+	GithubUpdatedAt   *time.Time `json:"github_updated_at" db:"github_updated_at"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// ThreadID returns the GitHub ID of the comment that started the thread
+func (c *PRReviewComment) ThreadID() int64 {
+	if c.InReplyToGithubID != nil {
+		return *c.InReplyToGithubID
+	}
+	return c.GithubCommentID
+}
+
+// IsReply reports whether the comment answers another comment of its thread
+func (c *PRReviewComment) IsReply() bool {
+	return c.InReplyToGithubID != nil
+}
+
+// PRIssueComment represents a comment on the conversation of a GitHub pull request
+type PRIssueComment struct {
+	ID              string     `json:"id" db:"id"`
+	RepositoryID    string     `json:"repository_id" db:"repository_id"`
+	PullRequestID   string     `json:"pull_request_id" db:"pull_request_id"`
+	GithubCommentID int64      `json:"github_comment_id" db:"github_comment_id"`
+	AuthorID        int64      `json:"author_id" db:"author_id"`
+	AuthorLogin     string     `json:"author_login" db:"author_login"`
+	Body            *string    `json:"body" db:"body"`
+	HTMLURL         *string    `json:"html_url" db:"html_url"`
+	GithubCreatedAt *time.Time `json:"github_created_at" db:"github_created_at"`
+	GithubUpdatedAt *time.Time `json:"github_updated_at" db:"github_updated_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+)
+
+// Pull request draft event types
+const (
+	PRDraftEventReadyForReview = "ready_for_review"
+	PRDraftEventConvertToDraft = "convert_to_draft"
+)
+
+// PRDraftEvent represents a pull request being marked ready for review or converted back to a draft
+type PRDraftEvent struct {
+	ID            string    `json:"id" db:"id"`
+	RepositoryID  string    `json:"repository_id" db:"repository_id"`
+	PullRequestID string    `json:"pull_request_id" db:"pull_request_id"`
+	EventType     string    `json:"event_type" db:"event_type"`
+	OccurredAt    time.Time `json:"occurred_at" db:"occurred_at"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// PRCycleMetrics represents the lifecycle metrics derived for a pull request.
+// Durations are in seconds, exclude the time spent as draft and are nil until the pull request gets there.
+type PRCycleMetrics struct {
+	ID                string     `json:"id" db:"id"`
+	PullRequestID     string     `json:"pull_request_id" db:"pull_request_id"`
+	RepositoryID      string     `json:"repository_id" db:"repository_id"`
+	AuthorLogin       string     `json:"author_login" db:"author_login"`
+	OpenedAt          time.Time  `json:"opened_at" db:"opened_at"`
+	FirstReviewAt     *time.Time `json:"first_review_at" db:"first_review_at"`
+	FirstApprovalAt   *time.Time `json:"first_approval_at" db:"first_approval_at"`
+	LastApprovalAt    *time.Time `json:"last_approval_at" db:"last_approval_at"`
+	MergedAt          *time.Time `json:"merged_at" db:"merged_at"`
+	ClosedAt          *time.Time `json:"closed_at" db:"closed_at"`
+	ReviewRounds      int        `json:"review_rounds" db:"review_rounds"`
+	DraftSeconds      int64      `json:"draft_seconds" db:"draft_seconds"`
+	TimeToFirstReview *int64     `json:"time_to_first_review" db:"time_to_first_review"`
+	TimeToApproval    *int64     `json:"time_to_approval" db:"time_to_approval"`
+	ApprovalToMerge   *int64     `json:"approval_to_merge" db:"approval_to_merge"`
+	CycleTime         *int64     `json:"cycle_time" db:"cycle_time"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+	Additions         *int       `json:"additions" db:"additions"`
+	Deletions         *int       `json:"deletions" db:"deletions"`
+	ChangedFiles      *int       `json:"changed_files" db:"changed_files"`
+	CommitCount       *int       `json:"commit_count" db:"commit_count"`
+}
+
+// LinesChanged returns the number of added and deleted lines, or nil when the size is unknown
+func (m *PRCycleMetrics) LinesChanged() *int {
+	if m.Additions == nil || m.Deletions == nil {
+		return nil
+	}
+	lines := *m.Additions + *m.Deletions
+	return &lines
+}
+
+// MetricSummary summarizes the values of a metric over a set of pull requests
+type MetricSummary struct {
+	Count  int     `json:"count"`
+	Median float64 `json:"median"`
+	P90    float64 `json:"p90"`
+}
+
+// PRCycleStats aggregates the cycle-time metrics of a project, a person or a repository
+type PRCycleStats struct {
+	Name              string        `json:"name"`
+	GitHubPersonID    string        `json:"github_person_id,omitempty"`
+	RepositoryID      string        `json:"repository_id,omitempty"`
+	TimeToFirstReview MetricSummary `json:"time_to_first_review"`
+	TimeToApproval    MetricSummary `json:"time_to_approval"`
+	ApprovalToMerge   MetricSummary `json:"approval_to_merge"`
+	CycleTime         MetricSummary `json:"cycle_time"`
+	ReviewRounds      MetricSummary `json:"review_rounds"`
+	Size              MetricSummary `json:"size"`
+}
+
+// PRSizeBucket groups the pull requests opened in a report period by lines changed
+type PRSizeBucket struct {
+	Label             string        `json:"label"`
+	PullRequests      int           `json:"pull_requests"`
+	TimeToFirstReview MetricSummary `json:"time_to_first_review"`
+	CycleTime         MetricSummary `json:"cycle_time"`
+}
+
+// PRCycleReport holds the cycle-time aggregates of a project for a report period
+type PRCycleReport struct {
+	Project      *PRCycleStats   `json:"project"`
+	People       []*PRCycleStats `json:"people"`
+	Repositories []*PRCycleStats `json:"repositories"`
+	SizeBuckets  []*PRSizeBucket `json:"size_buckets"`
+	// Rank correlation of size with review latency and cycle time, nil with too few pull requests
+	SizeReviewCorrelation    *float64 `json:"size_review_correlation"`
+	SizeCycleTimeCorrelation *float64 `json:"size_cycle_time_correlation"`
+}
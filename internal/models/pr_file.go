@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// PRFile represents a file changed by a GitHub pull request
+type PRFile struct {
+	ID               string    `json:"id" db:"id"`
+	RepositoryID     string    `json:"repository_id" db:"repository_id"`
+	PullRequestID    string    `json:"pull_request_id" db:"pull_request_id"`
+	Filename         string    `json:"filename" db:"filename"`
+	PreviousFilename *string   `json:"previous_filename" db:"previous_filename"`
+	Status           string    `json:"status" db:"status"`
+	Additions        int       `json:"additions" db:"additions"`
+	Deletions        int       `json:"deletions" db:"deletions"`
+	Changes          int       `json:"changes" db:"changes"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+// PRCommit represents a commit belonging to a GitHub pull request
+type PRCommit struct {
+	ID            string     `json:"id" db:"id"`
+	RepositoryID  string     `json:"repository_id" db:"repository_id"`
+	PullRequestID string     `json:"pull_request_id" db:"pull_request_id"`
+	CommitSHA     string     `json:"commit_sha" db:"commit_sha"`
+	AuthorLogin   *string    `json:"author_login" db:"author_login"`
+	AuthoredAt    *time.Time `json:"authored_at" db:"authored_at"`
+	CommitID      *string    `json:"commit_id"` // Analyzed commit with the same SHA, nil until the repository is analyzed
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+}
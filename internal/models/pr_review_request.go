@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// PRRequestedReviewer represents a person asked to review a pull request
+type PRRequestedReviewer struct {
+	ID             string    `json:"id" db:"id"`
+	RepositoryID   string    `json:"repository_id" db:"repository_id"`
+	PullRequestID  string    `json:"pull_request_id" db:"pull_request_id"`
+	GithubPersonID string    `json:"github_person_id" db:"github_person_id"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// PRRequestedTeam represents a team asked to review a pull request
+type PRRequestedTeam struct {
+	ID            string    `json:"id" db:"id"`
+	RepositoryID  string    `json:"repository_id" db:"repository_id"`
+	PullRequestID string    `json:"pull_request_id" db:"pull_request_id"`
+	GithubTeamID  string    `json:"github_team_id" db:"github_team_id"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
@@ -7,13 +7,17 @@ import (
 )
 
 type Project struct {
-	ID          uuid.UUID  `json:"id"`
-	Name        string     `json:"name"`
-	OwnerID     uuid.UUID  `json:"owner_id"`
-	Description string     `json:"description"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+	ID              uuid.UUID  `json:"id"`
+	Name            string     `json:"name"`
+	OwnerID         uuid.UUID  `json:"owner_id"`
+	Description     string     `json:"description"`
+	GitHubIngestion string     `json:"github_ingestion"`
+	SyncIssues      bool       `json:"sync_issues"`      // Issue jobs run with updates
+	SyncCI          bool       `json:"sync_ci"`          // CI jobs run with updates
+	SyncDeployments bool       `json:"sync_deployments"` // Deployment jobs run with updates
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
 }
 
 func (p *Project) Validate() error {
@@ -23,6 +27,42 @@ func (p *Project) Validate() error {
 	return nil
 }
 
+// Pull request ingestion modes
+const (
+	GitHubIngestionREST    = "rest"
+	GitHubIngestionGraphQL = "graphql"
+)
+
+// IsValidGitHubIngestion reports whether mode is a known ingestion mode
+func IsValidGitHubIngestion(mode string) bool {
+	return mode == GitHubIngestionREST || mode == GitHubIngestionGraphQL
+}
+
+// SyncsJobType tells whether jobs of a type are queued for the repositories of the project. Issues, CI
+// runs and deployments can be turned off; the other jobs always run.
+func (p *Project) SyncsJobType(jobType JobType) bool {
+	switch jobType {
+	case JobTypeIssue:
+		return p.SyncIssues
+	case JobTypeCI:
+		return p.SyncCI
+	case JobTypeDeployment:
+		return p.SyncDeployments
+	}
+	return true
+}
+
+// SyncedJobTypes keeps the job types the project syncs, in order
+func (p *Project) SyncedJobTypes(jobTypes ...JobType) []JobType {
+	var synced []JobType
+	for _, jobType := range jobTypes {
+		if p.SyncsJobType(jobType) {
+			synced = append(synced, jobType)
+		}
+	}
+	return synced
+}
+
 // Common errors
 var (
 	ErrProjectNameRequired = &ValidationError{Field: "name", Message: "Project name is required"}
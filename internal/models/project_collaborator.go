@@ -8,11 +8,12 @@ import (
 
 // ProjectCollaborator represents a user's collaboration on a project
 type ProjectCollaborator struct {
-	ID        string    `json:"id"`
-	ProjectID string    `json:"project_id"`
-	UserID    string    `json:"user_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID               string    `json:"id"`
+	ProjectID        string    `json:"project_id"`
+	UserID           string    `json:"user_id"`
+	ShareGitHubToken bool      `json:"share_github_token"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 // NewProjectCollaborator creates a new ProjectCollaborator with a generated UUID
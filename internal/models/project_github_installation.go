@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectGitHubInstallation maps a project to the GitHub App installation used for its background jobs
+type ProjectGitHubInstallation struct {
+	ID             string     `json:"id"`
+	ProjectID      string     `json:"project_id"`
+	InstallationID int64      `json:"installation_id"`
+	AccountLogin   *string    `json:"account_login"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+}
+
+// NewProjectGitHubInstallation creates a new ProjectGitHubInstallation with a generated ID
+func NewProjectGitHubInstallation(projectID string, installationID int64, accountLogin *string) *ProjectGitHubInstallation {
+	return &ProjectGitHubInstallation{
+		ID:             uuid.New().String(),
+		ProjectID:      projectID,
+		InstallationID: installationID,
+		AccountLogin:   accountLogin,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+}
+
+// Validate validates the ProjectGitHubInstallation fields
+func (i *ProjectGitHubInstallation) Validate() error {
+	if i.ProjectID == "" {
+		return &ValidationError{Field: "project_id", Message: "Project ID is required"}
+	}
+	if i.InstallationID <= 0 {
+		return &ValidationError{Field: "installation_id", Message: "Installation ID must be a positive number"}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -18,6 +19,9 @@ type ProjectRepository struct {
 	CreatedAt    time.Time  `json:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at"`
 	DeletedAt    *time.Time `json:"deleted_at"`
+
+	// Names of the repository groups the repository is in, sorted
+	Groups []string `json:"groups"`
 }
 
 // NewProjectRepository creates a new ProjectRepository with a generated UUID
@@ -30,3 +34,13 @@ func NewProjectRepository(projectID, githubRepoID string) *ProjectRepository {
 		IsTracked:    false,
 	}
 }
+
+// InGroup tells whether the repository is in a repository group. Group names are case insensitive.
+func (pr *ProjectRepository) InGroup(name string) bool {
+	for _, group := range pr.Groups {
+		if strings.EqualFold(group, name) {
+			return true
+		}
+	}
+	return false
+}
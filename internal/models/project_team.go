@@ -0,0 +1,179 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectTeam is a team a project's reports can be filtered and rolled up by. It is either synced from
+// a GitHub team or defined in the project.
+type ProjectTeam struct {
+	ID           string    `json:"id" db:"id"`
+	ProjectID    string    `json:"project_id" db:"project_id"`
+	GithubTeamID *string   `json:"github_team_id" db:"github_team_id"` // Nil for teams defined in the project
+	Name         string    `json:"name" db:"name"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+
+	// Loaded from the GitHub team
+	Organization *string `json:"organization,omitempty"`
+	Slug         *string `json:"slug,omitempty"`
+	HTMLURL      *string `json:"html_url,omitempty"`
+
+	// Memberships of the team. Members of GitHub teams have no dates.
+	Members []*ProjectTeamMember `json:"members"`
+}
+
+// NewProjectTeam creates a project team
+func NewProjectTeam(projectID, name string) *ProjectTeam {
+	return &ProjectTeam{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		Name:      name,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Validate validates the ProjectTeam fields
+func (t *ProjectTeam) Validate() error {
+	if t.ProjectID == "" {
+		return &ValidationError{Field: "project_id", Message: "Project ID is required"}
+	}
+	if strings.TrimSpace(t.Name) == "" {
+		return &ValidationError{Field: "name", Message: "Team name is required"}
+	}
+	if len(t.Name) > 100 {
+		return &ValidationError{Field: "name", Message: "Team name must be at most 100 characters"}
+	}
+	return nil
+}
+
+// IsManual tells whether the team is defined in the project rather than synced from GitHub
+func (t *ProjectTeam) IsManual() bool {
+	return t.GithubTeamID == nil
+}
+
+// HasMember tells whether a GitHub person is a member of the team at any time
+func (t *ProjectTeam) HasMember(githubPersonID string) bool {
+	for _, member := range t.Members {
+		if member.GithubPersonID == githubPersonID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMemberOn tells whether a GitHub person is a member of the team on a day
+func (t *ProjectTeam) IsMemberOn(githubPersonID string, date time.Time) bool {
+	for _, member := range t.Members {
+		if member.GithubPersonID == githubPersonID && member.ActiveOn(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentMemberIDs returns the GitHub person IDs of today's members
+func (t *ProjectTeam) CurrentMemberIDs() []string {
+	today := time.Now().UTC()
+	seen := make(map[string]bool)
+	var ids []string
+	for _, member := range t.Members {
+		if member.ActiveOn(today) && !seen[member.GithubPersonID] {
+			seen[member.GithubPersonID] = true
+			ids = append(ids, member.GithubPersonID)
+		}
+	}
+	return ids
+}
+
+// ProjectTeamMember is a stint of a GitHub person in a team, both dates included
+type ProjectTeamMember struct {
+	ID             string     `json:"id" db:"id"`
+	ProjectTeamID  string     `json:"project_team_id" db:"project_team_id"`
+	GithubPersonID string     `json:"github_person_id" db:"github_person_id"`
+	StartDate      *time.Time `json:"start_date" db:"start_date"` // Nil when the person was always a member
+	EndDate        *time.Time `json:"end_date" db:"end_date"`     // Nil while the person is still a member
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// NewProjectTeamMember creates a membership of a team
+func NewProjectTeamMember(projectTeamID, githubPersonID string, startDate, endDate *time.Time) *ProjectTeamMember {
+	return &ProjectTeamMember{
+		ID:             uuid.New().String(),
+		ProjectTeamID:  projectTeamID,
+		GithubPersonID: githubPersonID,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+}
+
+// Validate validates the ProjectTeamMember fields
+func (m *ProjectTeamMember) Validate() error {
+	if m.ProjectTeamID == "" {
+		return &ValidationError{Field: "project_team_id", Message: "Team ID is required"}
+	}
+	if m.GithubPersonID == "" {
+		return &ValidationError{Field: "github_person_id", Message: "Person is required"}
+	}
+	if m.StartDate != nil && m.EndDate != nil && m.EndDate.Before(*m.StartDate) {
+		return &ValidationError{Field: "end_date", Message: "End date must not be before the start date"}
+	}
+	return nil
+}
+
+// ActiveOn tells whether the membership covers a day
+func (m *ProjectTeamMember) ActiveOn(date time.Time) bool {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if m.StartDate != nil && day.Before(truncateDay(*m.StartDate)) {
+		return false
+	}
+	if m.EndDate != nil && day.After(truncateDay(*m.EndDate)) {
+		return false
+	}
+	return true
+}
+
+// Overlaps tells whether the membership covers any day of [start, end). Zero bounds are open.
+func (m *ProjectTeamMember) Overlaps(start, end time.Time) bool {
+	if !end.IsZero() && m.StartDate != nil && !truncateDay(*m.StartDate).Before(end) {
+		return false
+	}
+	if !start.IsZero() && m.EndDate != nil && truncateDay(*m.EndDate).Before(truncateDay(start)) {
+		return false
+	}
+	return true
+}
+
+// truncateDay returns the UTC midnight of a day
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// TeamStats is the rollup of the people statistics of a team's members
+type TeamStats struct {
+	Team              *ProjectTeam `json:"team"`
+	Members           int          `json:"members"`        // Members at any time in the period
+	ActiveMembers     int          `json:"active_members"` // Members with activity while in the team
+	TotalCommits      int          `json:"total_commits"`
+	TotalAdditions    int          `json:"total_additions"`
+	TotalDeletions    int          `json:"total_deletions"`
+	TotalComments     int          `json:"total_comments"`
+	TotalPullRequests int          `json:"total_pull_requests"`
+	TotalScore        int          `json:"total_score"`
+	AverageScore      float64      `json:"average_score"` // Per active member
+}
+
+// TeamReport holds the teams of a project, the team selected as filter and the team rollups of a
+// report period
+type TeamReport struct {
+	Teams        []*ProjectTeam `json:"teams"`
+	SelectedTeam *ProjectTeam   `json:"selected_team"`
+	TeamStats    []*TeamStats   `json:"team_stats"`
+}
@@ -15,6 +15,9 @@ type ProjectUpdateSettings struct {
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	// Repository group scheduled updates are limited to, nil for all tracked repositories
+	RepositoryGroup *string `json:"repository_group,omitempty"`
 }
 
 // NewProjectUpdateSettings creates a new ProjectUpdateSettings with a generated ID
@@ -39,3 +42,11 @@ func (pus *ProjectUpdateSettings) Validate() error {
 	}
 	return nil
 }
+
+// RepositoryGroupName returns the repository group scheduled updates are limited to, empty for all
+func (pus *ProjectUpdateSettings) RepositoryGroupName() string {
+	if pus.RepositoryGroup == nil {
+		return ""
+	}
+	return *pus.RepositoryGroup
+}
@@ -16,12 +16,28 @@ type PullRequest struct {
 	MergedAt           *time.Time `json:"merged_at" db:"merged_at"`
 	MergeCommitSHA     *string    `json:"merge_commit_sha" db:"merge_commit_sha"`
 	ClosedAt           *time.Time `json:"closed_at" db:"closed_at"`
-	User               *string    `json:"user" db:"user"`                               // JSON object with user information
-	RequestedReviewers *string    `json:"requested_reviewers" db:"requested_reviewers"` // JSON array of reviewer objects
-	RequestedTeams     *string    `json:"requested_teams" db:"requested_teams"`         // JSON array of team objects
+	User               *string    `json:"user" db:"user"`                               // JSON object with user information, as received
+	RequestedReviewers *string    `json:"requested_reviewers" db:"requested_reviewers"` // JSON array of reviewer objects, as received
+	RequestedTeams     *string    `json:"requested_teams" db:"requested_teams"`         // JSON array of team objects, as received
 	Draft              bool       `json:"draft" db:"draft"`
 	GithubCreatedAt    *time.Time `json:"github_created_at" db:"github_created_at"`
 	GithubUpdatedAt    *time.Time `json:"github_updated_at" db:"github_updated_at"`
 	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+	Additions          *int       `json:"additions" db:"additions"`
+	Deletions          *int       `json:"deletions" db:"deletions"`
+	ChangedFiles       *int       `json:"changed_files" db:"changed_files"`
+	CommitCount        *int       `json:"commit_count" db:"commit_count"`
+	AuthorID           *string    `json:"author_id" db:"author_id"`                 // GitHub person who opened the pull request
+	MergedByID         *string    `json:"merged_by_id" db:"merged_by_id"`           // GitHub person who merged the pull request
+	HeadSHA            *string    `json:"head_sha" db:"head_sha"`                   // Latest commit of the pull request branch
+	DetailsFailedAt    *time.Time `json:"details_failed_at" db:"details_failed_at"` // Last failed attempt to fetch the size of a pull request stored without it
+}
+
+// PRLabel represents a label currently on a pull request
+type PRLabel struct {
+	ID            string  `json:"id" db:"id"`
+	PullRequestID string  `json:"pull_request_id" db:"pull_request_id"`
+	Name          string  `json:"name" db:"name"`
+	Color         *string `json:"color" db:"color"`
 }
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Discovery rule actions
+const (
+	DiscoveryRuleInclude = "include"
+	DiscoveryRuleExclude = "exclude"
+)
+
+// Discovery rule fields
+const (
+	DiscoveryFieldName     = "name"
+	DiscoveryFieldTopic    = "topic"
+	DiscoveryFieldLanguage = "language"
+	DiscoveryFieldArchived = "archived"
+	DiscoveryFieldFork     = "fork"
+)
+
+// RepositoryDiscoverySource binds a project to a GitHub organization or one of its teams
+type RepositoryDiscoverySource struct {
+	ID           string     `json:"id"`
+	ProjectID    string     `json:"project_id"`
+	Organization string     `json:"organization"`
+	TeamSlug     *string    `json:"team_slug"`
+	AutoTrack    bool       `json:"auto_track"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+}
+
+// NewRepositoryDiscoverySource creates a new RepositoryDiscoverySource with a generated ID
+func NewRepositoryDiscoverySource(projectID, organization string, teamSlug *string, autoTrack bool) *RepositoryDiscoverySource {
+	return &RepositoryDiscoverySource{
+		ID:           uuid.New().String(),
+		ProjectID:    projectID,
+		Organization: organization,
+		TeamSlug:     teamSlug,
+		AutoTrack:    autoTrack,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+}
+
+// Validate validates the RepositoryDiscoverySource fields
+func (s *RepositoryDiscoverySource) Validate() error {
+	if s.ProjectID == "" {
+		return &ValidationError{Field: "project_id", Message: "Project ID is required"}
+	}
+	if s.Organization == "" {
+		return &ValidationError{Field: "organization", Message: "Organization is required"}
+	}
+	return nil
+}
+
+// RepositoryDiscoveryRule is an include or exclude filter applied to discovered repositories
+type RepositoryDiscoveryRule struct {
+	ID        string     `json:"id"`
+	ProjectID string     `json:"project_id"`
+	Action    string     `json:"action"`
+	Field     string     `json:"field"`
+	Value     string     `json:"value"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// NewRepositoryDiscoveryRule creates a new RepositoryDiscoveryRule with a generated ID
+func NewRepositoryDiscoveryRule(projectID, action, field, value string) *RepositoryDiscoveryRule {
+	return &RepositoryDiscoveryRule{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		Action:    action,
+		Field:     field,
+		Value:     value,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Validate validates the RepositoryDiscoveryRule fields
+func (r *RepositoryDiscoveryRule) Validate() error {
+	if r.ProjectID == "" {
+		return &ValidationError{Field: "project_id", Message: "Project ID is required"}
+	}
+	if r.Action != DiscoveryRuleInclude && r.Action != DiscoveryRuleExclude {
+		return &ValidationError{Field: "action", Message: "Action must be include or exclude"}
+	}
+	if r.Value == "" {
+		return &ValidationError{Field: "value", Message: "Value is required"}
+	}
+
+	switch r.Field {
+	case DiscoveryFieldName:
+		if _, err := regexp.Compile(r.Value); err != nil {
+			return &ValidationError{Field: "value", Message: "Invalid name regex: " + err.Error()}
+		}
+	case DiscoveryFieldTopic, DiscoveryFieldLanguage:
+	case DiscoveryFieldArchived, DiscoveryFieldFork:
+		if r.Value != "true" && r.Value != "false" {
+			return &ValidationError{Field: "value", Message: "Value must be true or false"}
+		}
+	default:
+		return &ValidationError{Field: "field", Message: "Field must be one of name, topic, language, archived, fork"}
+	}
+	return nil
+}
+
+// Matches reports whether the repository satisfies the rule condition
+func (r *RepositoryDiscoveryRule) Matches(repo *GitHubRepository) bool {
+	switch r.Field {
+	case DiscoveryFieldName:
+		re, err := regexp.Compile(r.Value)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(repo.Name)
+	case DiscoveryFieldTopic:
+		for _, topic := range repo.Topics {
+			if strings.EqualFold(topic, r.Value) {
+				return true
+			}
+		}
+		return false
+	case DiscoveryFieldLanguage:
+		return repo.Language != nil && strings.EqualFold(*repo.Language, r.Value)
+	case DiscoveryFieldArchived:
+		return repo.IsArchived == (r.Value == "true")
+	case DiscoveryFieldFork:
+		return repo.IsFork == (r.Value == "true")
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package models
+
+// RepositoryFilter narrows reports down to the repositories of a group, to a single repository or to
+// both. A nil filter includes every repository.
+type RepositoryFilter struct {
+	Group        string `json:"group,omitempty"`
+	RepositoryID string `json:"repository_id,omitempty"` // Project repository ID
+	Label        string `json:"label"`                   // Shown in report headings
+
+	projectRepositoryIDs map[string]bool
+	githubRepositoryIDs  map[string]bool
+}
+
+// NewRepositoryFilter creates a filter of the repositories of a project that are in a group and have an
+// ID, either of which may be empty
+func NewRepositoryFilter(group, repositoryID string, repos []*ProjectRepository) *RepositoryFilter {
+	filter := &RepositoryFilter{
+		Group:                group,
+		RepositoryID:         repositoryID,
+		projectRepositoryIDs: make(map[string]bool),
+		githubRepositoryIDs:  make(map[string]bool),
+	}
+	for _, repo := range repos {
+		if group != "" && !repo.InGroup(group) {
+			continue
+		}
+		if repositoryID != "" && repo.ID != repositoryID {
+			continue
+		}
+		filter.projectRepositoryIDs[repo.ID] = true
+		filter.githubRepositoryIDs[repo.GithubRepoID] = true
+	}
+	return filter
+}
+
+// IncludesRepository tells whether the filter includes a project repository, as referenced by people statistics
+func (f *RepositoryFilter) IncludesRepository(projectRepositoryID string) bool {
+	return f == nil || f.projectRepositoryIDs[projectRepositoryID]
+}
+
+// IncludesGithubRepository tells whether the filter includes a GitHub repository, as referenced by
+// commits, pull requests, issues, CI runs and deployments
+func (f *RepositoryFilter) IncludesGithubRepository(githubRepositoryID string) bool {
+	return f == nil || f.githubRepositoryIDs[githubRepositoryID]
+}
+
+// RepositoryOption is a repository reports can be filtered by
+type RepositoryOption struct {
+	ID     string   `json:"id"` // Project repository ID
+	Name   string   `json:"name"`
+	Groups []string `json:"groups"`
+}
+
+// RepositoryFilterOptions holds the repository groups and repositories of a project reports can be
+// filtered by, and the selected filter, nil when reports cover every repository
+type RepositoryFilterOptions struct {
+	Groups       []string            `json:"groups"`
+	Repositories []*RepositoryOption `json:"repositories"`
+	Filter       *RepositoryFilter   `json:"filter"`
+}
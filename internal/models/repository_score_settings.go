@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxScoreMultiplier is the largest multiplier a repository's scores can be weighted with
+const MaxScoreMultiplier = 100
+
+// RepositoryScoreSettings weights the scores of a project repository: every daily score is multiplied
+// by Multiplier and, when OverrideWeights is set, computed with the weights below instead of the
+// project's score settings
+type RepositoryScoreSettings struct {
+	ID                  string    `json:"id"`
+	ProjectRepositoryID string    `json:"project_repository_id"`
+	Multiplier          float64   `json:"multiplier"`
+	OverrideWeights     bool      `json:"override_weights"`
+	Additions           int       `json:"additions"`
+	Deletions           int       `json:"deletions"`
+	Commits             int       `json:"commits"`
+	PullRequests        int       `json:"pull_requests"`
+	Comments            int       `json:"comments"`
+	ReviewComments      int       `json:"review_comments"`
+	IssueComments       int       `json:"issue_comments"`
+	IssuesOpened        int       `json:"issues_opened"`
+	IssuesClosed        int       `json:"issues_closed"`
+	RepositoryName      string    `json:"repository_name"` // Filled in for display, not stored
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// NewRepositoryScoreSettings creates repository score settings that leave scores unchanged, starting
+// from the weights of the project
+func NewRepositoryScoreSettings(projectRepositoryID string, project *ScoreSettings) *RepositoryScoreSettings {
+	settings := &RepositoryScoreSettings{
+		ID:                  uuid.New().String(),
+		ProjectRepositoryID: projectRepositoryID,
+		Multiplier:          1,
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+	if project != nil {
+		settings.Additions = project.Additions
+		settings.Deletions = project.Deletions
+		settings.Commits = project.Commits
+		settings.PullRequests = project.PullRequests
+		settings.Comments = project.Comments
+		settings.ReviewComments = project.ReviewComments
+		settings.IssueComments = project.IssueComments
+		settings.IssuesOpened = project.IssuesOpened
+		settings.IssuesClosed = project.IssuesClosed
+	}
+	return settings
+}
+
+// Validate validates the RepositoryScoreSettings fields
+func (s *RepositoryScoreSettings) Validate() error {
+	if s.ProjectRepositoryID == "" {
+		return &ValidationError{Field: "project_repository_id", Message: "Repository is required"}
+	}
+	if s.Multiplier < 0 || s.Multiplier > MaxScoreMultiplier {
+		return &ValidationError{Field: "multiplier", Message: "Score multiplier must be between 0 and 100"}
+	}
+	for _, weight := range []int{
+		s.Additions, s.Deletions, s.Commits, s.PullRequests, s.Comments,
+		s.ReviewComments, s.IssueComments, s.IssuesOpened, s.IssuesClosed,
+	} {
+		if weight < 0 {
+			return &ValidationError{Field: "weights", Message: "Score weights can't be negative"}
+		}
+	}
+	return nil
+}
+
+// Weights returns the score settings the repository's scores are computed with: its own weights when
+// they override the project's, otherwise the project's
+func (s *RepositoryScoreSettings) Weights(project *ScoreSettings) *ScoreSettings {
+	if s == nil || !s.OverrideWeights {
+		return project
+	}
+	weights := *project
+	weights.Additions = s.Additions
+	weights.Deletions = s.Deletions
+	weights.Commits = s.Commits
+	weights.PullRequests = s.PullRequests
+	weights.Comments = s.Comments
+	weights.ReviewComments = s.ReviewComments
+	weights.IssueComments = s.IssueComments
+	weights.IssuesOpened = s.IssuesOpened
+	weights.IssuesClosed = s.IssuesClosed
+	return &weights
+}
+
+// ScoreMultiplier returns the multiplier of the repository's scores, 1 when there are no settings
+func (s *RepositoryScoreSettings) ScoreMultiplier() float64 {
+	if s == nil {
+		return 1
+	}
+	return s.Multiplier
+}
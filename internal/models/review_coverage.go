@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// CommitPullRequestLink tells whether a commit of the default branch came in through a pull request
+type CommitPullRequestLink struct {
+	RepositoryID string    `json:"repository_id"`
+	CommitSHA    string    `json:"commit_sha"`
+	CommitDate   time.Time `json:"commit_date"`
+	// Linked commits are the merge commit of a pull request or one of its commits
+	Linked bool `json:"linked"`
+}
+
+// ReviewCoverageStats counts how much code reached the default branch without review
+type ReviewCoverageStats struct {
+	RepositoryID string `json:"repository_id,omitempty"`
+	Name         string `json:"name"`
+
+	MergedPullRequests int `json:"merged_pull_requests"`
+	Unapproved         int `json:"unapproved"` // Merged without an approval submitted before the merge
+	// Merged pull requests whose merger is known, pull requests fetched before mergers were stored aren't
+	MergerKnown int `json:"merger_known"`
+	SelfMerged  int `json:"self_merged"` // Merged by their own author
+
+	Commits       int `json:"commits"`        // Commits of the default branch
+	DirectCommits int `json:"direct_commits"` // Commits of the default branch that aren't part of any pull request
+
+	UnapprovedRate   *float64 `json:"unapproved_rate"`
+	SelfMergedRate   *float64 `json:"self_merged_rate"`
+	DirectCommitRate *float64 `json:"direct_commit_rate"`
+}
+
+// ReviewCoverageReport is the review coverage of a project for a period, in total and per repository
+type ReviewCoverageReport struct {
+	Project      *ReviewCoverageStats   `json:"project"`
+	Repositories []*ReviewCoverageStats `json:"repositories"`
+}
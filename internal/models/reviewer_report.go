@@ -0,0 +1,28 @@
+package models
+
+// ReviewerStats breaks down the reviews of a person by outcome and compares them with the reviews they were asked for
+type ReviewerStats struct {
+	GithubPersonID     string   `json:"github_person_id"`
+	Username           string   `json:"username"`
+	Approved           int      `json:"approved"`
+	ChangesRequested   int      `json:"changes_requested"`
+	Commented          int      `json:"commented"`
+	Dismissed          int      `json:"dismissed"`
+	Reviews            int      `json:"reviews"`
+	PullRequests       int      `json:"pull_requests"`       // Pull requests of others reviewed in the period
+	Requested          int      `json:"requested"`           // Review requests on pull requests opened in the period
+	RequestedCompleted int      `json:"requested_completed"` // Of those, pull requests the person reviewed
+	CompletionRate     *float64 `json:"completion_rate"`     // Completed share of the requests, nil without requests
+	OpenRequests       int      `json:"open_requests"`       // Open pull requests waiting for the person's review
+	Share              float64  `json:"share"`               // Share of all reviewed pull requests of the period
+}
+
+// ReviewerReport holds the review outcomes and review load of a project for a report period
+type ReviewerReport struct {
+	Reviewers    []*ReviewerStats `json:"reviewers"`
+	PullRequests int              `json:"pull_requests"`
+	// Gini coefficient of reviewed pull requests across reviewers and the project's people who reviewed
+	// none, bots left out, 0 when the load is even and close to 1 when one person does all the reviews.
+	// Nil with fewer than two people.
+	Gini *float64 `json:"gini"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScoreFormula is a version of a project's score formula, an expression computing the score of what a
+// person did in a repository on a day. Saving a formula adds a version; at most one version of a project
+// is active, and projects without an active formula are scored with the weights of their score settings.
+// The score settings versions record over which days each formula scored.
+type ScoreFormula struct {
+	ID            string    `json:"id"`
+	ProjectID     string    `json:"project_id"`
+	Version       int       `json:"version"`
+	Expression    string    `json:"expression"`
+	Note          string    `json:"note"`
+	IsActive      bool      `json:"is_active"`
+	CreatedBy     *string   `json:"created_by"`      // User who saved the version
+	CreatedByName string    `json:"created_by_name"` // Filled in for display, not stored
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewScoreFormula creates an inactive score formula; its version is assigned when it is stored
+func NewScoreFormula(projectID, expression, note string, createdBy *string) *ScoreFormula {
+	return &ScoreFormula{
+		ID:         uuid.New().String(),
+		ProjectID:  projectID,
+		Expression: expression,
+		Note:       note,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// ScoreFormulaTerm documents a variable or function score formulas can use
+type ScoreFormulaTerm struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ScoreFormulaVariables are the variables of score formulas, counted per person, repository and day
+var ScoreFormulaVariables = []ScoreFormulaTerm{
+	{"commits", "Commits, without reverts and excluded files"},
+	{"additions", "Lines added by those commits"},
+	{"deletions", "Lines deleted by those commits"},
+	{"co_authored_commits", "Commits by others naming the person in a Co-authored-by trailer"},
+	{"pull_requests", "Pull requests opened"},
+	{"merged_pull_requests", "Pull requests of the person merged"},
+	{"pr_cycle_hours", "Average hours from opening to merge of those merged pull requests"},
+	{"reviews", "Reviews submitted, in any state"},
+	{"approvals", "Approving reviews"},
+	{"changes_requested", "Reviews requesting changes"},
+	{"commented_reviews", "Reviews that only comment"},
+	{"review_comments", "Inline review comments"},
+	{"issue_comments", "Conversation comments on pull requests"},
+	{"issues_opened", "Issues opened"},
+	{"issues_closed", "Issues closed"},
+}
+
+// ScoreFormulaFunctions are the functions of score formulas
+var ScoreFormulaFunctions = []ScoreFormulaTerm{
+	{"log(x)", "Natural logarithm, 0 for x ≤ 0; log(1 + additions) keeps large diffs in check"},
+	{"sqrt(x)", "Square root, 0 for x < 0"},
+	{"min(a, b, ...)", "Smallest argument"},
+	{"max(a, b, ...)", "Largest argument"},
+	{"cap(x, limit)", "x, but at most limit"},
+	{"abs(x)", "Absolute value"},
+	{"round(x)", "x rounded to the nearest integer"},
+	{"floor(x)", "x rounded down"},
+	{"ceil(x)", "x rounded up"},
+}
+
+// ScoreFormulaPreview compares the score of a person over a recent period with their score under a
+// candidate formula
+type ScoreFormulaPreview struct {
+	GitHubPerson *GithubPerson `json:"github_person"`
+	CurrentScore int           `json:"current_score"`
+	FormulaScore int           `json:"formula_score"`
+	CurrentRank  int           `json:"current_rank"`
+	FormulaRank  int           `json:"formula_rank"`
+}
+
+// RankChange returns how many places the person moves up under the formula, negative when moving down
+func (p *ScoreFormulaPreview) RankChange() int {
+	return p.CurrentRank - p.FormulaRank
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScoreSettingsVersion is a version of a project's score settings. Updating the score settings or changing
+// the active score formula adds a version, which scores the days from its effective date on, or all of
+// history when it has none, until a later version takes over. Older days keep being scored with the
+// versions that were in effect then.
+type ScoreSettingsVersion struct {
+	ID                 string     `json:"id"`
+	ProjectID          string     `json:"project_id"`
+	Version            int        `json:"version"`
+	EffectiveFrom      *time.Time `json:"effective_from"` // Nil when the version rescored all of history
+	Additions          int        `json:"additions"`
+	Deletions          int        `json:"deletions"`
+	Commits            int        `json:"commits"`
+	PullRequests       int        `json:"pull_requests"`
+	Comments           int        `json:"comments"`
+	ReviewComments     int        `json:"review_comments"`
+	IssueComments      int        `json:"issue_comments"`
+	IssuesOpened       int        `json:"issues_opened"`
+	IssuesClosed       int        `json:"issues_closed"`
+	ScoreFormulaID     *string    `json:"score_formula_id"`     // Formula scoring the days instead of the weights
+	ChangedBy          *string    `json:"changed_by"`           // User who saved the version
+	RecalculationJobID *string    `json:"recalculation_job_id"` // Stats job that recalculated the project
+	CreatedAt          time.Time  `json:"created_at"`
+
+	// Filled in for display, not stored
+	ChangedByName       string                `json:"changed_by_name"`
+	ScoreFormulaVersion int                   `json:"score_formula_version,omitempty"`
+	RecalculationStatus JobStatus             `json:"recalculation_status,omitempty"`
+	RecalculationError  string                `json:"recalculation_error,omitempty"`
+	Changes             []ScoreSettingsChange `json:"changes,omitempty"`
+}
+
+// ScoreSettingsChange is a weight that a version changed from the one before it
+type ScoreSettingsChange struct {
+	Name string `json:"name"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+}
+
+// NewScoreSettingsVersion creates a version with the weights of score settings; its number is assigned
+// when it is stored
+func NewScoreSettingsVersion(settings *ScoreSettings, effectiveFrom *time.Time, changedBy *string) *ScoreSettingsVersion {
+	return &ScoreSettingsVersion{
+		ID:             uuid.New().String(),
+		ProjectID:      settings.ProjectID,
+		EffectiveFrom:  effectiveFrom,
+		Additions:      settings.Additions,
+		Deletions:      settings.Deletions,
+		Commits:        settings.Commits,
+		PullRequests:   settings.PullRequests,
+		Comments:       settings.Comments,
+		ReviewComments: settings.ReviewComments,
+		IssueComments:  settings.IssueComments,
+		IssuesOpened:   settings.IssuesOpened,
+		IssuesClosed:   settings.IssuesClosed,
+		ChangedBy:      changedBy,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// Settings returns the weights of the version as score settings
+func (v *ScoreSettingsVersion) Settings() *ScoreSettings {
+	return &ScoreSettings{
+		ProjectID:      v.ProjectID,
+		Additions:      v.Additions,
+		Deletions:      v.Deletions,
+		Commits:        v.Commits,
+		PullRequests:   v.PullRequests,
+		Comments:       v.Comments,
+		ReviewComments: v.ReviewComments,
+		IssueComments:  v.IssueComments,
+		IssuesOpened:   v.IssuesOpened,
+		IssuesClosed:   v.IssuesClosed,
+	}
+}
+
+// HasFormula tells whether the version scores its days with a score formula
+func (v *ScoreSettingsVersion) HasFormula() bool {
+	return v.ScoreFormulaID != nil
+}
+
+// AppliesOn tells whether the version covers a day, leaving aside later versions
+func (v *ScoreSettingsVersion) AppliesOn(date time.Time) bool {
+	return v.EffectiveFrom == nil || !truncateDay(date).Before(truncateDay(*v.EffectiveFrom))
+}
+
+// ChangesFrom lists the weights that differ from a previous version
+func (v *ScoreSettingsVersion) ChangesFrom(previous *ScoreSettingsVersion) []ScoreSettingsChange {
+	var changes []ScoreSettingsChange
+	from := previous.weights()
+	for i, weight := range v.weights() {
+		if weight.To != from[i].To {
+			changes = append(changes, ScoreSettingsChange{Name: weight.Name, From: from[i].To, To: weight.To})
+		}
+	}
+	return changes
+}
+
+// weights lists the weights of the version by the names the settings page uses
+func (v *ScoreSettingsVersion) weights() []ScoreSettingsChange {
+	return []ScoreSettingsChange{
+		{Name: "Additions", To: v.Additions},
+		{Name: "Deletions", To: v.Deletions},
+		{Name: "Commits", To: v.Commits},
+		{Name: "Pull Requests", To: v.PullRequests},
+		{Name: "Reviews", To: v.Comments},
+		{Name: "Review Comments", To: v.ReviewComments},
+		{Name: "Conversation Comments", To: v.IssueComments},
+		{Name: "Issues Opened", To: v.IssuesOpened},
+		{Name: "Issues Closed", To: v.IssuesClosed},
+	}
+}
+
+// ScoreSettingsVersionOn returns the version that scores a day: the latest of the versions covering it
+func ScoreSettingsVersionOn(versions []*ScoreSettingsVersion, date time.Time) *ScoreSettingsVersion {
+	var current *ScoreSettingsVersion
+	for _, version := range versions {
+		if version.AppliesOn(date) && (current == nil || version.Version > current.Version) {
+			current = version
+		}
+	}
+	return current
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StalePRSettings holds the thresholds after which an open pull request of a project is reported
+type StalePRSettings struct {
+	ID             string    `json:"id"`
+	ProjectID      string    `json:"project_id"`
+	MaxAgeDays     int       `json:"max_age_days"`     // Open longer than this
+	ReviewSLAHours int       `json:"review_sla_hours"` // Waiting on a first review longer than this, draft time excluded
+	DraftIdleDays  int       `json:"draft_idle_days"`  // Drafts without activity for longer than this
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// NewStalePRSettings creates stale pull request settings with the default thresholds
+func NewStalePRSettings(projectID string) *StalePRSettings {
+	return &StalePRSettings{
+		ID:             uuid.New().String(),
+		ProjectID:      projectID,
+		MaxAgeDays:     14,
+		ReviewSLAHours: 24,
+		DraftIdleDays:  7,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+}
+
+// Validate validates the StalePRSettings fields
+func (s *StalePRSettings) Validate() error {
+	if s.ProjectID == "" {
+		return &ValidationError{Field: "project_id", Message: "Project ID is required"}
+	}
+	if s.MaxAgeDays < 1 {
+		return &ValidationError{Field: "max_age_days", Message: "Maximum age must be at least 1 day"}
+	}
+	if s.ReviewSLAHours < 1 {
+		return &ValidationError{Field: "review_sla_hours", Message: "Review SLA must be at least 1 hour"}
+	}
+	if s.DraftIdleDays < 1 {
+		return &ValidationError{Field: "draft_idle_days", Message: "Draft idle time must be at least 1 day"}
+	}
+	return nil
+}
+
+// StalePullRequest is an open pull request past one of the stale thresholds
+type StalePullRequest struct {
+	PullRequestID string    `json:"pull_request_id"`
+	Repository    string    `json:"repository"`
+	Number        int       `json:"number"`
+	Title         string    `json:"title"`
+	URL           string    `json:"url"`
+	Draft         bool      `json:"draft"`
+	OpenedAt      time.Time `json:"opened_at"`
+	AgeSeconds    int64     `json:"age_seconds"`
+	// Time spent waiting on a first review outside of drafts, nil once reviewed
+	WaitingForReviewSeconds *int64 `json:"waiting_for_review_seconds"`
+	IdleSeconds             int64  `json:"idle_seconds"` // Since the last update on GitHub
+
+	Owner string `json:"owner"` // Author of the pull request
+	// People the pull request is waiting on: the author for drafts, change requests and approved pull
+	// requests, otherwise the requested reviewers who haven't reviewed yet
+	WaitingOn          []string `json:"waiting_on"`
+	ChangesRequestedBy []string `json:"changes_requested_by"`
+
+	Aged             bool `json:"aged"`
+	ReviewOverdue    bool `json:"review_overdue"`
+	IdleDraft        bool `json:"idle_draft"`
+	ChangesRequested bool `json:"changes_requested"`
+}
+
+// StalePRReport lists the stale pull requests of a project
+type StalePRReport struct {
+	Settings         *StalePRSettings    `json:"settings"`
+	GeneratedAt      time.Time           `json:"generated_at"`
+	OpenPullRequests int                 `json:"open_pull_requests"`
+	PullRequests     []*StalePullRequest `json:"pull_requests"`
+}
+
+// AgeDays returns the age in whole days
+func (p *StalePullRequest) AgeDays() int {
+	return int(p.AgeSeconds / 86400)
+}
+
+// IdleDays returns the whole days since the last update
+func (p *StalePullRequest) IdleDays() int {
+	return int(p.IdleSeconds / 86400)
+}
+
+// WaitingForReviewHours returns the whole hours spent waiting on a first review, or 0 once reviewed
+func (p *StalePullRequest) WaitingForReviewHours() int {
+	if p.WaitingForReviewSeconds == nil {
+		return 0
+	}
+	return int(*p.WaitingForReviewSeconds / 3600)
+}
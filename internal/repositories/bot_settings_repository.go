@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/alimgiray/gscope/internal/models"
+)
+
+type BotSettingsRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewBotSettingsRepository(db *sql.DB) *BotSettingsRepository {
+	return &BotSettingsRepository{db: db}
+}
+
+// Create creates new bot settings
+func (r *BotSettingsRepository) Create(settings *models.BotSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		INSERT INTO bot_settings (id, project_id, policy, patterns, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		settings.ID, settings.ProjectID, settings.Policy, settings.Patterns,
+		settings.CreatedAt, settings.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByProjectID retrieves the bot settings of a project
+func (r *BotSettingsRepository) GetByProjectID(projectID string) (*models.BotSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT id, project_id, policy, patterns, created_at, updated_at
+		FROM bot_settings WHERE project_id = ?
+	`
+
+	var settings models.BotSettings
+	err := r.db.QueryRow(query, projectID).Scan(
+		&settings.ID, &settings.ProjectID, &settings.Policy, &settings.Patterns,
+		&settings.CreatedAt, &settings.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// Update updates bot settings
+func (r *BotSettingsRepository) Update(settings *models.BotSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		UPDATE bot_settings
+		SET policy = ?, patterns = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err := r.db.Exec(query,
+		settings.Policy, settings.Patterns, settings.UpdatedAt, settings.ID,
+	)
+
+	return err
+}
+
+// Upsert creates or updates the bot settings of a project
+func (r *BotSettingsRepository) Upsert(settings *models.BotSettings) error {
+	existing, err := r.GetByProjectID(settings.ProjectID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if existing != nil {
+		settings.ID = existing.ID
+		settings.CreatedAt = existing.CreatedAt
+		return r.Update(settings)
+	}
+
+	return r.Create(settings)
+}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/uuid"
+)
+
+type CIRunRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewCIRunRepository(db *sql.DB) *CIRunRepository {
+	return &CIRunRepository{db: db}
+}
+
+const ciRunColumns = `
+	id, repository_id, kind, github_id, attempt, name, head_sha, head_branch, event, status, conclusion,
+	html_url, github_created_at, started_at, completed_at, created_at, updated_at
+`
+
+// Upsert creates a CI run or updates the one with the same kind, GitHub ID and attempt
+func (r *CIRunRepository) Upsert(run *models.CIRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if run.ID == "" {
+		run.ID = uuid.New().String()
+	}
+	if run.Attempt == 0 {
+		run.Attempt = 1
+	}
+	run.CreatedAt = now
+	run.UpdatedAt = now
+
+	query := `
+		INSERT INTO ci_runs (` + ciRunColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(kind, github_id, attempt) DO UPDATE SET
+			name = excluded.name,
+			head_branch = excluded.head_branch,
+			event = excluded.event,
+			status = excluded.status,
+			conclusion = excluded.conclusion,
+			html_url = excluded.html_url,
+			started_at = excluded.started_at,
+			completed_at = excluded.completed_at,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query,
+		run.ID, run.RepositoryID, run.Kind, run.GithubID, run.Attempt, run.Name, run.HeadSHA, run.HeadBranch, run.Event, run.Status, run.Conclusion,
+		run.HTMLURL, run.GithubCreatedAt, run.StartedAt, run.CompletedAt, run.CreatedAt, run.UpdatedAt,
+	)
+
+	return err
+}
+
+// HasAttempt tells whether an attempt of a workflow run is stored
+func (r *CIRunRepository) HasAttempt(githubID int64, attempt int) (bool, error) {
+	query := `SELECT COUNT(*) FROM ci_runs WHERE kind = ? AND github_id = ? AND attempt = ?`
+
+	var count int
+	if err := r.db.QueryRow(query, models.CIRunKindWorkflowRun, githubID, attempt).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetByRepositoryID retrieves all CI runs of a repository
+func (r *CIRunRepository) GetByRepositoryID(repositoryID string) ([]*models.CIRun, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `SELECT ` + ciRunColumns + ` FROM ci_runs WHERE repository_id = ? ORDER BY started_at`
+	return r.query(query, repositoryID)
+}
+
+// GetByProjectID retrieves the CI runs of all repositories of a project
+func (r *CIRunRepository) GetByProjectID(projectID string) ([]*models.CIRun, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT ` + ciRunColumns + `
+		FROM ci_runs
+		WHERE repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+		ORDER BY started_at
+	`
+	return r.query(query, projectID)
+}
+
+// GetLatestWorkflowRunCreatedAt returns when the newest stored workflow run of a repository was created on GitHub
+func (r *CIRunRepository) GetLatestWorkflowRunCreatedAt(repositoryID string) (time.Time, error) {
+	query := `SELECT MAX(github_created_at) FROM ci_runs WHERE repository_id = ? AND kind = ?`
+
+	var latest sql.NullString
+	if err := r.db.QueryRow(query, repositoryID, models.CIRunKindWorkflowRun).Scan(&latest); err != nil {
+		return time.Time{}, err
+	}
+	if !latest.Valid {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(sqliteTimeLayout, latest.String)
+}
+
+// GetSettledCommits returns the commits of a repository whose check runs were all completed when last fetched
+func (r *CIRunRepository) GetSettledCommits(repositoryID string) (map[string]bool, error) {
+	query := `SELECT sha FROM ci_synced_commits WHERE repository_id = ? AND pending = 0`
+
+	rows, err := r.db.Query(query, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settled := make(map[string]bool)
+	for rows.Next() {
+		var sha string
+		if err := rows.Scan(&sha); err != nil {
+			return nil, err
+		}
+		settled[sha] = true
+	}
+
+	return settled, rows.Err()
+}
+
+// MarkCommitSynced records that the check runs of a commit were fetched, and whether any was still running
+func (r *CIRunRepository) MarkCommitSynced(repositoryID, sha string, pending bool) error {
+	query := `
+		INSERT INTO ci_synced_commits (repository_id, sha, pending, synced_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(repository_id, sha) DO UPDATE SET
+			pending = excluded.pending,
+			synced_at = excluded.synced_at
+	`
+
+	_, err := r.db.Exec(query, repositoryID, sha, pending)
+	return err
+}
+
+func (r *CIRunRepository) query(query string, args ...interface{}) ([]*models.CIRun, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*models.CIRun
+	for rows.Next() {
+		var run models.CIRun
+		err := rows.Scan(
+			&run.ID, &run.RepositoryID, &run.Kind, &run.GithubID, &run.Attempt, &run.Name, &run.HeadSHA, &run.HeadBranch, &run.Event, &run.Status, &run.Conclusion,
+			&run.HTMLURL, &run.GithubCreatedAt, &run.StartedAt, &run.CompletedAt, &run.CreatedAt, &run.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, &run)
+	}
+
+	return runs, rows.Err()
+}
@@ -118,6 +118,89 @@ func (r *CommitRepository) GetByRepositoryID(repositoryID string) ([]*models.Com
 	return commits, nil
 }
 
+// GetByProjectID retrieves all commits of a project's repositories
+func (r *CommitRepository) GetByProjectID(projectID string) ([]*models.Commit, error) {
+	query := `
+		SELECT id, github_repository_id, commit_sha, message, author_name, author_email,
+			   commit_date, is_merge_commit, merge_commit_sha, additions, deletions, changes, created_at
+		FROM commits
+		WHERE github_repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+		ORDER BY commit_date DESC
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commits []*models.Commit
+	for rows.Next() {
+		commit := &models.Commit{}
+		err := rows.Scan(
+			&commit.ID, &commit.GithubRepositoryID, &commit.CommitSHA, &commit.Message,
+			&commit.AuthorName, &commit.AuthorEmail, &commit.CommitDate, &commit.IsMergeCommit,
+			&commit.MergeCommitSHA, &commit.Additions, &commit.Deletions, &commit.Changes, &commit.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
+	}
+
+	return commits, rows.Err()
+}
+
+// GetPullRequestLinksByProjectID tells for each commit of a project whether it came in through a pull request
+func (r *CommitRepository) GetPullRequestLinksByProjectID(projectID string) ([]*models.CommitPullRequestLink, error) {
+	return r.queryPullRequestLinks(`
+		c.github_repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)`, projectID)
+}
+
+// GetPullRequestLinksByRepositoryID tells for each commit of a repository whether it came in through a pull request
+func (r *CommitRepository) GetPullRequestLinksByRepositoryID(repositoryID string) ([]*models.CommitPullRequestLink, error) {
+	return r.queryPullRequestLinks(`c.github_repository_id = ?`, repositoryID)
+}
+
+// queryPullRequestLinks links the commits matching the condition to pull requests by merge commit SHA
+// and by the SHAs of the pull request commits
+func (r *CommitRepository) queryPullRequestLinks(condition string, args ...interface{}) ([]*models.CommitPullRequestLink, error) {
+	query := `
+		SELECT c.github_repository_id, c.commit_sha, c.commit_date,
+			EXISTS (
+				SELECT 1 FROM pull_requests p
+				WHERE p.repository_id = c.github_repository_id AND p.merge_commit_sha = c.commit_sha
+			) OR EXISTS (
+				SELECT 1 FROM pr_commits pc
+				WHERE pc.repository_id = c.github_repository_id AND pc.commit_sha = c.commit_sha
+			)
+		FROM commits c
+		WHERE ` + condition + `
+		ORDER BY c.commit_date
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []*models.CommitPullRequestLink
+	for rows.Next() {
+		link := &models.CommitPullRequestLink{}
+		if err := rows.Scan(&link.RepositoryID, &link.CommitSHA, &link.CommitDate, &link.Linked); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
 // GetEmailStatsByProjectID retrieves email statistics for a project
 func (r *CommitRepository) GetEmailStatsByProjectID(projectID string, mergedEmails map[string]string) ([]*models.EmailStats, error) {
 
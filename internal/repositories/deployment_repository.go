@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/uuid"
+)
+
+type DeploymentRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewDeploymentRepository(db *sql.DB) *DeploymentRepository {
+	return &DeploymentRepository{db: db}
+}
+
+const deploymentColumns = `
+	id, repository_id, source, external_id, name, environment, sha, status, url, deployed_at, commits_linked,
+	created_at, updated_at
+`
+
+// Upsert creates a deployment or updates the one with the same repository, source and external ID.
+// Commits stay linked unless the deployment moved to another commit.
+func (r *DeploymentRepository) Upsert(deployment *models.Deployment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if deployment.ID == "" {
+		deployment.ID = uuid.New().String()
+	}
+	deployment.CreatedAt = now
+	deployment.UpdatedAt = now
+
+	query := `
+		INSERT INTO deployments (` + deploymentColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(repository_id, source, external_id) DO UPDATE SET
+			name = excluded.name,
+			environment = excluded.environment,
+			commits_linked = commits_linked AND sha IS excluded.sha,
+			sha = excluded.sha,
+			status = excluded.status,
+			url = excluded.url,
+			deployed_at = excluded.deployed_at,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query,
+		deployment.ID, deployment.RepositoryID, deployment.Source, deployment.ExternalID, deployment.Name, deployment.Environment,
+		deployment.SHA, deployment.Status, deployment.URL, deployment.DeployedAt, deployment.CommitsLinked,
+		deployment.CreatedAt, deployment.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByRepositoryID retrieves the deployments of a repository, oldest first
+func (r *DeploymentRepository) GetByRepositoryID(repositoryID string) ([]*models.Deployment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `SELECT ` + deploymentColumns + ` FROM deployments WHERE repository_id = ? ORDER BY deployed_at`
+	return r.query(query, repositoryID)
+}
+
+// GetByProjectID retrieves the deployments of all repositories of a project, oldest first
+func (r *DeploymentRepository) GetByProjectID(projectID string) ([]*models.Deployment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT ` + deploymentColumns + `
+		FROM deployments
+		WHERE repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+		ORDER BY deployed_at
+	`
+	return r.query(query, projectID)
+}
+
+// ReplaceCommits replaces the commits a deployment shipped and marks its commits as linked
+func (r *DeploymentRepository) ReplaceCommits(deploymentID string, shas []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM deployment_commits WHERE deployment_id = ?`, deploymentID); err != nil {
+		return err
+	}
+	for _, sha := range shas {
+		_, err := tx.Exec(`
+			INSERT INTO deployment_commits (deployment_id, commit_sha) VALUES (?, ?)
+			ON CONFLICT(deployment_id, commit_sha) DO NOTHING
+		`, deploymentID, sha)
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(`UPDATE deployments SET commits_linked = 1 WHERE id = ?`, deploymentID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetCommitsByProjectID retrieves the commits shipped by the deployments of all repositories of a project
+func (r *DeploymentRepository) GetCommitsByProjectID(projectID string) ([]*models.DeploymentCommit, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT dc.deployment_id, dc.commit_sha
+		FROM deployment_commits dc
+		JOIN deployments d ON d.id = dc.deployment_id
+		WHERE d.repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commits []*models.DeploymentCommit
+	for rows.Next() {
+		var commit models.DeploymentCommit
+		if err := rows.Scan(&commit.DeploymentID, &commit.CommitSHA); err != nil {
+			return nil, err
+		}
+		commits = append(commits, &commit)
+	}
+
+	return commits, rows.Err()
+}
+
+func (r *DeploymentRepository) query(query string, args ...interface{}) ([]*models.Deployment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deployments []*models.Deployment
+	for rows.Next() {
+		var deployment models.Deployment
+		err := rows.Scan(
+			&deployment.ID, &deployment.RepositoryID, &deployment.Source, &deployment.ExternalID, &deployment.Name, &deployment.Environment,
+			&deployment.SHA, &deployment.Status, &deployment.URL, &deployment.DeployedAt, &deployment.CommitsLinked,
+			&deployment.CreatedAt, &deployment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deployments = append(deployments, &deployment)
+	}
+
+	return deployments, rows.Err()
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/alimgiray/gscope/internal/models"
+)
+
+type DORASettingsRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewDORASettingsRepository(db *sql.DB) *DORASettingsRepository {
+	return &DORASettingsRepository{db: db}
+}
+
+// Create creates new DORA settings
+func (r *DORASettingsRepository) Create(settings *models.DORASettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		INSERT INTO dora_settings (
+			id, project_id, deploy_source, environment, tag_pattern, revert_is_failure, failure_labels,
+			failed_status_is_failure, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		settings.ID, settings.ProjectID, settings.DeploySource, settings.Environment, settings.TagPattern,
+		settings.RevertIsFailure, settings.FailureLabels, settings.FailedStatusIsFailure, settings.CreatedAt, settings.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByProjectID retrieves the DORA settings of a project
+func (r *DORASettingsRepository) GetByProjectID(projectID string) (*models.DORASettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT id, project_id, deploy_source, environment, tag_pattern, revert_is_failure, failure_labels,
+			failed_status_is_failure, created_at, updated_at
+		FROM dora_settings WHERE project_id = ?
+	`
+
+	var settings models.DORASettings
+	err := r.db.QueryRow(query, projectID).Scan(
+		&settings.ID, &settings.ProjectID, &settings.DeploySource, &settings.Environment, &settings.TagPattern,
+		&settings.RevertIsFailure, &settings.FailureLabels, &settings.FailedStatusIsFailure, &settings.CreatedAt, &settings.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// Update updates DORA settings
+func (r *DORASettingsRepository) Update(settings *models.DORASettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		UPDATE dora_settings
+		SET deploy_source = ?, environment = ?, tag_pattern = ?, revert_is_failure = ?, failure_labels = ?,
+			failed_status_is_failure = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err := r.db.Exec(query,
+		settings.DeploySource, settings.Environment, settings.TagPattern, settings.RevertIsFailure, settings.FailureLabels,
+		settings.FailedStatusIsFailure, settings.UpdatedAt, settings.ID,
+	)
+
+	return err
+}
+
+// Upsert creates or updates the DORA settings of a project
+func (r *DORASettingsRepository) Upsert(settings *models.DORASettings) error {
+	existing, err := r.GetByProjectID(settings.ProjectID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if existing != nil {
+		settings.ID = existing.ID
+		settings.CreatedAt = existing.CreatedAt
+		return r.Update(settings)
+	}
+
+	return r.Create(settings)
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+)
+
+type GitHubHTTPCacheRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewGitHubHTTPCacheRepository(db *sql.DB) *GitHubHTTPCacheRepository {
+	return &GitHubHTTPCacheRepository{db: db}
+}
+
+// Get retrieves a cached response by its key
+func (r *GitHubHTTPCacheRepository) Get(cacheKey string) (*models.GitHubHTTPCacheEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT cache_key, url, etag, last_modified, header, body, created_at, updated_at
+		FROM github_http_cache WHERE cache_key = ?
+	`
+
+	var entry models.GitHubHTTPCacheEntry
+	err := r.db.QueryRow(query, cacheKey).Scan(
+		&entry.CacheKey, &entry.URL, &entry.ETag, &entry.LastModified,
+		&entry.Header, &entry.Body, &entry.CreatedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Upsert stores a response, replacing any previous one with the same key
+func (r *GitHubHTTPCacheRepository) Upsert(entry *models.GitHubHTTPCacheEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		INSERT INTO github_http_cache (cache_key, url, etag, last_modified, header, body, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(cache_key) DO UPDATE SET
+			url = excluded.url, etag = excluded.etag, last_modified = excluded.last_modified,
+			header = excluded.header, body = excluded.body, updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query,
+		entry.CacheKey, entry.URL, entry.ETag, entry.LastModified,
+		entry.Header, entry.Body, entry.CreatedAt, entry.UpdatedAt,
+	)
+	return err
+}
+
+// Touch marks a cached response as still valid
+func (r *GitHubHTTPCacheRepository) Touch(cacheKey string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `UPDATE github_http_cache SET updated_at = ? WHERE cache_key = ?`
+	_, err := r.db.Exec(query, time.Now(), cacheKey)
+	return err
+}
+
+// DeleteOlderThan removes responses that haven't been validated since the given time
+func (r *GitHubHTTPCacheRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Compare as times, as stored timestamps don't all share one time zone
+	result, err := r.db.Exec(`DELETE FROM github_http_cache WHERE julianday(updated_at) < julianday(?)`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -2,11 +2,20 @@ package repositories
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/alimgiray/gscope/internal/models"
 )
 
+// githubRepositoryColumns is the column list shared by all SELECT queries below
+const githubRepositoryColumns = `
+	id, github_id, name, full_name, description, url, clone_url, language,
+	stars, forks, private, is_archived, is_fork, topics, default_branch, local_path, is_cloned, last_cloned,
+	github_created_at, github_updated_at, github_pushed_at, github_deleted_at, github_inaccessible_at,
+	previous_full_name, status_checked_at, created_at, updated_at
+`
+
 type GitHubRepositoryRepository struct {
 	db *sql.DB
 }
@@ -15,100 +24,97 @@ func NewGitHubRepositoryRepository(db *sql.DB) *GitHubRepositoryRepository {
 	return &GitHubRepositoryRepository{db: db}
 }
 
-// Create creates a new GitHub repository
-func (r *GitHubRepositoryRepository) Create(repo *models.GitHubRepository) error {
-	query := `
-		INSERT INTO github_repositories (
-			id, github_id, name, full_name, description, url, clone_url, language,
-			stars, forks, private, default_branch, local_path, is_cloned, last_cloned,
-			github_created_at, github_updated_at, github_pushed_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-
-	_, err := r.db.Exec(query,
-		repo.ID, repo.GithubID, repo.Name, repo.FullName, repo.Description,
-		repo.URL, repo.CloneURL, repo.Language, repo.Stars, repo.Forks,
-		repo.Private, repo.DefaultBranch, repo.LocalPath, repo.IsCloned,
-		repo.LastCloned, repo.GithubCreatedAt, repo.GithubUpdatedAt,
-		repo.GithubPushedAt,
-	)
-
-	return err
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
-// GetByID retrieves a GitHub repository by ID
-func (r *GitHubRepositoryRepository) GetByID(id string) (*models.GitHubRepository, error) {
-	query := `
-		SELECT id, github_id, name, full_name, description, url, clone_url, language,
-			   stars, forks, private, default_branch, local_path, is_cloned, last_cloned,
-			   github_created_at, github_updated_at, github_pushed_at, created_at, updated_at
-		FROM github_repositories WHERE id = ?
-	`
-
+// scanGitHubRepository scans a row selected with githubRepositoryColumns
+func scanGitHubRepository(scanner rowScanner) (*models.GitHubRepository, error) {
 	repo := &models.GitHubRepository{}
-	err := r.db.QueryRow(query, id).Scan(
+	var isArchived, isFork sql.NullBool
+	var topics sql.NullString
+
+	err := scanner.Scan(
 		&repo.ID, &repo.GithubID, &repo.Name, &repo.FullName, &repo.Description,
 		&repo.URL, &repo.CloneURL, &repo.Language, &repo.Stars, &repo.Forks,
-		&repo.Private, &repo.DefaultBranch, &repo.LocalPath, &repo.IsCloned,
+		&repo.Private, &isArchived, &isFork, &topics, &repo.DefaultBranch, &repo.LocalPath, &repo.IsCloned,
 		&repo.LastCloned, &repo.GithubCreatedAt, &repo.GithubUpdatedAt,
-		&repo.GithubPushedAt, &repo.CreatedAt, &repo.UpdatedAt,
+		&repo.GithubPushedAt, &repo.GithubDeletedAt, &repo.GithubInaccessibleAt,
+		&repo.PreviousFullName, &repo.StatusCheckedAt, &repo.CreatedAt, &repo.UpdatedAt,
 	)
-
 	if err != nil {
 		return nil, err
 	}
 
+	repo.IsArchived = isArchived.Bool
+	repo.IsFork = isFork.Bool
+	if topics.Valid && topics.String != "" {
+		if err := json.Unmarshal([]byte(topics.String), &repo.Topics); err != nil {
+			return nil, err
+		}
+	}
+
 	return repo, nil
 }
 
-// GetByGithubID retrieves a GitHub repository by GitHub ID
-func (r *GitHubRepositoryRepository) GetByGithubID(githubID int64) (*models.GitHubRepository, error) {
-	query := `
-		SELECT id, github_id, name, full_name, description, url, clone_url, language,
-			   stars, forks, private, default_branch, local_path, is_cloned, last_cloned,
-			   github_created_at, github_updated_at, github_pushed_at, created_at, updated_at
-		FROM github_repositories WHERE github_id = ?
-	`
-
-	repo := &models.GitHubRepository{}
-	err := r.db.QueryRow(query, githubID).Scan(
-		&repo.ID, &repo.GithubID, &repo.Name, &repo.FullName, &repo.Description,
-		&repo.URL, &repo.CloneURL, &repo.Language, &repo.Stars, &repo.Forks,
-		&repo.Private, &repo.DefaultBranch, &repo.LocalPath, &repo.IsCloned,
-		&repo.LastCloned, &repo.GithubCreatedAt, &repo.GithubUpdatedAt,
-		&repo.GithubPushedAt, &repo.CreatedAt, &repo.UpdatedAt,
-	)
-
+// marshalTopics converts repository topics into their JSON column value
+func marshalTopics(topics []string) (*string, error) {
+	if len(topics) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(topics)
 	if err != nil {
 		return nil, err
 	}
-
-	return repo, nil
+	value := string(data)
+	return &value, nil
 }
 
-// GetByFullName retrieves a GitHub repository by full name
-func (r *GitHubRepositoryRepository) GetByFullName(fullName string) (*models.GitHubRepository, error) {
+// Create creates a new GitHub repository
+func (r *GitHubRepositoryRepository) Create(repo *models.GitHubRepository) error {
 	query := `
-		SELECT id, github_id, name, full_name, description, url, clone_url, language,
-			   stars, forks, private, default_branch, local_path, is_cloned, last_cloned,
-			   github_created_at, github_updated_at, github_pushed_at, created_at, updated_at
-		FROM github_repositories WHERE full_name = ?
+		INSERT INTO github_repositories (
+			id, github_id, name, full_name, description, url, clone_url, language,
+			stars, forks, private, is_archived, is_fork, topics, default_branch, local_path, is_cloned, last_cloned,
+			github_created_at, github_updated_at, github_pushed_at, github_deleted_at, github_inaccessible_at,
+			previous_full_name, status_checked_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	repo := &models.GitHubRepository{}
-	err := r.db.QueryRow(query, fullName).Scan(
-		&repo.ID, &repo.GithubID, &repo.Name, &repo.FullName, &repo.Description,
-		&repo.URL, &repo.CloneURL, &repo.Language, &repo.Stars, &repo.Forks,
-		&repo.Private, &repo.DefaultBranch, &repo.LocalPath, &repo.IsCloned,
-		&repo.LastCloned, &repo.GithubCreatedAt, &repo.GithubUpdatedAt,
-		&repo.GithubPushedAt, &repo.CreatedAt, &repo.UpdatedAt,
-	)
-
+	topics, err := marshalTopics(repo.Topics)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return repo, nil
+	_, err = r.db.Exec(query,
+		repo.ID, repo.GithubID, repo.Name, repo.FullName, repo.Description,
+		repo.URL, repo.CloneURL, repo.Language, repo.Stars, repo.Forks,
+		repo.Private, repo.IsArchived, repo.IsFork, topics, repo.DefaultBranch, repo.LocalPath, repo.IsCloned,
+		repo.LastCloned, repo.GithubCreatedAt, repo.GithubUpdatedAt,
+		repo.GithubPushedAt, repo.GithubDeletedAt, repo.GithubInaccessibleAt,
+		repo.PreviousFullName, repo.StatusCheckedAt,
+	)
+
+	return err
+}
+
+// GetByID retrieves a GitHub repository by ID
+func (r *GitHubRepositoryRepository) GetByID(id string) (*models.GitHubRepository, error) {
+	query := `SELECT ` + githubRepositoryColumns + ` FROM github_repositories WHERE id = ?`
+	return scanGitHubRepository(r.db.QueryRow(query, id))
+}
+
+// GetByGithubID retrieves a GitHub repository by GitHub ID
+func (r *GitHubRepositoryRepository) GetByGithubID(githubID int64) (*models.GitHubRepository, error) {
+	query := `SELECT ` + githubRepositoryColumns + ` FROM github_repositories WHERE github_id = ?`
+	return scanGitHubRepository(r.db.QueryRow(query, githubID))
+}
+
+// GetByFullName retrieves a GitHub repository by full name
+func (r *GitHubRepositoryRepository) GetByFullName(fullName string) (*models.GitHubRepository, error) {
+	query := `SELECT ` + githubRepositoryColumns + ` FROM github_repositories WHERE full_name = ?`
+	return scanGitHubRepository(r.db.QueryRow(query, fullName))
 }
 
 // Update updates a GitHub repository
@@ -117,16 +123,25 @@ func (r *GitHubRepositoryRepository) Update(repo *models.GitHubRepository) error
 		UPDATE github_repositories SET
 			github_id = ?, name = ?, full_name = ?, description = ?, url = ?,
 			clone_url = ?, language = ?, stars = ?, forks = ?, private = ?,
+			is_archived = ?, is_fork = ?, topics = ?,
 			default_branch = ?, local_path = ?, is_cloned = ?, last_cloned = ?,
-			github_created_at = ?, github_updated_at = ?, github_pushed_at = ?
+			github_created_at = ?, github_updated_at = ?, github_pushed_at = ?,
+			github_deleted_at = ?, github_inaccessible_at = ?, previous_full_name = ?, status_checked_at = ?
 		WHERE id = ?
 	`
 
-	_, err := r.db.Exec(query,
+	topics, err := marshalTopics(repo.Topics)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(query,
 		repo.GithubID, repo.Name, repo.FullName, repo.Description, repo.URL,
 		repo.CloneURL, repo.Language, repo.Stars, repo.Forks, repo.Private,
+		repo.IsArchived, repo.IsFork, topics,
 		repo.DefaultBranch, repo.LocalPath, repo.IsCloned, repo.LastCloned,
 		repo.GithubCreatedAt, repo.GithubUpdatedAt, repo.GithubPushedAt,
+		repo.GithubDeletedAt, repo.GithubInaccessibleAt, repo.PreviousFullName, repo.StatusCheckedAt,
 		repo.ID,
 	)
 
@@ -142,48 +157,19 @@ func (r *GitHubRepositoryRepository) Delete(id string) error {
 
 // ListAll retrieves all GitHub repositories
 func (r *GitHubRepositoryRepository) ListAll() ([]*models.GitHubRepository, error) {
-	query := `
-		SELECT id, github_id, name, full_name, description, url, clone_url, language,
-			   stars, forks, private, default_branch, local_path, is_cloned, last_cloned,
-			   github_created_at, github_updated_at, github_pushed_at, created_at, updated_at
-		FROM github_repositories ORDER BY created_at DESC
-	`
-
-	rows, err := r.db.Query(query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var repos []*models.GitHubRepository
-	for rows.Next() {
-		repo := &models.GitHubRepository{}
-		err := rows.Scan(
-			&repo.ID, &repo.GithubID, &repo.Name, &repo.FullName, &repo.Description,
-			&repo.URL, &repo.CloneURL, &repo.Language, &repo.Stars, &repo.Forks,
-			&repo.Private, &repo.DefaultBranch, &repo.LocalPath, &repo.IsCloned,
-			&repo.LastCloned, &repo.GithubCreatedAt, &repo.GithubUpdatedAt,
-			&repo.GithubPushedAt, &repo.CreatedAt, &repo.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		repos = append(repos, repo)
-	}
-
-	return repos, nil
+	query := `SELECT ` + githubRepositoryColumns + ` FROM github_repositories ORDER BY created_at DESC`
+	return r.queryRepositories(query)
 }
 
 // ListByLanguage retrieves repositories by language
 func (r *GitHubRepositoryRepository) ListByLanguage(language string) ([]*models.GitHubRepository, error) {
-	query := `
-		SELECT id, github_id, name, full_name, description, url, clone_url, language,
-			   stars, forks, private, default_branch, local_path, is_cloned, last_cloned,
-			   github_created_at, github_updated_at, github_pushed_at, created_at, updated_at
-		FROM github_repositories WHERE language = ? ORDER BY stars DESC
-	`
+	query := `SELECT ` + githubRepositoryColumns + ` FROM github_repositories WHERE language = ? ORDER BY stars DESC`
+	return r.queryRepositories(query, language)
+}
 
-	rows, err := r.db.Query(query, language)
+// queryRepositories runs a query selecting githubRepositoryColumns and scans all rows
+func (r *GitHubRepositoryRepository) queryRepositories(query string, args ...interface{}) ([]*models.GitHubRepository, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -191,14 +177,7 @@ func (r *GitHubRepositoryRepository) ListByLanguage(language string) ([]*models.
 
 	var repos []*models.GitHubRepository
 	for rows.Next() {
-		repo := &models.GitHubRepository{}
-		err := rows.Scan(
-			&repo.ID, &repo.GithubID, &repo.Name, &repo.FullName, &repo.Description,
-			&repo.URL, &repo.CloneURL, &repo.Language, &repo.Stars, &repo.Forks,
-			&repo.Private, &repo.DefaultBranch, &repo.LocalPath, &repo.IsCloned,
-			&repo.LastCloned, &repo.GithubCreatedAt, &repo.GithubUpdatedAt,
-			&repo.GithubPushedAt, &repo.CreatedAt, &repo.UpdatedAt,
-		)
+		repo, err := scanGitHubRepository(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/uuid"
+)
+
+type GithubTeamRepository struct {
+	db *sql.DB
+}
+
+func NewGithubTeamRepository(db *sql.DB) *GithubTeamRepository {
+	return &GithubTeamRepository{db: db}
+}
+
+func (r *GithubTeamRepository) Create(team *models.GithubTeam) error {
+	team.ID = uuid.New().String()
+
+	query := `
+		INSERT INTO github_teams (id, github_team_id, slug, name, html_url, organization)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query, team.ID, team.GithubTeamID, team.Slug, team.Name, team.HTMLURL, team.Organization)
+
+	return err
+}
+
+func (r *GithubTeamRepository) GetByID(id string) (*models.GithubTeam, error) {
+	query := `SELECT id, github_team_id, slug, name, html_url, organization, created_at, updated_at FROM github_teams WHERE id = ?`
+
+	return scanGithubTeam(r.db.QueryRow(query, id))
+}
+
+func (r *GithubTeamRepository) GetByGithubTeamID(githubTeamID int64) (*models.GithubTeam, error) {
+	query := `SELECT id, github_team_id, slug, name, html_url, organization, created_at, updated_at FROM github_teams WHERE github_team_id = ?`
+
+	return scanGithubTeam(r.db.QueryRow(query, githubTeamID))
+}
+
+// Update updates a team, keeping its organization when the team comes from a pull request
+func (r *GithubTeamRepository) Update(team *models.GithubTeam) error {
+	team.UpdatedAt = time.Now()
+
+	query := `UPDATE github_teams SET slug = ?, name = ?, html_url = ?, organization = COALESCE(?, organization) WHERE id = ?`
+
+	_, err := r.db.Exec(query, team.Slug, team.Name, team.HTMLURL, team.Organization, team.ID)
+
+	return err
+}
+
+// Upsert creates or updates a team by its GitHub ID and sets the ID of the stored team
+func (r *GithubTeamRepository) Upsert(team *models.GithubTeam) error {
+	existing, err := r.GetByGithubTeamID(team.GithubTeamID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if existing != nil {
+		team.ID = existing.ID
+		team.CreatedAt = existing.CreatedAt
+		return r.Update(team)
+	}
+
+	return r.Create(team)
+}
+
+// ReplaceMembers replaces the members of a team with the given GitHub people
+func (r *GithubTeamRepository) ReplaceMembers(teamID string, githubPersonIDs []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM github_team_members WHERE github_team_id = ?`, teamID); err != nil {
+		return err
+	}
+	for _, personID := range githubPersonIDs {
+		_, err := tx.Exec(`INSERT OR IGNORE INTO github_team_members (github_team_id, github_person_id) VALUES (?, ?)`, teamID, personID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func scanGithubTeam(scanner rowScanner) (*models.GithubTeam, error) {
+	var team models.GithubTeam
+	err := scanner.Scan(
+		&team.ID, &team.GithubTeamID, &team.Slug, &team.Name, &team.HTMLURL, &team.Organization, &team.CreatedAt, &team.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
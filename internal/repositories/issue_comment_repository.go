@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/uuid"
+)
+
+type IssueCommentRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewIssueCommentRepository(db *sql.DB) *IssueCommentRepository {
+	return &IssueCommentRepository{db: db}
+}
+
+const issueCommentColumns = `
+	id, repository_id, issue_id, github_comment_id, author_id, author_login,
+	body, html_url, github_created_at, github_updated_at, created_at, updated_at
+`
+
+// Upsert creates an issue comment or updates the one with the same GitHub comment ID
+func (r *IssueCommentRepository) Upsert(comment *models.IssueComment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if comment.ID == "" {
+		comment.ID = uuid.New().String()
+	}
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+
+	query := `
+		INSERT INTO issue_comments (` + issueCommentColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(github_comment_id) DO UPDATE SET
+			repository_id = excluded.repository_id,
+			issue_id = excluded.issue_id,
+			author_id = excluded.author_id,
+			author_login = excluded.author_login,
+			body = excluded.body,
+			html_url = excluded.html_url,
+			github_created_at = excluded.github_created_at,
+			github_updated_at = excluded.github_updated_at,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query,
+		comment.ID, comment.RepositoryID, comment.IssueID, comment.GithubCommentID, comment.AuthorID, comment.AuthorLogin,
+		comment.Body, comment.HTMLURL, comment.GithubCreatedAt, comment.GithubUpdatedAt, comment.CreatedAt, comment.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByRepositoryID retrieves all issue comments of a repository
+func (r *IssueCommentRepository) GetByRepositoryID(repositoryID string) ([]*models.IssueComment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `SELECT ` + issueCommentColumns + ` FROM issue_comments WHERE repository_id = ? ORDER BY github_created_at`
+	return r.query(query, repositoryID)
+}
+
+// GetByProjectID retrieves the issue comments of all repositories of a project
+func (r *IssueCommentRepository) GetByProjectID(projectID string) ([]*models.IssueComment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT ` + issueCommentColumns + `
+		FROM issue_comments
+		WHERE repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+		ORDER BY github_created_at
+	`
+	return r.query(query, projectID)
+}
+
+// GetLatestUpdatedAtByRepositoryID returns when the most recently updated issue comment of a repository changed on GitHub
+func (r *IssueCommentRepository) GetLatestUpdatedAtByRepositoryID(repositoryID string) (time.Time, error) {
+	query := `SELECT MAX(github_updated_at) FROM issue_comments WHERE repository_id = ?`
+
+	var latest sql.NullString
+	if err := r.db.QueryRow(query, repositoryID).Scan(&latest); err != nil {
+		return time.Time{}, err
+	}
+	if !latest.Valid {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(sqliteTimeLayout, latest.String)
+}
+
+func (r *IssueCommentRepository) query(query string, args ...interface{}) ([]*models.IssueComment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*models.IssueComment
+	for rows.Next() {
+		var comment models.IssueComment
+		err := rows.Scan(
+			&comment.ID, &comment.RepositoryID, &comment.IssueID, &comment.GithubCommentID, &comment.AuthorID, &comment.AuthorLogin,
+			&comment.Body, &comment.HTMLURL, &comment.GithubCreatedAt, &comment.GithubUpdatedAt, &comment.CreatedAt, &comment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, &comment)
+	}
+
+	return comments, rows.Err()
+}
@@ -0,0 +1,267 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/uuid"
+)
+
+type IssueRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewIssueRepository(db *sql.DB) *IssueRepository {
+	return &IssueRepository{db: db}
+}
+
+const issueColumns = `
+	id, repository_id, github_issue_id, number, title, body, state, state_reason, author_id, closed_by_id,
+	html_url, github_created_at, github_updated_at, closed_at, created_at, updated_at
+`
+
+// Upsert creates an issue or updates the one with the same GitHub issue ID, setting the ID of the stored issue.
+// The person who closed the issue is kept when the update doesn't know them.
+func (r *IssueRepository) Upsert(issue *models.Issue) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if issue.ID == "" {
+		issue.ID = uuid.New().String()
+	}
+	issue.CreatedAt = now
+	issue.UpdatedAt = now
+
+	query := `
+		INSERT INTO issues (` + issueColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(github_issue_id) DO UPDATE SET
+			repository_id = excluded.repository_id,
+			number = excluded.number,
+			title = excluded.title,
+			body = excluded.body,
+			state = excluded.state,
+			state_reason = excluded.state_reason,
+			author_id = excluded.author_id,
+			closed_by_id = COALESCE(excluded.closed_by_id, issues.closed_by_id),
+			html_url = excluded.html_url,
+			github_created_at = excluded.github_created_at,
+			github_updated_at = excluded.github_updated_at,
+			closed_at = excluded.closed_at,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query,
+		issue.ID, issue.RepositoryID, issue.GithubIssueID, issue.Number, issue.Title, issue.Body, issue.State, issue.StateReason,
+		issue.AuthorID, issue.ClosedByID, issue.HTMLURL, issue.GithubCreatedAt, issue.GithubUpdatedAt, issue.ClosedAt,
+		issue.CreatedAt, issue.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.db.QueryRow(`SELECT id FROM issues WHERE github_issue_id = ?`, issue.GithubIssueID).Scan(&issue.ID)
+}
+
+// SetClosedBy records who closed an issue
+func (r *IssueRepository) SetClosedBy(issueID, githubPersonID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, err := r.db.Exec(`UPDATE issues SET closed_by_id = ? WHERE id = ?`, githubPersonID, issueID)
+	return err
+}
+
+// ReplaceLabels replaces the labels of an issue
+func (r *IssueRepository) ReplaceLabels(issueID string, labels []*models.IssueLabel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM issue_labels WHERE issue_id = ?`, issueID); err != nil {
+		return err
+	}
+	for _, label := range labels {
+		label.ID = uuid.New().String()
+		label.IssueID = issueID
+		_, err := tx.Exec(`
+			INSERT INTO issue_labels (id, issue_id, name, color) VALUES (?, ?, ?, ?)
+			ON CONFLICT(issue_id, name) DO NOTHING
+		`, label.ID, label.IssueID, label.Name, label.Color)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceAssignees replaces the people assigned to an issue
+func (r *IssueRepository) ReplaceAssignees(issueID string, githubPersonIDs []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM issue_assignees WHERE issue_id = ?`, issueID); err != nil {
+		return err
+	}
+	for _, githubPersonID := range githubPersonIDs {
+		_, err := tx.Exec(`
+			INSERT INTO issue_assignees (id, issue_id, github_person_id) VALUES (?, ?, ?)
+			ON CONFLICT(issue_id, github_person_id) DO NOTHING
+		`, uuid.New().String(), issueID, githubPersonID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// CreateEvent stores a close or reopen event, ignoring events that are already stored
+func (r *IssueRepository) CreateEvent(event *models.IssueEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	event.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO issue_events (id, repository_id, issue_id, github_event_id, event, actor_id, occurred_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(github_event_id) DO NOTHING
+	`
+
+	_, err := r.db.Exec(query,
+		event.ID, event.RepositoryID, event.IssueID, event.GithubEventID, event.Event, event.ActorID, event.OccurredAt, event.CreatedAt,
+	)
+
+	return err
+}
+
+// GetByRepositoryAndNumber retrieves an issue of a repository by its number
+func (r *IssueRepository) GetByRepositoryAndNumber(repositoryID string, number int) (*models.Issue, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	issues, err := r.query(`SELECT `+issueColumns+` FROM issues WHERE repository_id = ? AND number = ?`, repositoryID, number)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return issues[0], nil
+}
+
+// GetByRepositoryID retrieves all issues of a repository
+func (r *IssueRepository) GetByRepositoryID(repositoryID string) ([]*models.Issue, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.query(`SELECT `+issueColumns+` FROM issues WHERE repository_id = ? ORDER BY github_created_at`, repositoryID)
+}
+
+// GetByProjectID retrieves the issues of all repositories of a project
+func (r *IssueRepository) GetByProjectID(projectID string) ([]*models.Issue, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT ` + issueColumns + `
+		FROM issues
+		WHERE repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+		ORDER BY github_created_at
+	`
+	return r.query(query, projectID)
+}
+
+// GetLabelsByProjectID retrieves the labels of the issues of all repositories of a project
+func (r *IssueRepository) GetLabelsByProjectID(projectID string) ([]*models.IssueLabel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT l.id, l.issue_id, l.name, l.color
+		FROM issue_labels l
+		JOIN issues i ON i.id = l.issue_id
+		WHERE i.repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+		ORDER BY l.name
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []*models.IssueLabel
+	for rows.Next() {
+		var label models.IssueLabel
+		if err := rows.Scan(&label.ID, &label.IssueID, &label.Name, &label.Color); err != nil {
+			return nil, err
+		}
+		labels = append(labels, &label)
+	}
+
+	return labels, rows.Err()
+}
+
+// GetLatestUpdatedAtByRepositoryID returns when the most recently updated issue of a repository changed on GitHub
+func (r *IssueRepository) GetLatestUpdatedAtByRepositoryID(repositoryID string) (time.Time, error) {
+	query := `SELECT MAX(github_updated_at) FROM issues WHERE repository_id = ?`
+
+	var latest sql.NullString
+	if err := r.db.QueryRow(query, repositoryID).Scan(&latest); err != nil {
+		return time.Time{}, err
+	}
+	if !latest.Valid {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(sqliteTimeLayout, latest.String)
+}
+
+func (r *IssueRepository) query(query string, args ...interface{}) ([]*models.Issue, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var issues []*models.Issue
+	for rows.Next() {
+		var issue models.Issue
+		err := rows.Scan(
+			&issue.ID, &issue.RepositoryID, &issue.GithubIssueID, &issue.Number, &issue.Title, &issue.Body, &issue.State, &issue.StateReason,
+			&issue.AuthorID, &issue.ClosedByID, &issue.HTMLURL, &issue.GithubCreatedAt, &issue.GithubUpdatedAt, &issue.ClosedAt,
+			&issue.CreatedAt, &issue.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, &issue)
+	}
+
+	return issues, rows.Err()
+}
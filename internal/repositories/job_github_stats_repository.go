@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/alimgiray/gscope/internal/models"
+)
+
+type JobGitHubStatsRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewJobGitHubStatsRepository(db *sql.DB) *JobGitHubStatsRepository {
+	return &JobGitHubStatsRepository{db: db}
+}
+
+// Upsert stores the GitHub request stats of a job
+func (r *JobGitHubStatsRepository) Upsert(stats *models.JobGitHubStats) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		INSERT INTO job_github_stats (job_id, project_id, project_repository_id, requests, cache_hits, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(job_id) DO UPDATE SET
+			requests = excluded.requests, cache_hits = excluded.cache_hits
+	`
+
+	_, err := r.db.Exec(query,
+		stats.JobID, stats.ProjectID, stats.ProjectRepositoryID, stats.Requests, stats.CacheHits, stats.CreatedAt,
+	)
+	return err
+}
+
+// GetLatestByProjectRepositoryID retrieves the stats of the most recent job for a repository
+func (r *JobGitHubStatsRepository) GetLatestByProjectRepositoryID(projectRepositoryID string) (*models.JobGitHubStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT job_id, project_id, project_repository_id, requests, cache_hits, created_at
+		FROM job_github_stats
+		WHERE project_repository_id = ?
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var stats models.JobGitHubStats
+	err := r.db.QueryRow(query, projectRepositoryID).Scan(
+		&stats.JobID, &stats.ProjectID, &stats.ProjectRepositoryID, &stats.Requests, &stats.CacheHits, &stats.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
@@ -479,3 +479,128 @@ func (r *JobRepository) GetPendingJobsWithDependencies() ([]*models.Job, error)
 
 	return jobs, nil
 }
+
+// EnqueueChain creates a chain of jobs for a project repository, each depending on the previous one, and
+// returns how many were created. Jobs at the start of the chain that are already pending are reused
+// instead. Looking up the pending jobs and creating the rest happen in one transaction, so concurrent
+// callers can't both create the same job.
+func (r *JobRepository) EnqueueChain(projectID, projectRepositoryID string, jobTypes ...models.JobType) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var dependsOn *string
+	var jobs []*models.Job
+	for _, jobType := range jobTypes {
+		if len(jobs) == 0 {
+			var pendingID string
+			err := tx.QueryRow(`
+				SELECT id FROM jobs
+				WHERE project_repository_id = ? AND job_type = ? AND status = ?
+				ORDER BY created_at ASC
+				LIMIT 1
+			`, projectRepositoryID, jobType, models.JobStatusPending).Scan(&pendingID)
+			if err == nil {
+				dependsOn = &pendingID
+				continue
+			}
+			if err != sql.ErrNoRows {
+				return 0, err
+			}
+		}
+
+		job := models.NewJob(projectID, jobType)
+		job.ProjectRepositoryID = &projectRepositoryID
+		job.DependsOn = dependsOn
+		if err := createJobTx(tx, job); err != nil {
+			return 0, err
+		}
+		dependsOn = &job.ID
+		jobs = append(jobs, job)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(jobs), nil
+}
+
+// EnqueueProjectJob creates a job of a type that covers a whole project, or returns the one that is
+// already pending. The lookup and the creation happen in one transaction.
+func (r *JobRepository) EnqueueProjectJob(projectID string, jobType models.JobType) (*models.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT id, project_id, project_repository_id, job_type, status, error_message, depends_on, started_at, completed_at, worker_id, created_at, updated_at
+		FROM jobs
+		WHERE project_id = ? AND project_repository_id IS NULL AND job_type = ? AND status = ?
+		ORDER BY created_at ASC
+		LIMIT 1
+	`
+
+	job := &models.Job{}
+	err = tx.QueryRow(query, projectID, jobType, models.JobStatusPending).Scan(
+		&job.ID,
+		&job.ProjectID,
+		&job.ProjectRepositoryID,
+		&job.JobType,
+		&job.Status,
+		&job.ErrorMessage,
+		&job.DependsOn,
+		&job.StartedAt,
+		&job.CompletedAt,
+		&job.WorkerID,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if err == nil {
+		return job, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	job = models.NewJob(projectID, jobType)
+	if err := createJobTx(tx, job); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// createJobTx inserts a job within a transaction
+func createJobTx(tx *sql.Tx, job *models.Job) error {
+	query := `
+		INSERT INTO jobs (id, project_id, project_repository_id, job_type, status, error_message, depends_on, started_at, completed_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := tx.Exec(query,
+		job.ID,
+		job.ProjectID,
+		job.ProjectRepositoryID,
+		job.JobType,
+		job.Status,
+		job.ErrorMessage,
+		job.DependsOn,
+		job.StartedAt,
+		job.CompletedAt,
+		job.CreatedAt,
+		job.UpdatedAt,
+	)
+	return err
+}
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/uuid"
+)
+
+type PRCommitRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewPRCommitRepository(db *sql.DB) *PRCommitRepository {
+	return &PRCommitRepository{db: db}
+}
+
+// ReplaceByPullRequestID replaces the commits of a pull request, as force pushes can rewrite them
+func (r *PRCommitRepository) ReplaceByPullRequestID(pullRequestID string, commits []*models.PRCommit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM pr_commits WHERE pull_request_id = ?`, pullRequestID); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO pr_commits (id, repository_id, pull_request_id, commit_sha, author_login, authored_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(pull_request_id, commit_sha) DO NOTHING
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, commit := range commits {
+		if commit.ID == "" {
+			commit.ID = uuid.New().String()
+		}
+		commit.CreatedAt = now
+
+		_, err := stmt.Exec(
+			commit.ID, commit.RepositoryID, commit.PullRequestID, commit.CommitSHA, commit.AuthorLogin, commit.AuthoredAt, commit.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByPullRequestID retrieves the commits of a pull request together with the analyzed commits they match by SHA
+func (r *PRCommitRepository) GetByPullRequestID(pullRequestID string) ([]*models.PRCommit, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT pc.id, pc.repository_id, pc.pull_request_id, pc.commit_sha, pc.author_login, pc.authored_at,
+		       c.id, pc.created_at
+		FROM pr_commits pc
+		LEFT JOIN commits c ON c.commit_sha = pc.commit_sha
+		WHERE pc.pull_request_id = ?
+		ORDER BY pc.authored_at
+	`
+
+	rows, err := r.db.Query(query, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commits []*models.PRCommit
+	for rows.Next() {
+		var commit models.PRCommit
+		err := rows.Scan(
+			&commit.ID, &commit.RepositoryID, &commit.PullRequestID, &commit.CommitSHA, &commit.AuthorLogin, &commit.AuthoredAt,
+			&commit.CommitID, &commit.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, &commit)
+	}
+
+	return commits, rows.Err()
+}
+
+// GetByProjectID retrieves the commits of the pull requests of a project's repositories
+func (r *PRCommitRepository) GetByProjectID(projectID string) ([]*models.PRCommit, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT id, repository_id, pull_request_id, commit_sha, author_login, authored_at, created_at
+		FROM pr_commits
+		WHERE repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+		ORDER BY authored_at
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commits []*models.PRCommit
+	for rows.Next() {
+		var commit models.PRCommit
+		err := rows.Scan(
+			&commit.ID, &commit.RepositoryID, &commit.PullRequestID, &commit.CommitSHA, &commit.AuthorLogin, &commit.AuthoredAt,
+			&commit.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, &commit)
+	}
+
+	return commits, rows.Err()
+}
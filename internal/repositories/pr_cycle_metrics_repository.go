@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/uuid"
+)
+
+type PRCycleMetricsRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewPRCycleMetricsRepository(db *sql.DB) *PRCycleMetricsRepository {
+	return &PRCycleMetricsRepository{db: db}
+}
+
+const prCycleMetricsColumns = `
+	id, pull_request_id, repository_id, author_login, opened_at,
+	first_review_at, first_approval_at, last_approval_at, merged_at, closed_at,
+	review_rounds, draft_seconds, time_to_first_review, time_to_approval, approval_to_merge, cycle_time,
+	created_at, updated_at, additions, deletions, changed_files, commit_count
+`
+
+// ReplaceByRepositoryID replaces the metrics of a repository with freshly calculated ones
+func (r *PRCycleMetricsRepository) ReplaceByRepositoryID(repositoryID string, metrics []*models.PRCycleMetrics) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM pr_cycle_metrics WHERE repository_id = ?`, repositoryID); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO pr_cycle_metrics (` + prCycleMetricsColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, m := range metrics {
+		if m.ID == "" {
+			m.ID = uuid.New().String()
+		}
+		m.CreatedAt = now
+		m.UpdatedAt = now
+
+		_, err := stmt.Exec(
+			m.ID, m.PullRequestID, m.RepositoryID, m.AuthorLogin, m.OpenedAt,
+			m.FirstReviewAt, m.FirstApprovalAt, m.LastApprovalAt, m.MergedAt, m.ClosedAt,
+			m.ReviewRounds, m.DraftSeconds, m.TimeToFirstReview, m.TimeToApproval, m.ApprovalToMerge, m.CycleTime,
+			m.CreatedAt, m.UpdatedAt, m.Additions, m.Deletions, m.ChangedFiles, m.CommitCount,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByRepositoryID retrieves the metrics of a repository
+func (r *PRCycleMetricsRepository) GetByRepositoryID(repositoryID string) ([]*models.PRCycleMetrics, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `SELECT ` + prCycleMetricsColumns + ` FROM pr_cycle_metrics WHERE repository_id = ? ORDER BY opened_at`
+	return r.query(query, repositoryID)
+}
+
+// GetByProjectID retrieves the metrics of all repositories of a project
+func (r *PRCycleMetricsRepository) GetByProjectID(projectID string) ([]*models.PRCycleMetrics, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT ` + prCycleMetricsColumns + `
+		FROM pr_cycle_metrics
+		WHERE repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+		ORDER BY opened_at
+	`
+	return r.query(query, projectID)
+}
+
+func (r *PRCycleMetricsRepository) query(query string, args ...interface{}) ([]*models.PRCycleMetrics, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var metrics []*models.PRCycleMetrics
+	for rows.Next() {
+		var m models.PRCycleMetrics
+		err := rows.Scan(
+			&m.ID, &m.PullRequestID, &m.RepositoryID, &m.AuthorLogin, &m.OpenedAt,
+			&m.FirstReviewAt, &m.FirstApprovalAt, &m.LastApprovalAt, &m.MergedAt, &m.ClosedAt,
+			&m.ReviewRounds, &m.DraftSeconds, &m.TimeToFirstReview, &m.TimeToApproval, &m.ApprovalToMerge, &m.CycleTime,
+			&m.CreatedAt, &m.UpdatedAt, &m.Additions, &m.Deletions, &m.ChangedFiles, &m.CommitCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, &m)
+	}
+
+	return metrics, rows.Err()
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/uuid"
+)
+
+type PRDraftEventRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewPRDraftEventRepository(db *sql.DB) *PRDraftEventRepository {
+	return &PRDraftEventRepository{db: db}
+}
+
+// Create stores a draft event, ignoring events that are already stored
+func (r *PRDraftEventRepository) Create(event *models.PRDraftEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	event.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO pr_draft_events (id, repository_id, pull_request_id, event_type, occurred_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(pull_request_id, event_type, occurred_at) DO NOTHING
+	`
+
+	_, err := r.db.Exec(query,
+		event.ID, event.RepositoryID, event.PullRequestID, event.EventType, event.OccurredAt, event.CreatedAt,
+	)
+
+	return err
+}
+
+// GetByRepositoryID retrieves the draft events of a repository in the order they happened
+func (r *PRDraftEventRepository) GetByRepositoryID(repositoryID string) ([]*models.PRDraftEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT id, repository_id, pull_request_id, event_type, occurred_at, created_at
+		FROM pr_draft_events
+		WHERE repository_id = ?
+		ORDER BY occurred_at
+	`
+
+	rows, err := r.db.Query(query, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*models.PRDraftEvent
+	for rows.Next() {
+		var event models.PRDraftEvent
+		err := rows.Scan(
+			&event.ID, &event.RepositoryID, &event.PullRequestID, &event.EventType, &event.OccurredAt, &event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
+
+// GetByProjectID retrieves the draft events of the repositories of a project in the order they happened
+func (r *PRDraftEventRepository) GetByProjectID(projectID string) ([]*models.PRDraftEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT id, repository_id, pull_request_id, event_type, occurred_at, created_at
+		FROM pr_draft_events
+		WHERE repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+		ORDER BY occurred_at
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*models.PRDraftEvent
+	for rows.Next() {
+		var event models.PRDraftEvent
+		err := rows.Scan(
+			&event.ID, &event.RepositoryID, &event.PullRequestID, &event.EventType, &event.OccurredAt, &event.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+
+	return events, rows.Err()
+}
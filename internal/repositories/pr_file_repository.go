@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/uuid"
+)
+
+type PRFileRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewPRFileRepository(db *sql.DB) *PRFileRepository {
+	return &PRFileRepository{db: db}
+}
+
+// ReplaceByPullRequestID replaces the changed files of a pull request, as they change while it is open
+func (r *PRFileRepository) ReplaceByPullRequestID(pullRequestID string, files []*models.PRFile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM pr_files WHERE pull_request_id = ?`, pullRequestID); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO pr_files (
+			id, repository_id, pull_request_id, filename, previous_filename, status,
+			additions, deletions, changes, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(pull_request_id, filename) DO NOTHING
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, file := range files {
+		if file.ID == "" {
+			file.ID = uuid.New().String()
+		}
+		file.CreatedAt = now
+
+		_, err := stmt.Exec(
+			file.ID, file.RepositoryID, file.PullRequestID, file.Filename, file.PreviousFilename, file.Status,
+			file.Additions, file.Deletions, file.Changes, file.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByPullRequestID retrieves the changed files of a pull request
+func (r *PRFileRepository) GetByPullRequestID(pullRequestID string) ([]*models.PRFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT id, repository_id, pull_request_id, filename, previous_filename, status,
+		       additions, deletions, changes, created_at
+		FROM pr_files
+		WHERE pull_request_id = ?
+		ORDER BY filename
+	`
+
+	rows, err := r.db.Query(query, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []*models.PRFile
+	for rows.Next() {
+		var file models.PRFile
+		err := rows.Scan(
+			&file.ID, &file.RepositoryID, &file.PullRequestID, &file.Filename, &file.PreviousFilename, &file.Status,
+			&file.Additions, &file.Deletions, &file.Changes, &file.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &file)
+	}
+
+	return files, rows.Err()
+}
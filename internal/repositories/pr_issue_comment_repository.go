@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/uuid"
+)
+
+type PRIssueCommentRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewPRIssueCommentRepository(db *sql.DB) *PRIssueCommentRepository {
+	return &PRIssueCommentRepository{db: db}
+}
+
+const prIssueCommentColumns = `
+	id, repository_id, pull_request_id, github_comment_id, author_id, author_login,
+	body, html_url, github_created_at, github_updated_at, created_at, updated_at
+`
+
+// Upsert creates a conversation comment or updates the one with the same GitHub comment ID
+func (r *PRIssueCommentRepository) Upsert(comment *models.PRIssueComment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if comment.ID == "" {
+		comment.ID = uuid.New().String()
+	}
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+
+	query := `
+		INSERT INTO pr_issue_comments (` + prIssueCommentColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(github_comment_id) DO UPDATE SET
+			repository_id = excluded.repository_id,
+			pull_request_id = excluded.pull_request_id,
+			author_id = excluded.author_id,
+			author_login = excluded.author_login,
+			body = excluded.body,
+			html_url = excluded.html_url,
+			github_created_at = excluded.github_created_at,
+			github_updated_at = excluded.github_updated_at,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query,
+		comment.ID, comment.RepositoryID, comment.PullRequestID, comment.GithubCommentID, comment.AuthorID, comment.AuthorLogin,
+		comment.Body, comment.HTMLURL, comment.GithubCreatedAt, comment.GithubUpdatedAt, comment.CreatedAt, comment.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByRepositoryID retrieves all conversation comments of a repository
+func (r *PRIssueCommentRepository) GetByRepositoryID(repositoryID string) ([]*models.PRIssueComment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `SELECT ` + prIssueCommentColumns + ` FROM pr_issue_comments WHERE repository_id = ? ORDER BY github_created_at`
+	return r.query(query, repositoryID)
+}
+
+// GetByPullRequestID retrieves the conversation comments of a pull request
+func (r *PRIssueCommentRepository) GetByPullRequestID(pullRequestID string) ([]*models.PRIssueComment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `SELECT ` + prIssueCommentColumns + ` FROM pr_issue_comments WHERE pull_request_id = ? ORDER BY github_created_at`
+	return r.query(query, pullRequestID)
+}
+
+// GetLatestUpdatedAtByRepositoryID returns when the most recently updated conversation comment of a repository changed on GitHub
+func (r *PRIssueCommentRepository) GetLatestUpdatedAtByRepositoryID(repositoryID string) (time.Time, error) {
+	query := `SELECT MAX(github_updated_at) FROM pr_issue_comments WHERE repository_id = ?`
+
+	var latest sql.NullString
+	if err := r.db.QueryRow(query, repositoryID).Scan(&latest); err != nil {
+		return time.Time{}, err
+	}
+	if !latest.Valid {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(sqliteTimeLayout, latest.String)
+}
+
+func (r *PRIssueCommentRepository) query(query string, args ...interface{}) ([]*models.PRIssueComment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*models.PRIssueComment
+	for rows.Next() {
+		var comment models.PRIssueComment
+		err := rows.Scan(
+			&comment.ID, &comment.RepositoryID, &comment.PullRequestID, &comment.GithubCommentID, &comment.AuthorID, &comment.AuthorLogin,
+			&comment.Body, &comment.HTMLURL, &comment.GithubCreatedAt, &comment.GithubUpdatedAt, &comment.CreatedAt, &comment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, &comment)
+	}
+
+	return comments, rows.Err()
+}
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/uuid"
+)
+
+// sqliteTimeLayout parses DATETIME values that aggregates like MAX return as plain strings
+const sqliteTimeLayout = "2006-01-02 15:04:05.999999999-07:00"
+
+type PRReviewCommentRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewPRReviewCommentRepository(db *sql.DB) *PRReviewCommentRepository {
+	return &PRReviewCommentRepository{db: db}
+}
+
+const prReviewCommentColumns = `
+	id, repository_id, pull_request_id, github_comment_id, github_review_id, in_reply_to_github_id,
+	author_id, author_login, body, path, line, original_line, start_line, side, commit_id,
+	html_url, github_created_at, github_updated_at, created_at, updated_at
+`
+
+// Upsert creates a review comment or updates the one with the same GitHub comment ID
+func (r *PRReviewCommentRepository) Upsert(comment *models.PRReviewComment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if comment.ID == "" {
+		comment.ID = uuid.New().String()
+	}
+	comment.CreatedAt = now
+	comment.UpdatedAt = now
+
+	query := `
+		INSERT INTO pr_review_comments (` + prReviewCommentColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(github_comment_id) DO UPDATE SET
+			repository_id = excluded.repository_id,
+			pull_request_id = excluded.pull_request_id,
+			github_review_id = excluded.github_review_id,
+			in_reply_to_github_id = excluded.in_reply_to_github_id,
+			author_id = excluded.author_id,
+			author_login = excluded.author_login,
+			body = excluded.body,
+			path = excluded.path,
+			line = excluded.line,
+			original_line = excluded.original_line,
+			start_line = excluded.start_line,
+			side = excluded.side,
+			commit_id = excluded.commit_id,
+			html_url = excluded.html_url,
+			github_created_at = excluded.github_created_at,
+			github_updated_at = excluded.github_updated_at,
+			updated_at = excluded.updated_at
+	`
+
+	_, err := r.db.Exec(query,
+		comment.ID, comment.RepositoryID, comment.PullRequestID, comment.GithubCommentID, comment.GithubReviewID, comment.InReplyToGithubID,
+		comment.AuthorID, comment.AuthorLogin, comment.Body, comment.Path, comment.Line, comment.OriginalLine, comment.StartLine, comment.Side, comment.CommitID,
+		comment.HTMLURL, comment.GithubCreatedAt, comment.GithubUpdatedAt, comment.CreatedAt, comment.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByRepositoryID retrieves all review comments of a repository
+func (r *PRReviewCommentRepository) GetByRepositoryID(repositoryID string) ([]*models.PRReviewComment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `SELECT ` + prReviewCommentColumns + ` FROM pr_review_comments WHERE repository_id = ? ORDER BY github_created_at`
+	return r.query(query, repositoryID)
+}
+
+// GetByPullRequestID retrieves the review comments of a pull request in thread order
+func (r *PRReviewCommentRepository) GetByPullRequestID(pullRequestID string) ([]*models.PRReviewComment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT ` + prReviewCommentColumns + `
+		FROM pr_review_comments
+		WHERE pull_request_id = ?
+		ORDER BY COALESCE(in_reply_to_github_id, github_comment_id), github_created_at
+	`
+	return r.query(query, pullRequestID)
+}
+
+// GetLatestUpdatedAtByRepositoryID returns when the most recently updated review comment of a repository changed on GitHub
+func (r *PRReviewCommentRepository) GetLatestUpdatedAtByRepositoryID(repositoryID string) (time.Time, error) {
+	query := `SELECT MAX(github_updated_at) FROM pr_review_comments WHERE repository_id = ?`
+
+	var latest sql.NullString
+	if err := r.db.QueryRow(query, repositoryID).Scan(&latest); err != nil {
+		return time.Time{}, err
+	}
+	if !latest.Valid {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(sqliteTimeLayout, latest.String)
+}
+
+func (r *PRReviewCommentRepository) query(query string, args ...interface{}) ([]*models.PRReviewComment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []*models.PRReviewComment
+	for rows.Next() {
+		var comment models.PRReviewComment
+		err := rows.Scan(
+			&comment.ID, &comment.RepositoryID, &comment.PullRequestID, &comment.GithubCommentID, &comment.GithubReviewID, &comment.InReplyToGithubID,
+			&comment.AuthorID, &comment.AuthorLogin, &comment.Body, &comment.Path, &comment.Line, &comment.OriginalLine, &comment.StartLine, &comment.Side, &comment.CommitID,
+			&comment.HTMLURL, &comment.GithubCreatedAt, &comment.GithubUpdatedAt, &comment.CreatedAt, &comment.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, &comment)
+	}
+
+	return comments, rows.Err()
+}
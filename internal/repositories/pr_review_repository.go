@@ -131,6 +131,40 @@ func (r *PRReviewRepository) GetByRepositoryID(repositoryID string) ([]*models.P
 	return reviews, nil
 }
 
+// GetByProjectID retrieves the reviews of all repositories of a project
+func (r *PRReviewRepository) GetByProjectID(projectID string) ([]*models.PRReview, error) {
+	query := `
+		SELECT * FROM pr_reviews
+		WHERE repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+		ORDER BY submitted_at
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []*models.PRReview
+	for rows.Next() {
+		var review models.PRReview
+		err := rows.Scan(
+			&review.ID, &review.RepositoryID, &review.PullRequestID, &review.GithubReviewID, &review.ReviewerID,
+			&review.ReviewerLogin, &review.Body, &review.State, &review.AuthorAssociation, &review.SubmittedAt, &review.CommitID,
+			&review.HTMLURL, &review.GithubCreatedAt, &review.GithubUpdatedAt,
+			&review.CreatedAt, &review.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, &review)
+	}
+
+	return reviews, nil
+}
+
 func (r *PRReviewRepository) Update(review *models.PRReview) error {
 	query := `
 		UPDATE pr_reviews SET 
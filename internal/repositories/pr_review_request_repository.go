@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/uuid"
+)
+
+type PRReviewRequestRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewPRReviewRequestRepository(db *sql.DB) *PRReviewRequestRepository {
+	return &PRReviewRequestRepository{db: db}
+}
+
+// CreateRequestedReviewer stores a review request for a person, ignoring requests that are already stored
+func (r *PRReviewRequestRepository) CreateRequestedReviewer(request *models.PRRequestedReviewer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request.ID = uuid.New().String()
+	request.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO pr_requested_reviewers (id, repository_id, pull_request_id, github_person_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(pull_request_id, github_person_id) DO NOTHING
+	`
+
+	_, err := r.db.Exec(query,
+		request.ID, request.RepositoryID, request.PullRequestID, request.GithubPersonID, request.CreatedAt,
+	)
+
+	return err
+}
+
+// CreateRequestedTeam stores a review request for a team, ignoring requests that are already stored
+func (r *PRReviewRequestRepository) CreateRequestedTeam(request *models.PRRequestedTeam) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request.ID = uuid.New().String()
+	request.CreatedAt = time.Now()
+
+	query := `
+		INSERT INTO pr_requested_teams (id, repository_id, pull_request_id, github_team_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(pull_request_id, github_team_id) DO NOTHING
+	`
+
+	_, err := r.db.Exec(query,
+		request.ID, request.RepositoryID, request.PullRequestID, request.GithubTeamID, request.CreatedAt,
+	)
+
+	return err
+}
+
+// GetRequestedReviewersByPullRequestID retrieves the people asked to review a pull request
+func (r *PRReviewRequestRepository) GetRequestedReviewersByPullRequestID(pullRequestID string) ([]*models.PRRequestedReviewer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT id, repository_id, pull_request_id, github_person_id, created_at
+		FROM pr_requested_reviewers
+		WHERE pull_request_id = ?
+	`
+
+	return r.queryRequestedReviewers(query, pullRequestID)
+}
+
+// GetRequestedReviewersByProjectID retrieves the review requests of all repositories of a project
+func (r *PRReviewRequestRepository) GetRequestedReviewersByProjectID(projectID string) ([]*models.PRRequestedReviewer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT id, repository_id, pull_request_id, github_person_id, created_at
+		FROM pr_requested_reviewers
+		WHERE repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+	`
+
+	return r.queryRequestedReviewers(query, projectID)
+}
+
+func (r *PRReviewRequestRepository) queryRequestedReviewers(query string, args ...interface{}) ([]*models.PRRequestedReviewer, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []*models.PRRequestedReviewer
+	for rows.Next() {
+		var request models.PRRequestedReviewer
+		if err := rows.Scan(&request.ID, &request.RepositoryID, &request.PullRequestID, &request.GithubPersonID, &request.CreatedAt); err != nil {
+			return nil, err
+		}
+		requests = append(requests, &request)
+	}
+
+	return requests, rows.Err()
+}
@@ -41,14 +41,14 @@ func (r *ProjectCollaboratorRepository) GetByID(id string) (*models.ProjectColla
 	defer r.mu.RUnlock()
 
 	query := `
-		SELECT id, project_id, user_id, created_at, updated_at
+		SELECT id, project_id, user_id, share_github_token, created_at, updated_at
 		FROM project_collaborators WHERE id = ?
 	`
 
 	collaborator := &models.ProjectCollaborator{}
 	err := r.db.QueryRow(query, id).Scan(
 		&collaborator.ID, &collaborator.ProjectID, &collaborator.UserID,
-		&collaborator.CreatedAt, &collaborator.UpdatedAt,
+		&collaborator.ShareGitHubToken, &collaborator.CreatedAt, &collaborator.UpdatedAt,
 	)
 
 	if err != nil {
@@ -64,7 +64,7 @@ func (r *ProjectCollaboratorRepository) GetByProjectID(projectID string) ([]*mod
 	defer r.mu.RUnlock()
 
 	query := `
-		SELECT id, project_id, user_id, created_at, updated_at
+		SELECT id, project_id, user_id, share_github_token, created_at, updated_at
 		FROM project_collaborators WHERE project_id = ?
 		ORDER BY created_at ASC
 	`
@@ -80,7 +80,7 @@ func (r *ProjectCollaboratorRepository) GetByProjectID(projectID string) ([]*mod
 		collaborator := &models.ProjectCollaborator{}
 		err := rows.Scan(
 			&collaborator.ID, &collaborator.ProjectID, &collaborator.UserID,
-			&collaborator.CreatedAt, &collaborator.UpdatedAt,
+			&collaborator.ShareGitHubToken, &collaborator.CreatedAt, &collaborator.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -97,7 +97,7 @@ func (r *ProjectCollaboratorRepository) GetByUserID(userID string) ([]*models.Pr
 	defer r.mu.RUnlock()
 
 	query := `
-		SELECT id, project_id, user_id, created_at, updated_at
+		SELECT id, project_id, user_id, share_github_token, created_at, updated_at
 		FROM project_collaborators WHERE user_id = ?
 		ORDER BY created_at ASC
 	`
@@ -113,7 +113,7 @@ func (r *ProjectCollaboratorRepository) GetByUserID(userID string) ([]*models.Pr
 		collaborator := &models.ProjectCollaborator{}
 		err := rows.Scan(
 			&collaborator.ID, &collaborator.ProjectID, &collaborator.UserID,
-			&collaborator.CreatedAt, &collaborator.UpdatedAt,
+			&collaborator.ShareGitHubToken, &collaborator.CreatedAt, &collaborator.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -130,14 +130,14 @@ func (r *ProjectCollaboratorRepository) GetByProjectAndUserID(projectID, userID
 	defer r.mu.RUnlock()
 
 	query := `
-		SELECT id, project_id, user_id, created_at, updated_at
+		SELECT id, project_id, user_id, share_github_token, created_at, updated_at
 		FROM project_collaborators WHERE project_id = ? AND user_id = ?
 	`
 
 	collaborator := &models.ProjectCollaborator{}
 	err := r.db.QueryRow(query, projectID, userID).Scan(
 		&collaborator.ID, &collaborator.ProjectID, &collaborator.UserID,
-		&collaborator.CreatedAt, &collaborator.UpdatedAt,
+		&collaborator.ShareGitHubToken, &collaborator.CreatedAt, &collaborator.UpdatedAt,
 	)
 
 	if err != nil {
@@ -197,3 +197,13 @@ func (r *ProjectCollaboratorRepository) ExistsByProjectAndUserID(projectID, user
 	err := r.db.QueryRow(query, projectID, userID).Scan(&count)
 	return count > 0, err
 }
+
+// UpdateShareGitHubToken sets whether a collaborator lets background jobs use their GitHub token
+func (r *ProjectCollaboratorRepository) UpdateShareGitHubToken(projectID, userID string, share bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `UPDATE project_collaborators SET share_github_token = ?, updated_at = CURRENT_TIMESTAMP WHERE project_id = ? AND user_id = ?`
+	_, err := r.db.Exec(query, share, projectID, userID)
+	return err
+}
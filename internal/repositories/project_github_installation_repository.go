@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/alimgiray/gscope/internal/models"
+)
+
+type ProjectGitHubInstallationRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewProjectGitHubInstallationRepository(db *sql.DB) *ProjectGitHubInstallationRepository {
+	return &ProjectGitHubInstallationRepository{db: db}
+}
+
+// GetByProjectID retrieves the installation mapped to a project
+func (r *ProjectGitHubInstallationRepository) GetByProjectID(projectID string) (*models.ProjectGitHubInstallation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT id, project_id, installation_id, account_login, created_at, updated_at, deleted_at
+		FROM project_github_installations WHERE project_id = ? AND deleted_at IS NULL
+	`
+
+	var installation models.ProjectGitHubInstallation
+	err := r.db.QueryRow(query, projectID).Scan(
+		&installation.ID, &installation.ProjectID, &installation.InstallationID, &installation.AccountLogin,
+		&installation.CreatedAt, &installation.UpdatedAt, &installation.DeletedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &installation, nil
+}
+
+// Upsert maps an installation to a project, replacing any existing mapping
+func (r *ProjectGitHubInstallationRepository) Upsert(installation *models.ProjectGitHubInstallation) error {
+	existing, err := r.GetByProjectID(installation.ProjectID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing != nil {
+		installation.ID = existing.ID
+		installation.CreatedAt = existing.CreatedAt
+
+		query := `
+			UPDATE project_github_installations
+			SET installation_id = ?, account_login = ?, updated_at = ?
+			WHERE id = ?
+		`
+		_, err := r.db.Exec(query, installation.InstallationID, installation.AccountLogin, installation.UpdatedAt, installation.ID)
+		return err
+	}
+
+	query := `
+		INSERT INTO project_github_installations (id, project_id, installation_id, account_login, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err = r.db.Exec(query,
+		installation.ID, installation.ProjectID, installation.InstallationID, installation.AccountLogin,
+		installation.CreatedAt, installation.UpdatedAt,
+	)
+	return err
+}
+
+// DeleteByProjectID soft deletes the installation mapping of a project
+func (r *ProjectGitHubInstallationRepository) DeleteByProjectID(projectID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `UPDATE project_github_installations SET deleted_at = CURRENT_TIMESTAMP WHERE project_id = ? AND deleted_at IS NULL`
+	_, err := r.db.Exec(query, projectID)
+	return err
+}
@@ -20,17 +20,23 @@ func NewProjectRepository(db *sql.DB) *ProjectRepository {
 // Create creates a new project
 func (r *ProjectRepository) Create(project *models.Project) error {
 	query := `
-		INSERT INTO projects (id, name, owner_id, description)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO projects (id, name, owner_id, description, github_ingestion)
+		VALUES ($1, $2, $3, $4, $5)
 	`
 
 	project.ID = uuid.New()
+	if project.GitHubIngestion == "" {
+		project.GitHubIngestion = models.GitHubIngestionREST
+	}
+	// New projects fetch issues, CI runs and deployments until the owner turns them off
+	project.SyncIssues, project.SyncCI, project.SyncDeployments = true, true, true
 
 	_, err := r.db.Exec(query,
 		project.ID,
 		project.Name,
 		project.OwnerID,
 		project.Description,
+		project.GitHubIngestion,
 	)
 
 	return err
@@ -39,7 +45,7 @@ func (r *ProjectRepository) Create(project *models.Project) error {
 // GetByID retrieves a project by ID (excluding soft deleted)
 func (r *ProjectRepository) GetByID(id string) (*models.Project, error) {
 	query := `
-		SELECT id, name, owner_id, description, created_at, updated_at, deleted_at
+		SELECT id, name, owner_id, description, github_ingestion, sync_issues, sync_ci, sync_deployments, created_at, updated_at, deleted_at
 		FROM projects 
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -50,6 +56,10 @@ func (r *ProjectRepository) GetByID(id string) (*models.Project, error) {
 		&project.Name,
 		&project.OwnerID,
 		&project.Description,
+		&project.GitHubIngestion,
+		&project.SyncIssues,
+		&project.SyncCI,
+		&project.SyncDeployments,
 		&project.CreatedAt,
 		&project.UpdatedAt,
 		&project.DeletedAt,
@@ -65,7 +75,7 @@ func (r *ProjectRepository) GetByID(id string) (*models.Project, error) {
 // GetByOwnerID retrieves all projects for an owner (excluding soft deleted)
 func (r *ProjectRepository) GetByOwnerID(ownerID string) ([]*models.Project, error) {
 	query := `
-		SELECT id, name, owner_id, description, created_at, updated_at, deleted_at
+		SELECT id, name, owner_id, description, github_ingestion, sync_issues, sync_ci, sync_deployments, created_at, updated_at, deleted_at
 		FROM projects 
 		WHERE owner_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
@@ -85,6 +95,10 @@ func (r *ProjectRepository) GetByOwnerID(ownerID string) ([]*models.Project, err
 			&project.Name,
 			&project.OwnerID,
 			&project.Description,
+			&project.GitHubIngestion,
+			&project.SyncIssues,
+			&project.SyncCI,
+			&project.SyncDeployments,
 			&project.CreatedAt,
 			&project.UpdatedAt,
 			&project.DeletedAt,
@@ -128,6 +142,56 @@ func (r *ProjectRepository) Update(project *models.Project) error {
 	return nil
 }
 
+// UpdateGitHubIngestion sets the pull request ingestion mode of a project
+func (r *ProjectRepository) UpdateGitHubIngestion(id, mode string) error {
+	query := `
+		UPDATE projects 
+		SET github_ingestion = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, mode, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpdateGitHubSync saves which GitHub data of a project is fetched with its updates
+func (r *ProjectRepository) UpdateGitHubSync(project *models.Project) error {
+	query := `
+		UPDATE projects 
+		SET sync_issues = $1, sync_ci = $2, sync_deployments = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, project.SyncIssues, project.SyncCI, project.SyncDeployments, project.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
 // Delete performs a soft delete of a project
 func (r *ProjectRepository) Delete(id string) error {
 	query := `
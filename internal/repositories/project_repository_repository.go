@@ -81,10 +81,100 @@ func (r *ProjectRepositoryRepository) GetByProjectID(projectID string) ([]*model
 		}
 		projectRepos = append(projectRepos, projectRepo)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	groups, err := r.getGroupsByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, projectRepo := range projectRepos {
+		projectRepo.Groups = groups[projectRepo.ID]
+	}
 
 	return projectRepos, nil
 }
 
+// GetGroupNamesByProjectID retrieves the names of the repository groups of a project, sorted
+func (r *ProjectRepositoryRepository) GetGroupNamesByProjectID(projectID string) ([]string, error) {
+	query := `
+		SELECT DISTINCT g.name
+		FROM project_repository_groups g
+		JOIN project_repositories pr ON pr.id = g.project_repository_id
+		WHERE pr.project_id = ? AND pr.deleted_at IS NULL
+		ORDER BY g.name COLLATE NOCASE
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+// SetGroups replaces the repository groups a project repository is in
+func (r *ProjectRepositoryRepository) SetGroups(projectRepositoryID string, names []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM project_repository_groups WHERE project_repository_id = ?`, projectRepositoryID); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, err := tx.Exec(
+			`INSERT OR IGNORE INTO project_repository_groups (project_repository_id, name) VALUES (?, ?)`,
+			projectRepositoryID, name,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// getGroupsByProjectID retrieves the group names of the repositories of a project keyed by project repository ID
+func (r *ProjectRepositoryRepository) getGroupsByProjectID(projectID string) (map[string][]string, error) {
+	query := `
+		SELECT g.project_repository_id, g.name
+		FROM project_repository_groups g
+		JOIN project_repositories pr ON pr.id = g.project_repository_id
+		WHERE pr.project_id = ?
+		ORDER BY g.name COLLATE NOCASE
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := make(map[string][]string)
+	for rows.Next() {
+		var projectRepositoryID, name string
+		if err := rows.Scan(&projectRepositoryID, &name); err != nil {
+			return nil, err
+		}
+		groups[projectRepositoryID] = append(groups[projectRepositoryID], name)
+	}
+
+	return groups, rows.Err()
+}
+
 // UpdateLastAnalyzed updates the last_analyzed field for a project repository
 func (r *ProjectRepositoryRepository) UpdateLastAnalyzed(id string, lastAnalyzed *time.Time) error {
 	query := `
@@ -0,0 +1,282 @@
+package repositories
+
+import (
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+)
+
+type ProjectTeamRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewProjectTeamRepository(db *sql.DB) *ProjectTeamRepository {
+	return &ProjectTeamRepository{db: db}
+}
+
+const projectTeamColumns = `
+	pt.id, pt.project_id, pt.github_team_id, pt.name, pt.created_at, pt.updated_at,
+	gt.organization, gt.slug, gt.html_url
+`
+
+// Create creates a new project team
+func (r *ProjectTeamRepository) Create(team *models.ProjectTeam) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		INSERT INTO project_teams (id, project_id, github_team_id, name, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query, team.ID, team.ProjectID, team.GithubTeamID, team.Name, team.CreatedAt, team.UpdatedAt)
+
+	return err
+}
+
+// Update updates the name of a project team
+func (r *ProjectTeamRepository) Update(team *models.ProjectTeam) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	team.UpdatedAt = time.Now()
+
+	_, err := r.db.Exec(`UPDATE project_teams SET name = ?, updated_at = ? WHERE id = ?`, team.Name, team.UpdatedAt, team.ID)
+
+	return err
+}
+
+// Delete deletes a project team
+func (r *ProjectTeamRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, err := r.db.Exec(`DELETE FROM project_teams WHERE id = ?`, id)
+
+	return err
+}
+
+// GetByID retrieves a project team with its members
+func (r *ProjectTeamRepository) GetByID(id string) (*models.ProjectTeam, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT ` + projectTeamColumns + `
+		FROM project_teams pt
+		LEFT JOIN github_teams gt ON gt.id = pt.github_team_id
+		WHERE pt.id = ?
+	`
+
+	team, err := scanProjectTeam(r.db.QueryRow(query, id))
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := r.getMembers(`pt.id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	team.Members = members[team.ID]
+
+	return team, nil
+}
+
+// GetByProjectID retrieves the teams of a project with their members, ordered by name
+func (r *ProjectTeamRepository) GetByProjectID(projectID string) ([]*models.ProjectTeam, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT ` + projectTeamColumns + `
+		FROM project_teams pt
+		LEFT JOIN github_teams gt ON gt.id = pt.github_team_id
+		WHERE pt.project_id = ?
+		ORDER BY pt.name COLLATE NOCASE ASC
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []*models.ProjectTeam
+	for rows.Next() {
+		team, err := scanProjectTeam(rows)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	members, err := r.getMembers(`pt.project_id = ?`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range teams {
+		team.Members = members[team.ID]
+	}
+
+	return teams, nil
+}
+
+// GetByProjectAndGithubTeam retrieves the project team of a GitHub team
+func (r *ProjectTeamRepository) GetByProjectAndGithubTeam(projectID, githubTeamID string) (*models.ProjectTeam, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT ` + projectTeamColumns + `
+		FROM project_teams pt
+		LEFT JOIN github_teams gt ON gt.id = pt.github_team_id
+		WHERE pt.project_id = ? AND pt.github_team_id = ?
+	`
+
+	return scanProjectTeam(r.db.QueryRow(query, projectID, githubTeamID))
+}
+
+// DeleteGithubTeamsNotIn deletes the project teams synced from an organization whose GitHub team is
+// not among the given ones, and returns the number of deleted teams
+func (r *ProjectTeamRepository) DeleteGithubTeamsNotIn(projectID, organization string, githubTeamIDs []string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		DELETE FROM project_teams
+		WHERE project_id = ? AND github_team_id IN (SELECT id FROM github_teams WHERE organization = ?)
+	`
+	args := []interface{}{projectID, organization}
+	if len(githubTeamIDs) > 0 {
+		query += ` AND github_team_id NOT IN (?` + strings.Repeat(", ?", len(githubTeamIDs)-1) + `)`
+		for _, id := range githubTeamIDs {
+			args = append(args, id)
+		}
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
+
+// CreateMember creates a membership of a team defined in the project
+func (r *ProjectTeamRepository) CreateMember(member *models.ProjectTeamMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		INSERT INTO project_team_members (id, project_team_id, github_person_id, start_date, end_date, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		member.ID, member.ProjectTeamID, member.GithubPersonID, member.StartDate, member.EndDate,
+		member.CreatedAt, member.UpdatedAt,
+	)
+
+	return err
+}
+
+// UpdateMember updates the dates of a membership
+func (r *ProjectTeamRepository) UpdateMember(member *models.ProjectTeamMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member.UpdatedAt = time.Now()
+
+	query := `UPDATE project_team_members SET start_date = ?, end_date = ?, updated_at = ? WHERE id = ?`
+
+	_, err := r.db.Exec(query, member.StartDate, member.EndDate, member.UpdatedAt, member.ID)
+
+	return err
+}
+
+// DeleteMember deletes a membership
+func (r *ProjectTeamRepository) DeleteMember(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, err := r.db.Exec(`DELETE FROM project_team_members WHERE id = ?`, id)
+
+	return err
+}
+
+// getMembers returns the memberships of the matching teams by team ID: the members of the GitHub team
+// for synced teams, and the dated memberships for teams defined in the project
+func (r *ProjectTeamRepository) getMembers(where string, args ...interface{}) (map[string][]*models.ProjectTeamMember, error) {
+	members := make(map[string][]*models.ProjectTeamMember)
+
+	githubQuery := `
+		SELECT pt.id, gtm.github_person_id
+		FROM project_teams pt
+		JOIN github_team_members gtm ON gtm.github_team_id = pt.github_team_id
+		WHERE ` + where
+
+	rows, err := r.db.Query(githubQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		member := &models.ProjectTeamMember{}
+		if err := rows.Scan(&member.ProjectTeamID, &member.GithubPersonID); err != nil {
+			return nil, err
+		}
+		members[member.ProjectTeamID] = append(members[member.ProjectTeamID], member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	manualQuery := `
+		SELECT ptm.id, ptm.project_team_id, ptm.github_person_id, ptm.start_date, ptm.end_date, ptm.created_at, ptm.updated_at
+		FROM project_team_members ptm
+		JOIN project_teams pt ON pt.id = ptm.project_team_id
+		WHERE ` + where + `
+		ORDER BY ptm.start_date ASC
+	`
+
+	manualRows, err := r.db.Query(manualQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer manualRows.Close()
+
+	for manualRows.Next() {
+		member := &models.ProjectTeamMember{}
+		err := manualRows.Scan(
+			&member.ID, &member.ProjectTeamID, &member.GithubPersonID, &member.StartDate, &member.EndDate,
+			&member.CreatedAt, &member.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		members[member.ProjectTeamID] = append(members[member.ProjectTeamID], member)
+	}
+
+	return members, manualRows.Err()
+}
+
+func scanProjectTeam(scanner rowScanner) (*models.ProjectTeam, error) {
+	var team models.ProjectTeam
+	err := scanner.Scan(
+		&team.ID, &team.ProjectID, &team.GithubTeamID, &team.Name, &team.CreatedAt, &team.UpdatedAt,
+		&team.Organization, &team.Slug, &team.HTMLURL,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
@@ -22,12 +22,12 @@ func (r *ProjectUpdateSettingsRepository) Create(settings *models.ProjectUpdateS
 	defer r.mu.Unlock()
 
 	query := `
-		INSERT INTO project_update_settings (id, project_id, is_enabled, hour, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO project_update_settings (id, project_id, is_enabled, hour, repository_group, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := r.db.Exec(query,
-		settings.ID, settings.ProjectID, settings.IsEnabled, settings.Hour, settings.CreatedAt, settings.UpdatedAt,
+		settings.ID, settings.ProjectID, settings.IsEnabled, settings.Hour, settings.RepositoryGroup, settings.CreatedAt, settings.UpdatedAt,
 	)
 
 	return err
@@ -39,13 +39,13 @@ func (r *ProjectUpdateSettingsRepository) GetByID(id string) (*models.ProjectUpd
 	defer r.mu.RUnlock()
 
 	query := `
-		SELECT id, project_id, is_enabled, hour, created_at, updated_at, deleted_at
+		SELECT id, project_id, is_enabled, hour, repository_group, created_at, updated_at, deleted_at
 		FROM project_update_settings WHERE id = ? AND deleted_at IS NULL
 	`
 
 	var settings models.ProjectUpdateSettings
 	err := r.db.QueryRow(query, id).Scan(
-		&settings.ID, &settings.ProjectID, &settings.IsEnabled, &settings.Hour, &settings.CreatedAt, &settings.UpdatedAt, &settings.DeletedAt,
+		&settings.ID, &settings.ProjectID, &settings.IsEnabled, &settings.Hour, &settings.RepositoryGroup, &settings.CreatedAt, &settings.UpdatedAt, &settings.DeletedAt,
 	)
 
 	if err != nil {
@@ -61,13 +61,13 @@ func (r *ProjectUpdateSettingsRepository) GetByProjectID(projectID string) (*mod
 	defer r.mu.RUnlock()
 
 	query := `
-		SELECT id, project_id, is_enabled, hour, created_at, updated_at, deleted_at
+		SELECT id, project_id, is_enabled, hour, repository_group, created_at, updated_at, deleted_at
 		FROM project_update_settings WHERE project_id = ? AND deleted_at IS NULL
 	`
 
 	var settings models.ProjectUpdateSettings
 	err := r.db.QueryRow(query, projectID).Scan(
-		&settings.ID, &settings.ProjectID, &settings.IsEnabled, &settings.Hour, &settings.CreatedAt, &settings.UpdatedAt, &settings.DeletedAt,
+		&settings.ID, &settings.ProjectID, &settings.IsEnabled, &settings.Hour, &settings.RepositoryGroup, &settings.CreatedAt, &settings.UpdatedAt, &settings.DeletedAt,
 	)
 
 	if err != nil {
@@ -84,12 +84,12 @@ func (r *ProjectUpdateSettingsRepository) Update(settings *models.ProjectUpdateS
 
 	query := `
 		UPDATE project_update_settings
-		SET project_id = ?, is_enabled = ?, hour = ?, updated_at = ?
+		SET project_id = ?, is_enabled = ?, hour = ?, repository_group = ?, updated_at = ?
 		WHERE id = ? AND deleted_at IS NULL
 	`
 
 	_, err := r.db.Exec(query,
-		settings.ProjectID, settings.IsEnabled, settings.Hour, settings.UpdatedAt, settings.ID,
+		settings.ProjectID, settings.IsEnabled, settings.Hour, settings.RepositoryGroup, settings.UpdatedAt, settings.ID,
 	)
 
 	return err
@@ -137,7 +137,7 @@ func (r *ProjectUpdateSettingsRepository) GetAllEnabled() ([]*models.ProjectUpda
 	defer r.mu.RUnlock()
 
 	query := `
-		SELECT id, project_id, is_enabled, hour, created_at, updated_at, deleted_at
+		SELECT id, project_id, is_enabled, hour, repository_group, created_at, updated_at, deleted_at
 		FROM project_update_settings
 		WHERE is_enabled = 1 AND deleted_at IS NULL
 		ORDER BY hour ASC
@@ -153,7 +153,7 @@ func (r *ProjectUpdateSettingsRepository) GetAllEnabled() ([]*models.ProjectUpda
 	for rows.Next() {
 		var setting models.ProjectUpdateSettings
 		err := rows.Scan(
-			&setting.ID, &setting.ProjectID, &setting.IsEnabled, &setting.Hour, &setting.CreatedAt, &setting.UpdatedAt, &setting.DeletedAt,
+			&setting.ID, &setting.ProjectID, &setting.IsEnabled, &setting.Hour, &setting.RepositoryGroup, &setting.CreatedAt, &setting.UpdatedAt, &setting.DeletedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -18,6 +18,25 @@ func NewPullRequestRepository(db *sql.DB) *PullRequestRepository {
 	return &PullRequestRepository{db: db}
 }
 
+// scanPullRequest scans a row selected with SELECT *, whose columns follow the order of the table
+// including the size, author, merger and head SHA columns added by later migrations
+func scanPullRequest(scanner rowScanner) (*models.PullRequest, error) {
+	var pr models.PullRequest
+	err := scanner.Scan(
+		&pr.ID, &pr.RepositoryID, &pr.GithubPRNumber, &pr.GithubPRID, &pr.Title, &pr.Body,
+		&pr.State, &pr.MergedAt, &pr.MergeCommitSHA, &pr.ClosedAt, &pr.User,
+		&pr.RequestedReviewers, &pr.RequestedTeams, &pr.Draft, &pr.GithubCreatedAt,
+		&pr.GithubUpdatedAt, &pr.CreatedAt, &pr.UpdatedAt,
+		&pr.Additions, &pr.Deletions, &pr.ChangedFiles, &pr.CommitCount, &pr.AuthorID, &pr.MergedByID,
+		&pr.HeadSHA, &pr.DetailsFailedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
 func (r *PullRequestRepository) Create(pr *models.PullRequest) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -29,15 +48,15 @@ func (r *PullRequestRepository) Create(pr *models.PullRequest) error {
 			id, repository_id, github_pr_number, github_pr_id, title, body, 
 			state, merged_at, merge_commit_sha, closed_at, user, 
 			requested_reviewers, requested_teams, draft, github_created_at, 
-			github_updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			github_updated_at, additions, deletions, changed_files, commit_count, author_id, merged_by_id, head_sha
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := r.db.Exec(query,
 		pr.ID, pr.RepositoryID, pr.GithubPRNumber, pr.GithubPRID, pr.Title, pr.Body,
 		pr.State, pr.MergedAt, pr.MergeCommitSHA, pr.ClosedAt, pr.User,
 		pr.RequestedReviewers, pr.RequestedTeams, pr.Draft, pr.GithubCreatedAt,
-		pr.GithubUpdatedAt,
+		pr.GithubUpdatedAt, pr.Additions, pr.Deletions, pr.ChangedFiles, pr.CommitCount, pr.AuthorID, pr.MergedByID, pr.HeadSHA,
 	)
 
 	return err
@@ -46,25 +65,41 @@ func (r *PullRequestRepository) Create(pr *models.PullRequest) error {
 func (r *PullRequestRepository) GetByID(id string) (*models.PullRequest, error) {
 	query := `SELECT * FROM pull_requests WHERE id = ?`
 
-	var pr models.PullRequest
-	err := r.db.QueryRow(query, id).Scan(
-		&pr.ID, &pr.RepositoryID, &pr.GithubPRNumber, &pr.GithubPRID, &pr.Title, &pr.Body,
-		&pr.State, &pr.MergedAt, &pr.MergeCommitSHA, &pr.ClosedAt, &pr.User,
-		&pr.RequestedReviewers, &pr.RequestedTeams, &pr.Draft, &pr.GithubCreatedAt,
-		&pr.GithubUpdatedAt, &pr.CreatedAt, &pr.UpdatedAt,
-	)
+	return scanPullRequest(r.db.QueryRow(query, id))
+}
+
+func (r *PullRequestRepository) GetByRepositoryID(repositoryID string) ([]*models.PullRequest, error) {
+	query := `SELECT * FROM pull_requests WHERE repository_id = ? ORDER BY github_pr_number DESC`
 
+	rows, err := r.db.Query(query, repositoryID)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	var pullRequests []*models.PullRequest
+	for rows.Next() {
+		pr, err := scanPullRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		pullRequests = append(pullRequests, pr)
+	}
 
-	return &pr, nil
+	return pullRequests, nil
 }
 
-func (r *PullRequestRepository) GetByRepositoryID(repositoryID string) ([]*models.PullRequest, error) {
-	query := `SELECT * FROM pull_requests WHERE repository_id = ? ORDER BY github_pr_number DESC`
+// GetByProjectID retrieves the pull requests of all repositories of a project
+func (r *PullRequestRepository) GetByProjectID(projectID string) ([]*models.PullRequest, error) {
+	query := `
+		SELECT * FROM pull_requests
+		WHERE repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+		ORDER BY github_created_at
+	`
 
-	rows, err := r.db.Query(query, repositoryID)
+	rows, err := r.db.Query(query, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -72,17 +107,39 @@ func (r *PullRequestRepository) GetByRepositoryID(repositoryID string) ([]*model
 
 	var pullRequests []*models.PullRequest
 	for rows.Next() {
-		var pr models.PullRequest
-		err := rows.Scan(
-			&pr.ID, &pr.RepositoryID, &pr.GithubPRNumber, &pr.GithubPRID, &pr.Title, &pr.Body,
-			&pr.State, &pr.MergedAt, &pr.MergeCommitSHA, &pr.ClosedAt, &pr.User,
-			&pr.RequestedReviewers, &pr.RequestedTeams, &pr.Draft, &pr.GithubCreatedAt,
-			&pr.GithubUpdatedAt, &pr.CreatedAt, &pr.UpdatedAt,
+		pr, err := scanPullRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		pullRequests = append(pullRequests, pr)
+	}
+
+	return pullRequests, nil
+}
+
+// GetOpenByProjectID retrieves the open pull requests of a project, oldest first
+func (r *PullRequestRepository) GetOpenByProjectID(projectID string) ([]*models.PullRequest, error) {
+	query := `
+		SELECT * FROM pull_requests
+		WHERE state = 'open' AND repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
 		)
+		ORDER BY github_created_at
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pullRequests []*models.PullRequest
+	for rows.Next() {
+		pr, err := scanPullRequest(rows)
 		if err != nil {
 			return nil, err
 		}
-		pullRequests = append(pullRequests, &pr)
+		pullRequests = append(pullRequests, pr)
 	}
 
 	return pullRequests, nil
@@ -91,28 +148,27 @@ func (r *PullRequestRepository) GetByRepositoryID(repositoryID string) ([]*model
 func (r *PullRequestRepository) GetByGithubPRID(githubPRID int) (*models.PullRequest, error) {
 	query := `SELECT * FROM pull_requests WHERE github_pr_id = ?`
 
-	var pr models.PullRequest
-	err := r.db.QueryRow(query, githubPRID).Scan(
-		&pr.ID, &pr.RepositoryID, &pr.GithubPRNumber, &pr.GithubPRID, &pr.Title, &pr.Body,
-		&pr.State, &pr.MergedAt, &pr.MergeCommitSHA, &pr.ClosedAt, &pr.User,
-		&pr.RequestedReviewers, &pr.RequestedTeams, &pr.Draft, &pr.GithubCreatedAt,
-		&pr.GithubUpdatedAt, &pr.CreatedAt, &pr.UpdatedAt,
-	)
+	return scanPullRequest(r.db.QueryRow(query, githubPRID))
+}
 
-	if err != nil {
-		return nil, err
-	}
+// GetByRepositoryAndNumber retrieves a pull request by its number within a repository
+func (r *PullRequestRepository) GetByRepositoryAndNumber(repositoryID string, number int) (*models.PullRequest, error) {
+	query := `SELECT * FROM pull_requests WHERE repository_id = ? AND github_pr_number = ?`
 
-	return &pr, nil
+	return scanPullRequest(r.db.QueryRow(query, repositoryID, number))
 }
 
+// Update updates a pull request. Size, author, merger and head SHA columns keep their stored values when the update doesn't carry them.
 func (r *PullRequestRepository) Update(pr *models.PullRequest) error {
 	query := `
 		UPDATE pull_requests SET 
 			repository_id = ?, github_pr_number = ?, github_pr_id = ?, title = ?, body = ?,
 			state = ?, merged_at = ?, merge_commit_sha = ?, closed_at = ?, user = ?,
 			requested_reviewers = ?, requested_teams = ?, draft = ?, github_created_at = ?,
-			github_updated_at = ?
+			github_updated_at = ?, additions = COALESCE(?, additions), deletions = COALESCE(?, deletions),
+			changed_files = COALESCE(?, changed_files), commit_count = COALESCE(?, commit_count),
+			author_id = COALESCE(?, author_id), merged_by_id = COALESCE(?, merged_by_id),
+			head_sha = COALESCE(?, head_sha)
 		WHERE id = ?
 	`
 
@@ -120,12 +176,60 @@ func (r *PullRequestRepository) Update(pr *models.PullRequest) error {
 		pr.RepositoryID, pr.GithubPRNumber, pr.GithubPRID, pr.Title, pr.Body,
 		pr.State, pr.MergedAt, pr.MergeCommitSHA, pr.ClosedAt, pr.User,
 		pr.RequestedReviewers, pr.RequestedTeams, pr.Draft, pr.GithubCreatedAt,
-		pr.GithubUpdatedAt, pr.ID,
+		pr.GithubUpdatedAt, pr.Additions, pr.Deletions, pr.ChangedFiles, pr.CommitCount, pr.AuthorID, pr.MergedByID, pr.HeadSHA, pr.ID,
 	)
 
 	return err
 }
 
+// GetMissingDetailsByRepositoryID gets up to limit pull requests of a repository that were stored before their
+// size was fetched. Size, merger and draft transitions are fetched together, so these lack all of them. Pull
+// requests whose details failed to be fetched since retryFailedBefore are left out.
+func (r *PullRequestRepository) GetMissingDetailsByRepositoryID(repositoryID string, retryFailedBefore time.Time, limit int) ([]*models.PullRequest, error) {
+	query := `
+		SELECT * FROM pull_requests
+		WHERE repository_id = ? AND additions IS NULL AND (details_failed_at IS NULL OR details_failed_at < ?)
+		ORDER BY details_failed_at IS NOT NULL, github_pr_number
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, repositoryID, retryFailedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var pullRequests []*models.PullRequest
+	for rows.Next() {
+		pr, err := scanPullRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		pullRequests = append(pullRequests, pr)
+	}
+
+	return pullRequests, rows.Err()
+}
+
+// UpdateDetails updates the size and merger of a pull request, keeping the stored merger when the update has none
+func (r *PullRequestRepository) UpdateDetails(pr *models.PullRequest) error {
+	query := `
+		UPDATE pull_requests SET
+			additions = ?, deletions = ?, changed_files = ?, commit_count = ?,
+			merged_by_id = COALESCE(?, merged_by_id), details_failed_at = NULL
+		WHERE id = ?
+	`
+
+	_, err := r.db.Exec(query, pr.Additions, pr.Deletions, pr.ChangedFiles, pr.CommitCount, pr.MergedByID, pr.ID)
+	return err
+}
+
+// MarkDetailsFailed records that fetching the size of a pull request stored without it failed
+func (r *PullRequestRepository) MarkDetailsFailed(id string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE pull_requests SET details_failed_at = ? WHERE id = ?`, at, id)
+	return err
+}
+
 func (r *PullRequestRepository) Delete(id string) error {
 	query := `DELETE FROM pull_requests WHERE id = ?`
 	_, err := r.db.Exec(query, id)
@@ -218,7 +322,7 @@ func (r *PullRequestRepository) GetOpenPRNumbersByRepositoryID(repositoryID stri
 	return prNumbers, nil
 }
 
-// GetByProjectAndPerson retrieves PRs for a specific person in a project
+// GetByProjectAndPerson retrieves the PRs a specific person opened in a project
 func (r *PullRequestRepository) GetByProjectAndPerson(projectID, githubPersonID string) ([]*models.PullRequest, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -226,11 +330,8 @@ func (r *PullRequestRepository) GetByProjectAndPerson(projectID, githubPersonID
 	query := `
 		SELECT pr.*
 		FROM pull_requests pr
-		INNER JOIN github_repositories gr ON pr.repository_id = gr.id
-		INNER JOIN project_repositories prj ON gr.id = prj.github_repo_id
-		INNER JOIN github_people_emails gpe ON gpe.project_id = prj.project_id
-		INNER JOIN github_people gp ON gpe.github_person_id = gp.id
-		WHERE prj.project_id = ? AND gpe.github_person_id = ?
+		INNER JOIN project_repositories prj ON pr.repository_id = prj.github_repo_id
+		WHERE prj.project_id = ? AND pr.author_id = ?
 		ORDER BY pr.github_created_at DESC
 	`
 
@@ -242,18 +343,74 @@ func (r *PullRequestRepository) GetByProjectAndPerson(projectID, githubPersonID
 
 	var pullRequests []*models.PullRequest
 	for rows.Next() {
-		var pr models.PullRequest
-		err := rows.Scan(
-			&pr.ID, &pr.RepositoryID, &pr.GithubPRNumber, &pr.GithubPRID, &pr.Title, &pr.Body,
-			&pr.State, &pr.MergedAt, &pr.MergeCommitSHA, &pr.ClosedAt, &pr.User,
-			&pr.RequestedReviewers, &pr.RequestedTeams, &pr.Draft, &pr.GithubCreatedAt,
-			&pr.GithubUpdatedAt, &pr.CreatedAt, &pr.UpdatedAt,
-		)
+		pr, err := scanPullRequest(rows)
 		if err != nil {
 			return nil, err
 		}
-		pullRequests = append(pullRequests, &pr)
+		pullRequests = append(pullRequests, pr)
 	}
 
 	return pullRequests, nil
 }
+
+// ReplaceLabels replaces the labels of a pull request
+func (r *PullRequestRepository) ReplaceLabels(pullRequestID string, labels []*models.PRLabel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM pr_labels WHERE pull_request_id = ?`, pullRequestID); err != nil {
+		return err
+	}
+	for _, label := range labels {
+		label.ID = uuid.New().String()
+		label.PullRequestID = pullRequestID
+		_, err := tx.Exec(`
+			INSERT INTO pr_labels (id, pull_request_id, name, color) VALUES (?, ?, ?, ?)
+			ON CONFLICT(pull_request_id, name) DO NOTHING
+		`, label.ID, label.PullRequestID, label.Name, label.Color)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetLabelsByProjectID retrieves the labels of the pull requests of all repositories of a project
+func (r *PullRequestRepository) GetLabelsByProjectID(projectID string) ([]*models.PRLabel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT l.id, l.pull_request_id, l.name, l.color
+		FROM pr_labels l
+		JOIN pull_requests p ON p.id = l.pull_request_id
+		WHERE p.repository_id IN (
+			SELECT github_repo_id FROM project_repositories WHERE project_id = ? AND deleted_at IS NULL
+		)
+		ORDER BY l.name
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []*models.PRLabel
+	for rows.Next() {
+		var label models.PRLabel
+		if err := rows.Scan(&label.ID, &label.PullRequestID, &label.Name, &label.Color); err != nil {
+			return nil, err
+		}
+		labels = append(labels, &label)
+	}
+
+	return labels, rows.Err()
+}
@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/alimgiray/gscope/internal/models"
+)
+
+type RepositoryDiscoveryRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewRepositoryDiscoveryRepository(db *sql.DB) *RepositoryDiscoveryRepository {
+	return &RepositoryDiscoveryRepository{db: db}
+}
+
+// CreateSource creates a new discovery source
+func (r *RepositoryDiscoveryRepository) CreateSource(source *models.RepositoryDiscoverySource) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		INSERT INTO repository_discovery_sources (id, project_id, organization, team_slug, auto_track, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		source.ID, source.ProjectID, source.Organization, source.TeamSlug, source.AutoTrack, source.CreatedAt, source.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetSourcesByProjectID retrieves all discovery sources for a project
+func (r *RepositoryDiscoveryRepository) GetSourcesByProjectID(projectID string) ([]*models.RepositoryDiscoverySource, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT id, project_id, organization, team_slug, auto_track, created_at, updated_at, deleted_at
+		FROM repository_discovery_sources
+		WHERE project_id = ? AND deleted_at IS NULL
+		ORDER BY organization ASC, team_slug ASC
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sources []*models.RepositoryDiscoverySource
+	for rows.Next() {
+		var source models.RepositoryDiscoverySource
+		err := rows.Scan(
+			&source.ID, &source.ProjectID, &source.Organization, &source.TeamSlug, &source.AutoTrack,
+			&source.CreatedAt, &source.UpdatedAt, &source.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, &source)
+	}
+
+	return sources, nil
+}
+
+// ExistsSource checks if a discovery source already exists for a project
+func (r *RepositoryDiscoveryRepository) ExistsSource(projectID, organization string, teamSlug *string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT COUNT(*) FROM repository_discovery_sources
+		WHERE project_id = ? AND organization = ? AND COALESCE(team_slug, '') = COALESCE(?, '') AND deleted_at IS NULL
+	`
+
+	var count int
+	err := r.db.QueryRow(query, projectID, organization, teamSlug).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// DeleteSource soft deletes a discovery source belonging to a project
+func (r *RepositoryDiscoveryRepository) DeleteSource(projectID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `UPDATE repository_discovery_sources SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND project_id = ?`
+	_, err := r.db.Exec(query, id, projectID)
+	return err
+}
+
+// CreateRule creates a new discovery rule
+func (r *RepositoryDiscoveryRepository) CreateRule(rule *models.RepositoryDiscoveryRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		INSERT INTO repository_discovery_rules (id, project_id, action, field, value, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		rule.ID, rule.ProjectID, rule.Action, rule.Field, rule.Value, rule.CreatedAt, rule.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetRulesByProjectID retrieves all discovery rules for a project
+func (r *RepositoryDiscoveryRepository) GetRulesByProjectID(projectID string) ([]*models.RepositoryDiscoveryRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT id, project_id, action, field, value, created_at, updated_at, deleted_at
+		FROM repository_discovery_rules
+		WHERE project_id = ? AND deleted_at IS NULL
+		ORDER BY action ASC, field ASC, value ASC
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*models.RepositoryDiscoveryRule
+	for rows.Next() {
+		var rule models.RepositoryDiscoveryRule
+		err := rows.Scan(
+			&rule.ID, &rule.ProjectID, &rule.Action, &rule.Field, &rule.Value,
+			&rule.CreatedAt, &rule.UpdatedAt, &rule.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+
+	return rules, nil
+}
+
+// DeleteRule soft deletes a discovery rule belonging to a project
+func (r *RepositoryDiscoveryRepository) DeleteRule(projectID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `UPDATE repository_discovery_rules SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND project_id = ?`
+	_, err := r.db.Exec(query, id, projectID)
+	return err
+}
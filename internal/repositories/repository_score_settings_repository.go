@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"github.com/alimgiray/gscope/internal/models"
+)
+
+type RepositoryScoreSettingsRepository struct {
+	db *sql.DB
+}
+
+func NewRepositoryScoreSettingsRepository(db *sql.DB) *RepositoryScoreSettingsRepository {
+	return &RepositoryScoreSettingsRepository{db: db}
+}
+
+const repositoryScoreSettingsColumns = `
+	rss.id, rss.project_repository_id, rss.multiplier, rss.override_weights,
+	rss.additions, rss.deletions, rss.commits, rss.pull_requests, rss.comments,
+	rss.review_comments, rss.issue_comments, rss.issues_opened, rss.issues_closed,
+	rss.created_at, rss.updated_at
+`
+
+// Upsert creates or replaces the score settings of a project repository
+func (r *RepositoryScoreSettingsRepository) Upsert(settings *models.RepositoryScoreSettings) error {
+	query := `
+		INSERT INTO repository_score_settings (
+			id, project_repository_id, multiplier, override_weights,
+			additions, deletions, commits, pull_requests, comments,
+			review_comments, issue_comments, issues_opened, issues_closed,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(project_repository_id) DO UPDATE SET
+			multiplier = EXCLUDED.multiplier,
+			override_weights = EXCLUDED.override_weights,
+			additions = EXCLUDED.additions,
+			deletions = EXCLUDED.deletions,
+			commits = EXCLUDED.commits,
+			pull_requests = EXCLUDED.pull_requests,
+			comments = EXCLUDED.comments,
+			review_comments = EXCLUDED.review_comments,
+			issue_comments = EXCLUDED.issue_comments,
+			issues_opened = EXCLUDED.issues_opened,
+			issues_closed = EXCLUDED.issues_closed
+	`
+
+	_, err := r.db.Exec(query,
+		settings.ID, settings.ProjectRepositoryID, settings.Multiplier, settings.OverrideWeights,
+		settings.Additions, settings.Deletions, settings.Commits, settings.PullRequests, settings.Comments,
+		settings.ReviewComments, settings.IssueComments, settings.IssuesOpened, settings.IssuesClosed,
+		settings.CreatedAt, settings.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByProjectRepositoryID retrieves the score settings of a project repository
+func (r *RepositoryScoreSettingsRepository) GetByProjectRepositoryID(projectRepositoryID string) (*models.RepositoryScoreSettings, error) {
+	query := `SELECT ` + repositoryScoreSettingsColumns + `
+		FROM repository_score_settings rss
+		WHERE rss.project_repository_id = ?
+	`
+
+	return scanRepositoryScoreSettings(r.db.QueryRow(query, projectRepositoryID))
+}
+
+// GetByProjectID retrieves the score settings of the repositories of a project
+func (r *RepositoryScoreSettingsRepository) GetByProjectID(projectID string) ([]*models.RepositoryScoreSettings, error) {
+	query := `SELECT ` + repositoryScoreSettingsColumns + `
+		FROM repository_score_settings rss
+		JOIN project_repositories pr ON pr.id = rss.project_repository_id
+		WHERE pr.project_id = ? AND pr.deleted_at IS NULL
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settings []*models.RepositoryScoreSettings
+	for rows.Next() {
+		setting, err := scanRepositoryScoreSettings(rows)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, setting)
+	}
+
+	return settings, rows.Err()
+}
+
+// DeleteByProjectRepositoryID deletes the score settings of a project repository
+func (r *RepositoryScoreSettingsRepository) DeleteByProjectRepositoryID(projectRepositoryID string) error {
+	_, err := r.db.Exec(`DELETE FROM repository_score_settings WHERE project_repository_id = ?`, projectRepositoryID)
+	return err
+}
+
+// scanRepositoryScoreSettings scans a row selected with repositoryScoreSettingsColumns
+func scanRepositoryScoreSettings(row rowScanner) (*models.RepositoryScoreSettings, error) {
+	settings := &models.RepositoryScoreSettings{}
+	err := row.Scan(
+		&settings.ID, &settings.ProjectRepositoryID, &settings.Multiplier, &settings.OverrideWeights,
+		&settings.Additions, &settings.Deletions, &settings.Commits, &settings.PullRequests, &settings.Comments,
+		&settings.ReviewComments, &settings.IssueComments, &settings.IssuesOpened, &settings.IssuesClosed,
+		&settings.CreatedAt, &settings.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"database/sql"
+
+	"github.com/alimgiray/gscope/internal/models"
+)
+
+type ScoreFormulaRepository struct {
+	db *sql.DB
+}
+
+func NewScoreFormulaRepository(db *sql.DB) *ScoreFormulaRepository {
+	return &ScoreFormulaRepository{db: db}
+}
+
+const scoreFormulaColumns = `
+	sf.id, sf.project_id, sf.version, sf.expression, sf.note, sf.is_active, sf.created_by,
+	COALESCE(u.name, ''), sf.created_at
+`
+
+// Create stores a score formula as the next version of its project's formula
+func (r *ScoreFormulaRepository) Create(formula *models.ScoreFormula) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRow(
+		`SELECT COALESCE(MAX(version), 0) + 1 FROM score_formulas WHERE project_id = ?`, formula.ProjectID,
+	).Scan(&formula.Version); err != nil {
+		return err
+	}
+
+	if formula.IsActive {
+		if _, err := tx.Exec(`UPDATE score_formulas SET is_active = FALSE WHERE project_id = ?`, formula.ProjectID); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO score_formulas (id, project_id, version, expression, note, is_active, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	if _, err := tx.Exec(query,
+		formula.ID, formula.ProjectID, formula.Version, formula.Expression, formula.Note,
+		formula.IsActive, formula.CreatedBy, formula.CreatedAt,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetByID retrieves a score formula by ID
+func (r *ScoreFormulaRepository) GetByID(id string) (*models.ScoreFormula, error) {
+	query := `SELECT ` + scoreFormulaColumns + `
+		FROM score_formulas sf
+		LEFT JOIN users u ON u.id = sf.created_by
+		WHERE sf.id = ?
+	`
+
+	return scanScoreFormula(r.db.QueryRow(query, id))
+}
+
+// GetActiveByProjectID retrieves the active score formula of a project
+func (r *ScoreFormulaRepository) GetActiveByProjectID(projectID string) (*models.ScoreFormula, error) {
+	query := `SELECT ` + scoreFormulaColumns + `
+		FROM score_formulas sf
+		LEFT JOIN users u ON u.id = sf.created_by
+		WHERE sf.project_id = ? AND sf.is_active
+	`
+
+	return scanScoreFormula(r.db.QueryRow(query, projectID))
+}
+
+// GetByProjectID retrieves the versions of the score formula of a project, newest first
+func (r *ScoreFormulaRepository) GetByProjectID(projectID string) ([]*models.ScoreFormula, error) {
+	query := `SELECT ` + scoreFormulaColumns + `
+		FROM score_formulas sf
+		LEFT JOIN users u ON u.id = sf.created_by
+		WHERE sf.project_id = ?
+		ORDER BY sf.version DESC
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var formulas []*models.ScoreFormula
+	for rows.Next() {
+		formula, err := scanScoreFormula(rows)
+		if err != nil {
+			return nil, err
+		}
+		formulas = append(formulas, formula)
+	}
+
+	return formulas, rows.Err()
+}
+
+// SetActive makes a version the active score formula of a project, or deactivates the project's formula
+// when formulaID is empty
+func (r *ScoreFormulaRepository) SetActive(projectID, formulaID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE score_formulas SET is_active = FALSE WHERE project_id = ?`, projectID); err != nil {
+		return err
+	}
+
+	if formulaID != "" {
+		result, err := tx.Exec(`UPDATE score_formulas SET is_active = TRUE WHERE project_id = ? AND id = ?`, projectID, formulaID)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return sql.ErrNoRows
+		}
+	}
+
+	return tx.Commit()
+}
+
+// scanScoreFormula scans a row selected with scoreFormulaColumns
+func scanScoreFormula(scanner rowScanner) (*models.ScoreFormula, error) {
+	formula := &models.ScoreFormula{}
+	var createdBy sql.NullString
+	err := scanner.Scan(
+		&formula.ID, &formula.ProjectID, &formula.Version, &formula.Expression, &formula.Note, &formula.IsActive,
+		&createdBy, &formula.CreatedByName, &formula.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if createdBy.Valid {
+		formula.CreatedBy = &createdBy.String
+	}
+	return formula, nil
+}
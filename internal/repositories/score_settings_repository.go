@@ -19,8 +19,8 @@ func NewScoreSettingsRepository(db *sql.DB) *ScoreSettingsRepository {
 // Create creates new score settings for a project
 func (r *ScoreSettingsRepository) Create(settings *models.ScoreSettings) error {
 	query := `
-		INSERT INTO score_settings (id, project_id, additions, deletions, commits, pull_requests, comments)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO score_settings (id, project_id, additions, deletions, commits, pull_requests, comments, review_comments, issue_comments, issues_opened, issues_closed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
 	_, err := r.db.Exec(query,
@@ -31,6 +31,10 @@ func (r *ScoreSettingsRepository) Create(settings *models.ScoreSettings) error {
 		settings.Commits,
 		settings.PullRequests,
 		settings.Comments,
+		settings.ReviewComments,
+		settings.IssueComments,
+		settings.IssuesOpened,
+		settings.IssuesClosed,
 	)
 
 	return err
@@ -39,7 +43,7 @@ func (r *ScoreSettingsRepository) Create(settings *models.ScoreSettings) error {
 // GetByProjectID retrieves score settings for a project
 func (r *ScoreSettingsRepository) GetByProjectID(projectID string) (*models.ScoreSettings, error) {
 	query := `
-		SELECT id, project_id, additions, deletions, commits, pull_requests, comments, created_at, updated_at
+		SELECT id, project_id, additions, deletions, commits, pull_requests, comments, review_comments, issue_comments, issues_opened, issues_closed, created_at, updated_at
 		FROM score_settings 
 		WHERE project_id = $1
 	`
@@ -53,6 +57,10 @@ func (r *ScoreSettingsRepository) GetByProjectID(projectID string) (*models.Scor
 		&settings.Commits,
 		&settings.PullRequests,
 		&settings.Comments,
+		&settings.ReviewComments,
+		&settings.IssueComments,
+		&settings.IssuesOpened,
+		&settings.IssuesClosed,
 		&settings.CreatedAt,
 		&settings.UpdatedAt,
 	)
@@ -68,8 +76,10 @@ func (r *ScoreSettingsRepository) GetByProjectID(projectID string) (*models.Scor
 func (r *ScoreSettingsRepository) Update(settings *models.ScoreSettings) error {
 	query := `
 		UPDATE score_settings 
-		SET additions = $1, deletions = $2, commits = $3, pull_requests = $4, comments = $5, updated_at = CURRENT_TIMESTAMP
-		WHERE project_id = $6
+		SET additions = $1, deletions = $2, commits = $3, pull_requests = $4, comments = $5,
+			review_comments = $6, issue_comments = $7, issues_opened = $8, issues_closed = $9,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE project_id = $10
 	`
 
 	result, err := r.db.Exec(query,
@@ -78,6 +88,10 @@ func (r *ScoreSettingsRepository) Update(settings *models.ScoreSettings) error {
 		settings.Commits,
 		settings.PullRequests,
 		settings.Comments,
+		settings.ReviewComments,
+		settings.IssueComments,
+		settings.IssuesOpened,
+		settings.IssuesClosed,
 		settings.ProjectID,
 	)
 
@@ -117,3 +131,131 @@ func (r *ScoreSettingsRepository) Delete(projectID string) error {
 
 	return nil
 }
+
+const scoreSettingsVersionColumns = `
+	v.id, v.project_id, v.version, v.effective_from, v.additions, v.deletions, v.commits, v.pull_requests,
+	v.comments, v.review_comments, v.issue_comments, v.issues_opened, v.issues_closed, v.score_formula_id,
+	v.changed_by, v.recalculation_job_id, v.created_at, COALESCE(u.name, ''), COALESCE(f.version, 0),
+	COALESCE(j.status, ''), COALESCE(j.error_message, '')
+`
+
+// CreateVersion stores a version of the score settings of a project as its next one and makes its
+// weights the project's current score settings
+func (r *ScoreSettingsRepository) CreateVersion(version *models.ScoreSettingsVersion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := tx.QueryRow(
+		`SELECT COALESCE(MAX(version), 0) + 1 FROM score_settings_versions WHERE project_id = ?`, version.ProjectID,
+	).Scan(&version.Version); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO score_settings_versions (id, project_id, version, effective_from, additions, deletions, commits,
+			pull_requests, comments, review_comments, issue_comments, issues_opened, issues_closed, score_formula_id,
+			changed_by, recalculation_job_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	if _, err := tx.Exec(query,
+		version.ID, version.ProjectID, version.Version, version.EffectiveFrom, version.Additions, version.Deletions,
+		version.Commits, version.PullRequests, version.Comments, version.ReviewComments, version.IssueComments,
+		version.IssuesOpened, version.IssuesClosed, version.ScoreFormulaID, version.ChangedBy,
+		version.RecalculationJobID, version.CreatedAt,
+	); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE score_settings
+		SET additions = ?, deletions = ?, commits = ?, pull_requests = ?, comments = ?,
+			review_comments = ?, issue_comments = ?, issues_opened = ?, issues_closed = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE project_id = ?
+	`,
+		version.Additions, version.Deletions, version.Commits, version.PullRequests, version.Comments,
+		version.ReviewComments, version.IssueComments, version.IssuesOpened, version.IssuesClosed,
+		version.ProjectID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// SetVersionRecalculationJob records the stats job that recalculates the statistics after a version
+func (r *ScoreSettingsRepository) SetVersionRecalculationJob(versionID, jobID string) error {
+	_, err := r.db.Exec(`UPDATE score_settings_versions SET recalculation_job_id = ? WHERE id = ?`, jobID, versionID)
+	return err
+}
+
+// GetVersionsByProjectID retrieves the versions of the score settings of a project, oldest first
+func (r *ScoreSettingsRepository) GetVersionsByProjectID(projectID string) ([]*models.ScoreSettingsVersion, error) {
+	query := `SELECT ` + scoreSettingsVersionColumns + `
+		FROM score_settings_versions v
+		LEFT JOIN users u ON u.id = v.changed_by
+		LEFT JOIN score_formulas f ON f.id = v.score_formula_id
+		LEFT JOIN jobs j ON j.id = v.recalculation_job_id
+		WHERE v.project_id = ?
+		ORDER BY v.version ASC
+	`
+
+	rows, err := r.db.Query(query, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []*models.ScoreSettingsVersion
+	for rows.Next() {
+		version, err := scanScoreSettingsVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+func scanScoreSettingsVersion(scanner rowScanner) (*models.ScoreSettingsVersion, error) {
+	version := &models.ScoreSettingsVersion{}
+	var effectiveFrom sql.NullTime
+	var scoreFormulaID, changedBy, recalculationJobID sql.NullString
+	var recalculationStatus string
+	err := scanner.Scan(
+		&version.ID, &version.ProjectID, &version.Version, &effectiveFrom, &version.Additions, &version.Deletions,
+		&version.Commits, &version.PullRequests, &version.Comments, &version.ReviewComments, &version.IssueComments,
+		&version.IssuesOpened, &version.IssuesClosed, &scoreFormulaID, &changedBy, &recalculationJobID,
+		&version.CreatedAt, &version.ChangedByName, &version.ScoreFormulaVersion, &recalculationStatus,
+		&version.RecalculationError,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if effectiveFrom.Valid {
+		version.EffectiveFrom = &effectiveFrom.Time
+	}
+	if scoreFormulaID.Valid {
+		version.ScoreFormulaID = &scoreFormulaID.String
+	}
+	if changedBy.Valid {
+		version.ChangedBy = &changedBy.String
+	}
+	if recalculationJobID.Valid {
+		version.RecalculationJobID = &recalculationJobID.String
+	}
+	version.RecalculationStatus = models.JobStatus(recalculationStatus)
+	return version, nil
+}
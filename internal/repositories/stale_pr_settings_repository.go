@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/alimgiray/gscope/internal/models"
+)
+
+type StalePRSettingsRepository struct {
+	db *sql.DB
+	mu sync.RWMutex
+}
+
+func NewStalePRSettingsRepository(db *sql.DB) *StalePRSettingsRepository {
+	return &StalePRSettingsRepository{db: db}
+}
+
+// Create creates new stale pull request settings
+func (r *StalePRSettingsRepository) Create(settings *models.StalePRSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		INSERT INTO stale_pr_settings (id, project_id, max_age_days, review_sla_hours, draft_idle_days, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.Exec(query,
+		settings.ID, settings.ProjectID, settings.MaxAgeDays, settings.ReviewSLAHours, settings.DraftIdleDays,
+		settings.CreatedAt, settings.UpdatedAt,
+	)
+
+	return err
+}
+
+// GetByProjectID retrieves the stale pull request settings of a project
+func (r *StalePRSettingsRepository) GetByProjectID(projectID string) (*models.StalePRSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := `
+		SELECT id, project_id, max_age_days, review_sla_hours, draft_idle_days, created_at, updated_at
+		FROM stale_pr_settings WHERE project_id = ?
+	`
+
+	var settings models.StalePRSettings
+	err := r.db.QueryRow(query, projectID).Scan(
+		&settings.ID, &settings.ProjectID, &settings.MaxAgeDays, &settings.ReviewSLAHours, &settings.DraftIdleDays,
+		&settings.CreatedAt, &settings.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// Update updates stale pull request settings
+func (r *StalePRSettingsRepository) Update(settings *models.StalePRSettings) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	query := `
+		UPDATE stale_pr_settings
+		SET max_age_days = ?, review_sla_hours = ?, draft_idle_days = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	_, err := r.db.Exec(query,
+		settings.MaxAgeDays, settings.ReviewSLAHours, settings.DraftIdleDays, settings.UpdatedAt, settings.ID,
+	)
+
+	return err
+}
+
+// Upsert creates or updates the stale pull request settings of a project
+func (r *StalePRSettingsRepository) Upsert(settings *models.StalePRSettings) error {
+	existing, err := r.GetByProjectID(settings.ProjectID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if existing != nil {
+		settings.ID = existing.ID
+		settings.CreatedAt = existing.CreatedAt
+		return r.Update(settings)
+	}
+
+	return r.Create(settings)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+// BotService detects the bot and automation accounts of a project and applies its bot policy
+type BotService struct {
+	botSettingsRepo *repositories.BotSettingsRepository
+}
+
+func NewBotService(botSettingsRepo *repositories.BotSettingsRepository) *BotService {
+	return &BotService{
+		botSettingsRepo: botSettingsRepo,
+	}
+}
+
+// GetSettings retrieves the bot settings of a project, falling back to the defaults
+func (s *BotService) GetSettings(projectID string) (*models.BotSettings, error) {
+	settings, err := s.botSettingsRepo.GetByProjectID(projectID)
+	if err == sql.ErrNoRows {
+		return models.NewBotSettings(projectID), nil
+	}
+	return settings, err
+}
+
+// UpdateSettings validates and stores the bot settings of a project
+func (s *BotService) UpdateSettings(projectID, policy, patterns string) (*models.BotSettings, error) {
+	settings := models.NewBotSettings(projectID)
+	settings.Policy = policy
+	settings.Patterns = patterns
+	settings.Patterns = strings.Join(settings.PatternList(), "\n")
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.botSettingsRepo.Upsert(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// ApplyPolicy applies the bot policy of a project to its people statistics
+func (s *BotService) ApplyPolicy(projectID string, stats []*models.GitHubPersonStats) ([]*models.GitHubPersonStats, error) {
+	settings, err := s.GetSettings(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return applyBotPolicy(settings, stats), nil
+}
+
+// applyBotPolicy marks the bots among people statistics sorted by score and applies the policy: hidden
+// bots are dropped, unscored bots lose their score and move to the end, and separate bots are combined
+// into one automation row at the end
+func applyBotPolicy(settings *models.BotSettings, stats []*models.GitHubPersonStats) []*models.GitHubPersonStats {
+	var people, bots []*models.GitHubPersonStats
+	for _, stat := range stats {
+		if stat.GitHubPerson != nil && settings.IsBotPerson(stat.GitHubPerson) {
+			stat.IsBot = true
+			bots = append(bots, stat)
+			continue
+		}
+		people = append(people, stat)
+	}
+	if len(bots) == 0 {
+		return stats
+	}
+
+	switch settings.Policy {
+	case models.BotPolicyHide:
+		return people
+	case models.BotPolicySeparate:
+		automation := &models.GitHubPersonStats{IsAutomation: true}
+		for _, bot := range bots {
+			automation.TotalCommits += bot.TotalCommits
+			automation.TotalAdditions += bot.TotalAdditions
+			automation.TotalDeletions += bot.TotalDeletions
+			automation.TotalComments += bot.TotalComments
+			automation.TotalPullRequests += bot.TotalPullRequests
+		}
+		displayName := fmt.Sprintf("%d bots", len(bots))
+		if len(bots) == 1 {
+			displayName = bots[0].GitHubPerson.Username
+		}
+		automation.GitHubPerson = &models.GithubPerson{Username: "Automation", DisplayName: &displayName}
+		return append(people, automation)
+	default:
+		for _, bot := range bots {
+			bot.TotalScore = 0
+		}
+		sort.SliceStable(bots, func(i, j int) bool {
+			return bots[i].TotalCommits+bots[i].TotalPullRequests > bots[j].TotalCommits+bots[j].TotalPullRequests
+		})
+		return append(people, bots...)
+	}
+}
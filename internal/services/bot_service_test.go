@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBotDetection(t *testing.T) {
+	settings := models.NewBotSettings("project")
+	settings.Patterns = "release-*\n*@ci.example.com"
+
+	botType, userType := "Bot", "User"
+	assert.True(t, settings.IsBotPerson(&models.GithubPerson{Username: "dependabot", Type: &botType}))
+	assert.True(t, settings.IsBotPerson(&models.GithubPerson{Username: "renovate[bot]", Type: &userType}))
+	assert.True(t, settings.IsBotPerson(&models.GithubPerson{Username: "Release-Manager", Type: &userType}))
+	assert.False(t, settings.IsBotPerson(&models.GithubPerson{Username: "alice", Type: &userType}))
+
+	assert.True(t, settings.IsBotAuthor("dependabot[bot]", "49699333+dependabot[bot]@users.noreply.github.com"))
+	assert.True(t, settings.IsBotAuthor("GitHub", "noreply@github.com"))
+	assert.True(t, settings.IsBotAuthor("Build", "builder@CI.example.com"))
+	assert.False(t, settings.IsBotAuthor("Alice", "1234+alice@users.noreply.github.com"))
+
+	settings.Patterns = "["
+	assert.Error(t, settings.Validate())
+}
+
+func TestApplyBotPolicy(t *testing.T) {
+	botType := "Bot"
+	stats := func() []*models.GitHubPersonStats {
+		return []*models.GitHubPersonStats{
+			{GitHubPerson: &models.GithubPerson{ID: "renovate", Username: "renovate[bot]"}, TotalCommits: 50, TotalScore: 500},
+			{GitHubPerson: &models.GithubPerson{ID: "alice", Username: "alice"}, TotalCommits: 10, TotalScore: 100},
+			{GitHubPerson: &models.GithubPerson{ID: "dependabot", Username: "dependabot", Type: &botType}, TotalCommits: 20, TotalPullRequests: 20, TotalScore: 300},
+		}
+	}
+	settings := models.NewBotSettings("project")
+
+	unscored := applyBotPolicy(settings, stats())
+	if assert.Len(t, unscored, 3) {
+		assert.Equal(t, "alice", unscored[0].GitHubPerson.ID)
+		assert.Equal(t, "renovate", unscored[1].GitHubPerson.ID)
+		assert.True(t, unscored[1].IsBot)
+		assert.Equal(t, 0, unscored[1].TotalScore)
+		assert.Equal(t, 0, unscored[2].TotalScore)
+	}
+
+	settings.Policy = models.BotPolicyHide
+	hidden := applyBotPolicy(settings, stats())
+	if assert.Len(t, hidden, 1) {
+		assert.Equal(t, "alice", hidden[0].GitHubPerson.ID)
+	}
+
+	settings.Policy = models.BotPolicySeparate
+	separate := applyBotPolicy(settings, stats())
+	if assert.Len(t, separate, 2) {
+		automation := separate[1]
+		assert.True(t, automation.IsAutomation)
+		assert.Equal(t, 70, automation.TotalCommits)
+		assert.Equal(t, 20, automation.TotalPullRequests)
+		assert.Equal(t, 0, automation.TotalScore)
+		assert.Equal(t, "2 bots", *automation.GitHubPerson.DisplayName)
+	}
+}
@@ -0,0 +1,404 @@
+package services
+
+import (
+	"database/sql"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+// CIReportService reports how often CI passes per author and repository, how long merged pull requests
+// waited on CI, and which checks fail and then pass on a re-run of the same commit
+type CIReportService struct {
+	ciRunRepo        *repositories.CIRunRepository
+	pullRequestRepo  *repositories.PullRequestRepository
+	prCommitRepo     *repositories.PRCommitRepository
+	commitRepo       *repositories.CommitRepository
+	githubPersonRepo *repositories.GithubPersonRepository
+	githubRepoRepo   *repositories.GitHubRepositoryRepository
+}
+
+func NewCIReportService(
+	ciRunRepo *repositories.CIRunRepository,
+	pullRequestRepo *repositories.PullRequestRepository,
+	prCommitRepo *repositories.PRCommitRepository,
+	commitRepo *repositories.CommitRepository,
+	githubPersonRepo *repositories.GithubPersonRepository,
+	githubRepoRepo *repositories.GitHubRepositoryRepository,
+) *CIReportService {
+	return &CIReportService{
+		ciRunRepo:        ciRunRepo,
+		pullRequestRepo:  pullRequestRepo,
+		prCommitRepo:     prCommitRepo,
+		commitRepo:       commitRepo,
+		githubPersonRepo: githubPersonRepo,
+		githubRepoRepo:   githubRepoRepo,
+	}
+}
+
+// GetAllTimeReportByProject reports the CI results of a project over all time
+func (s *CIReportService) GetAllTimeReportByProject(projectID string, filter *models.RepositoryFilter) (*models.CIReport, error) {
+	return s.getReportByProject(projectID, time.Time{}, time.Time{}, filter)
+}
+
+// GetYearlyReportByProject reports the CI results of a project for a year
+func (s *CIReportService) GetYearlyReportByProject(projectID string, year int, filter *models.RepositoryFilter) (*models.CIReport, error) {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(1, 0, 0), filter)
+}
+
+// GetMonthlyReportByProject reports the CI results of a project for a month
+func (s *CIReportService) GetMonthlyReportByProject(projectID string, year, month int, filter *models.RepositoryFilter) (*models.CIReport, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(0, 1, 0), filter)
+}
+
+// GetWeeklyReportByProject reports the CI results of a project for a week numbered like the weekly reports
+func (s *CIReportService) GetWeeklyReportByProject(projectID string, year, week int, filter *models.RepositoryFilter) (*models.CIReport, error) {
+	start, end := weekRange(year, week)
+	return s.getReportByProject(projectID, start, end, filter)
+}
+
+// GetDailyReportByProject reports the CI results of a project for a day
+func (s *CIReportService) GetDailyReportByProject(projectID string, date time.Time, filter *models.RepositoryFilter) (*models.CIReport, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(0, 0, 1), filter)
+}
+
+// getReportByProject loads the CI runs, pull requests and commits of a project and reports them for [start, end)
+func (s *CIReportService) getReportByProject(projectID string, start, end time.Time, filter *models.RepositoryFilter) (*models.CIReport, error) {
+	runs, err := s.ciRunRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	pullRequests, err := s.pullRequestRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	prCommits, err := s.prCommitRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := s.commitRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	projectPeople, err := s.githubPersonRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	runs = filterByRepository(runs, filter, func(r *models.CIRun) string { return r.RepositoryID })
+	pullRequests = filterByRepository(pullRequests, filter, func(pr *models.PullRequest) string { return pr.RepositoryID })
+	prCommits = filterByRepository(prCommits, filter, func(c *models.PRCommit) string { return c.RepositoryID })
+	commits = filterByRepository(commits, filter, func(c *models.Commit) string { return c.GithubRepositoryID })
+
+	people := newReviewerPeople(projectPeople)
+	for _, pr := range pullRequests {
+		if pr.AuthorID == nil {
+			continue
+		}
+		if _, ok := people.byID[*pr.AuthorID]; !ok {
+			person, err := s.githubPersonRepo.GetByID(*pr.AuthorID)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			people.add(person, 0)
+		}
+	}
+
+	report := buildCIReport(runs, pullRequests, prCommits, commits, people, periodFilter(start, end))
+
+	names := make(map[string]string)
+	repositoryName := func(repositoryID string) string {
+		if _, ok := names[repositoryID]; !ok {
+			names[repositoryID] = repositoryID
+			if repo, err := s.githubRepoRepo.GetByID(repositoryID); err == nil {
+				names[repositoryID] = repo.FullName
+			}
+		}
+		return names[repositoryID]
+	}
+	for _, stats := range report.Repositories {
+		stats.Name = repositoryName(stats.Name)
+	}
+	for _, flaky := range report.Flaky {
+		flaky.Repository = repositoryName(flaky.Repository)
+	}
+	sortCIPassStats(report.Repositories)
+
+	return report, nil
+}
+
+// buildCIReport reports the CI runs in the period. Repositories are left as repository IDs.
+//
+// A build is the CI of one commit: for every check and workflow the first completed run counts, and the
+// build fails when any of those failed. Builds are attributed to the author of the pull request the commit
+// belongs to, falling back to the git author of default branch commits, and fall in the period they
+// started in. Cancelled runs neither pass nor fail.
+func buildCIReport(runs []*models.CIRun, pullRequests []*models.PullRequest, prCommits []*models.PRCommit, commits []*models.Commit, people *reviewerPeople, inPeriod func(*time.Time) bool) *models.CIReport {
+	prByID := make(map[string]*models.PullRequest, len(pullRequests))
+	prBySHA := make(map[string]*models.PullRequest)
+	for _, pr := range pullRequests {
+		prByID[pr.ID] = pr
+		if pr.MergeCommitSHA != nil {
+			prBySHA[pr.RepositoryID+"/"+*pr.MergeCommitSHA] = pr
+		}
+	}
+	for _, commit := range prCommits {
+		if pr := prByID[commit.PullRequestID]; pr != nil {
+			prBySHA[commit.RepositoryID+"/"+commit.CommitSHA] = pr
+		}
+	}
+	for _, pr := range pullRequests {
+		if pr.HeadSHA != nil {
+			prBySHA[pr.RepositoryID+"/"+*pr.HeadSHA] = pr
+		}
+	}
+	commitAuthor := make(map[string]string, len(commits))
+	for _, commit := range commits {
+		commitAuthor[commit.GithubRepositoryID+"/"+commit.CommitSHA] = commit.AuthorName
+	}
+
+	sorted := append([]*models.CIRun(nil), runs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := ciRunTime(sorted[i]), ciRunTime(sorted[j])
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return sorted[i].Attempt < sorted[j].Attempt
+	})
+
+	// The first completed run of every check and workflow on every commit
+	bySHA := make(map[string][]*models.CIRun)
+	firstRuns := make(map[string]map[string]*models.CIRun)
+	var shas []string
+	for _, run := range sorted {
+		sha := run.RepositoryID + "/" + run.HeadSHA
+		bySHA[sha] = append(bySHA[sha], run)
+		if !run.Completed() {
+			continue
+		}
+		if firstRuns[sha] == nil {
+			firstRuns[sha] = make(map[string]*models.CIRun)
+			shas = append(shas, sha)
+		}
+		check := run.Kind + "/" + run.Name
+		if firstRuns[sha][check] == nil {
+			firstRuns[sha][check] = run
+		}
+	}
+
+	report := &models.CIReport{}
+	authors := make(map[string]*models.CIPassStats)
+	repos := make(map[string]*models.CIPassStats)
+	get := func(stats map[string]*models.CIPassStats, key, name string) *models.CIPassStats {
+		if stats[key] == nil {
+			stats[key] = &models.CIPassStats{Name: name}
+		}
+		return stats[key]
+	}
+
+	for _, sha := range shas {
+		var startedAt *time.Time
+		passed, failed := false, false
+		var repositoryID string
+		for _, run := range firstRuns[sha] {
+			repositoryID = run.RepositoryID
+			at := ciRunTime(run)
+			if startedAt == nil || at.Before(*startedAt) {
+				startedAt = &at
+			}
+			failed = failed || run.Failed()
+			passed = passed || run.Passed()
+		}
+		if (!passed && !failed) || !inPeriod(startedAt) {
+			continue
+		}
+
+		targets := []*models.CIPassStats{get(repos, repositoryID, repositoryID)}
+		if pr := prBySHA[sha]; pr != nil {
+			if pr.AuthorID != nil && people.byID[*pr.AuthorID] != nil {
+				author := people.byID[*pr.AuthorID]
+				stats := get(authors, author.ID, author.Username)
+				stats.GithubPersonID = author.ID
+				targets = append(targets, stats)
+			}
+		} else if name := commitAuthor[sha]; name != "" {
+			targets = append(targets, get(authors, "name:"+strings.ToLower(name), name))
+		}
+
+		report.Builds++
+		if failed {
+			report.Failed++
+		} else {
+			report.Passed++
+		}
+		for _, stats := range targets {
+			stats.Builds++
+			if failed {
+				stats.Failed++
+			} else {
+				stats.Passed++
+			}
+		}
+	}
+	report.PassRate = ciPassRate(report.Passed, report.Builds)
+	for _, stats := range authors {
+		stats.PassRate = ciPassRate(stats.Passed, stats.Builds)
+		report.Authors = append(report.Authors, stats)
+	}
+	for _, stats := range repos {
+		stats.PassRate = ciPassRate(stats.Passed, stats.Builds)
+		report.Repositories = append(report.Repositories, stats)
+	}
+	sortCIPassStats(report.Authors)
+	sortCIPassStats(report.Repositories)
+
+	// Time from the first run starting to the last one completing on the final head of merged pull requests
+	var waits, cycles []float64
+	var totalWait, totalCycle float64
+	for _, pr := range pullRequests {
+		if pr.HeadSHA == nil || pr.GithubCreatedAt == nil || !inPeriod(pr.MergedAt) {
+			continue
+		}
+		var first, last *time.Time
+		for _, run := range bySHA[pr.RepositoryID+"/"+*pr.HeadSHA] {
+			if !run.Completed() || run.StartedAt == nil || run.CompletedAt == nil {
+				continue
+			}
+			if first == nil || run.StartedAt.Before(*first) {
+				first = run.StartedAt
+			}
+			if last == nil || run.CompletedAt.After(*last) {
+				last = run.CompletedAt
+			}
+		}
+		if first == nil {
+			continue
+		}
+		wait := last.Sub(*first).Seconds()
+		cycle := pr.MergedAt.Sub(*pr.GithubCreatedAt).Seconds()
+		if wait < 0 || cycle < 0 {
+			continue
+		}
+		waits = append(waits, wait)
+		cycles = append(cycles, cycle)
+		totalWait += wait
+		totalCycle += cycle
+	}
+	report.CIWait = summarize(waits)
+	report.CycleTime = summarize(cycles)
+	if totalCycle > 0 {
+		share := totalWait / totalCycle
+		report.CIWaitShare = &share
+	}
+
+	report.Flaky = findFlakyChecks(sorted, inPeriod)
+
+	return report
+}
+
+// findFlakyChecks finds the checks that failed and then passed on the same commit, and the workflow runs
+// with a failed attempt followed by a passing one. Runs must be sorted by time. A commit counts as flaky
+// in the period its passing run completed in.
+func findFlakyChecks(runs []*models.CIRun, inPeriod func(*time.Time) bool) []*models.CIFlakyCheck {
+	// Check runs get a new ID when re-run, workflow runs keep theirs and count attempts
+	groups := make(map[string][]*models.CIRun)
+	var keys []string
+	for _, run := range runs {
+		key := run.RepositoryID + "/" + run.Kind + "/" + run.HeadSHA + "/" + run.Name
+		if run.Kind == models.CIRunKindWorkflowRun {
+			key = run.RepositoryID + "/" + run.Kind + "/" + strconv.FormatInt(run.GithubID, 10)
+		}
+		if groups[key] == nil {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], run)
+	}
+
+	checks := make(map[string]*models.CIFlakyCheck)
+	commits := make(map[string]map[string]bool)
+	flakyCommits := make(map[string]map[string]bool)
+	for _, key := range keys {
+		group := groups[key]
+		first := group[0]
+		checkKey := first.RepositoryID + "/" + first.Kind + "/" + first.Name
+		if checks[checkKey] == nil {
+			checks[checkKey] = &models.CIFlakyCheck{Repository: first.RepositoryID, Kind: first.Kind, Name: first.Name}
+			commits[checkKey] = make(map[string]bool)
+			flakyCommits[checkKey] = make(map[string]bool)
+		}
+		check := checks[checkKey]
+
+		failed := false
+		for _, run := range group {
+			if run.Completed() && inPeriod(run.CompletedAt) {
+				commits[checkKey][run.HeadSHA] = true
+			}
+			if run.Failed() {
+				failed = true
+				continue
+			}
+			if failed && run.Passed() && inPeriod(run.CompletedAt) {
+				flakyCommits[checkKey][run.HeadSHA] = true
+				if run.CompletedAt.After(check.LastSeenAt) {
+					check.LastSeenAt = *run.CompletedAt
+				}
+			}
+		}
+	}
+
+	var flaky []*models.CIFlakyCheck
+	for checkKey, check := range checks {
+		check.Flaky = len(flakyCommits[checkKey])
+		check.Commits = len(commits[checkKey])
+		if check.Flaky > 0 {
+			flaky = append(flaky, check)
+		}
+	}
+	sort.Slice(flaky, func(i, j int) bool {
+		if flaky[i].Flaky != flaky[j].Flaky {
+			return flaky[i].Flaky > flaky[j].Flaky
+		}
+		if flaky[i].Repository != flaky[j].Repository {
+			return flaky[i].Repository < flaky[j].Repository
+		}
+		return strings.ToLower(flaky[i].Name) < strings.ToLower(flaky[j].Name)
+	})
+
+	return flaky
+}
+
+// ciRunTime is when a run started, falling back to when it was created or completed
+func ciRunTime(run *models.CIRun) time.Time {
+	switch {
+	case run.StartedAt != nil:
+		return *run.StartedAt
+	case run.GithubCreatedAt != nil:
+		return *run.GithubCreatedAt
+	case run.CompletedAt != nil:
+		return *run.CompletedAt
+	}
+	return time.Time{}
+}
+
+func ciPassRate(passed, builds int) *float64 {
+	if builds == 0 {
+		return nil
+	}
+	rate := float64(passed) / float64(builds)
+	return &rate
+}
+
+func sortCIPassStats(stats []*models.CIPassStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Builds != stats[j].Builds {
+			return stats[i].Builds > stats[j].Builds
+		}
+		return strings.ToLower(stats[i].Name) < strings.ToLower(stats[j].Name)
+	})
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCIReport(t *testing.T) {
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2025, 8, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	conclusion := func(c string) *string { return &c }
+	run := func(kind string, id int64, attempt int, name, sha, result string, start, end *time.Time) *models.CIRun {
+		return &models.CIRun{
+			RepositoryID: "repo", Kind: kind, GithubID: id, Attempt: attempt, Name: name, HeadSHA: sha,
+			Status: "completed", Conclusion: conclusion(result), StartedAt: start, CompletedAt: end,
+		}
+	}
+	alice := &models.GithubPerson{ID: "alice", GithubUserID: 1, Username: "alice"}
+	people := newReviewerPeople([]*models.GithubPerson{alice})
+
+	head, first, direct := "head", "first", "direct"
+	pullRequests := []*models.PullRequest{
+		{ID: "pr1", RepositoryID: "repo", AuthorID: &alice.ID, HeadSHA: &head, GithubCreatedAt: at(1, 0), MergedAt: at(2, 0)},
+	}
+	prCommits := []*models.PRCommit{{RepositoryID: "repo", PullRequestID: "pr1", CommitSHA: first}}
+	commits := []*models.Commit{{GithubRepositoryID: "repo", CommitSHA: direct, AuthorName: "Bob"}}
+
+	runs := []*models.CIRun{
+		// The first commit of the pull request fails its tests
+		run(models.CIRunKindCheckRun, 1, 1, "test", first, "failure", at(1, 1), at(1, 2)),
+		// The head fails once and passes on a re-run, lint passes
+		run(models.CIRunKindCheckRun, 2, 1, "test", head, "failure", at(1, 3), at(1, 4)),
+		run(models.CIRunKindCheckRun, 3, 1, "lint", head, "success", at(1, 3), at(1, 5)),
+		run(models.CIRunKindCheckRun, 4, 1, "test", head, "success", at(1, 6), at(1, 9)),
+		// A workflow on a direct commit fails its first attempt and passes the second
+		run(models.CIRunKindWorkflowRun, 10, 1, "CI", direct, "failure", at(3, 1), at(3, 2)),
+		run(models.CIRunKindWorkflowRun, 10, 2, "CI", direct, "success", at(3, 3), at(3, 4)),
+		// Cancelled runs neither pass nor fail
+		run(models.CIRunKindCheckRun, 5, 1, "test", "cancelled", "cancelled", at(3, 1), at(3, 2)),
+	}
+
+	report := buildCIReport(runs, pullRequests, prCommits, commits, people, periodFilter(*at(1, 0), *at(10, 0)))
+
+	assert.Equal(t, 3, report.Builds)
+	assert.Equal(t, 0, report.Passed)
+	assert.Equal(t, 3, report.Failed)
+
+	if assert.Len(t, report.Authors, 2) {
+		assert.Equal(t, "alice", report.Authors[0].GithubPersonID)
+		assert.Equal(t, 2, report.Authors[0].Builds)
+		assert.Equal(t, "Bob", report.Authors[1].Name)
+		assert.Equal(t, 1, report.Authors[1].Failed)
+	}
+	if assert.Len(t, report.Repositories, 1) {
+		assert.Equal(t, 3, report.Repositories[0].Builds)
+		assert.Equal(t, 0.0, *report.Repositories[0].PassRate)
+	}
+
+	// CI on the head ran from 03:00 to 09:00 of a day-long cycle
+	assert.Equal(t, 1, report.CIWait.Count)
+	assert.Equal(t, float64(6*3600), report.CIWait.Median)
+	assert.Equal(t, float64(24*3600), report.CycleTime.Median)
+	assert.InDelta(t, 0.25, *report.CIWaitShare, 1e-9)
+
+	if assert.Len(t, report.Flaky, 2) {
+		assert.Equal(t, "CI", report.Flaky[0].Name)
+		assert.Equal(t, models.CIRunKindWorkflowRun, report.Flaky[0].Kind)
+		assert.Equal(t, 1, report.Flaky[0].Flaky)
+		assert.Equal(t, "test", report.Flaky[1].Name)
+		assert.Equal(t, 1, report.Flaky[1].Flaky)
+		assert.Equal(t, 3, report.Flaky[1].Commits)
+		assert.Equal(t, *at(1, 9), report.Flaky[1].LastSeenAt)
+	}
+
+	// Only the direct commit's workflow falls after the first day
+	later := buildCIReport(runs, pullRequests, prCommits, commits, people, periodFilter(*at(2, 1), *at(10, 0)))
+	assert.Equal(t, 1, later.Builds)
+	assert.Len(t, later.Flaky, 1)
+	assert.Equal(t, 0, later.CIWait.Count)
+}
@@ -14,8 +14,7 @@ import (
 
 // CloneService handles repository cloning operations
 type CloneService struct {
-	projectRepo           *repositories.ProjectRepository
-	userRepo              *repositories.UserRepository
+	githubAppService      *GitHubAppService
 	githubRepoRepo        *repositories.GitHubRepositoryRepository
 	projectRepositoryRepo *repositories.ProjectRepositoryRepository
 	cloneBasePath         string
@@ -23,14 +22,12 @@ type CloneService struct {
 
 // NewCloneService creates a new clone service
 func NewCloneService(
-	projectRepo *repositories.ProjectRepository,
-	userRepo *repositories.UserRepository,
+	githubAppService *GitHubAppService,
 	githubRepoRepo *repositories.GitHubRepositoryRepository,
 	projectRepositoryRepo *repositories.ProjectRepositoryRepository,
 ) *CloneService {
 	return &CloneService{
-		projectRepo:           projectRepo,
-		userRepo:              userRepo,
+		githubAppService:      githubAppService,
 		githubRepoRepo:        githubRepoRepo,
 		projectRepositoryRepo: projectRepositoryRepo,
 		cloneBasePath:         "./clones",
@@ -39,20 +36,10 @@ func NewCloneService(
 
 // CloneRepository clones or pulls a repository
 func (s *CloneService) CloneRepository(job *models.Job) error {
-	// Get the project to access owner information
-	project, err := s.projectRepo.GetByID(job.ProjectID)
+	// Get the token the project's background jobs run with
+	token, err := s.githubAppService.GetProjectToken(job.ProjectID)
 	if err != nil {
-		return fmt.Errorf("failed to get project: %w", err)
-	}
-
-	// Get the project owner to access GitHub token
-	owner, err := s.userRepo.GetByID(project.OwnerID.String())
-	if err != nil {
-		return fmt.Errorf("failed to get project owner: %w", err)
-	}
-
-	if owner.GitHubAccessToken == "" {
-		return fmt.Errorf("GitHub access token not found for project owner")
+		return fmt.Errorf("failed to get GitHub token: %w", err)
 	}
 
 	// Get the project repository to access GitHub repository info
@@ -76,13 +63,24 @@ func (s *CloneService) CloneRepository(job *models.Job) error {
 	// Use full_name to ensure unique paths for repositories with same name from different owners
 	repoClonePath := filepath.Join(s.cloneBasePath, githubRepo.FullName)
 
+	if githubRepo.IsDeleted() || githubRepo.IsInaccessible() {
+		return fmt.Errorf("repository %s is deleted or inaccessible on GitHub", githubRepo.FullName)
+	}
+
+	// A rename that happened outside of the fetcher leaves the clone at its old path
+	if githubRepo.LocalPath != nil && *githubRepo.LocalPath != repoClonePath {
+		if err := moveClone(*githubRepo.LocalPath, repoClonePath); err != nil {
+			fmt.Printf("Warning: failed to move clone from %s to %s: %v\n", *githubRepo.LocalPath, repoClonePath, err)
+		}
+	}
+
 	// Check if repository is already cloned
 	if s.isRepositoryCloned(repoClonePath) {
 		// Repository exists, do a git pull
-		return s.pullRepository(repoClonePath, githubRepo, owner.GitHubAccessToken)
+		return s.pullRepository(repoClonePath, githubRepo, token)
 	} else {
 		// Repository doesn't exist, do a full clone
-		return s.cloneRepository(repoClonePath, githubRepo, owner.GitHubAccessToken)
+		return s.cloneRepository(repoClonePath, githubRepo, token)
 	}
 }
 
@@ -101,7 +99,7 @@ func (s *CloneService) cloneRepository(repoPath string, githubRepo *models.GitHu
 	}
 
 	// Create authenticated clone URL with token
-	authURL := strings.Replace(githubRepo.CloneURL, "https://", "https://"+token+"@", 1)
+	authURL := authenticatedCloneURL(githubRepo.CloneURL, token)
 
 	// Try to clone with retry logic for potential lock issues
 	maxRetries := 3
@@ -150,7 +148,7 @@ func (s *CloneService) pullRepository(repoPath string, githubRepo *models.GitHub
 	}
 
 	// Set up the remote URL with token
-	authURL := strings.Replace(githubRepo.CloneURL, "https://", "https://"+token+"@", 1)
+	authURL := authenticatedCloneURL(githubRepo.CloneURL, token)
 	cmd = exec.Command("git", "remote", "set-url", "origin", authURL)
 	cmd.Dir = repoPath
 	if err := cmd.Run(); err != nil {
@@ -281,3 +279,40 @@ func (s *CloneService) cleanupGitLocks(repoPath string) error {
 func (s *CloneService) GetClonePath(fullName string) string {
 	return filepath.Join(s.cloneBasePath, fullName)
 }
+
+// authenticatedCloneURL embeds a token into an HTTPS clone URL.
+// The x-access-token user works for both installation and OAuth tokens.
+func authenticatedCloneURL(cloneURL, token string) string {
+	return strings.Replace(cloneURL, "https://", "https://x-access-token:"+token+"@", 1)
+}
+
+// relocatedClonePath returns the clone path of a repository after its full name changed
+func relocatedClonePath(clonePath, oldFullName, newFullName string) string {
+	base := strings.TrimSuffix(filepath.ToSlash(clonePath), oldFullName)
+	return filepath.Join(filepath.FromSlash(base), newFullName)
+}
+
+// moveClone moves a local clone to a new path. A missing source is not an error.
+func moveClone(oldPath, newPath string) error {
+	if oldPath == newPath {
+		return nil
+	}
+	if _, err := os.Stat(oldPath); os.IsNotExist(err) {
+		return nil
+	}
+	if _, err := os.Stat(newPath); err == nil {
+		return fmt.Errorf("destination %s already exists", newPath)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return fmt.Errorf("failed to move clone: %w", err)
+	}
+
+	// Remove the old owner directory if it is empty now; ignore errors
+	_ = os.Remove(filepath.Dir(oldPath))
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelocatedClonePath(t *testing.T) {
+	testCases := []struct {
+		name        string
+		clonePath   string
+		oldFullName string
+		newFullName string
+		expected    string
+	}{
+		{
+			name:        "Rename within owner",
+			clonePath:   "clones/acme/api",
+			oldFullName: "acme/api",
+			newFullName: "acme/api-server",
+			expected:    filepath.Join("clones", "acme", "api-server"),
+		},
+		{
+			name:        "Transfer to another organization",
+			clonePath:   "clones/alice/tool",
+			oldFullName: "alice/tool",
+			newFullName: "acme/tool",
+			expected:    filepath.Join("clones", "acme", "tool"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, relocatedClonePath(tc.clonePath, tc.oldFullName, tc.newFullName))
+		})
+	}
+}
+
+func TestMoveClone(t *testing.T) {
+	base := t.TempDir()
+	oldPath := filepath.Join(base, "alice", "tool")
+	newPath := filepath.Join(base, "acme", "tool")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(oldPath, ".git"), 0755))
+
+	require.NoError(t, moveClone(oldPath, newPath))
+
+	_, err := os.Stat(filepath.Join(newPath, ".git"))
+	assert.NoError(t, err, "clone should exist at the new path")
+	_, err = os.Stat(filepath.Join(base, "alice"))
+	assert.True(t, os.IsNotExist(err), "empty old owner directory should be removed")
+
+	// Moving a clone that doesn't exist is a no-op
+	assert.NoError(t, moveClone(oldPath, newPath))
+}
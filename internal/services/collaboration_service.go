@@ -0,0 +1,266 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+// CollaborationService builds the reviewer–author network of a project
+type CollaborationService struct {
+	pullRequestRepo  *repositories.PullRequestRepository
+	prReviewRepo     *repositories.PRReviewRepository
+	githubPersonRepo *repositories.GithubPersonRepository
+}
+
+func NewCollaborationService(
+	pullRequestRepo *repositories.PullRequestRepository,
+	prReviewRepo *repositories.PRReviewRepository,
+	githubPersonRepo *repositories.GithubPersonRepository,
+) *CollaborationService {
+	return &CollaborationService{
+		pullRequestRepo:  pullRequestRepo,
+		prReviewRepo:     prReviewRepo,
+		githubPersonRepo: githubPersonRepo,
+	}
+}
+
+// GetGraphByProject builds the collaboration graph of a project from the reviews submitted in [from, to)
+func (s *CollaborationService) GetGraphByProject(projectID string, from, to time.Time) (*models.CollaborationGraph, error) {
+	pullRequests, err := s.pullRequestRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.prReviewRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	projectPeople, err := s.githubPersonRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	people := newReviewerPeople(projectPeople)
+	for _, review := range reviews {
+		if _, ok := people.byGithubUserID[review.ReviewerID]; !ok {
+			person, err := s.githubPersonRepo.GetByGithubUserID(review.ReviewerID)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			people.add(person, review.ReviewerID)
+		}
+	}
+	for _, pr := range pullRequests {
+		if pr.AuthorID == nil {
+			continue
+		}
+		if _, ok := people.byID[*pr.AuthorID]; !ok {
+			person, err := s.githubPersonRepo.GetByID(*pr.AuthorID)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			people.add(person, 0)
+		}
+	}
+
+	graph := buildCollaborationGraph(pullRequests, reviews, people, periodFilter(from, to))
+	graph.From = from
+	graph.To = to
+	layoutCollaborationGraph(graph, 300, 300, 240)
+
+	return graph, nil
+}
+
+// buildCollaborationGraph links the author of each pull request to the people who reviewed it in the period.
+// Pending reviews, self-reviews and pull requests without a known author are left out.
+func buildCollaborationGraph(pullRequests []*models.PullRequest, reviews []*models.PRReview, people *reviewerPeople, inPeriod func(*time.Time) bool) *models.CollaborationGraph {
+	graph := &models.CollaborationGraph{}
+	nodes := make(map[string]*models.CollaborationNode)
+	node := func(person *models.GithubPerson, login string) *models.CollaborationNode {
+		key := reviewerKey(person, login)
+		if nodes[key] == nil {
+			nodes[key] = &models.CollaborationNode{ID: key, Username: login}
+			if person != nil {
+				nodes[key].GithubPersonID = person.ID
+				nodes[key].Username = person.Username
+			}
+			graph.Nodes = append(graph.Nodes, nodes[key])
+		}
+		return nodes[key]
+	}
+
+	prByID := make(map[string]*models.PullRequest, len(pullRequests))
+	for _, pr := range pullRequests {
+		prByID[pr.ID] = pr
+		if pr.AuthorID == nil || !inPeriod(pr.GithubCreatedAt) {
+			continue
+		}
+		if author := people.byID[*pr.AuthorID]; author != nil {
+			node(author, "").PullRequests++
+		}
+	}
+
+	edges := make(map[[2]string]*models.CollaborationEdge)
+	for _, review := range reviews {
+		pr := prByID[review.PullRequestID]
+		if pr == nil || pr.AuthorID == nil || review.State == "PENDING" || !inPeriod(review.SubmittedAt) {
+			continue
+		}
+		author := people.byID[*pr.AuthorID]
+		reviewer := people.byGithubUserID[review.ReviewerID]
+		if author == nil || (reviewer != nil && reviewer.ID == author.ID) {
+			continue
+		}
+
+		source := node(author, "")
+		target := node(reviewer, review.ReviewerLogin)
+		source.ReviewsReceived++
+		target.ReviewsGiven++
+
+		key := [2]string{source.ID, target.ID}
+		if edges[key] == nil {
+			edges[key] = &models.CollaborationEdge{Source: source.ID, Target: target.ID, SourceNode: source, TargetNode: target}
+			graph.Edges = append(graph.Edges, edges[key])
+		}
+		edges[key].Reviews++
+	}
+
+	// Who reviews each author, and whom each reviewer reviews
+	reviewersOf := make(map[string]map[string]bool)
+	revieweesOf := make(map[string]map[string]bool)
+	for _, edge := range graph.Edges {
+		if reviewersOf[edge.Source] == nil {
+			reviewersOf[edge.Source] = make(map[string]bool)
+		}
+		reviewersOf[edge.Source][edge.Target] = true
+		if revieweesOf[edge.Target] == nil {
+			revieweesOf[edge.Target] = make(map[string]bool)
+		}
+		revieweesOf[edge.Target][edge.Source] = true
+	}
+	onlyWith := func(set map[string]bool, id string) bool {
+		return len(set) == 1 && set[id]
+	}
+	for _, edge := range graph.Edges {
+		a, b := edge.Source, edge.Target
+		edge.ReciprocalOnly = onlyWith(reviewersOf[a], b) && onlyWith(revieweesOf[a], b) &&
+			onlyWith(reviewersOf[b], a) && onlyWith(revieweesOf[b], a)
+	}
+
+	for _, n := range graph.Nodes {
+		n.Isolated = n.PullRequests > 0 && n.ReviewsGiven == 0 && n.ReviewsReceived == 0
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return strings.ToLower(graph.Nodes[i].Username) < strings.ToLower(graph.Nodes[j].Username)
+	})
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].Reviews != graph.Edges[j].Reviews {
+			return graph.Edges[i].Reviews > graph.Edges[j].Reviews
+		}
+		if graph.Edges[i].SourceNode.Username != graph.Edges[j].SourceNode.Username {
+			return graph.Edges[i].SourceNode.Username < graph.Edges[j].SourceNode.Username
+		}
+		return graph.Edges[i].TargetNode.Username < graph.Edges[j].TargetNode.Username
+	})
+
+	return graph
+}
+
+// layoutCollaborationGraph places the nodes on a circle around (cx, cy)
+func layoutCollaborationGraph(graph *models.CollaborationGraph, cx, cy, radius float64) {
+	for i, n := range graph.Nodes {
+		angle := 2*math.Pi*float64(i)/float64(len(graph.Nodes)) - math.Pi/2
+		n.X = math.Round((cx+radius*math.Cos(angle))*10) / 10
+		n.Y = math.Round((cy+radius*math.Sin(angle))*10) / 10
+	}
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// CollaborationGraphML encodes a collaboration graph as GraphML
+func CollaborationGraphML(graph *models.CollaborationGraph) ([]byte, error) {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "username", For: "node", AttrName: "username", AttrType: "string"},
+			{ID: "pull_requests", For: "node", AttrName: "pull_requests", AttrType: "int"},
+			{ID: "reviews_given", For: "node", AttrName: "reviews_given", AttrType: "int"},
+			{ID: "reviews_received", For: "node", AttrName: "reviews_received", AttrType: "int"},
+			{ID: "isolated", For: "node", AttrName: "isolated", AttrType: "boolean"},
+			{ID: "reviews", For: "edge", AttrName: "reviews", AttrType: "int"},
+			{ID: "reciprocal_only", For: "edge", AttrName: "reciprocal_only", AttrType: "boolean"},
+		},
+		Graph: graphMLGraph{ID: "collaboration", EdgeDefault: "directed"},
+	}
+	for _, n := range graph.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: n.ID,
+			Data: []graphMLData{
+				{Key: "username", Value: n.Username},
+				{Key: "pull_requests", Value: strconv.Itoa(n.PullRequests)},
+				{Key: "reviews_given", Value: strconv.Itoa(n.ReviewsGiven)},
+				{Key: "reviews_received", Value: strconv.Itoa(n.ReviewsReceived)},
+				{Key: "isolated", Value: strconv.FormatBool(n.Isolated)},
+			},
+		})
+	}
+	for _, e := range graph.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: e.Source,
+			Target: e.Target,
+			Data: []graphMLData{
+				{Key: "reviews", Value: strconv.Itoa(e.Reviews)},
+				{Key: "reciprocal_only", Value: strconv.FormatBool(e.ReciprocalOnly)},
+			},
+		})
+	}
+
+	output, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), output...), nil
+}
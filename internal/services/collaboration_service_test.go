@@ -0,0 +1,95 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCollaborationGraph(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2025, 8, d, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+	alice := &models.GithubPerson{ID: "alice", GithubUserID: 1, Username: "alice"}
+	bob := &models.GithubPerson{ID: "bob", GithubUserID: 2, Username: "bob"}
+	carol := &models.GithubPerson{ID: "carol", GithubUserID: 3, Username: "carol"}
+	dave := &models.GithubPerson{ID: "dave", GithubUserID: 4, Username: "dave"}
+	erin := &models.GithubPerson{ID: "erin", GithubUserID: 5, Username: "erin"}
+	people := newReviewerPeople([]*models.GithubPerson{alice, bob, carol, dave, erin})
+
+	pullRequests := []*models.PullRequest{
+		{ID: "pr1", AuthorID: &alice.ID, GithubCreatedAt: day(4)},
+		{ID: "pr2", AuthorID: &bob.ID, GithubCreatedAt: day(5)},
+		{ID: "pr3", AuthorID: &carol.ID, GithubCreatedAt: day(5)},
+		{ID: "pr4", AuthorID: &dave.ID, GithubCreatedAt: day(6)},
+		{ID: "pr5", AuthorID: &erin.ID, GithubCreatedAt: day(6)},
+		{ID: "pr6", AuthorID: &alice.ID, GithubCreatedAt: day(20)},
+	}
+	reviews := []*models.PRReview{
+		// alice and bob only review each other
+		{PullRequestID: "pr1", ReviewerID: 2, ReviewerLogin: "bob", State: "CHANGES_REQUESTED", SubmittedAt: day(4)},
+		{PullRequestID: "pr1", ReviewerID: 2, ReviewerLogin: "bob", State: "APPROVED", SubmittedAt: day(5)},
+		{PullRequestID: "pr2", ReviewerID: 1, ReviewerLogin: "alice", State: "APPROVED", SubmittedAt: day(5)},
+		// carol is reviewed by dave but doesn't review back
+		{PullRequestID: "pr3", ReviewerID: 4, ReviewerLogin: "dave", State: "COMMENTED", SubmittedAt: day(6)},
+		// Self, pending and out of range reviews don't count
+		{PullRequestID: "pr5", ReviewerID: 5, ReviewerLogin: "erin", State: "COMMENTED", SubmittedAt: day(6)},
+		{PullRequestID: "pr5", ReviewerID: 3, ReviewerLogin: "carol", State: "PENDING", SubmittedAt: day(6)},
+		{PullRequestID: "pr6", ReviewerID: 3, ReviewerLogin: "carol", State: "APPROVED", SubmittedAt: day(21)},
+	}
+
+	graph := buildCollaborationGraph(pullRequests, reviews, people, periodFilter(*day(1), *day(15)))
+
+	nodes := make(map[string]*models.CollaborationNode)
+	for _, n := range graph.Nodes {
+		nodes[n.ID] = n
+	}
+	assert.Len(t, graph.Nodes, 5)
+	assert.Equal(t, 1, nodes["alice"].PullRequests)
+	assert.Equal(t, 1, nodes["alice"].ReviewsGiven)
+	assert.Equal(t, 2, nodes["alice"].ReviewsReceived)
+	assert.False(t, nodes["carol"].Isolated)
+	assert.False(t, nodes["dave"].Isolated)
+	assert.True(t, nodes["erin"].Isolated)
+
+	if assert.Len(t, graph.Edges, 3) {
+		assert.Equal(t, "alice", graph.Edges[0].Source)
+		assert.Equal(t, "bob", graph.Edges[0].Target)
+		assert.Equal(t, 2, graph.Edges[0].Reviews)
+		assert.True(t, graph.Edges[0].ReciprocalOnly)
+
+		assert.Equal(t, "bob", graph.Edges[1].Source)
+		assert.Equal(t, "alice", graph.Edges[1].Target)
+		assert.True(t, graph.Edges[1].ReciprocalOnly)
+
+		assert.Equal(t, "carol", graph.Edges[2].Source)
+		assert.Equal(t, "dave", graph.Edges[2].Target)
+		assert.False(t, graph.Edges[2].ReciprocalOnly)
+	}
+}
+
+func TestCollaborationGraphML(t *testing.T) {
+	graph := &models.CollaborationGraph{
+		Nodes: []*models.CollaborationNode{
+			{ID: "alice", Username: "alice", PullRequests: 1},
+			{ID: "login:b&b", Username: "b&b"},
+		},
+		Edges: []*models.CollaborationEdge{
+			{Source: "alice", Target: "login:b&b", Reviews: 3},
+		},
+	}
+
+	output, err := CollaborationGraphML(graph)
+	assert.NoError(t, err)
+
+	xml := string(output)
+	assert.True(t, strings.HasPrefix(xml, "<?xml"))
+	assert.Contains(t, xml, `<graph id="collaboration" edgedefault="directed">`)
+	assert.Contains(t, xml, `<node id="login:b&amp;b">`)
+	assert.Contains(t, xml, `<edge source="alice" target="login:b&amp;b">`)
+	assert.Contains(t, xml, `<data key="reviews">3</data>`)
+}
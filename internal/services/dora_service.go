@@ -0,0 +1,388 @@
+package services
+
+import (
+	"database/sql"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+// DORAService computes the DORA metrics of a project from its deployments: deployment frequency, lead
+// time for changes, change failure rate and time to restore
+type DORAService struct {
+	doraSettingsRepo *repositories.DORASettingsRepository
+	deploymentRepo   *repositories.DeploymentRepository
+	pullRequestRepo  *repositories.PullRequestRepository
+	prCommitRepo     *repositories.PRCommitRepository
+	commitRepo       *repositories.CommitRepository
+	githubRepoRepo   *repositories.GitHubRepositoryRepository
+}
+
+func NewDORAService(
+	doraSettingsRepo *repositories.DORASettingsRepository,
+	deploymentRepo *repositories.DeploymentRepository,
+	pullRequestRepo *repositories.PullRequestRepository,
+	prCommitRepo *repositories.PRCommitRepository,
+	commitRepo *repositories.CommitRepository,
+	githubRepoRepo *repositories.GitHubRepositoryRepository,
+) *DORAService {
+	return &DORAService{
+		doraSettingsRepo: doraSettingsRepo,
+		deploymentRepo:   deploymentRepo,
+		pullRequestRepo:  pullRequestRepo,
+		prCommitRepo:     prCommitRepo,
+		commitRepo:       commitRepo,
+		githubRepoRepo:   githubRepoRepo,
+	}
+}
+
+// GetSettings retrieves the DORA settings of a project, falling back to the defaults
+func (s *DORAService) GetSettings(projectID string) (*models.DORASettings, error) {
+	settings, err := s.doraSettingsRepo.GetByProjectID(projectID)
+	if err == sql.ErrNoRows {
+		return models.NewDORASettings(projectID), nil
+	}
+	return settings, err
+}
+
+// UpdateSettings validates and stores the DORA settings of a project
+func (s *DORAService) UpdateSettings(projectID, deploySource, environment, tagPattern string, revertIsFailure bool, failureLabels string, failedStatusIsFailure bool) (*models.DORASettings, error) {
+	settings := models.NewDORASettings(projectID)
+	settings.DeploySource = deploySource
+	settings.Environment = strings.TrimSpace(environment)
+	settings.TagPattern = strings.TrimSpace(tagPattern)
+	settings.RevertIsFailure = revertIsFailure
+	settings.FailureLabels = failureLabels
+	settings.FailureLabels = strings.Join(settings.FailureLabelList(), ",")
+	settings.FailedStatusIsFailure = failedStatusIsFailure
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.doraSettingsRepo.Upsert(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetAllTimeReportByProject reports the DORA metrics of a project over all time
+func (s *DORAService) GetAllTimeReportByProject(projectID string, filter *models.RepositoryFilter) (*models.DORAReport, error) {
+	return s.getReportByProject(projectID, time.Time{}, time.Time{}, filter)
+}
+
+// GetYearlyReportByProject reports the DORA metrics of a project for a year
+func (s *DORAService) GetYearlyReportByProject(projectID string, year int, filter *models.RepositoryFilter) (*models.DORAReport, error) {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(1, 0, 0), filter)
+}
+
+// GetMonthlyReportByProject reports the DORA metrics of a project for a month
+func (s *DORAService) GetMonthlyReportByProject(projectID string, year, month int, filter *models.RepositoryFilter) (*models.DORAReport, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(0, 1, 0), filter)
+}
+
+// GetWeeklyReportByProject reports the DORA metrics of a project for a week numbered like the weekly reports
+func (s *DORAService) GetWeeklyReportByProject(projectID string, year, week int, filter *models.RepositoryFilter) (*models.DORAReport, error) {
+	start, end := weekRange(year, week)
+	return s.getReportByProject(projectID, start, end, filter)
+}
+
+// GetDailyReportByProject reports the DORA metrics of a project for a day
+func (s *DORAService) GetDailyReportByProject(projectID string, date time.Time, filter *models.RepositoryFilter) (*models.DORAReport, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(0, 0, 1), filter)
+}
+
+// getReportByProject loads the deployments, commits and pull requests of a project and reports them for [start, end)
+func (s *DORAService) getReportByProject(projectID string, start, end time.Time, filter *models.RepositoryFilter) (*models.DORAReport, error) {
+	settings, err := s.GetSettings(projectID)
+	if err != nil {
+		return nil, err
+	}
+	deployments, err := s.deploymentRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	shipped, err := s.deploymentRepo.GetCommitsByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := s.commitRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	pullRequests, err := s.pullRequestRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	prCommits, err := s.prCommitRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.pullRequestRepo.GetLabelsByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	deployments = filterByRepository(deployments, filter, func(d *models.Deployment) string { return d.RepositoryID })
+	commits = filterByRepository(commits, filter, func(c *models.Commit) string { return c.GithubRepositoryID })
+	pullRequests = filterByRepository(pullRequests, filter, func(pr *models.PullRequest) string { return pr.RepositoryID })
+	prCommits = filterByRepository(prCommits, filter, func(c *models.PRCommit) string { return c.RepositoryID })
+
+	report := buildDORAReport(settings, deployments, shipped, commits, pullRequests, prCommits, labels, start, end, time.Now())
+	for _, stats := range report.Repositories {
+		if repo, err := s.githubRepoRepo.GetByID(stats.RepositoryID); err == nil {
+			stats.Name = repo.FullName
+		}
+	}
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		a, b := report.Repositories[i], report.Repositories[j]
+		if a.Deployments != b.Deployments {
+			return a.Deployments > b.Deployments
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+
+	return report, nil
+}
+
+// doraDeploy is a deployment that counts as a deploy, with the commits it shipped
+type doraDeploy struct {
+	deployment *models.Deployment
+	commits    []string
+	failed     bool
+	restoredAt *time.Time
+}
+
+// buildDORAReport computes the DORA metrics of the deploys in [start, end), per repository and for the
+// project. Repositories are named by their ID.
+//
+// Deployments of the configured source count as deploys: published releases, GitHub deployments to the
+// environment that finished, or tags, where releases and tags must match the tag pattern. Commits shipped
+// by deployments that don't count go with the next deploy. A deploy failed when the next deploy ships a
+// revert commit or a commit of a pull request with a failure label, or when its deployment status is
+// failure or error. It is restored by the next deploy that didn't fail.
+func buildDORAReport(settings *models.DORASettings, deployments []*models.Deployment, shipped []*models.DeploymentCommit, commits []*models.Commit, pullRequests []*models.PullRequest, prCommits []*models.PRCommit, labels []*models.PRLabel, start, end, now time.Time) *models.DORAReport {
+	commitsByDeployment := make(map[string][]string)
+	for _, commit := range shipped {
+		commitsByDeployment[commit.DeploymentID] = append(commitsByDeployment[commit.DeploymentID], commit.CommitSHA)
+	}
+	commitBySHA := make(map[string]*models.Commit, len(commits))
+	for _, commit := range commits {
+		commitBySHA[commit.GithubRepositoryID+"/"+commit.CommitSHA] = commit
+	}
+
+	// Commits of pull requests with a failure label
+	failureLabels := make(map[string]bool)
+	for _, label := range settings.FailureLabelList() {
+		failureLabels[label] = true
+	}
+	labeled := make(map[string]bool)
+	for _, label := range labels {
+		if failureLabels[strings.ToLower(label.Name)] {
+			labeled[label.PullRequestID] = true
+		}
+	}
+	fixCommits := make(map[string]bool)
+	for _, pr := range pullRequests {
+		if !labeled[pr.ID] {
+			continue
+		}
+		for _, sha := range []*string{pr.MergeCommitSHA, pr.HeadSHA} {
+			if sha != nil {
+				fixCommits[pr.RepositoryID+"/"+*sha] = true
+			}
+		}
+	}
+	for _, commit := range prCommits {
+		if labeled[commit.PullRequestID] {
+			fixCommits[commit.RepositoryID+"/"+commit.CommitSHA] = true
+		}
+	}
+	isFix := func(repositoryID string, shas []string) bool {
+		for _, sha := range shas {
+			key := repositoryID + "/" + sha
+			if fixCommits[key] {
+				return true
+			}
+			if commit := commitBySHA[key]; settings.RevertIsFailure && commit != nil && strings.HasPrefix(commit.Message, "Revert ") {
+				return true
+			}
+		}
+		return false
+	}
+
+	sorted := append([]*models.Deployment(nil), deployments...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DeployedAt.Before(sorted[j].DeployedAt)
+	})
+
+	// Deploys per repository and environment, in order
+	groups := make(map[string][]*doraDeploy)
+	pending := make(map[string][]string)
+	var groupKeys []string
+	for _, deployment := range sorted {
+		if deployment.Source != settings.DeploySource {
+			continue
+		}
+		group := deployment.RepositoryID
+		if deployment.Environment != nil {
+			group += "/" + *deployment.Environment
+		}
+		if deployment.Source == models.DeploymentSourceDeployment && settings.Environment != "" &&
+			(deployment.Environment == nil || !strings.EqualFold(*deployment.Environment, settings.Environment)) {
+			continue
+		}
+
+		shippedCommits := append(pending[group], commitsByDeployment[deployment.ID]...)
+		if !countsAsDeploy(settings, deployment) {
+			pending[group] = shippedCommits
+			continue
+		}
+		pending[group] = nil
+
+		if groups[group] == nil {
+			groupKeys = append(groupKeys, group)
+		}
+		groups[group] = append(groups[group], &doraDeploy{deployment: deployment, commits: shippedCommits})
+	}
+
+	for _, deploys := range groups {
+		for i, deploy := range deploys {
+			status := deploy.deployment.Status
+			if settings.FailedStatusIsFailure && (status == "failure" || status == "error") {
+				deploy.failed = true
+			}
+			if i > 0 && isFix(deploy.deployment.RepositoryID, deploy.commits) {
+				deploys[i-1].failed = true
+			}
+		}
+		for i, deploy := range deploys {
+			if !deploy.failed {
+				continue
+			}
+			for _, next := range deploys[i+1:] {
+				if !next.failed {
+					deployedAt := next.deployment.DeployedAt
+					deploy.restoredAt = &deployedAt
+					break
+				}
+			}
+		}
+	}
+
+	// The period deploys are counted over; all time spans the first to the last deploy, and a period
+	// that isn't over yet ends now
+	periodStart, periodEnd := start, end
+	if start.IsZero() && end.IsZero() {
+		for _, deploys := range groups {
+			for _, deploy := range deploys {
+				if periodStart.IsZero() || deploy.deployment.DeployedAt.Before(periodStart) {
+					periodStart = deploy.deployment.DeployedAt
+				}
+				if deploy.deployment.DeployedAt.After(periodEnd) {
+					periodEnd = deploy.deployment.DeployedAt
+				}
+			}
+		}
+	} else if periodEnd.After(now) {
+		periodEnd = now
+	}
+	days := periodEnd.Sub(periodStart).Hours() / 24
+	if days < 1 {
+		days = 1
+	}
+
+	inPeriod := periodFilter(start, end)
+	report := &models.DORAReport{Settings: settings, Project: &models.DORAStats{Name: "All repositories"}}
+	byRepository := make(map[string]*models.DORAStats)
+	leadTimes := make(map[string][]float64)
+	restoreTimes := make(map[string][]float64)
+	sort.Strings(groupKeys)
+	for _, group := range groupKeys {
+		for _, deploy := range groups[group] {
+			deployedAt := deploy.deployment.DeployedAt
+			if !inPeriod(&deployedAt) {
+				continue
+			}
+			repositoryID := deploy.deployment.RepositoryID
+			stats := byRepository[repositoryID]
+			if stats == nil {
+				stats = &models.DORAStats{RepositoryID: repositoryID, Name: repositoryID}
+				byRepository[repositoryID] = stats
+				report.Repositories = append(report.Repositories, stats)
+			}
+
+			stats.Deployments++
+			for _, sha := range deploy.commits {
+				if commit := commitBySHA[repositoryID+"/"+sha]; commit != nil && !commit.CommitDate.After(deployedAt) {
+					leadTimes[repositoryID] = append(leadTimes[repositoryID], deployedAt.Sub(commit.CommitDate).Seconds())
+				}
+			}
+			if deploy.failed {
+				stats.Failures++
+				if deploy.restoredAt != nil {
+					restoreTimes[repositoryID] = append(restoreTimes[repositoryID], deploy.restoredAt.Sub(deployedAt).Seconds())
+				} else {
+					stats.Unrestored++
+				}
+			}
+		}
+	}
+
+	var allLeadTimes, allRestoreTimes []float64
+	for _, stats := range report.Repositories {
+		stats.LeadTime = summarize(leadTimes[stats.RepositoryID])
+		stats.TimeToRestore = summarize(restoreTimes[stats.RepositoryID])
+		setDORARates(stats, days)
+		allLeadTimes = append(allLeadTimes, leadTimes[stats.RepositoryID]...)
+		allRestoreTimes = append(allRestoreTimes, restoreTimes[stats.RepositoryID]...)
+
+		report.Project.Deployments += stats.Deployments
+		report.Project.Failures += stats.Failures
+		report.Project.Unrestored += stats.Unrestored
+	}
+	report.Project.LeadTime = summarize(allLeadTimes)
+	report.Project.TimeToRestore = summarize(allRestoreTimes)
+	setDORARates(report.Project, days)
+
+	return report
+}
+
+// countsAsDeploy tells whether a deployment of the configured source counts as a deploy
+func countsAsDeploy(settings *models.DORASettings, deployment *models.Deployment) bool {
+	switch deployment.Source {
+	case models.DeploymentSourceRelease:
+		if deployment.Status != "published" {
+			return false
+		}
+	case models.DeploymentSourceDeployment:
+		// Deployments still in progress or never started don't count yet
+		switch deployment.Status {
+		case "success", "inactive", "failure", "error":
+		default:
+			return false
+		}
+		return true
+	}
+	if settings.TagPattern == "" {
+		return true
+	}
+	matched, err := path.Match(settings.TagPattern, deployment.Name)
+	return err == nil && matched
+}
+
+// setDORARates sets the deployment frequency over the number of days and the change failure rate
+func setDORARates(stats *models.DORAStats, days float64) {
+	if stats.Deployments == 0 {
+		return
+	}
+	perWeek := float64(stats.Deployments) / days * 7
+	stats.PerWeek = &perWeek
+	rate := float64(stats.Failures) / float64(stats.Deployments)
+	stats.ChangeFailureRate = &rate
+}
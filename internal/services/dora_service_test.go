@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildDORAReport(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2025, 8, day, hour, 0, 0, 0, time.UTC)
+	}
+	release := func(id, name, status string, deployedAt time.Time) *models.Deployment {
+		return &models.Deployment{ID: id, RepositoryID: "repo", Source: models.DeploymentSourceRelease, ExternalID: id, Name: name, Status: status, DeployedAt: deployedAt}
+	}
+	settings := models.NewDORASettings("project")
+	settings.TagPattern = "v*"
+	settings.FailureLabels = "Hotfix"
+
+	commits := []*models.Commit{
+		{GithubRepositoryID: "repo", CommitSHA: "c1", Message: "Add feature", CommitDate: at(1, 0)},
+		{GithubRepositoryID: "repo", CommitSHA: "c2", Message: "Change feature", CommitDate: at(2, 0)},
+		{GithubRepositoryID: "repo", CommitSHA: "c3", Message: `Revert "Add feature"`, CommitDate: at(4, 0)},
+		{GithubRepositoryID: "repo", CommitSHA: "c4", Message: "Fix crash", CommitDate: at(7, 0)},
+	}
+	deployments := []*models.Deployment{
+		release("r1", "v1.0", "published", at(1, 12)),
+		// A prerelease passes its commits on to the next release
+		release("r2", "v1.1-rc", "prerelease", at(3, 0)),
+		// Reverts the first release
+		release("r3", "v1.1", "published", at(5, 0)),
+		// Ships a hotfix for the second release
+		release("r4", "v1.2", "published", at(8, 0)),
+		// Doesn't match the tag pattern
+		release("r5", "nightly", "published", at(9, 0)),
+	}
+	shipped := []*models.DeploymentCommit{
+		{DeploymentID: "r1", CommitSHA: "c1"},
+		{DeploymentID: "r2", CommitSHA: "c2"},
+		{DeploymentID: "r3", CommitSHA: "c3"},
+		{DeploymentID: "r4", CommitSHA: "c4"},
+	}
+	pullRequests := []*models.PullRequest{{ID: "pr1", RepositoryID: "repo"}}
+	prCommits := []*models.PRCommit{{RepositoryID: "repo", PullRequestID: "pr1", CommitSHA: "c4"}}
+	labels := []*models.PRLabel{{PullRequestID: "pr1", Name: "hotfix"}}
+
+	report := buildDORAReport(settings, deployments, shipped, commits, pullRequests, prCommits, labels, at(1, 0), at(15, 0), at(20, 0))
+
+	if assert.Len(t, report.Repositories, 1) {
+		stats := report.Repositories[0]
+		assert.Equal(t, 3, stats.Deployments)
+		assert.Equal(t, 2, stats.Failures)
+		assert.Equal(t, 0, stats.Unrestored)
+		if assert.NotNil(t, stats.PerWeek) {
+			assert.InDelta(t, 1.5, *stats.PerWeek, 0.001)
+		}
+		if assert.NotNil(t, stats.ChangeFailureRate) {
+			assert.InDelta(t, 2.0/3, *stats.ChangeFailureRate, 0.001)
+		}
+		assert.Equal(t, 4, stats.LeadTime.Count)
+		assert.Equal(t, (24 * time.Hour).Seconds(), stats.LeadTime.Median)
+		assert.Equal(t, 2, stats.TimeToRestore.Count)
+		assert.Equal(t, (114 * time.Hour).Seconds(), stats.TimeToRestore.Median)
+	}
+	assert.Equal(t, 3, report.Project.Deployments)
+	assert.Equal(t, 4, report.Project.LeadTime.Count)
+
+	// A later period only counts the last release
+	report = buildDORAReport(settings, deployments, shipped, commits, pullRequests, prCommits, labels, at(6, 0), at(15, 0), at(20, 0))
+	assert.Equal(t, 1, report.Project.Deployments)
+	assert.Equal(t, 0, report.Project.Failures)
+}
+
+func TestBuildDORAReportDeployments(t *testing.T) {
+	at := func(day int) time.Time {
+		return time.Date(2025, 8, day, 0, 0, 0, 0, time.UTC)
+	}
+	production, staging := "Production", "staging"
+	deployment := func(id string, environment *string, status string, day int) *models.Deployment {
+		return &models.Deployment{ID: id, RepositoryID: "repo", Source: models.DeploymentSourceDeployment, ExternalID: id, Environment: environment, Status: status, DeployedAt: at(day)}
+	}
+	settings := models.NewDORASettings("project")
+	settings.DeploySource = models.DeploymentSourceDeployment
+
+	deployments := []*models.Deployment{
+		deployment("d1", &production, "success", 1),
+		deployment("d2", &staging, "failure", 2),
+		deployment("d3", &production, "failure", 3),
+		// Still running
+		deployment("d4", &production, "in_progress", 4),
+		// Releases are ignored when deployments are the deploys
+		{ID: "r1", RepositoryID: "repo", Source: models.DeploymentSourceRelease, Name: "v1", Status: "published", DeployedAt: at(2)},
+	}
+
+	report := buildDORAReport(settings, deployments, nil, nil, nil, nil, nil, time.Time{}, time.Time{}, at(10))
+
+	assert.Equal(t, 2, report.Project.Deployments)
+	assert.Equal(t, 1, report.Project.Failures)
+	assert.Equal(t, 1, report.Project.Unrestored)
+	assert.Equal(t, 0, report.Project.TimeToRestore.Count)
+	if assert.NotNil(t, report.Project.PerWeek) {
+		// All time spans the two days between the first and last deploy
+		assert.InDelta(t, 7, *report.Project.PerWeek, 0.001)
+	}
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+	"github.com/google/go-github/v57/github"
+)
+
+// installationTokenRefreshMargin is how long before expiry a cached installation token is replaced
+const installationTokenRefreshMargin = 5 * time.Minute
+
+type installationToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// GitHubAppService mints installation tokens for the configured GitHub App and
+// resolves which token background jobs of a project should use
+type GitHubAppService struct {
+	appID            int64
+	appSlug          string
+	privateKey       *rsa.PrivateKey
+	installationRepo *repositories.ProjectGitHubInstallationRepository
+	projectRepo      *repositories.ProjectRepository
+	userRepo         *repositories.UserRepository
+	collaboratorRepo *repositories.ProjectCollaboratorRepository
+
+	mu     sync.Mutex
+	tokens map[int64]installationToken
+}
+
+func NewGitHubAppService(
+	appID int64,
+	appSlug string,
+	privateKeyPEM string,
+	installationRepo *repositories.ProjectGitHubInstallationRepository,
+	projectRepo *repositories.ProjectRepository,
+	userRepo *repositories.UserRepository,
+	collaboratorRepo *repositories.ProjectCollaboratorRepository,
+) *GitHubAppService {
+	service := &GitHubAppService{
+		appID:            appID,
+		appSlug:          appSlug,
+		installationRepo: installationRepo,
+		projectRepo:      projectRepo,
+		userRepo:         userRepo,
+		collaboratorRepo: collaboratorRepo,
+		tokens:           make(map[int64]installationToken),
+	}
+
+	if appID != 0 && privateKeyPEM != "" {
+		key, err := parseAppPrivateKey([]byte(privateKeyPEM))
+		if err != nil {
+			log.Printf("GitHub App disabled: %v", err)
+		} else {
+			service.privateKey = key
+		}
+	}
+
+	return service
+}
+
+// IsConfigured reports whether a GitHub App ID and a valid private key are available
+func (s *GitHubAppService) IsConfigured() bool {
+	return s.appID != 0 && s.privateKey != nil
+}
+
+// InstallURL returns the page where the app can be installed on an account, if the slug is known
+func (s *GitHubAppService) InstallURL() string {
+	if s.appSlug == "" {
+		return ""
+	}
+	return "https://github.com/apps/" + s.appSlug + "/installations/new"
+}
+
+// GetProjectInstallation returns the installation mapped to a project, or nil when none is mapped
+func (s *GitHubAppService) GetProjectInstallation(projectID string) (*models.ProjectGitHubInstallation, error) {
+	installation, err := s.installationRepo.GetByProjectID(projectID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return installation, err
+}
+
+// SetProjectInstallation maps an installation of the app to a project after checking that the user
+// connecting it can access the installation on GitHub, so that projects can't borrow the installations
+// of other accounts
+func (s *GitHubAppService) SetProjectInstallation(projectID, userID string, installationID int64) (*models.ProjectGitHubInstallation, error) {
+	if !s.IsConfigured() {
+		return nil, &models.ValidationError{Message: "GitHub App is not configured on this server"}
+	}
+
+	installation := models.NewProjectGitHubInstallation(projectID, installationID, nil)
+	if err := installation.Validate(); err != nil {
+		return nil, err
+	}
+
+	userInstallations, err := s.ListUserInstallations(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var githubInstallation *github.Installation
+	for _, candidate := range userInstallations {
+		if candidate.GetID() == installationID {
+			githubInstallation = candidate
+			break
+		}
+	}
+	if githubInstallation == nil {
+		return nil, &models.ValidationError{Field: "installation_id", Message: "Installation not found among the GitHub App installations you can access"}
+	}
+	if login := githubInstallation.GetAccount().GetLogin(); login != "" {
+		installation.AccountLogin = &login
+	}
+
+	if err := s.installationRepo.Upsert(installation); err != nil {
+		return nil, err
+	}
+
+	return installation, nil
+}
+
+// RemoveProjectInstallation removes the installation mapping of a project
+func (s *GitHubAppService) RemoveProjectInstallation(projectID string) error {
+	return s.installationRepo.DeleteByProjectID(projectID)
+}
+
+// ListUserInstallations lists the installations of the app a user can access on GitHub with their
+// own token
+func (s *GitHubAppService) ListUserInstallations(userID string) ([]*github.Installation, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.GitHubAccessToken == "" {
+		return nil, fmt.Errorf("user has no GitHub access token")
+	}
+
+	client := github.NewClient(nil).WithAuthToken(user.GitHubAccessToken)
+
+	var allInstallations []*github.Installation
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		installations, resp, err := client.Apps.ListUserInstallations(context.Background(), opt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list installations: %w", err)
+		}
+		for _, installation := range installations {
+			if installation.GetAppID() == s.appID {
+				allInstallations = append(allInstallations, installation)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return allInstallations, nil
+}
+
+// GetInstallationToken returns a short-lived token for an installation, reusing a cached one until shortly before it expires
+func (s *GitHubAppService) GetInstallationToken(installationID int64) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cached, ok := s.tokens[installationID]; ok && time.Now().Add(installationTokenRefreshMargin).Before(cached.expiresAt) {
+		return cached.token, nil
+	}
+
+	client, err := s.appClient()
+	if err != nil {
+		return "", err
+	}
+
+	token, _, err := client.Apps.CreateInstallationToken(context.Background(), installationID, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create installation token: %w", err)
+	}
+
+	s.tokens[installationID] = installationToken{
+		token:     token.GetToken(),
+		expiresAt: token.GetExpiresAt().Time,
+	}
+
+	return token.GetToken(), nil
+}
+
+// GetProjectToken returns the token background jobs of a project should use.
+// Projects mapped to an installation get an installation token. Other projects use
+// the project owner's OAuth token as before, whether or not an app is configured.
+func (s *GitHubAppService) GetProjectToken(projectID string) (string, error) {
+	installation, err := s.GetProjectInstallation(projectID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get GitHub App installation: %w", err)
+	}
+
+	if installation != nil {
+		if !s.IsConfigured() {
+			return "", fmt.Errorf("project is mapped to GitHub App installation %d but the app is not configured", installation.InstallationID)
+		}
+		return s.GetInstallationToken(installation.InstallationID)
+	}
+
+	return s.getOwnerToken(projectID)
+}
+
+// ProjectToken is a GitHub token background jobs of a project may use
+type ProjectToken struct {
+	Label string
+	Token string
+}
+
+// GetProjectTokens returns every token background jobs of a project may spend.
+// A connected installation provides a single token. Without one, the owner's OAuth
+// token is followed by those of collaborators who agreed to share theirs.
+func (s *GitHubAppService) GetProjectTokens(projectID string) ([]ProjectToken, error) {
+	installation, err := s.GetProjectInstallation(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitHub App installation: %w", err)
+	}
+
+	if installation != nil {
+		token, err := s.GetProjectToken(projectID)
+		if err != nil {
+			return nil, err
+		}
+		label := "GitHub App"
+		if installation.AccountLogin != nil {
+			label = "GitHub App (" + *installation.AccountLogin + ")"
+		}
+		return []ProjectToken{{Label: label, Token: token}}, nil
+	}
+
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	var tokens []ProjectToken
+	owner, err := s.userRepo.GetByID(project.OwnerID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project owner: %w", err)
+	}
+	if owner.GitHubAccessToken != "" {
+		tokens = append(tokens, ProjectToken{Label: owner.Username, Token: owner.GitHubAccessToken})
+	}
+
+	collaborators, err := s.collaboratorRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project collaborators: %w", err)
+	}
+	for _, collaborator := range collaborators {
+		if !collaborator.ShareGitHubToken {
+			continue
+		}
+		user, err := s.userRepo.GetByID(collaborator.UserID)
+		if err != nil {
+			log.Printf("Failed to get collaborator %s: %v", collaborator.UserID, err)
+			continue
+		}
+		if user.GitHubAccessToken != "" {
+			tokens = append(tokens, ProjectToken{Label: user.Username, Token: user.GitHubAccessToken})
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no GitHub token available for project")
+	}
+
+	return tokens, nil
+}
+
+// getOwnerToken returns the OAuth token of the project owner
+func (s *GitHubAppService) getOwnerToken(projectID string) (string, error) {
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get project: %w", err)
+	}
+
+	owner, err := s.userRepo.GetByID(project.OwnerID.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to get project owner: %w", err)
+	}
+
+	if owner.GitHubAccessToken == "" {
+		return "", fmt.Errorf("project owner has no GitHub access token")
+	}
+
+	return owner.GitHubAccessToken, nil
+}
+
+// appClient creates a GitHub client authenticated as the app itself
+func (s *GitHubAppService) appClient() (*github.Client, error) {
+	if !s.IsConfigured() {
+		return nil, fmt.Errorf("GitHub App is not configured")
+	}
+
+	jwt, err := createAppJWT(s.appID, s.privateKey, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub App JWT: %w", err)
+	}
+
+	return github.NewClient(nil).WithAuthToken(jwt), nil
+}
+
+// createAppJWT creates the RS256 signed JWT GitHub expects when authenticating as an app.
+// The issue time is backdated to allow for clock drift and the token lives for less than
+// the ten minutes GitHub allows.
+func createAppJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-60 * time.Second).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	hash := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parseAppPrivateKey parses a PEM encoded RSA key in PKCS#1 or PKCS#8 form
+func parseAppPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/repositories"
+	"github.com/google/go-github/v57/github"
+)
+
+// projectTokensTTL is how long a project's token list is reused before it is resolved again,
+// so new collaborators and refreshed installation tokens are picked up during long jobs
+const projectTokensTTL = time.Minute
+
+// GitHubTokenQuota is the last known rate limit of one of a project's tokens
+type GitHubTokenQuota struct {
+	Label     string
+	Known     bool
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// GitHubClientPool hands out GitHub clients that rotate across all tokens a project may
+// use and throttle against the shared rate limit budget. GET requests are revalidated
+// against the response cache so unchanged data doesn't count against the rate limit.
+type GitHubClientPool struct {
+	githubAppService *GitHubAppService
+	budget           *GitHubRateBudget
+	cacheRepo        *repositories.GitHubHTTPCacheRepository
+}
+
+func NewGitHubClientPool(githubAppService *GitHubAppService, budget *GitHubRateBudget, cacheRepo *repositories.GitHubHTTPCacheRepository) *GitHubClientPool {
+	return &GitHubClientPool{
+		githubAppService: githubAppService,
+		budget:           budget,
+		cacheRepo:        cacheRepo,
+	}
+}
+
+// ProjectClient returns a client for background jobs of a project. Requests are counted in stats when it is not nil.
+func (p *GitHubClientPool) ProjectClient(projectID string, stats *GitHubRequestStats) (*github.Client, error) {
+	httpClient, err := p.projectHTTPClient(projectID, stats)
+	if err != nil {
+		return nil, err
+	}
+	return github.NewClient(httpClient), nil
+}
+
+// ProjectGraphQLClient returns a GraphQL client for background jobs of a project, sharing
+// the token rotation and rate limit budget of ProjectClient
+func (p *GitHubClientPool) ProjectGraphQLClient(projectID string, stats *GitHubRequestStats) (*GitHubGraphQLClient, error) {
+	httpClient, err := p.projectHTTPClient(projectID, stats)
+	if err != nil {
+		return nil, err
+	}
+	return NewGitHubGraphQLClient(httpClient), nil
+}
+
+// projectHTTPClient creates an HTTP client that authenticates with the project's tokens
+func (p *GitHubClientPool) projectHTTPClient(projectID string, stats *GitHubRequestStats) (*http.Client, error) {
+	// Fail early when the project has no usable token at all
+	if _, err := p.githubAppService.GetProjectTokens(projectID); err != nil {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	var cached []string
+	var resolvedAt time.Time
+
+	transport := p.budget.Transport(func() ([]string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if cached != nil && time.Since(resolvedAt) < projectTokensTTL {
+			return cached, nil
+		}
+
+		tokens, err := p.githubAppService.GetProjectTokens(projectID)
+		if err != nil {
+			return nil, err
+		}
+
+		cached = make([]string, 0, len(tokens))
+		for _, token := range tokens {
+			cached = append(cached, token.Token)
+		}
+		resolvedAt = time.Now()
+		return cached, nil
+	})
+
+	// The project's tokens are shared by its jobs, so its responses are cached per project
+	transport = &githubCacheTransport{
+		cacheRepo: p.cacheRepo,
+		scope:     "project:" + projectID,
+		stats:     stats,
+		base:      transport,
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// ProjectToken returns the project token with the most core budget left, for callers
+// that need a raw token rather than a client
+func (p *GitHubClientPool) ProjectToken(projectID string) (string, error) {
+	tokens, err := p.githubAppService.GetProjectTokens(projectID)
+	if err != nil {
+		return "", err
+	}
+
+	values := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		values = append(values, token.Token)
+	}
+
+	token, _ := p.budget.Pick(values, rateResourceCore, time.Now())
+	return token, nil
+}
+
+// ProjectQuota returns the remaining core rate limit of every token a project may use
+func (p *GitHubClientPool) ProjectQuota(projectID string) ([]GitHubTokenQuota, error) {
+	tokens, err := p.githubAppService.GetProjectTokens(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := make([]GitHubTokenQuota, 0, len(tokens))
+	for _, token := range tokens {
+		quota := GitHubTokenQuota{Label: token.Label}
+		if rate, ok := p.budget.Get(token.Token, rateResourceCore); ok && time.Now().Before(rate.Reset.Time) {
+			quota.Known = true
+			quota.Limit = rate.Limit
+			quota.Remaining = rate.Remaining
+			quota.Reset = rate.Reset.Time
+		}
+		quotas = append(quotas, quota)
+	}
+
+	return quotas, nil
+}
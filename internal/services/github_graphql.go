@@ -0,0 +1,103 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// githubGraphQLURL is the GraphQL endpoint of github.com
+const githubGraphQLURL = "https://api.github.com/graphql"
+
+// GitHubGraphQLClient runs queries against the GitHub GraphQL API
+type GitHubGraphQLClient struct {
+	httpClient *http.Client
+	endpoint   string
+}
+
+func NewGitHubGraphQLClient(httpClient *http.Client) *GitHubGraphQLClient {
+	return &GitHubGraphQLClient{
+		httpClient: httpClient,
+		endpoint:   githubGraphQLURL,
+	}
+}
+
+// GitHubGraphQLError is a single error reported in a GraphQL response
+type GitHubGraphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// GitHubGraphQLErrors is returned when GitHub reports errors for a query
+type GitHubGraphQLErrors []GitHubGraphQLError
+
+func (e GitHubGraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		if err.Type != "" {
+			messages = append(messages, err.Type+": "+err.Message)
+		} else {
+			messages = append(messages, err.Message)
+		}
+	}
+	return "GitHub GraphQL: " + strings.Join(messages, "; ")
+}
+
+// Query runs a query and decodes its data into result. Responses with errors are
+// rejected even when they carry partial data.
+func (c *GitHubGraphQLClient) Query(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub GraphQL request failed with status %d: %s", resp.StatusCode, truncate(string(body), 200))
+	}
+
+	var envelope struct {
+		Data   json.RawMessage     `json:"data"`
+		Errors GitHubGraphQLErrors `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("failed to decode GitHub GraphQL response: %w", err)
+	}
+	if len(envelope.Errors) > 0 {
+		return envelope.Errors
+	}
+
+	return json.Unmarshal(envelope.Data, result)
+}
+
+// truncate shortens s to at most n bytes for log and error messages
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubGraphQLClientQuery(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        int
+		response      string
+		expectedLogin string
+		expectedError string
+	}{
+		{
+			name:          "Data is decoded",
+			status:        http.StatusOK,
+			response:      `{"data":{"viewer":{"login":"octocat"}}}`,
+			expectedLogin: "octocat",
+		},
+		{
+			name:          "Errors are returned",
+			status:        http.StatusOK,
+			response:      `{"data":null,"errors":[{"type":"NOT_FOUND","message":"Could not resolve to a Repository"}]}`,
+			expectedError: "GitHub GraphQL: NOT_FOUND: Could not resolve to a Repository",
+		},
+		{
+			name:          "HTTP failures are returned",
+			status:        http.StatusBadGateway,
+			response:      `bad gateway`,
+			expectedError: "GitHub GraphQL request failed with status 502: bad gateway",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var payload struct {
+				Query     string                 `json:"query"`
+				Variables map[string]interface{} `json:"variables"`
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.response))
+			}))
+			defer server.Close()
+
+			client := NewGitHubGraphQLClient(server.Client())
+			client.endpoint = server.URL
+
+			var result struct {
+				Viewer struct {
+					Login string `json:"login"`
+				} `json:"viewer"`
+			}
+			err := client.Query(context.Background(), "query { viewer { login } }", map[string]interface{}{"n": 1}, &result)
+
+			assert.Equal(t, "query { viewer { login } }", payload.Query)
+			assert.Equal(t, float64(1), payload.Variables["n"])
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLogin, result.Viewer.Login)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+// GitHubRequestStats counts the GitHub API requests of a job and how many were answered from the cache
+type GitHubRequestStats struct {
+	requests  atomic.Int64
+	cacheHits atomic.Int64
+}
+
+// Requests returns the number of requests made
+func (s *GitHubRequestStats) Requests() int64 {
+	return s.requests.Load()
+}
+
+// CacheHits returns the number of requests GitHub answered with 304 Not Modified
+func (s *GitHubRequestStats) CacheHits() int64 {
+	return s.cacheHits.Load()
+}
+
+// rateLimitHeaders are copied from a 304 response onto the cached one so callers see the current rate limit
+var rateLimitHeaders = []string{
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+	"X-RateLimit-Used",
+	"X-RateLimit-Resource",
+}
+
+// githubCacheTransport makes GET requests conditional on the ETag or Last-Modified date of the
+// stored response. GitHub answers unchanged data with 304 Not Modified, which doesn't count
+// against the rate limit, and the stored response is returned instead.
+type githubCacheTransport struct {
+	cacheRepo *repositories.GitHubHTTPCacheRepository
+	scope     string // Credentials the responses were fetched with; entries aren't shared across scopes
+	stats     *GitHubRequestStats
+	base      http.RoundTripper
+}
+
+func (t *githubCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.stats != nil {
+		t.stats.requests.Add(1)
+	}
+
+	if req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+
+	key := cacheKey(t.scope, req)
+	entry, err := t.cacheRepo.Get(key)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Failed to read GitHub cache for %s: %v", req.URL, err)
+	}
+
+	condReq := req
+	if entry != nil {
+		condReq = req.Clone(req.Context())
+		if entry.ETag != nil {
+			condReq.Header.Set("If-None-Match", *entry.ETag)
+		}
+		if entry.LastModified != nil {
+			condReq.Header.Set("If-Modified-Since", *entry.LastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(condReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		cached, err := cachedResponse(req, resp, entry)
+		if err != nil {
+			log.Printf("Failed to restore cached GitHub response for %s: %v", req.URL, err)
+			return resp, nil
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if t.stats != nil {
+			t.stats.cacheHits.Add(1)
+		}
+		if err := t.cacheRepo.Touch(key); err != nil {
+			log.Printf("Failed to touch GitHub cache for %s: %v", req.URL, err)
+		}
+		return cached, nil
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		t.store(key, req, resp)
+	}
+
+	return resp, nil
+}
+
+// store saves a response that carries a validator so it can be revalidated later
+func (t *githubCacheTransport) store(key string, req *http.Request, resp *http.Response) {
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	header, err := json.Marshal(resp.Header)
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	entry := &models.GitHubHTTPCacheEntry{
+		CacheKey:  key,
+		URL:       req.URL.String(),
+		Header:    string(header),
+		Body:      body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if etag != "" {
+		entry.ETag = &etag
+	}
+	if lastModified != "" {
+		entry.LastModified = &lastModified
+	}
+
+	if err := t.cacheRepo.Upsert(entry); err != nil {
+		log.Printf("Failed to store GitHub cache for %s: %v", req.URL, err)
+	}
+}
+
+// cachedResponse rebuilds a 200 response from a cache entry, keeping the rate limit headers of the 304 response
+func cachedResponse(req *http.Request, notModified *http.Response, entry *models.GitHubHTTPCacheEntry) (*http.Response, error) {
+	header := http.Header{}
+	if err := json.Unmarshal([]byte(entry.Header), &header); err != nil {
+		return nil, err
+	}
+
+	for _, name := range rateLimitHeaders {
+		if value := notModified.Header.Get(name); value != "" {
+			header.Set(name, value)
+		}
+	}
+	header.Set("X-From-Cache", "1")
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         notModified.Proto,
+		ProtoMajor:    notModified.ProtoMajor,
+		ProtoMinor:    notModified.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}, nil
+}
+
+// cacheKey identifies a request by the credentials it's made with, its URL and the representation it
+// asks for. The scope names the tokens the transport authenticates with; a token set on the request
+// itself is part of the key as well.
+func cacheKey(scope string, req *http.Request) string {
+	sum := sha256.Sum256([]byte(
+		scope + " " + req.Header.Get("Authorization") + " " +
+			req.Method + " " + req.URL.String() + " " + req.Header.Get("Accept"),
+	))
+	return hex.EncodeToString(sum[:])
+}
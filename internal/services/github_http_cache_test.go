@@ -0,0 +1,54 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedResponse(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://api.github.com/repos/a/b/pulls", nil)
+	require.NoError(t, err)
+
+	notModified := &http.Response{StatusCode: http.StatusNotModified, Header: http.Header{}}
+	notModified.Header.Set("X-RateLimit-Remaining", "4999")
+
+	entry := &models.GitHubHTTPCacheEntry{
+		Header: `{"Content-Type":["application/json"],"X-Ratelimit-Remaining":["100"]}`,
+		Body:   []byte(`[{"number":1}]`),
+	}
+
+	resp, err := cachedResponse(req, notModified, entry)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "4999", resp.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "1", resp.Header.Get("X-From-Cache"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `[{"number":1}]`, string(body))
+}
+
+func TestCacheKeyDependsOnAccept(t *testing.T) {
+	a, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/a/b", nil)
+	b, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/a/b", nil)
+	b.Header.Set("Accept", "application/vnd.github.diff")
+
+	assert.NotEqual(t, cacheKey("project:1", a), cacheKey("project:1", b))
+	assert.Equal(t, cacheKey("project:1", a), cacheKey("project:1", a.Clone(a.Context())))
+}
+
+func TestCacheKeyDependsOnCredentials(t *testing.T) {
+	a, _ := http.NewRequest(http.MethodGet, "https://api.github.com/repos/a/b", nil)
+	b := a.Clone(a.Context())
+	b.Header.Set("Authorization", "Bearer other")
+
+	// Responses fetched for one project or token are never served to another
+	assert.NotEqual(t, cacheKey("project:1", a), cacheKey("project:2", a))
+	assert.NotEqual(t, cacheKey("project:1", a), cacheKey("project:1", b))
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+const (
+	// defaultGitHubRateLimit is assumed for tokens that haven't made a request yet
+	defaultGitHubRateLimit = 5000
+
+	rateResourceCore    = "core"
+	rateResourceSearch  = "search"
+	rateResourceGraphQL = "graphql"
+)
+
+// GitHubRateBudget tracks the remaining GitHub rate limit of every token in use,
+// shared by all workers and services so they throttle against the same numbers
+type GitHubRateBudget struct {
+	mu      sync.Mutex
+	rates   map[string]github.Rate
+	reserve int
+}
+
+func NewGitHubRateBudget(reserve int) *GitHubRateBudget {
+	return &GitHubRateBudget{
+		rates:   make(map[string]github.Rate),
+		reserve: reserve,
+	}
+}
+
+// rateKey identifies a token and resource without keeping the token itself as a map key
+func rateKey(token, resource string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8]) + ":" + resource
+}
+
+// Update records the rate limit GitHub reported for a token
+func (b *GitHubRateBudget) Update(token, resource string, rate github.Rate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.rates[rateKey(token, resource)] = rate
+}
+
+// Get returns the last known rate limit of a token
+func (b *GitHubRateBudget) Get(token, resource string) (github.Rate, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	rate, ok := b.rates[rateKey(token, resource)]
+	return rate, ok
+}
+
+// Pick returns the token with the most requests left for a resource and reserves one
+// request of it. When every token is down to its reserve, it returns the token whose
+// window resets first together with how long to wait before using it.
+func (b *GitHubRateBudget) Pick(tokens []string, resource string, now time.Time) (string, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bestToken := ""
+	bestRemaining := -1
+	earliestToken := ""
+	var earliestReset time.Time
+
+	for _, token := range tokens {
+		key := rateKey(token, resource)
+		rate, ok := b.rates[key]
+		if !ok || !now.Before(rate.Reset.Time) {
+			// Unknown or expired windows start full
+			rate = github.Rate{Limit: defaultGitHubRateLimit, Remaining: defaultGitHubRateLimit}
+			b.rates[key] = rate
+		}
+
+		if rate.Remaining > b.reserve {
+			if rate.Remaining > bestRemaining {
+				bestToken = token
+				bestRemaining = rate.Remaining
+			}
+			continue
+		}
+
+		if earliestToken == "" || rate.Reset.Time.Before(earliestReset) {
+			earliestToken = token
+			earliestReset = rate.Reset.Time
+		}
+	}
+
+	if bestToken != "" {
+		key := rateKey(bestToken, resource)
+		rate := b.rates[key]
+		rate.Remaining--
+		b.rates[key] = rate
+		return bestToken, 0
+	}
+
+	return earliestToken, earliestReset.Sub(now)
+}
+
+// Best returns the highest known remaining budget across tokens, used to present
+// the pool as a single rate limit to callers
+func (b *GitHubRateBudget) Best(tokens []string, resource string) (github.Rate, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var best github.Rate
+	found := false
+	for _, token := range tokens {
+		rate, ok := b.rates[rateKey(token, resource)]
+		if !ok {
+			continue
+		}
+		if !found || rate.Remaining > best.Remaining {
+			best = rate
+			found = true
+		}
+	}
+	return best, found
+}
+
+// NewClient creates a GitHub client that spends this budget for the tokens returned by tokens
+func (b *GitHubRateBudget) NewClient(tokens func() ([]string, error)) *github.Client {
+	return github.NewClient(&http.Client{Transport: b.Transport(tokens)})
+}
+
+// Transport returns an authenticating transport that spends this budget for the tokens returned by tokens
+func (b *GitHubRateBudget) Transport(tokens func() ([]string, error)) http.RoundTripper {
+	return &githubTokenTransport{
+		budget: b,
+		tokens: tokens,
+		base:   http.DefaultTransport,
+	}
+}
+
+// NewTokenClient creates a GitHub client for a single token that spends this budget
+func (b *GitHubRateBudget) NewTokenClient(token string) *github.Client {
+	return b.NewClient(func() ([]string, error) {
+		return []string{token}, nil
+	})
+}
+
+// githubTokenTransport authenticates each request with the token that has the most budget
+// left, waits when all tokens are exhausted and records the rate limit of every response
+type githubTokenTransport struct {
+	budget *GitHubRateBudget
+	tokens func() ([]string, error)
+	base   http.RoundTripper
+}
+
+func (t *githubTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tokens, err := t.tokens()
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no GitHub token available")
+	}
+
+	resource := requestRateResource(req)
+
+	// A request can be retried with another token unless its body can't be replayed
+	attempts := len(tokens)
+	if req.Body != nil && req.GetBody == nil {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		token, err := t.waitForToken(req.Context(), tokens, resource)
+		if err != nil {
+			return nil, err
+		}
+
+		authReq := req.Clone(req.Context())
+		if attempt > 1 && req.GetBody != nil {
+			if authReq.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		authReq.Header.Set("Authorization", "Bearer "+token)
+
+		resp, err := t.base.RoundTrip(authReq)
+		if err != nil {
+			return nil, err
+		}
+
+		rate, ok := parseRateHeaders(resp.Header)
+		if !ok {
+			return resp, nil
+		}
+		if headerResource := resp.Header.Get("X-RateLimit-Resource"); headerResource != "" {
+			resource = headerResource
+		}
+		t.budget.Update(token, resource, rate)
+
+		if isRateLimitedResponse(resp, rate) && attempt < attempts {
+			log.Printf("GitHub rate limit of a token exhausted for %s, retrying with another token", resource)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			continue
+		}
+
+		// Report the pool's best budget so the client's own limit check
+		// doesn't block requests that another token can still serve
+		if best, ok := t.budget.Best(tokens, resource); ok && best.Remaining > rate.Remaining {
+			resp.Header.Set("X-RateLimit-Limit", strconv.Itoa(best.Limit))
+			resp.Header.Set("X-RateLimit-Remaining", strconv.Itoa(best.Remaining))
+			resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(best.Reset.Unix(), 10))
+		}
+
+		return resp, nil
+	}
+}
+
+// waitForToken picks a token, sleeping until a rate limit window resets when all are exhausted
+func (t *githubTokenTransport) waitForToken(ctx context.Context, tokens []string, resource string) (string, error) {
+	for {
+		token, wait := t.budget.Pick(tokens, resource, time.Now())
+		if wait <= 0 {
+			return token, nil
+		}
+
+		log.Printf("GitHub %s rate limit exhausted for all tokens, waiting %v", resource, wait.Round(time.Second))
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// requestRateResource returns the rate limit resource a request is counted against
+func requestRateResource(req *http.Request) string {
+	path := req.URL.Path
+	switch {
+	case path == "/graphql" || strings.HasSuffix(path, "/graphql"):
+		return rateResourceGraphQL
+	case strings.HasPrefix(path, "/search/") || strings.Contains(path, "/api/v3/search/"):
+		return rateResourceSearch
+	default:
+		return rateResourceCore
+	}
+}
+
+// parseRateHeaders reads the rate limit headers of a response. X-RateLimit-Reset is a unix timestamp.
+func parseRateHeaders(header http.Header) (github.Rate, bool) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return github.Rate{}, false
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return github.Rate{}, false
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return github.Rate{}, false
+	}
+
+	return github.Rate{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     github.Timestamp{Time: time.Unix(reset, 0)},
+	}, true
+}
+
+// isRateLimitedResponse reports whether GitHub rejected a request because the token's primary limit is used up
+func isRateLimitedResponse(resp *http.Response, rate github.Rate) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return false
+	}
+	return rate.Remaining == 0
+}
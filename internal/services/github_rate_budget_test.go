@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubRateBudgetPick(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	reset := func(d time.Duration) github.Timestamp {
+		return github.Timestamp{Time: now.Add(d)}
+	}
+
+	testCases := []struct {
+		name          string
+		rates         map[string]github.Rate
+		expectedToken string
+		expectedWait  time.Duration
+	}{
+		{
+			name:          "Unknown tokens are treated as full",
+			rates:         map[string]github.Rate{"a": {Limit: 5000, Remaining: 100, Reset: reset(time.Hour)}},
+			expectedToken: "b",
+		},
+		{
+			name: "Token with most remaining wins",
+			rates: map[string]github.Rate{
+				"a": {Limit: 5000, Remaining: 300, Reset: reset(time.Hour)},
+				"b": {Limit: 5000, Remaining: 4000, Reset: reset(time.Hour)},
+			},
+			expectedToken: "b",
+		},
+		{
+			name: "Expired window is full again",
+			rates: map[string]github.Rate{
+				"a": {Limit: 5000, Remaining: 0, Reset: reset(-time.Minute)},
+				"b": {Limit: 5000, Remaining: 4000, Reset: reset(time.Hour)},
+			},
+			expectedToken: "a",
+		},
+		{
+			name: "All exhausted waits for earliest reset",
+			rates: map[string]github.Rate{
+				"a": {Limit: 5000, Remaining: 10, Reset: reset(30 * time.Minute)},
+				"b": {Limit: 5000, Remaining: 0, Reset: reset(10 * time.Minute)},
+			},
+			expectedToken: "b",
+			expectedWait:  10 * time.Minute,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			budget := NewGitHubRateBudget(50)
+			for token, rate := range tc.rates {
+				budget.Update(token, rateResourceCore, rate)
+			}
+
+			token, wait := budget.Pick([]string{"a", "b"}, rateResourceCore, now)
+			assert.Equal(t, tc.expectedToken, token)
+			assert.Equal(t, tc.expectedWait, wait)
+		})
+	}
+}
+
+func TestParseRateHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "5000")
+	header.Set("X-RateLimit-Remaining", "4321")
+	header.Set("X-RateLimit-Reset", "1700003600")
+
+	rate, ok := parseRateHeaders(header)
+	require.True(t, ok)
+	assert.Equal(t, 5000, rate.Limit)
+	assert.Equal(t, 4321, rate.Remaining)
+	assert.Equal(t, time.Unix(1700003600, 0), rate.Reset.Time)
+
+	_, ok = parseRateHeaders(http.Header{})
+	assert.False(t, ok)
+}
+
+func TestGitHubTokenTransportRotatesOnRateLimit(t *testing.T) {
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	var seen []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		seen = append(seen, auth)
+
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Reset", reset)
+		if auth == "Bearer exhausted" {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "4000")
+		w.Write([]byte(`{"login":"octocat"}`))
+	}))
+	defer server.Close()
+
+	budget := NewGitHubRateBudget(50)
+	client := budget.NewClient(func() ([]string, error) {
+		return []string{"exhausted", "fresh"}, nil
+	})
+	client.BaseURL, _ = client.BaseURL.Parse(server.URL + "/")
+
+	// Both tokens look equally full, so the first request may hit the exhausted one
+	user, _, err := client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+	assert.Equal(t, "octocat", user.GetLogin())
+	assert.Equal(t, "Bearer fresh", seen[len(seen)-1])
+
+	rate, ok := budget.Get("exhausted", rateResourceCore)
+	require.True(t, ok)
+	assert.Equal(t, 0, rate.Remaining)
+
+	// Once known, the exhausted token is skipped entirely
+	seen = nil
+	_, _, err = client.Users.Get(context.Background(), "octocat")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bearer fresh"}, seen)
+}
@@ -2,38 +2,43 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"log"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/alimgiray/gscope/internal/models"
 	"github.com/alimgiray/gscope/internal/repositories"
 	"github.com/google/go-github/v57/github"
-	"golang.org/x/oauth2"
 )
 
+// repositoryStatusRefreshInterval is how often GitHub is asked whether a repository was renamed,
+// archived, deleted or became inaccessible
+const repositoryStatusRefreshInterval = 24 * time.Hour
+
 type GitHubRepositoryService struct {
 	githubRepoRepo  *repositories.GitHubRepositoryRepository
 	projectRepoRepo *repositories.ProjectRepositoryRepository
+	rateBudget      *GitHubRateBudget
 }
 
 func NewGitHubRepositoryService(
 	githubRepoRepo *repositories.GitHubRepositoryRepository,
 	projectRepoRepo *repositories.ProjectRepositoryRepository,
+	rateBudget *GitHubRateBudget,
 ) *GitHubRepositoryService {
 	return &GitHubRepositoryService{
 		githubRepoRepo:  githubRepoRepo,
 		projectRepoRepo: projectRepoRepo,
+		rateBudget:      rateBudget,
 	}
 }
 
-// createGitHubClient creates a GitHub client with the provided token
+// createGitHubClient creates a GitHub client with the provided token that spends the shared rate limit budget
 func (s *GitHubRepositoryService) createGitHubClient(token string) *github.Client {
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
-	return github.NewClient(tc)
+	return s.rateBudget.NewTokenClient(token)
 }
 
 // FetchUserRepositories fetches all repositories the user has access to
@@ -83,8 +88,55 @@ func (s *GitHubRepositoryService) FetchUserRepositories(projectID, token string)
 	return nil
 }
 
+// FetchInstallationRepositories fetches all repositories a GitHub App installation can access
+func (s *GitHubRepositoryService) FetchInstallationRepositories(projectID, token string) error {
+	if token == "" {
+		return fmt.Errorf("GitHub token is required")
+	}
+
+	githubClient := s.createGitHubClient(token)
+	ctx := context.Background()
+
+	opt := &github.ListOptions{PerPage: 100}
+
+	var allRepos []*github.Repository
+	for {
+		result, resp, err := githubClient.Apps.ListRepos(ctx, opt)
+		if err != nil {
+			return fmt.Errorf("failed to list installation repositories: %w", err)
+		}
+		allRepos = append(allRepos, result.Repositories...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	// Process each repository
+	for _, repo := range allRepos {
+		if err := s.processRepository(repo, projectID); err != nil {
+			// Log error but continue processing other repos
+			fmt.Printf("Error processing repository %s: %v\n", repo.GetFullName(), err)
+		}
+	}
+
+	return nil
+}
+
 // processRepository processes a single GitHub repository
 func (s *GitHubRepositoryService) processRepository(repo *github.Repository, projectID string) error {
+	githubRepo, err := s.upsertRepository(repo)
+	if err != nil {
+		return err
+	}
+
+	// Default to untracked as requested
+	_, err = s.attachToProject(projectID, githubRepo.ID, false)
+	return err
+}
+
+// upsertRepository creates or updates the stored copy of a GitHub repository
+func (s *GitHubRepositoryService) upsertRepository(repo *github.Repository) (*models.GitHubRepository, error) {
 	// Check if repository already exists in our database
 	existingRepo, err := s.githubRepoRepo.GetByGithubID(repo.GetID())
 
@@ -94,29 +146,36 @@ func (s *GitHubRepositoryService) processRepository(repo *github.Repository, pro
 		// Repository doesn't exist, create new one
 		githubRepo = s.createGitHubRepositoryFromAPI(repo)
 		if err := s.githubRepoRepo.Create(githubRepo); err != nil {
-			return fmt.Errorf("failed to create GitHub repository: %w", err)
+			return nil, fmt.Errorf("failed to create GitHub repository: %w", err)
 		}
 	} else {
 		// Repository exists, update it
+		oldFullName := existingRepo.FullName
 		githubRepo = s.updateGitHubRepositoryFromAPI(existingRepo, repo)
-		if err := s.githubRepoRepo.Update(githubRepo); err != nil {
-			return fmt.Errorf("failed to update GitHub repository: %w", err)
+		if err := s.saveRepository(githubRepo, oldFullName); err != nil {
+			return nil, fmt.Errorf("failed to update GitHub repository: %w", err)
 		}
 	}
 
+	return githubRepo, nil
+}
+
+// attachToProject creates the project-repository relationship if it doesn't exist yet.
+// It reports whether a new relationship was created.
+func (s *GitHubRepositoryService) attachToProject(projectID, githubRepoID string, track bool) (bool, error) {
 	// Check if project-repository relationship already exists
-	_, err = s.projectRepoRepo.GetByProjectAndGithubRepo(projectID, githubRepo.ID)
+	if _, err := s.projectRepoRepo.GetByProjectAndGithubRepo(projectID, githubRepoID); err == nil {
+		return false, nil
+	}
 
-	if err != nil {
-		// Relationship doesn't exist, create new one
-		projectRepo := models.NewProjectRepository(projectID, githubRepo.ID)
-		projectRepo.IsTracked = false // Default to false as requested
-		if err := s.projectRepoRepo.Create(projectRepo); err != nil {
-			return fmt.Errorf("failed to create project repository relationship: %w", err)
-		}
+	// Relationship doesn't exist, create new one
+	projectRepo := models.NewProjectRepository(projectID, githubRepoID)
+	projectRepo.IsTracked = track
+	if err := s.projectRepoRepo.Create(projectRepo); err != nil {
+		return false, fmt.Errorf("failed to create project repository relationship: %w", err)
 	}
 
-	return nil
+	return true, nil
 }
 
 // createGitHubRepositoryFromAPI creates a new GitHubRepository from GitHub API data
@@ -139,6 +198,9 @@ func (s *GitHubRepositoryService) createGitHubRepositoryFromAPI(repo *github.Rep
 	githubRepo.Stars = repo.GetStargazersCount()
 	githubRepo.Forks = repo.GetForksCount()
 	githubRepo.Private = repo.GetPrivate()
+	githubRepo.IsArchived = repo.GetArchived()
+	githubRepo.IsFork = repo.GetFork()
+	githubRepo.Topics = repo.Topics
 	if repo.DefaultBranch != nil {
 		githubRepo.DefaultBranch = repo.DefaultBranch
 	}
@@ -157,6 +219,15 @@ func (s *GitHubRepositoryService) createGitHubRepositoryFromAPI(repo *github.Rep
 
 // updateGitHubRepositoryFromAPI updates an existing GitHubRepository from GitHub API data
 func (s *GitHubRepositoryService) updateGitHubRepositoryFromAPI(existingRepo *models.GitHubRepository, repo *github.Repository) *models.GitHubRepository {
+	// A changed full name means the repository was renamed or transferred
+	if existingRepo.FullName != "" && existingRepo.FullName != repo.GetFullName() {
+		s.handleRename(existingRepo, repo.GetFullName())
+	}
+
+	// GitHub returned the repository, so it is no longer considered deleted or inaccessible
+	existingRepo.GithubDeletedAt = nil
+	existingRepo.GithubInaccessibleAt = nil
+
 	// Update fields that might have changed
 	existingRepo.Name = repo.GetName()
 	existingRepo.FullName = repo.GetFullName()
@@ -172,6 +243,9 @@ func (s *GitHubRepositoryService) updateGitHubRepositoryFromAPI(existingRepo *mo
 	existingRepo.Stars = repo.GetStargazersCount()
 	existingRepo.Forks = repo.GetForksCount()
 	existingRepo.Private = repo.GetPrivate()
+	existingRepo.IsArchived = repo.GetArchived()
+	existingRepo.IsFork = repo.GetFork()
+	existingRepo.Topics = repo.Topics
 	if repo.DefaultBranch != nil {
 		existingRepo.DefaultBranch = repo.DefaultBranch
 	}
@@ -185,6 +259,128 @@ func (s *GitHubRepositoryService) updateGitHubRepositoryFromAPI(existingRepo *mo
 	return existingRepo
 }
 
+// handleRename records the previous full name. The local clone is moved by saveRepository once the
+// new name is stored.
+func (s *GitHubRepositoryService) handleRename(existingRepo *models.GitHubRepository, newFullName string) {
+	oldFullName := existingRepo.FullName
+	log.Printf("Repository %s was renamed or transferred to %s", oldFullName, newFullName)
+	existingRepo.PreviousFullName = &oldFullName
+}
+
+// saveRepository stores a repository updated from GitHub data and then, if it was renamed from
+// oldFullName, moves its local clone to the path of the new name
+func (s *GitHubRepositoryService) saveRepository(githubRepo *models.GitHubRepository, oldFullName string) error {
+	if err := s.githubRepoRepo.Update(githubRepo); err != nil {
+		return err
+	}
+	if oldFullName == "" || oldFullName == githubRepo.FullName || githubRepo.LocalPath == nil {
+		return nil
+	}
+
+	oldPath := *githubRepo.LocalPath
+	newPath := relocatedClonePath(oldPath, oldFullName, githubRepo.FullName)
+	if err := moveClone(oldPath, newPath); err != nil {
+		// Force a fresh clone at the new location on the next clone job
+		log.Printf("Failed to move clone of %s to %s: %v", oldFullName, newPath, err)
+		githubRepo.IsCloned = false
+		githubRepo.LocalPath = nil
+		return s.githubRepoRepo.Update(githubRepo)
+	}
+
+	githubRepo.LocalPath = &newPath
+	if err := s.githubRepoRepo.Update(githubRepo); err != nil {
+		// Keep the clone where the stored path points
+		if moveErr := moveClone(newPath, oldPath); moveErr != nil {
+			log.Printf("Failed to move clone of %s back to %s: %v", githubRepo.FullName, oldPath, moveErr)
+		}
+		githubRepo.LocalPath = &oldPath
+		return err
+	}
+	return nil
+}
+
+// RefreshRepositoryStatus asks GitHub for the current state of a repository by its ID,
+// which follows renames and transfers, and records archival, deletion or lost access
+func (s *GitHubRepositoryService) RefreshRepositoryStatus(githubRepo *models.GitHubRepository, token string) error {
+	if token == "" {
+		return fmt.Errorf("GitHub token is required")
+	}
+
+	githubClient := s.createGitHubClient(token)
+	repo, resp, err := githubClient.Repositories.GetByID(context.Background(), githubRepo.GithubID)
+	now := time.Now()
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusGone {
+			if githubRepo.GithubDeletedAt == nil {
+				githubRepo.GithubDeletedAt = &now
+				log.Printf("Repository %s no longer exists on GitHub", githubRepo.FullName)
+			}
+			githubRepo.StatusCheckedAt = &now
+			return s.githubRepoRepo.Update(githubRepo)
+		}
+		// GitHub answers 404 both for deleted repositories and for ones the token can't see
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			if githubRepo.GithubInaccessibleAt == nil {
+				githubRepo.GithubInaccessibleAt = &now
+				log.Printf("Repository %s can no longer be accessed on GitHub", githubRepo.FullName)
+			}
+			githubRepo.StatusCheckedAt = &now
+			return s.githubRepoRepo.Update(githubRepo)
+		}
+		return fmt.Errorf("failed to get repository %s: %w", githubRepo.FullName, err)
+	}
+
+	githubRepo.StatusCheckedAt = &now
+
+	oldFullName := githubRepo.FullName
+	s.updateGitHubRepositoryFromAPI(githubRepo, repo)
+	return s.saveRepository(githubRepo, oldFullName)
+}
+
+// RefreshRepositoryStatusIfDue refreshes the status of a repository only when it was never checked, the
+// last check failed or it is older than repositoryStatusRefreshInterval. Webhooks keep the stored status
+// current in between.
+func (s *GitHubRepositoryService) RefreshRepositoryStatusIfDue(githubRepo *models.GitHubRepository, token string) error {
+	if githubRepo.StatusCheckedAt != nil && time.Since(*githubRepo.StatusCheckedAt) < repositoryStatusRefreshInterval {
+		return nil
+	}
+	return s.RefreshRepositoryStatus(githubRepo, token)
+}
+
+// UpdateFromWebhook applies a repository webhook to the stored copy of the repository without asking
+// GitHub again. Repositories that aren't stored are ignored.
+func (s *GitHubRepositoryService) UpdateFromWebhook(action string, repo *github.Repository) error {
+	githubRepo, err := s.githubRepoRepo.GetByGithubID(repo.GetID())
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if action == "deleted" {
+		if githubRepo.GithubDeletedAt == nil {
+			now := time.Now()
+			githubRepo.GithubDeletedAt = &now
+			log.Printf("Repository %s was deleted on GitHub", githubRepo.FullName)
+		}
+		return s.githubRepoRepo.Update(githubRepo)
+	}
+
+	oldFullName := githubRepo.FullName
+	s.updateGitHubRepositoryFromAPI(githubRepo, repo)
+	return s.saveRepository(githubRepo, oldFullName)
+}
+
+// IsRepositoryActive reports whether jobs should be created for a project repository
+func (s *GitHubRepositoryService) IsRepositoryActive(projectRepo *models.ProjectRepository) bool {
+	githubRepo, err := s.githubRepoRepo.GetByID(projectRepo.GithubRepoID)
+	if err != nil {
+		return false
+	}
+	return githubRepo.IsActive()
+}
+
 // GetProjectRepositories gets all repositories for a project, sorted by tracked status and name
 func (s *GitHubRepositoryService) GetProjectRepositories(projectID string) ([]*models.ProjectRepository, error) {
 	projectRepos, err := s.projectRepoRepo.GetByProjectID(projectID)
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+// IssueReportService reports the issues people opened and closed, how long issues take to close per
+// label, and how many bugs come in versus get fixed
+type IssueReportService struct {
+	issueRepo        *repositories.IssueRepository
+	issueCommentRepo *repositories.IssueCommentRepository
+	githubPersonRepo *repositories.GithubPersonRepository
+}
+
+func NewIssueReportService(
+	issueRepo *repositories.IssueRepository,
+	issueCommentRepo *repositories.IssueCommentRepository,
+	githubPersonRepo *repositories.GithubPersonRepository,
+) *IssueReportService {
+	return &IssueReportService{
+		issueRepo:        issueRepo,
+		issueCommentRepo: issueCommentRepo,
+		githubPersonRepo: githubPersonRepo,
+	}
+}
+
+// GetAllTimeReportByProject reports the issues of a project over all time, with the bug flow per month
+func (s *IssueReportService) GetAllTimeReportByProject(projectID string, filter *models.RepositoryFilter) (*models.IssueReport, error) {
+	return s.getReportByProject(projectID, time.Time{}, time.Time{}, monthBucket, filter)
+}
+
+// GetYearlyReportByProject reports the issues of a project for a year, with the bug flow per month
+func (s *IssueReportService) GetYearlyReportByProject(projectID string, year int, filter *models.RepositoryFilter) (*models.IssueReport, error) {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(1, 0, 0), monthBucket, filter)
+}
+
+// GetMonthlyReportByProject reports the issues of a project for a month, with the bug flow per day
+func (s *IssueReportService) GetMonthlyReportByProject(projectID string, year, month int, filter *models.RepositoryFilter) (*models.IssueReport, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(0, 1, 0), dayBucket, filter)
+}
+
+// GetWeeklyReportByProject reports the issues of a project for a week numbered like the weekly reports,
+// with the bug flow per day
+func (s *IssueReportService) GetWeeklyReportByProject(projectID string, year, week int, filter *models.RepositoryFilter) (*models.IssueReport, error) {
+	start, end := weekRange(year, week)
+	return s.getReportByProject(projectID, start, end, dayBucket, filter)
+}
+
+// GetDailyReportByProject reports the issues of a project for a day
+func (s *IssueReportService) GetDailyReportByProject(projectID string, date time.Time, filter *models.RepositoryFilter) (*models.IssueReport, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(0, 0, 1), nil, filter)
+}
+
+func monthBucket(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+func dayBucket(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// getReportByProject loads the issues, labels and issue comments of a project and reports them for [start, end)
+func (s *IssueReportService) getReportByProject(projectID string, start, end time.Time, bucket func(time.Time) string, filter *models.RepositoryFilter) (*models.IssueReport, error) {
+	issues, err := s.issueRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := s.issueRepo.GetLabelsByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.issueCommentRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	projectPeople, err := s.githubPersonRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	issues = filterByRepository(issues, filter, func(i *models.Issue) string { return i.RepositoryID })
+	comments = filterByRepository(comments, filter, func(c *models.IssueComment) string { return c.RepositoryID })
+
+	people := newReviewerPeople(projectPeople)
+	// Issue authors and closers are normally linked to the project, look up the ones that aren't
+	for _, issue := range issues {
+		for _, personID := range []*string{issue.AuthorID, issue.ClosedByID} {
+			if personID == nil {
+				continue
+			}
+			if _, ok := people.byID[*personID]; !ok {
+				person, err := s.githubPersonRepo.GetByID(*personID)
+				if err != nil && err != sql.ErrNoRows {
+					return nil, err
+				}
+				people.add(person, 0)
+			}
+		}
+	}
+
+	openAt := end
+	if openAt.IsZero() {
+		openAt = time.Now()
+	}
+
+	return buildIssueReport(issues, labels, comments, people, periodFilter(start, end), openAt, bucket), nil
+}
+
+// buildIssueReport counts the issues opened and closed in the period in total, per person and per label,
+// and the comments people wrote on issues. Bugs still open at openAt are counted whatever the period.
+// The bug flow is split with bucket, and left out when bucket is nil.
+func buildIssueReport(issues []*models.Issue, labels []*models.IssueLabel, comments []*models.IssueComment, people *reviewerPeople, inPeriod func(*time.Time) bool, openAt time.Time, bucket func(time.Time) string) *models.IssueReport {
+	labelsByIssue := make(map[string][]string)
+	for _, label := range labels {
+		labelsByIssue[label.IssueID] = append(labelsByIssue[label.IssueID], label.Name)
+	}
+
+	stats := make(map[string]*models.IssuePersonStats)
+	get := func(person *models.GithubPerson, login string) *models.IssuePersonStats {
+		key := reviewerKey(person, login)
+		if stats[key] == nil {
+			stats[key] = &models.IssuePersonStats{Username: login}
+			if person != nil {
+				stats[key].GithubPersonID = person.ID
+				stats[key].Username = person.Username
+			}
+		}
+		return stats[key]
+	}
+
+	flow := make(map[string]*models.IssueFlow)
+	flowAt := func(t time.Time) *models.IssueFlow {
+		if bucket == nil {
+			return nil
+		}
+		period := bucket(t)
+		if flow[period] == nil {
+			flow[period] = &models.IssueFlow{Period: period}
+		}
+		return flow[period]
+	}
+
+	report := &models.IssueReport{}
+	var timeToClose []float64
+	timeToCloseByLabel := make(map[string][]float64)
+
+	for _, issue := range issues {
+		bug := hasBugLabel(labelsByIssue[issue.ID])
+
+		if inPeriod(issue.GithubCreatedAt) {
+			report.Opened++
+			if issue.AuthorID != nil {
+				if person := people.byID[*issue.AuthorID]; person != nil {
+					get(person, "").Opened++
+				}
+			}
+			if bug {
+				report.BugsOpened++
+				if f := flowAt(*issue.GithubCreatedAt); f != nil {
+					f.Opened++
+				}
+			}
+		}
+
+		if inPeriod(issue.ClosedAt) {
+			report.Closed++
+			if issue.ClosedByID != nil {
+				if person := people.byID[*issue.ClosedByID]; person != nil {
+					get(person, "").Closed++
+				}
+			}
+			if bug {
+				report.BugsClosed++
+				if f := flowAt(*issue.ClosedAt); f != nil {
+					f.Closed++
+				}
+			}
+			if issue.GithubCreatedAt != nil && !issue.ClosedAt.Before(*issue.GithubCreatedAt) {
+				seconds := issue.ClosedAt.Sub(*issue.GithubCreatedAt).Seconds()
+				timeToClose = append(timeToClose, seconds)
+				for _, label := range labelsByIssue[issue.ID] {
+					timeToCloseByLabel[label] = append(timeToCloseByLabel[label], seconds)
+				}
+			}
+		}
+
+		if bug && issue.GithubCreatedAt != nil && issue.GithubCreatedAt.Before(openAt) &&
+			(issue.ClosedAt == nil || !issue.ClosedAt.Before(openAt)) {
+			report.OpenBugs++
+		}
+	}
+
+	for _, comment := range comments {
+		if !inPeriod(comment.GithubCreatedAt) {
+			continue
+		}
+		get(people.byGithubUserID[int(comment.AuthorID)], comment.AuthorLogin).Comments++
+	}
+
+	report.TimeToClose = summarize(timeToClose)
+	for label, values := range timeToCloseByLabel {
+		report.Labels = append(report.Labels, &models.IssueLabelStats{
+			Label:       label,
+			Closed:      len(values),
+			TimeToClose: summarize(values),
+		})
+	}
+	sort.Slice(report.Labels, func(i, j int) bool {
+		a, b := report.Labels[i], report.Labels[j]
+		if a.Closed != b.Closed {
+			return a.Closed > b.Closed
+		}
+		return strings.ToLower(a.Label) < strings.ToLower(b.Label)
+	})
+
+	for _, person := range stats {
+		report.People = append(report.People, person)
+	}
+	sort.Slice(report.People, func(i, j int) bool {
+		a, b := report.People[i], report.People[j]
+		if a.Opened+a.Closed != b.Opened+b.Closed {
+			return a.Opened+a.Closed > b.Opened+b.Closed
+		}
+		if a.Comments != b.Comments {
+			return a.Comments > b.Comments
+		}
+		return strings.ToLower(a.Username) < strings.ToLower(b.Username)
+	})
+
+	for _, f := range flow {
+		report.BugFlow = append(report.BugFlow, f)
+	}
+	sort.Slice(report.BugFlow, func(i, j int) bool {
+		return report.BugFlow[i].Period < report.BugFlow[j].Period
+	})
+
+	return report
+}
+
+// hasBugLabel tells whether any of the labels marks a bug, like "bug", "type: bug" or "Bugs"
+func hasBugLabel(labels []string) bool {
+	for _, label := range labels {
+		words := strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		for _, word := range words {
+			if word == "bug" || word == "bugs" {
+				return true
+			}
+		}
+	}
+	return false
+}
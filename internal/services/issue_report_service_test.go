@@ -0,0 +1,80 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildIssueReport(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2025, 8, d, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+	alice := &models.GithubPerson{ID: "alice", GithubUserID: 1, Username: "alice"}
+	bob := &models.GithubPerson{ID: "bob", GithubUserID: 2, Username: "bob"}
+	people := newReviewerPeople([]*models.GithubPerson{alice, bob})
+
+	issues := []*models.Issue{
+		// Bug opened by alice, closed by bob two days later
+		{ID: "i1", AuthorID: &alice.ID, ClosedByID: &bob.ID, GithubCreatedAt: day(1), ClosedAt: day(3)},
+		// Bug opened by bob, still open
+		{ID: "i2", AuthorID: &bob.ID, GithubCreatedAt: day(2)},
+		// Feature opened before the period, closed by alice in it
+		{ID: "i3", AuthorID: &bob.ID, ClosedByID: &alice.ID, GithubCreatedAt: day(1), ClosedAt: day(5)},
+		// Bug opened and closed after the period, but open at its end
+		{ID: "i4", AuthorID: &alice.ID, GithubCreatedAt: day(9), ClosedAt: day(20)},
+	}
+	labels := []*models.IssueLabel{
+		{IssueID: "i1", Name: "type: bug"},
+		{IssueID: "i2", Name: "Bugs"},
+		{IssueID: "i3", Name: "feature"},
+		{IssueID: "i4", Name: "bug"},
+		{IssueID: "i4", Name: "debugging"},
+	}
+	comments := []*models.IssueComment{
+		{AuthorID: 1, AuthorLogin: "alice", GithubCreatedAt: day(2)},
+		{AuthorID: 3, AuthorLogin: "carol", GithubCreatedAt: day(3)},
+		{AuthorID: 2, AuthorLogin: "bob", GithubCreatedAt: day(25)},
+	}
+
+	report := buildIssueReport(issues, labels, comments, people, periodFilter(*day(2), *day(10)), *day(10), dayBucket)
+
+	assert.Equal(t, 2, report.Opened)
+	assert.Equal(t, 2, report.Closed)
+	assert.Equal(t, 2, report.TimeToClose.Count)
+	assert.Equal(t, 2, report.BugsOpened)
+	assert.Equal(t, 1, report.BugsClosed)
+	assert.Equal(t, 2, report.OpenBugs)
+
+	if assert.Len(t, report.Labels, 2) {
+		assert.Equal(t, "feature", report.Labels[0].Label)
+		assert.Equal(t, float64(4*24*3600), report.Labels[0].TimeToClose.Median)
+		assert.Equal(t, "type: bug", report.Labels[1].Label)
+		assert.Equal(t, float64(2*24*3600), report.Labels[1].TimeToClose.Median)
+	}
+
+	byName := make(map[string]*models.IssuePersonStats)
+	for _, person := range report.People {
+		byName[person.Username] = person
+	}
+	assert.Equal(t, models.IssuePersonStats{GithubPersonID: "alice", Username: "alice", Opened: 1, Closed: 1, Comments: 1}, *byName["alice"])
+	assert.Equal(t, models.IssuePersonStats{GithubPersonID: "bob", Username: "bob", Opened: 1, Closed: 1}, *byName["bob"])
+	assert.Equal(t, models.IssuePersonStats{Username: "carol", Comments: 1}, *byName["carol"])
+
+	assert.Equal(t, []*models.IssueFlow{
+		{Period: "2025-08-02", Opened: 1},
+		{Period: "2025-08-03", Closed: 1},
+		{Period: "2025-08-09", Opened: 1},
+	}, report.BugFlow)
+}
+
+func TestHasBugLabel(t *testing.T) {
+	assert.True(t, hasBugLabel([]string{"bug"}))
+	assert.True(t, hasBugLabel([]string{"enhancement", "Type: Bug"}))
+	assert.True(t, hasBugLabel([]string{"kind/bugs"}))
+	assert.False(t, hasBugLabel([]string{"debugging", "bugfix-later"}))
+	assert.False(t, hasBugLabel(nil))
+}
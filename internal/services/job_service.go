@@ -84,6 +84,38 @@ func (s *JobService) CreatePullRequestJob(projectID string, projectRepositoryID
 	return nil
 }
 
+// CreateGitHubFetchJobs creates a chain of pull_request, issue, ci and deployment jobs, each depending
+// on the previous one, leaving out the jobs the project doesn't sync
+func (s *JobService) CreateGitHubFetchJobs(project *models.Project, projectRepositoryID string) error {
+	var dependsOn *string
+	for _, jobType := range project.SyncedJobTypes(models.JobTypePullRequest, models.JobTypeIssue, models.JobTypeCI, models.JobTypeDeployment) {
+		job := models.NewJob(project.ID.String(), jobType)
+		job.ProjectRepositoryID = &projectRepositoryID
+		job.DependsOn = dependsOn
+
+		if err := s.jobRepo.Create(job); err != nil {
+			return err
+		}
+		dependsOn = &job.ID
+	}
+
+	return nil
+}
+
+// EnqueueRepositoryJobs creates a chain of jobs for a project repository, each depending on the previous
+// one, and returns how many were created. Jobs at the start of the chain that are already pending are
+// reused instead; once a job is created, the rest of the chain is created after it so that it sees the
+// new data.
+func (s *JobService) EnqueueRepositoryJobs(projectID string, projectRepositoryID string, jobTypes ...models.JobType) (int, error) {
+	return s.jobRepo.EnqueueChain(projectID, projectRepositoryID, jobTypes...)
+}
+
+// EnqueueProjectStatsJob creates a stats job that recalculates every tracked repository of a project,
+// reusing one that is already pending
+func (s *JobService) EnqueueProjectStatsJob(projectID string) (*models.Job, error) {
+	return s.jobRepo.EnqueueProjectJob(projectID, models.JobTypeStats)
+}
+
 // CreateStatsJob creates only a stats job
 func (s *JobService) CreateStatsJob(projectID string, projectRepositoryID string) error {
 	// Create stats job
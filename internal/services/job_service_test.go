@@ -0,0 +1,59 @@
+package services
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnqueueRepositoryJobsConcurrently(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "jobs.db")+"?_journal_mode=WAL&_busy_timeout=5000")
+	require.NoError(t, err)
+	defer db.Close()
+
+	migration, err := os.ReadFile("../../migrations/007_create_jobs.sql")
+	require.NoError(t, err)
+	_, err = db.Exec(string(migration))
+	require.NoError(t, err)
+
+	jobService := NewJobService(repositories.NewJobRepository(db))
+
+	// Webhook deliveries for the same repository arriving together queue one chain
+	var wg sync.WaitGroup
+	created := make([]int, 10)
+	for i := range created {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			n, err := jobService.EnqueueRepositoryJobs("project", "repo", models.JobTypePullRequest, models.JobTypeStats)
+			assert.NoError(t, err)
+			created[i] = n
+		}(i)
+	}
+	wg.Wait()
+
+	total := 0
+	for _, n := range created {
+		total += n
+	}
+	assert.Equal(t, 2, total)
+
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM jobs WHERE status = 'pending'`).Scan(&count))
+	assert.Equal(t, 2, count)
+
+	// Project-wide jobs are reused the same way
+	first, err := jobService.EnqueueProjectStatsJob("project")
+	require.NoError(t, err)
+	second, err := jobService.EnqueueProjectStatsJob("project")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+}
@@ -1,8 +1,9 @@
 package services
 
 import (
-	"encoding/json"
+	"database/sql"
 	"fmt"
+	"math"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -32,6 +33,12 @@ type PeopleStatisticsService struct {
 	projectRepositoryRepo       *repositories.ProjectRepositoryRepository
 	workingHoursSettingsService *WorkingHoursSettingsService
 	projectGithubPersonService  *ProjectGithubPersonService
+	prReviewCommentRepo         *repositories.PRReviewCommentRepository
+	prIssueCommentRepo          *repositories.PRIssueCommentRepository
+	issueRepo                   *repositories.IssueRepository
+	botService                  *BotService
+	repositoryScoreSettingsRepo *repositories.RepositoryScoreSettingsRepository
+	scoreFormulaRepo            *repositories.ScoreFormulaRepository
 }
 
 func NewPeopleStatisticsService(
@@ -51,6 +58,12 @@ func NewPeopleStatisticsService(
 	projectRepositoryRepo *repositories.ProjectRepositoryRepository,
 	workingHoursSettingsService *WorkingHoursSettingsService,
 	projectGithubPersonService *ProjectGithubPersonService,
+	prReviewCommentRepo *repositories.PRReviewCommentRepository,
+	prIssueCommentRepo *repositories.PRIssueCommentRepository,
+	issueRepo *repositories.IssueRepository,
+	botService *BotService,
+	repositoryScoreSettingsRepo *repositories.RepositoryScoreSettingsRepository,
+	scoreFormulaRepo *repositories.ScoreFormulaRepository,
 ) *PeopleStatisticsService {
 	return &PeopleStatisticsService{
 		peopleStatsRepo:             peopleStatsRepo,
@@ -69,17 +82,130 @@ func NewPeopleStatisticsService(
 		projectRepositoryRepo:       projectRepositoryRepo,
 		workingHoursSettingsService: workingHoursSettingsService,
 		projectGithubPersonService:  projectGithubPersonService,
+		prReviewCommentRepo:         prReviewCommentRepo,
+		prIssueCommentRepo:          prIssueCommentRepo,
+		issueRepo:                   issueRepo,
+		botService:                  botService,
+		repositoryScoreSettingsRepo: repositoryScoreSettingsRepo,
+		scoreFormulaRepo:            scoreFormulaRepo,
 	}
 }
 
+// repositoryScoring is how the daily rows of a repository are scored
+type repositoryScoring struct {
+	settings        *models.ScoreSettings
+	overrideWeights bool                           // The repository's own weights replace those of versions
+	versions        []*models.ScoreSettingsVersion // Weights and formula by day
+	formulas        map[string]*scoreFormula       // Formulas of the versions by ID
+	formula         *scoreFormula                  // Replaces the weights of settings when set
+	multiplier      float64
+}
+
+// on returns the scoring of a day, with the weights or formula of the score settings version in effect then
+func (r *repositoryScoring) on(date time.Time) *repositoryScoring {
+	version := models.ScoreSettingsVersionOn(r.versions, date)
+	if version == nil {
+		return r
+	}
+
+	scoring := &repositoryScoring{settings: r.settings, multiplier: r.multiplier}
+	if !r.overrideWeights {
+		scoring.settings = version.Settings()
+	}
+	if version.HasFormula() {
+		// Formulas that failed to parse leave the day scored with weights
+		scoring.formula = r.formulas[*version.ScoreFormulaID]
+	}
+	return scoring
+}
+
+// getRepositoryScoring loads the score settings of a project and their versions, weighted for one of its
+// repositories, and the score formulas of the versions
+func (s *PeopleStatisticsService) getRepositoryScoring(projectID, projectRepositoryID string) (*repositoryScoring, error) {
+	scoreSettings, err := s.scoreSettingsRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Weight the repository's scores with its own settings, if it has any
+	repositoryScoreSettings, err := s.repositoryScoreSettingsRepo.GetByProjectRepositoryID(projectRepositoryID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	scoring := &repositoryScoring{
+		settings:        repositoryScoreSettings.Weights(scoreSettings),
+		overrideWeights: repositoryScoreSettings != nil && repositoryScoreSettings.OverrideWeights,
+		multiplier:      repositoryScoreSettings.ScoreMultiplier(),
+		formulas:        make(map[string]*scoreFormula),
+	}
+
+	// Otherwise each day is scored with the project's weights of the time, and formulas replace the
+	// weights on the days of the versions that have one either way
+	scoring.versions, err = s.scoreSettingsRepo.GetVersionsByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, version := range scoring.versions {
+		if !version.HasFormula() {
+			continue
+		}
+		if _, ok := scoring.formulas[*version.ScoreFormulaID]; ok {
+			continue
+		}
+		scoreFormula, err := s.scoreFormulaRepo.GetByID(*version.ScoreFormulaID)
+		if err != nil {
+			return nil, err
+		}
+		scoring.formulas[scoreFormula.ID], err = parseScoreFormula(scoreFormula.Expression)
+		if err != nil {
+			// Formulas are validated when saved, so fall back to the weights rather than fail the job
+			logger.WithFields(logrus.Fields{
+				"project_id": projectID,
+				"version":    scoreFormula.Version,
+			}).WithError(err).Error("Invalid score formula, scoring with weights")
+		}
+	}
+
+	return scoring, nil
+}
+
 // CalculateStatisticsForRepository calculates daily statistics for a specific repository
 func (s *PeopleStatisticsService) CalculateStatisticsForRepository(projectID, projectRepositoryID, githubRepositoryID string) error {
-	// Get score settings for the project
-	scoreSettings, err := s.scoreSettingsRepo.GetByProjectID(projectID)
+	scoring, err := s.getRepositoryScoring(projectID, projectRepositoryID)
 	if err != nil {
 		return err
 	}
 
+	// Get the date range from actual commits to ensure we calculate for the correct period
+	minCommitDate, _, err := s.commitRepo.GetDateRangeByRepositoryID(githubRepositoryID)
+	var startDate time.Time
+	if err != nil {
+		// Fallback to 1 year ago if we can't get commit dates
+		startDate = time.Now().AddDate(-1, 0, 0)
+	} else {
+		// Start from the first commit date and go to today
+		startDate = minCommitDate
+	}
+
+	// Delete existing statistics for this repository to ensure fresh calculation
+	if err := s.peopleStatsRepo.DeleteByRepositoryID(projectRepositoryID); err != nil {
+		return err
+	}
+
+	return s.computeRepositoryStatistics(projectID, projectRepositoryID, githubRepositoryID, startDate, scoring, s.peopleStatsRepo.Upsert)
+}
+
+// computeRepositoryStatistics computes the daily statistics of a repository from a date until today and
+// hands each row with activity to save
+func (s *PeopleStatisticsService) computeRepositoryStatistics(
+	projectID, projectRepositoryID, githubRepositoryID string,
+	startDate time.Time,
+	scoring *repositoryScoring,
+	save func(*models.PeopleStatistics) error,
+) error {
+	endDate := time.Now()
+
 	// Get excluded extensions for the project
 	excludedExtensions, err := s.excludedExtRepo.GetByProjectID(projectID)
 	if err != nil {
@@ -114,42 +240,41 @@ func (s *PeopleStatisticsService) CalculateStatisticsForRepository(projectID, pr
 		personEmailMap[assoc.GitHubPersonID] = person.PrimaryEmail
 	}
 
-	// Get the date range from actual commits to ensure we calculate for the correct period
-	minCommitDate, _, err := s.commitRepo.GetDateRangeByRepositoryID(githubRepositoryID)
-	var startDate, endDate time.Time
+	// OPTIMIZATION: Pre-load all data for the repository to avoid N+1 queries
+	allCommits, err := s.commitRepo.GetByRepositoryID(githubRepositoryID)
 	if err != nil {
-		// Fallback to 1 year ago if we can't get commit dates
-		startDate = time.Now().AddDate(-1, 0, 0)
-		endDate = time.Now()
-	} else {
-		// Start from the first commit date and go to today
-		startDate = minCommitDate
-		endDate = time.Now()
+		return err
 	}
 
-	// Delete existing statistics for this repository to ensure fresh calculation
-	if err := s.peopleStatsRepo.DeleteByRepositoryID(projectRepositoryID); err != nil {
+	allPullRequests, err := s.pullRequestRepo.GetByRepositoryID(githubRepositoryID)
+	if err != nil {
 		return err
 	}
 
-	// OPTIMIZATION: Pre-load all data for the repository to avoid N+1 queries
-	allCommits, err := s.commitRepo.GetByRepositoryID(githubRepositoryID)
+	allPRReviews, err := s.prReviewRepo.GetByRepositoryID(githubRepositoryID)
 	if err != nil {
 		return err
 	}
 
-	allPullRequests, err := s.pullRequestRepo.GetByRepositoryID(githubRepositoryID)
+	allReviewComments, err := s.prReviewCommentRepo.GetByRepositoryID(githubRepositoryID)
 	if err != nil {
 		return err
 	}
 
-	allPRReviews, err := s.prReviewRepo.GetByRepositoryID(githubRepositoryID)
+	allIssueComments, err := s.prIssueCommentRepo.GetByRepositoryID(githubRepositoryID)
+	if err != nil {
+		return err
+	}
+
+	allIssues, err := s.issueRepo.GetByRepositoryID(githubRepositoryID)
 	if err != nil {
 		return err
 	}
 
 	// OPTIMIZATION: Find actual activity dates to avoid processing empty days
 	activityDates := s.findActivityDates(allCommits, allPullRequests, allPRReviews, startDate, endDate)
+	activityDates = mergeCommentActivityDates(activityDates, allReviewComments, allIssueComments, startDate, endDate)
+	activityDates = mergeIssueActivityDates(activityDates, allIssues, startDate, endDate)
 
 	// Pre-load all commit files for the repository
 	allCommitFiles := make(map[string][]*models.CommitFile)
@@ -186,8 +311,9 @@ func (s *PeopleStatisticsService) CalculateStatisticsForRepository(projectID, pr
 	for _, date := range activityDates {
 		if err := s.calculateDailyStatisticsOptimized(
 			projectID, projectRepositoryID, date,
-			scoreSettings, excludedExtMap, excludedFolders, emailMerges, personEmailMap,
-			allCommits, allPullRequests, allPRReviews, allCommitFiles, githubPeople,
+			scoring, excludedExtMap, excludedFolders, emailMerges, personEmailMap,
+			allCommits, allPullRequests, allPRReviews, allReviewComments, allIssueComments, allIssues, allCommitFiles, githubPeople,
+			save,
 		); err != nil {
 			return err
 		}
@@ -221,6 +347,10 @@ func (s *PeopleStatisticsService) findActivityDates(
 				activityMap[prDate] = true
 			}
 		}
+		// Merges count for score formulas
+		if pr.MergedAt != nil && pr.MergedAt.After(startDate) && pr.MergedAt.Before(endDate.AddDate(0, 0, 1)) {
+			activityMap[pr.MergedAt.Format("2006-01-02")] = true
+		}
 	}
 
 	// Add review creation dates
@@ -253,7 +383,7 @@ func (s *PeopleStatisticsService) findActivityDates(
 func (s *PeopleStatisticsService) calculateDailyStatisticsOptimized(
 	projectID, projectRepositoryID string,
 	date time.Time,
-	scoreSettings *models.ScoreSettings,
+	scoring *repositoryScoring,
 	excludedExtMap map[string]bool,
 	excludedFolders []*models.ExcludedFolder,
 	emailMerges map[string]string,
@@ -261,21 +391,25 @@ func (s *PeopleStatisticsService) calculateDailyStatisticsOptimized(
 	allCommits []*models.Commit,
 	allPullRequests []*models.PullRequest,
 	allPRReviews []*models.PRReview,
+	allReviewComments []*models.PRReviewComment,
+	allIssueComments []*models.PRIssueComment,
+	allIssues []*models.Issue,
 	allCommitFiles map[string][]*models.CommitFile,
 	githubPeople []*models.GithubPerson,
+	save func(*models.PeopleStatistics) error,
 ) error {
 
 	// Calculate statistics for each person
 	for _, person := range githubPeople {
 		stats := s.calculatePersonDailyStatsOptimized(
 			projectID, projectRepositoryID, person.ID, date,
-			scoreSettings, excludedExtMap, excludedFolders, emailMerges, personEmailMap,
-			allCommits, allPullRequests, allPRReviews, allCommitFiles,
+			scoring, excludedExtMap, excludedFolders, emailMerges, personEmailMap,
+			allCommits, allPullRequests, allPRReviews, allReviewComments, allIssueComments, allIssues, allCommitFiles,
 		)
 
-		if stats != nil && (stats.Score > 0 || stats.Commits > 0 || stats.PullRequests > 0 || stats.Comments > 0) {
-			// Upsert if there's any activity (score > 0 or any commits/PRs/comments)
-			if err := s.peopleStatsRepo.Upsert(stats); err != nil {
+		if stats != nil {
+			// Save if there's any activity or score, which formulas can make negative
+			if err := save(stats); err != nil {
 				return err
 			}
 		}
@@ -288,7 +422,7 @@ func (s *PeopleStatisticsService) calculateDailyStatisticsOptimized(
 func (s *PeopleStatisticsService) calculatePersonDailyStatsOptimized(
 	projectID, projectRepositoryID, githubPersonID string,
 	date time.Time,
-	scoreSettings *models.ScoreSettings,
+	scoring *repositoryScoring,
 	excludedExtMap map[string]bool,
 	excludedFolders []*models.ExcludedFolder,
 	emailMerges map[string]string,
@@ -296,6 +430,9 @@ func (s *PeopleStatisticsService) calculatePersonDailyStatsOptimized(
 	allCommits []*models.Commit,
 	allPullRequests []*models.PullRequest,
 	allPRReviews []*models.PRReview,
+	allReviewComments []*models.PRReviewComment,
+	allIssueComments []*models.PRIssueComment,
+	allIssues []*models.Issue,
 	allCommitFiles map[string][]*models.CommitFile,
 ) *models.PeopleStatistics {
 
@@ -305,19 +442,29 @@ func (s *PeopleStatisticsService) calculatePersonDailyStatsOptimized(
 		return nil // No email association, skip
 	}
 
+	var activity scoreActivity
+
 	// Calculate commit statistics using pre-loaded data
-	commits, additions, deletions := s.calculateCommitStatsOptimized(
+	activity.Commits, activity.Additions, activity.Deletions = s.calculateCommitStatsOptimized(
 		allCommits, allCommitFiles, personEmail, date, excludedExtMap, excludedFolders, emailMerges,
 	)
+	activity.CoAuthoredCommits = countCoAuthoredCommits(allCommits, s.getEmailsForPerson(personEmail, emailMerges), date)
 
 	// Calculate PR statistics using pre-loaded data
-	pullRequests := s.calculatePRStatsOptimized(allPullRequests, githubPersonID, date)
+	activity.PullRequests = s.calculatePRStatsOptimized(allPullRequests, githubPersonID, date)
+	activity.MergedPullRequests, activity.PRCycleHours = countMergedPullRequests(allPullRequests, githubPersonID, date)
 
 	// Calculate comment statistics using pre-loaded data
-	comments := s.calculateCommentStatsOptimized(allPRReviews, githubPersonID, date)
+	activity.Comments = s.calculateCommentStatsOptimized(allPRReviews, allReviewComments, allIssueComments, githubPersonID, date)
 
-	// Calculate score based on score settings
-	score := s.calculateScore(commits, additions, deletions, pullRequests, comments, scoreSettings)
+	// Issues only add to the score, they aren't counted as pull requests or comments
+	activity.IssuesOpened, activity.IssuesClosed = countIssuesByPerson(allIssues, githubPersonID, date)
+
+	// Calculate score based on score settings or formula and the repository's multiplier
+	score := s.calculateScore(activity, scoring.on(date))
+	if score == 0 && activity.isEmpty() {
+		return nil // Nothing to save
+	}
 
 	// Create statistics record
 	stats := &models.PeopleStatistics{
@@ -326,11 +473,11 @@ func (s *PeopleStatisticsService) calculatePersonDailyStatsOptimized(
 		RepositoryID:   projectRepositoryID,
 		GithubPersonID: githubPersonID,
 		StatDate:       date,
-		Commits:        commits,
-		Additions:      additions,
-		Deletions:      deletions,
-		Comments:       comments,
-		PullRequests:   pullRequests,
+		Commits:        activity.Commits,
+		Additions:      activity.Additions,
+		Deletions:      activity.Deletions,
+		Comments:       activity.Comments.Total(),
+		PullRequests:   activity.PullRequests,
 		Score:          score,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
@@ -420,7 +567,7 @@ func (s *PeopleStatisticsService) DeleteStatisticsByRepository(repositoryID stri
 }
 
 // GetAllTimeStatisticsByProject retrieves aggregated statistics for all GitHub people in a project
-func (s *PeopleStatisticsService) GetAllTimeStatisticsByProject(projectID string) ([]*models.GitHubPersonStats, error) {
+func (s *PeopleStatisticsService) GetAllTimeStatisticsByProject(projectID string, filter *models.RepositoryFilter) ([]*models.GitHubPersonStats, error) {
 	// Get all active GitHub people for this project (excluding deleted ones)
 	projectGithubPeople, err := s.projectGithubPersonService.GetProjectGithubPeopleByProjectID(projectID)
 	if err != nil {
@@ -443,6 +590,7 @@ func (s *PeopleStatisticsService) GetAllTimeStatisticsByProject(projectID string
 		if err != nil {
 			continue
 		}
+		stats = filterPeopleStatistics(stats, filter)
 
 		// Aggregate the statistics
 		totalCommits := 0
@@ -480,11 +628,11 @@ func (s *PeopleStatisticsService) GetAllTimeStatisticsByProject(projectID string
 		return results[i].TotalScore > results[j].TotalScore
 	})
 
-	return results, nil
+	return s.botService.ApplyPolicy(projectID, results)
 }
 
 // GetYearlyStatisticsByProject retrieves yearly statistics for a project
-func (s *PeopleStatisticsService) GetYearlyStatisticsByProject(projectID string, year int) ([]*models.GitHubPersonStats, error) {
+func (s *PeopleStatisticsService) GetYearlyStatisticsByProject(projectID string, year int, filter *models.RepositoryFilter) ([]*models.GitHubPersonStats, error) {
 	// Get all active GitHub people for this project (excluding deleted ones)
 	projectGithubPeople, err := s.projectGithubPersonService.GetProjectGithubPeopleByProjectID(projectID)
 	if err != nil {
@@ -507,6 +655,7 @@ func (s *PeopleStatisticsService) GetYearlyStatisticsByProject(projectID string,
 		if err != nil {
 			continue
 		}
+		stats = filterPeopleStatistics(stats, filter)
 
 		// Aggregate the statistics
 		totalCommits := 0
@@ -544,7 +693,7 @@ func (s *PeopleStatisticsService) GetYearlyStatisticsByProject(projectID string,
 		return results[i].TotalScore > results[j].TotalScore
 	})
 
-	return results, nil
+	return s.botService.ApplyPolicy(projectID, results)
 }
 
 // GetAvailableYearsForProject retrieves all available years for a project
@@ -564,7 +713,7 @@ func (s *PeopleStatisticsService) GetAvailableYearsForProject(projectID string)
 }
 
 // GetMonthlyStatisticsByProject retrieves monthly statistics for a project
-func (s *PeopleStatisticsService) GetMonthlyStatisticsByProject(projectID string, year int, month int) ([]*models.GitHubPersonStats, error) {
+func (s *PeopleStatisticsService) GetMonthlyStatisticsByProject(projectID string, year int, month int, filter *models.RepositoryFilter) ([]*models.GitHubPersonStats, error) {
 	// Get all active GitHub people for this project (excluding deleted ones)
 	projectGithubPeople, err := s.projectGithubPersonService.GetProjectGithubPeopleByProjectID(projectID)
 	if err != nil {
@@ -587,6 +736,7 @@ func (s *PeopleStatisticsService) GetMonthlyStatisticsByProject(projectID string
 		if err != nil {
 			continue
 		}
+		stats = filterPeopleStatistics(stats, filter)
 
 		// Aggregate the statistics
 		totalCommits := 0
@@ -624,7 +774,7 @@ func (s *PeopleStatisticsService) GetMonthlyStatisticsByProject(projectID string
 		return results[i].TotalScore > results[j].TotalScore
 	})
 
-	return results, nil
+	return s.botService.ApplyPolicy(projectID, results)
 }
 
 // GetAvailableMonthsForProject retrieves all available months for a project
@@ -645,7 +795,7 @@ func (s *PeopleStatisticsService) GetAvailableMonthsForProject(projectID string)
 }
 
 // GetWeeklyStatisticsByProject retrieves weekly statistics for a project
-func (s *PeopleStatisticsService) GetWeeklyStatisticsByProject(projectID string, year int, week int) ([]*models.GitHubPersonStats, error) {
+func (s *PeopleStatisticsService) GetWeeklyStatisticsByProject(projectID string, year int, week int, filter *models.RepositoryFilter) ([]*models.GitHubPersonStats, error) {
 	// Get all active GitHub people for this project (excluding deleted ones)
 	projectGithubPeople, err := s.projectGithubPersonService.GetProjectGithubPeopleByProjectID(projectID)
 	if err != nil {
@@ -668,6 +818,7 @@ func (s *PeopleStatisticsService) GetWeeklyStatisticsByProject(projectID string,
 		if err != nil {
 			continue
 		}
+		stats = filterPeopleStatistics(stats, filter)
 
 		// Aggregate the statistics
 		totalCommits := 0
@@ -705,7 +856,7 @@ func (s *PeopleStatisticsService) GetWeeklyStatisticsByProject(projectID string,
 		return results[i].TotalScore > results[j].TotalScore
 	})
 
-	return results, nil
+	return s.botService.ApplyPolicy(projectID, results)
 }
 
 // GetAvailableWeeksForProject retrieves all available weeks for a project
@@ -727,7 +878,7 @@ func (s *PeopleStatisticsService) GetAvailableWeeksForProject(projectID string)
 }
 
 // GetDailyStatisticsByProject retrieves daily statistics for a project
-func (s *PeopleStatisticsService) GetDailyStatisticsByProject(projectID string, date time.Time) ([]*models.GitHubPersonStats, error) {
+func (s *PeopleStatisticsService) GetDailyStatisticsByProject(projectID string, date time.Time, filter *models.RepositoryFilter) ([]*models.GitHubPersonStats, error) {
 	// Get all active GitHub people for this project (excluding deleted ones)
 	projectGithubPeople, err := s.projectGithubPersonService.GetProjectGithubPeopleByProjectID(projectID)
 	if err != nil {
@@ -750,6 +901,7 @@ func (s *PeopleStatisticsService) GetDailyStatisticsByProject(projectID string,
 		if err != nil {
 			continue
 		}
+		stats = filterPeopleStatistics(stats, filter)
 
 		// Aggregate the statistics (should be only one record per day)
 		totalCommits := 0
@@ -787,7 +939,7 @@ func (s *PeopleStatisticsService) GetDailyStatisticsByProject(projectID string,
 		return results[i].TotalScore > results[j].TotalScore
 	})
 
-	return results, nil
+	return s.botService.ApplyPolicy(projectID, results)
 }
 
 // GetAvailableDaysForProject retrieves all available days for a project (last 30 days)
@@ -935,102 +1087,432 @@ func (s *PeopleStatisticsService) calculatePRStatsOptimized(
 	githubPersonID string,
 	date time.Time,
 ) int {
-	// Get the GitHub person to get their username
-	githubPerson, err := s.githubPersonRepo.GetByID(githubPersonID)
-	if err != nil {
-		return 0
-	}
-
 	count := 0
 	for _, pr := range allPullRequests {
 		// Check if PR was created by this person on the specified date
-		if pr.GithubCreatedAt != nil {
-			prYear, prMonth, prDay := pr.GithubCreatedAt.Date()
-			dateYear, dateMonth, dateDay := date.Date()
-
-			if prYear == dateYear && prMonth == dateMonth && prDay == dateDay {
-				// Parse the user JSON to get the login (username)
-				if pr.User != nil {
-					var userData map[string]interface{}
-					if err := json.Unmarshal([]byte(*pr.User), &userData); err == nil {
-						if login, ok := userData["login"].(string); ok {
-							// Check if the PR was created by this GitHub person
-							if login == githubPerson.Username {
-								count++
-							}
-						}
-					}
-				}
-			}
+		if pr.AuthorID == nil || *pr.AuthorID != githubPersonID || pr.GithubCreatedAt == nil {
+			continue
+		}
+
+		prYear, prMonth, prDay := pr.GithubCreatedAt.Date()
+		dateYear, dateMonth, dateDay := date.Date()
+		if prYear == dateYear && prMonth == dateMonth && prDay == dateDay {
+			count++
 		}
 	}
 
 	return count
 }
 
+// countMergedPullRequests counts the pull requests of a person merged on a date and their average
+// hours from opening to merge
+func countMergedPullRequests(allPullRequests []*models.PullRequest, githubPersonID string, date time.Time) (int, float64) {
+	merged := 0
+	hours := 0.0
+	for _, pr := range allPullRequests {
+		if pr.AuthorID == nil || *pr.AuthorID != githubPersonID || !sameDay(pr.MergedAt, date) || pr.GithubCreatedAt == nil {
+			continue
+		}
+		merged++
+		hours += pr.MergedAt.Sub(*pr.GithubCreatedAt).Hours()
+	}
+	if merged == 0 {
+		return 0, 0
+	}
+	return merged, hours / float64(merged)
+}
+
+// countCoAuthoredCommits counts the commits on a date that name one of a person's emails in a
+// Co-authored-by trailer, leaving out the person's own commits and reverts
+func countCoAuthoredCommits(allCommits []*models.Commit, emails []string, date time.Time) int {
+	isPersonEmail := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		isPersonEmail[strings.ToLower(email)] = true
+	}
+
+	count := 0
+	for _, commit := range allCommits {
+		if !sameDay(&commit.CommitDate, date) || strings.HasPrefix(commit.Message, "Revert") {
+			continue
+		}
+		if commit.AuthorEmail != nil && isPersonEmail[strings.ToLower(*commit.AuthorEmail)] {
+			continue
+		}
+		for _, email := range coAuthorEmails(commit.Message) {
+			if isPersonEmail[email] {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// coAuthorEmails returns the lowercase emails of the Co-authored-by trailers of a commit message
+func coAuthorEmails(message string) []string {
+	var emails []string
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) < 15 || !strings.EqualFold(line[:15], "co-authored-by:") {
+			continue
+		}
+		start, end := strings.Index(line, "<"), strings.LastIndex(line, ">")
+		if start >= 0 && end > start {
+			emails = append(emails, strings.ToLower(strings.TrimSpace(line[start+1:end])))
+		}
+	}
+	return emails
+}
+
+// commentStats counts the comments of a person on one day by kind
+type commentStats struct {
+	Reviews        int
+	ReviewComments int
+	IssueComments  int
+
+	// Reviews by state
+	Approvals        int
+	ChangesRequested int
+	CommentedReviews int
+}
+
+// Total returns the number of comments of all kinds
+func (c commentStats) Total() int {
+	return c.Reviews + c.ReviewComments + c.IssueComments
+}
+
 // calculateCommentStatsOptimized calculates comment statistics using pre-loaded data
 func (s *PeopleStatisticsService) calculateCommentStatsOptimized(
 	allPRReviews []*models.PRReview,
+	allReviewComments []*models.PRReviewComment,
+	allIssueComments []*models.PRIssueComment,
 	githubPersonID string,
 	date time.Time,
-) int {
+) commentStats {
 	// Get the GitHub person to get their username
 	githubPerson, err := s.githubPersonRepo.GetByID(githubPersonID)
 	if err != nil {
-		return 0
+		return commentStats{}
 	}
 
-	count := 0
-	for _, review := range allPRReviews {
-		// Check if review was created by this person on the specified date
-		if review.GithubCreatedAt != nil {
-			reviewYear, reviewMonth, reviewDay := review.GithubCreatedAt.Date()
-			dateYear, dateMonth, dateDay := date.Date()
+	return countCommentsByLogin(allPRReviews, allReviewComments, allIssueComments, githubPerson.Username, date)
+}
 
-			if reviewYear == dateYear && reviewMonth == dateMonth && reviewDay == dateDay {
-				// Check if the review was created by this GitHub person
-				if review.ReviewerLogin == githubPerson.Username {
-					count++
-				}
+// countCommentsByLogin counts the reviews, inline review comments and conversation comments a login wrote on a date
+func countCommentsByLogin(
+	allPRReviews []*models.PRReview,
+	allReviewComments []*models.PRReviewComment,
+	allIssueComments []*models.PRIssueComment,
+	login string,
+	date time.Time,
+) commentStats {
+	var stats commentStats
+
+	for _, review := range allPRReviews {
+		if review.ReviewerLogin == login && sameDay(review.GithubCreatedAt, date) {
+			stats.Reviews++
+			switch review.State {
+			case "APPROVED":
+				stats.Approvals++
+			case "CHANGES_REQUESTED":
+				stats.ChangesRequested++
+			case "COMMENTED":
+				stats.CommentedReviews++
 			}
 		}
 	}
 
-	return count
+	for _, comment := range allReviewComments {
+		if comment.AuthorLogin == login && sameDay(comment.GithubCreatedAt, date) {
+			stats.ReviewComments++
+		}
+	}
+
+	for _, comment := range allIssueComments {
+		if comment.AuthorLogin == login && sameDay(comment.GithubCreatedAt, date) {
+			stats.IssueComments++
+		}
+	}
+
+	return stats
 }
 
-// calculateScore calculates the score based on activity and score settings
-func (s *PeopleStatisticsService) calculateScore(
-	commits, additions, deletions, pullRequests, comments int,
-	scoreSettings *models.ScoreSettings,
-) int {
+// sameDay reports whether t is set and falls on the calendar day of date
+func sameDay(t *time.Time, date time.Time) bool {
+	if t == nil {
+		return false
+	}
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := date.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// mergeCommentActivityDates adds the days with review or conversation comments to the sorted activity dates
+func mergeCommentActivityDates(
+	activityDates []time.Time,
+	allReviewComments []*models.PRReviewComment,
+	allIssueComments []*models.PRIssueComment,
+	startDate, endDate time.Time,
+) []time.Time {
+	activityMap := make(map[string]bool)
+	for _, date := range activityDates {
+		activityMap[date.Format("2006-01-02")] = true
+	}
+
+	add := func(t *time.Time) {
+		if t == nil || !t.After(startDate) || !t.Before(endDate.AddDate(0, 0, 1)) {
+			return
+		}
+		day := t.Format("2006-01-02")
+		if activityMap[day] {
+			return
+		}
+		if date, err := time.Parse("2006-01-02", day); err == nil {
+			activityMap[day] = true
+			activityDates = append(activityDates, date)
+		}
+	}
+
+	for _, comment := range allReviewComments {
+		add(comment.GithubCreatedAt)
+	}
+	for _, comment := range allIssueComments {
+		add(comment.GithubCreatedAt)
+	}
+
+	sort.Slice(activityDates, func(i, j int) bool {
+		return activityDates[i].Before(activityDates[j])
+	})
+
+	return activityDates
+}
+
+// countIssuesByPerson counts the issues a person opened and closed on a date
+func countIssuesByPerson(allIssues []*models.Issue, githubPersonID string, date time.Time) (opened, closed int) {
+	for _, issue := range allIssues {
+		if issue.AuthorID != nil && *issue.AuthorID == githubPersonID && sameDay(issue.GithubCreatedAt, date) {
+			opened++
+		}
+		if issue.ClosedByID != nil && *issue.ClosedByID == githubPersonID && sameDay(issue.ClosedAt, date) {
+			closed++
+		}
+	}
+	return opened, closed
+}
+
+// mergeIssueActivityDates adds the days issues were opened or closed on to the sorted activity dates
+func mergeIssueActivityDates(activityDates []time.Time, allIssues []*models.Issue, startDate, endDate time.Time) []time.Time {
+	activityMap := make(map[string]bool)
+	for _, date := range activityDates {
+		activityMap[date.Format("2006-01-02")] = true
+	}
+
+	add := func(t *time.Time) {
+		if t == nil || !t.After(startDate) || !t.Before(endDate.AddDate(0, 0, 1)) {
+			return
+		}
+		day := t.Format("2006-01-02")
+		if activityMap[day] {
+			return
+		}
+		if date, err := time.Parse("2006-01-02", day); err == nil {
+			activityMap[day] = true
+			activityDates = append(activityDates, date)
+		}
+	}
+
+	for _, issue := range allIssues {
+		add(issue.GithubCreatedAt)
+		add(issue.ClosedAt)
+	}
+
+	sort.Slice(activityDates, func(i, j int) bool {
+		return activityDates[i].Before(activityDates[j])
+	})
+
+	return activityDates
+}
+
+// scoreActivity is what a person did in a repository on a day, as scored by score settings and formulas
+type scoreActivity struct {
+	Commits            int
+	Additions          int
+	Deletions          int
+	CoAuthoredCommits  int
+	PullRequests       int
+	MergedPullRequests int
+	PRCycleHours       float64
+	Comments           commentStats
+	IssuesOpened       int
+	IssuesClosed       int
+}
+
+// variables returns the activity as the variables of score formulas
+func (a scoreActivity) variables() map[string]float64 {
+	return map[string]float64{
+		"commits":              float64(a.Commits),
+		"additions":            float64(a.Additions),
+		"deletions":            float64(a.Deletions),
+		"co_authored_commits":  float64(a.CoAuthoredCommits),
+		"pull_requests":        float64(a.PullRequests),
+		"merged_pull_requests": float64(a.MergedPullRequests),
+		"pr_cycle_hours":       a.PRCycleHours,
+		"reviews":              float64(a.Comments.Reviews),
+		"approvals":            float64(a.Comments.Approvals),
+		"changes_requested":    float64(a.Comments.ChangesRequested),
+		"commented_reviews":    float64(a.Comments.CommentedReviews),
+		"review_comments":      float64(a.Comments.ReviewComments),
+		"issue_comments":       float64(a.Comments.IssueComments),
+		"issues_opened":        float64(a.IssuesOpened),
+		"issues_closed":        float64(a.IssuesClosed),
+	}
+}
+
+// isEmpty tells whether every variable of the activity is zero
+func (a scoreActivity) isEmpty() bool {
+	for _, value := range a.variables() {
+		if value != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// calculateScore calculates the score of an activity with the project's score formula, or else as a sum
+// weighted by its score settings, and applies the multiplier of the repository. Reviews keep the comment
+// weight, inline review comments and conversation comments have their own.
+func (s *PeopleStatisticsService) calculateScore(activity scoreActivity, scoring *repositoryScoring) int {
 	score := 0
 
-	// Add points for commits
-	score += commits * scoreSettings.Commits
+	if scoring.formula != nil {
+		score = scoring.formula.Evaluate(activity.variables())
+	} else {
+		scoreSettings := scoring.settings
+
+		// Add points for commits
+		score += activity.Commits * scoreSettings.Commits
+
+		// Add points for additions
+		score += activity.Additions * scoreSettings.Additions
+
+		// Add points for deletions
+		score += activity.Deletions * scoreSettings.Deletions
+
+		// Add points for pull requests
+		score += activity.PullRequests * scoreSettings.PullRequests
+
+		// Add points for comments
+		score += activity.Comments.Reviews * scoreSettings.Comments
+		score += activity.Comments.ReviewComments * scoreSettings.ReviewComments
+		score += activity.Comments.IssueComments * scoreSettings.IssueComments
+
+		// Add points for issues
+		score += activity.IssuesOpened * scoreSettings.IssuesOpened
+		score += activity.IssuesClosed * scoreSettings.IssuesClosed
+	}
+
+	if scoring.multiplier == 1 {
+		return score
+	}
+	return int(math.Round(float64(score) * scoring.multiplier))
+}
+
+// previewScoreFormula scores what people did in the tracked repositories of a project over the last days
+// with a formula, without storing anything, and compares their totals with their current scores over the
+// same days. Bots are left out.
+func (s *PeopleStatisticsService) previewScoreFormula(projectID string, formula *scoreFormula, days int) ([]*models.ScoreFormulaPreview, error) {
+	endDate := time.Now()
+	startDate := endDate.UTC().Truncate(24*time.Hour).AddDate(0, 0, -days)
+
+	projectRepos, err := s.projectRepositoryRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	formulaScores := make(map[string]int)
+	tracked := make(map[string]bool)
+	for _, projectRepo := range projectRepos {
+		if !projectRepo.IsTracked {
+			continue
+		}
+		tracked[projectRepo.ID] = true
+
+		// Keep the repository's multiplier, the formula replaces the weights and formulas of every day
+		scoring, err := s.getRepositoryScoring(projectID, projectRepo.ID)
+		if err != nil {
+			return nil, err
+		}
+		scoring.versions = nil
+		scoring.formula = formula
+
+		if err := s.computeRepositoryStatistics(projectID, projectRepo.ID, projectRepo.GithubRepoID, startDate, scoring,
+			func(stat *models.PeopleStatistics) error {
+				formulaScores[stat.GithubPersonID] += stat.Score
+				return nil
+			},
+		); err != nil {
+			return nil, err
+		}
+	}
 
-	// Add points for additions
-	score += additions * scoreSettings.Additions
+	currentStats, err := s.peopleStatsRepo.GetByDateRange(projectID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	currentScores := make(map[string]int)
+	for _, stat := range currentStats {
+		if tracked[stat.RepositoryID] {
+			currentScores[stat.GithubPersonID] += stat.Score
+		}
+	}
+
+	botSettings, err := s.botService.GetSettings(projectID)
+	if err != nil {
+		return nil, err
+	}
 
-	// Add points for deletions
-	score += deletions * scoreSettings.Deletions
+	var previews []*models.ScoreFormulaPreview
+	seen := make(map[string]bool)
+	for _, scores := range []map[string]int{formulaScores, currentScores} {
+		for personID := range scores {
+			if seen[personID] {
+				continue
+			}
+			seen[personID] = true
 
-	// Add points for pull requests
-	score += pullRequests * scoreSettings.PullRequests
+			person, err := s.githubPersonRepo.GetByID(personID)
+			if err != nil || botSettings.IsBotPerson(person) {
+				continue
+			}
+			previews = append(previews, &models.ScoreFormulaPreview{
+				GitHubPerson: person,
+				CurrentScore: currentScores[personID],
+				FormulaScore: formulaScores[personID],
+			})
+		}
+	}
 
-	// Add points for comments
-	score += comments * scoreSettings.Comments
+	// Rank by current score, then by formula score, which is the order shown
+	sort.Slice(previews, func(i, j int) bool { return previews[i].CurrentScore > previews[j].CurrentScore })
+	for i, preview := range previews {
+		preview.CurrentRank = i + 1
+	}
+	sort.SliceStable(previews, func(i, j int) bool { return previews[i].FormulaScore > previews[j].FormulaScore })
+	for i, preview := range previews {
+		preview.FormulaRank = i + 1
+	}
 
-	return score
+	return previews, nil
 }
 
 // GetPersonWeeklyAverages calculates weekly averages for a specific person in a project
-func (s *PeopleStatisticsService) GetPersonWeeklyAverages(projectID, githubPersonID string) (map[string]float64, error) {
+func (s *PeopleStatisticsService) GetPersonWeeklyAverages(projectID, githubPersonID string, filter *models.RepositoryFilter) (map[string]float64, error) {
 	// Get all statistics for this person in this project
 	stats, err := s.peopleStatsRepo.GetByProjectAndPerson(projectID, githubPersonID)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching person statistics: %w", err)
 	}
+	stats = filterPeopleStatistics(stats, filter)
 
 	if len(stats) == 0 {
 		// Return zero averages if no data
@@ -1090,12 +1572,13 @@ func (s *PeopleStatisticsService) GetPersonWeeklyAverages(projectID, githubPerso
 }
 
 // GetPersonScoreHistory returns the person's score history for graphing
-func (s *PeopleStatisticsService) GetPersonScoreHistory(projectID, githubPersonID string) ([]map[string]interface{}, error) {
+func (s *PeopleStatisticsService) GetPersonScoreHistory(projectID, githubPersonID string, filter *models.RepositoryFilter) ([]map[string]interface{}, error) {
 	// Get all statistics for this person in this project
 	stats, err := s.peopleStatsRepo.GetByProjectAndPerson(projectID, githubPersonID)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching person statistics: %w", err)
 	}
+	stats = filterPeopleStatistics(stats, filter)
 
 	if len(stats) == 0 {
 		return []map[string]interface{}{}, nil
@@ -1144,12 +1627,13 @@ func (s *PeopleStatisticsService) GetPersonScoreHistory(projectID, githubPersonI
 }
 
 // GetPersonTopReposAndLanguages returns top repositories and languages for a person
-func (s *PeopleStatisticsService) GetPersonTopReposAndLanguages(projectID, githubPersonID string) (map[string]interface{}, error) {
+func (s *PeopleStatisticsService) GetPersonTopReposAndLanguages(projectID, githubPersonID string, filter *models.RepositoryFilter) (map[string]interface{}, error) {
 	// Get all statistics for this person in this project
 	stats, err := s.peopleStatsRepo.GetByProjectAndPerson(projectID, githubPersonID)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching person statistics: %w", err)
 	}
+	stats = filterPeopleStatistics(stats, filter)
 
 	if len(stats) == 0 {
 		return map[string]interface{}{
@@ -1210,6 +1694,9 @@ func (s *PeopleStatisticsService) GetPersonTopReposAndLanguages(projectID, githu
 
 	if err == nil {
 		for _, commit := range commits {
+			if !filter.IncludesGithubRepository(commit.GithubRepositoryID) {
+				continue
+			}
 			// Get commit files for this commit
 			commitFiles, err := s.commitFileRepo.GetByCommitID(commit.ID)
 			if err == nil {
@@ -1255,7 +1742,7 @@ func (s *PeopleStatisticsService) GetPersonTopReposAndLanguages(projectID, githu
 }
 
 // GetPersonTopCommitsAndPRs returns top commits and PRs for a person
-func (s *PeopleStatisticsService) GetPersonTopCommitsAndPRs(projectID, githubPersonID string) (map[string]interface{}, error) {
+func (s *PeopleStatisticsService) GetPersonTopCommitsAndPRs(projectID, githubPersonID string, filter *models.RepositoryFilter) (map[string]interface{}, error) {
 	// Get all commits for this person in this project
 	commits, err := s.commitRepo.GetByProjectAndPerson(projectID, githubPersonID)
 	if err != nil {
@@ -1265,6 +1752,9 @@ func (s *PeopleStatisticsService) GetPersonTopCommitsAndPRs(projectID, githubPer
 	// Get top 3 commits by LoC (additions + deletions)
 	var topCommits []map[string]interface{}
 	for _, commit := range commits {
+		if !filter.IncludesGithubRepository(commit.GithubRepositoryID) {
+			continue
+		}
 		loc := commit.Additions + commit.Deletions
 		topCommits = append(topCommits, map[string]interface{}{
 			"ID":        commit.ID,
@@ -1295,33 +1785,12 @@ func (s *PeopleStatisticsService) GetPersonTopCommitsAndPRs(projectID, githubPer
 		}, nil
 	}
 
-	// Get the GitHub person to get their username
-	githubPerson, err := s.githubPersonRepo.GetByID(githubPersonID)
-	if err != nil {
-		return map[string]interface{}{
-			"TopCommits": topCommits,
-			"TopPRs":     []map[string]interface{}{},
-		}, nil
-	}
-
-	// Filter PRs by username
-	var filteredPRs []*models.PullRequest
-	for _, pr := range prs {
-		if pr.User != nil {
-			var userData map[string]interface{}
-			if err := json.Unmarshal([]byte(*pr.User), &userData); err == nil {
-				if login, ok := userData["login"].(string); ok {
-					if login == githubPerson.Username {
-						filteredPRs = append(filteredPRs, pr)
-					}
-				}
-			}
-		}
-	}
-
 	// Get top 3 PRs by comment count
 	var topPRs []map[string]interface{}
-	for _, pr := range filteredPRs {
+	for _, pr := range prs {
+		if !filter.IncludesGithubRepository(pr.RepositoryID) {
+			continue
+		}
 		// Get comment count for this PR
 		commentCount, err := s.prReviewRepo.GetCommentCountByPRID(pr.ID)
 		if err != nil {
@@ -1401,12 +1870,13 @@ func getLanguageFromFile(filename string) string {
 }
 
 // GetPersonDetailedStats returns detailed statistics for a person
-func (s *PeopleStatisticsService) GetPersonDetailedStats(projectID, githubPersonID string) (map[string]interface{}, error) {
+func (s *PeopleStatisticsService) GetPersonDetailedStats(projectID, githubPersonID string, filter *models.RepositoryFilter) (map[string]interface{}, error) {
 	// Get all statistics for this person in this project
 	stats, err := s.peopleStatsRepo.GetByProjectAndPerson(projectID, githubPersonID)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching person statistics: %w", err)
 	}
+	stats = filterPeopleStatistics(stats, filter)
 
 	if len(stats) == 0 {
 		return map[string]interface{}{
@@ -1530,7 +2000,7 @@ func (s *PeopleStatisticsService) GetPersonDetailedStats(projectID, githubPerson
 }
 
 // GetPersonOvertimeStats calculates overtime statistics for a person
-func (s *PeopleStatisticsService) GetPersonOvertimeStats(projectID, githubPersonID string) (map[string]interface{}, error) {
+func (s *PeopleStatisticsService) GetPersonOvertimeStats(projectID, githubPersonID string, filter *models.RepositoryFilter) (map[string]interface{}, error) {
 	// Get the person's commits for the project
 	commits, err := s.commitRepo.GetByProjectAndPerson(projectID, githubPersonID)
 	if err != nil {
@@ -1551,6 +2021,9 @@ func (s *PeopleStatisticsService) GetPersonOvertimeStats(projectID, githubPerson
 
 	// Process commits
 	for _, commit := range commits {
+		if !filter.IncludesGithubRepository(commit.GithubRepositoryID) {
+			continue
+		}
 		totalCommits++
 		commitTime := commit.CommitDate
 
@@ -1567,6 +2040,9 @@ func (s *PeopleStatisticsService) GetPersonOvertimeStats(projectID, githubPerson
 
 	// Process PR reviews (comments)
 	for _, review := range prReviews {
+		if !filter.IncludesGithubRepository(review.RepositoryID) {
+			continue
+		}
 		totalComments++
 		reviewTime := review.SubmittedAt
 
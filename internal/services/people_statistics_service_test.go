@@ -1,11 +1,16 @@
 package services
 
 import (
+	"database/sql"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCalculateScore(t *testing.T) {
@@ -74,12 +79,14 @@ func TestCalculateScore(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			score := service.calculateScore(
-				tc.commits,
-				tc.additions,
-				tc.deletions,
-				tc.pullRequests,
-				tc.comments,
-				tc.scoreSettings,
+				scoreActivity{
+					Commits:      tc.commits,
+					Additions:    tc.additions,
+					Deletions:    tc.deletions,
+					PullRequests: tc.pullRequests,
+					Comments:     commentStats{Reviews: tc.comments},
+				},
+				&repositoryScoring{settings: tc.scoreSettings, multiplier: 1},
 			)
 
 			assert.Equal(t, tc.expectedScore, score, "Score calculation should match expected value")
@@ -99,7 +106,10 @@ func TestCalculateScoreEdgeCases(t *testing.T) {
 	// Test with negative values (should still calculate)
 	t.Run("Negative values", func(t *testing.T) {
 		scoreSettings := models.NewScoreSettings("test-project")
-		score := service.calculateScore(-1, -1, -1, -1, -1, scoreSettings)
+		score := service.calculateScore(
+			scoreActivity{Commits: -1, Additions: -1, Deletions: -1, PullRequests: -1, Comments: commentStats{Reviews: -1}},
+			&repositoryScoring{settings: scoreSettings, multiplier: 1},
+		)
 		expected := -1*10 + -1*1 + -1*3 + -1*20 + -1*100
 		assert.Equal(t, expected, score, "Score should handle negative values")
 	})
@@ -107,10 +117,102 @@ func TestCalculateScoreEdgeCases(t *testing.T) {
 	// Test with very large values
 	t.Run("Large values", func(t *testing.T) {
 		scoreSettings := models.NewScoreSettings("test-project")
-		score := service.calculateScore(1000, 10000, 5000, 100, 500, scoreSettings)
+		score := service.calculateScore(
+			scoreActivity{Commits: 1000, Additions: 10000, Deletions: 5000, PullRequests: 100, Comments: commentStats{Reviews: 500}},
+			&repositoryScoring{settings: scoreSettings, multiplier: 1},
+		)
 		expected := 1000*10 + 10000*1 + 5000*3 + 100*20 + 500*100
 		assert.Equal(t, expected, score, "Score should handle large values")
 	})
+
+	// Test with comments, issues and a repository multiplier
+	t.Run("Repository multiplier", func(t *testing.T) {
+		scoreSettings := models.NewScoreSettings("test-project")
+		scoreSettings.IssuesOpened = 5
+		comments := commentStats{Reviews: 1, ReviewComments: 2, IssueComments: 3}
+		activity := scoreActivity{Commits: 2, Comments: comments, IssuesOpened: 1}
+		base := 2*10 + 1*100 + 2*20 + 3*20 + 1*5 // 225
+
+		assert.Equal(t, base, service.calculateScore(activity, &repositoryScoring{settings: scoreSettings, multiplier: 1}))
+		assert.Equal(t, 338, service.calculateScore(activity, &repositoryScoring{settings: scoreSettings, multiplier: 1.5}), "Weighted scores should be rounded")
+		assert.Equal(t, 0, service.calculateScore(activity, &repositoryScoring{settings: scoreSettings, multiplier: 0}))
+	})
+
+	// Test with an active score formula, which replaces the weights
+	t.Run("Score formula", func(t *testing.T) {
+		formula, err := parseScoreFormula("commits * 10 + cap(additions, 100) + approvals * 5")
+		assert.NoError(t, err)
+
+		activity := scoreActivity{Commits: 2, Additions: 500, Deletions: 300, Comments: commentStats{Reviews: 2, Approvals: 1}}
+		scoring := &repositoryScoring{settings: models.NewScoreSettings("test-project"), formula: formula, multiplier: 2}
+		assert.Equal(t, (2*10+100+1*5)*2, service.calculateScore(activity, scoring))
+	})
+}
+
+func TestDailyStatisticsWithNegativeScore(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	migration, err := os.ReadFile("../../migrations/009_create_pull_requests_tables.sql")
+	require.NoError(t, err)
+	_, err = db.Exec(string(migration))
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO github_people (id, github_user_id, username) VALUES ('person-1', 1, 'one'), ('person-2', 2, 'two')`)
+	require.NoError(t, err)
+
+	service := &PeopleStatisticsService{githubPersonRepo: repositories.NewGithubPersonRepository(db)}
+	day := time.Date(2025, 8, 20, 0, 0, 0, 0, time.UTC)
+	openedAt := day.Add(10 * time.Hour)
+	author := "person-1"
+
+	formula, err := parseScoreFormula("-issues_opened * 5")
+	require.NoError(t, err)
+	scoring := &repositoryScoring{settings: models.NewScoreSettings("test-project"), formula: formula, multiplier: 1}
+
+	people := []*models.GithubPerson{{ID: "person-1"}, {ID: "person-2"}}
+	personEmailMap := map[string]string{"person-1": "one@example.com", "person-2": "two@example.com"}
+	issues := []*models.Issue{{AuthorID: &author, GithubCreatedAt: &openedAt}}
+
+	var saved []*models.PeopleStatistics
+	err = service.calculateDailyStatisticsOptimized(
+		"test-project", "repo", day, scoring, nil, nil, nil, personEmailMap,
+		nil, nil, nil, nil, nil, issues, nil, people,
+		func(stats *models.PeopleStatistics) error {
+			saved = append(saved, stats)
+			return nil
+		},
+	)
+	assert.NoError(t, err)
+
+	// Only opening an issue counts, and it lowers the score; the idle person gets no row
+	if assert.Len(t, saved, 1) {
+		assert.Equal(t, "person-1", saved[0].GithubPersonID)
+		assert.Equal(t, -5, saved[0].Score)
+	}
+}
+
+func TestRepositoryScoreSettingsWeights(t *testing.T) {
+	project := models.NewScoreSettings("test-project")
+
+	var none *models.RepositoryScoreSettings
+	assert.Same(t, project, none.Weights(project))
+	assert.Equal(t, 1.0, none.ScoreMultiplier())
+
+	settings := models.NewRepositoryScoreSettings("repo", project)
+	settings.Multiplier = 2
+	settings.Commits = 50
+	assert.Same(t, project, settings.Weights(project), "Weights only apply when they override the project's")
+
+	settings.OverrideWeights = true
+	weights := settings.Weights(project)
+	assert.Equal(t, 50, weights.Commits)
+	assert.Equal(t, project.Additions, weights.Additions)
+	assert.Equal(t, 10, project.Commits, "Project weights should be left unchanged")
+
+	settings.Multiplier = models.MaxScoreMultiplier + 1
+	assert.IsType(t, &models.ValidationError{}, settings.Validate())
 }
 
 func TestScoreSettingsValidation(t *testing.T) {
@@ -355,3 +457,110 @@ func TestIsExcludedFolder(t *testing.T) {
 		})
 	}
 }
+
+func TestCountCommentsByLogin(t *testing.T) {
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	morning := day.Add(9 * time.Hour)
+	nextDay := day.AddDate(0, 0, 1)
+	rootID := int64(1)
+
+	reviews := []*models.PRReview{
+		{ReviewerLogin: "alice", GithubCreatedAt: &morning},
+		{ReviewerLogin: "bob", GithubCreatedAt: &morning},
+	}
+	reviewComments := []*models.PRReviewComment{
+		{GithubCommentID: 1, AuthorLogin: "alice", GithubCreatedAt: &morning},
+		{GithubCommentID: 2, InReplyToGithubID: &rootID, AuthorLogin: "alice", GithubCreatedAt: &morning},
+		{GithubCommentID: 3, AuthorLogin: "alice", GithubCreatedAt: &nextDay},
+		{GithubCommentID: 4, AuthorLogin: "alice"},
+	}
+	issueComments := []*models.PRIssueComment{
+		{AuthorLogin: "alice", GithubCreatedAt: &morning},
+		{AuthorLogin: "bob", GithubCreatedAt: &morning},
+	}
+
+	stats := countCommentsByLogin(reviews, reviewComments, issueComments, "alice", day)
+	assert.Equal(t, commentStats{Reviews: 1, ReviewComments: 2, IssueComments: 1}, stats)
+	assert.Equal(t, 4, stats.Total())
+
+	assert.Equal(t, int64(1), reviewComments[1].ThreadID())
+	assert.True(t, reviewComments[1].IsReply())
+	assert.False(t, reviewComments[0].IsReply())
+}
+
+func TestCountIssuesByPerson(t *testing.T) {
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	morning := day.Add(9 * time.Hour)
+	nextDay := day.AddDate(0, 0, 1)
+	alice, bob := "alice", "bob"
+
+	issues := []*models.Issue{
+		{AuthorID: &alice, GithubCreatedAt: &morning, ClosedByID: &bob, ClosedAt: &nextDay},
+		{AuthorID: &alice, GithubCreatedAt: &morning, ClosedByID: &alice, ClosedAt: &morning},
+		{AuthorID: &bob, GithubCreatedAt: &morning},
+		{GithubCreatedAt: &morning},
+	}
+
+	opened, closed := countIssuesByPerson(issues, alice, day)
+	assert.Equal(t, 2, opened)
+	assert.Equal(t, 1, closed)
+
+	opened, closed = countIssuesByPerson(issues, bob, nextDay)
+	assert.Equal(t, 0, opened)
+	assert.Equal(t, 1, closed)
+}
+
+func TestScoreSettingsVersions(t *testing.T) {
+	service := &PeopleStatisticsService{}
+	day := func(d int) time.Time { return time.Date(2025, 8, d, 15, 0, 0, 0, time.UTC) }
+	from := func(d int) *time.Time { date := time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC); return &date }
+	version := func(number int, effectiveFrom *time.Time, commits int) *models.ScoreSettingsVersion {
+		settings := models.NewScoreSettings("test-project")
+		settings.Commits = commits
+		v := models.NewScoreSettingsVersion(settings, effectiveFrom, nil)
+		v.Version = number
+		return v
+	}
+
+	// Version 2 keeps the scores before the 10th, version 3 rescores all of history and version 4
+	// applies from the 20th
+	versions := []*models.ScoreSettingsVersion{
+		version(1, nil, 10),
+		version(2, from(10), 20),
+	}
+	scoring := &repositoryScoring{settings: models.NewScoreSettings("test-project"), versions: versions, multiplier: 1}
+	activity := scoreActivity{Commits: 1}
+
+	assert.Equal(t, 10, service.calculateScore(activity, scoring.on(day(9))))
+	assert.Equal(t, 20, service.calculateScore(activity, scoring.on(day(10))))
+
+	scoring.versions = append(versions, version(3, nil, 30), version(4, from(20), 40))
+	assert.Equal(t, 30, service.calculateScore(activity, scoring.on(day(9))))
+	assert.Equal(t, 30, service.calculateScore(activity, scoring.on(day(19))))
+	assert.Equal(t, 40, service.calculateScore(activity, scoring.on(day(20))))
+
+	// Version 5 scores the days from the 25th with a formula, which version 6 keeps while changing the
+	// weights, and version 7 goes back to the weights from the 28th
+	formula, err := parseScoreFormula("100 * commits")
+	assert.NoError(t, err)
+	formulaID := "formula"
+	withFormula := func(v *models.ScoreSettingsVersion) *models.ScoreSettingsVersion {
+		v.ScoreFormulaID = &formulaID
+		return v
+	}
+	scoring.formulas = map[string]*scoreFormula{formulaID: formula}
+	scoring.versions = append(scoring.versions, withFormula(version(5, from(25), 40)), withFormula(version(6, from(25), 50)), version(7, from(28), 60))
+	assert.Equal(t, 40, service.calculateScore(activity, scoring.on(day(24))))
+	assert.Equal(t, 100, service.calculateScore(activity, scoring.on(day(25))))
+	assert.Equal(t, 100, service.calculateScore(activity, scoring.on(day(27))))
+	assert.Equal(t, 60, service.calculateScore(activity, scoring.on(day(28))))
+
+	// Repositories with their own weights still get the formulas of the versions
+	scoring.overrideWeights = true
+	assert.Equal(t, 10, service.calculateScore(activity, scoring.on(day(24))))
+	assert.Equal(t, 100, service.calculateScore(activity, scoring.on(day(25))))
+
+	changes := versions[1].ChangesFrom(versions[0])
+	assert.Equal(t, []models.ScoreSettingsChange{{Name: "Commits", From: 10, To: 20}}, changes)
+	assert.Empty(t, versions[0].ChangesFrom(versions[0]))
+}
@@ -0,0 +1,543 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+// PRCycleMetricsService derives lifecycle metrics of pull requests and aggregates them for reports
+type PRCycleMetricsService struct {
+	pullRequestRepo    *repositories.PullRequestRepository
+	prReviewRepo       *repositories.PRReviewRepository
+	prDraftEventRepo   *repositories.PRDraftEventRepository
+	prCycleMetricsRepo *repositories.PRCycleMetricsRepository
+	githubPersonRepo   *repositories.GithubPersonRepository
+	githubRepoRepo     *repositories.GitHubRepositoryRepository
+}
+
+func NewPRCycleMetricsService(
+	pullRequestRepo *repositories.PullRequestRepository,
+	prReviewRepo *repositories.PRReviewRepository,
+	prDraftEventRepo *repositories.PRDraftEventRepository,
+	prCycleMetricsRepo *repositories.PRCycleMetricsRepository,
+	githubPersonRepo *repositories.GithubPersonRepository,
+	githubRepoRepo *repositories.GitHubRepositoryRepository,
+) *PRCycleMetricsService {
+	return &PRCycleMetricsService{
+		pullRequestRepo:    pullRequestRepo,
+		prReviewRepo:       prReviewRepo,
+		prDraftEventRepo:   prDraftEventRepo,
+		prCycleMetricsRepo: prCycleMetricsRepo,
+		githubPersonRepo:   githubPersonRepo,
+		githubRepoRepo:     githubRepoRepo,
+	}
+}
+
+// CalculateForRepository derives the metrics of every pull request of a GitHub repository and replaces the stored ones
+func (s *PRCycleMetricsService) CalculateForRepository(githubRepositoryID string) error {
+	pullRequests, err := s.pullRequestRepo.GetByRepositoryID(githubRepositoryID)
+	if err != nil {
+		return err
+	}
+
+	reviews, err := s.prReviewRepo.GetByRepositoryID(githubRepositoryID)
+	if err != nil {
+		return err
+	}
+	reviewsByPR := make(map[string][]*models.PRReview)
+	for _, review := range reviews {
+		reviewsByPR[review.PullRequestID] = append(reviewsByPR[review.PullRequestID], review)
+	}
+
+	events, err := s.prDraftEventRepo.GetByRepositoryID(githubRepositoryID)
+	if err != nil {
+		return err
+	}
+	eventsByPR := make(map[string][]*models.PRDraftEvent)
+	for _, event := range events {
+		eventsByPR[event.PullRequestID] = append(eventsByPR[event.PullRequestID], event)
+	}
+
+	now := time.Now()
+	logins := make(map[string]string)
+	var metrics []*models.PRCycleMetrics
+	for _, pr := range pullRequests {
+		if pr.GithubCreatedAt == nil {
+			continue
+		}
+		metrics = append(metrics, calculatePRCycleMetrics(pr, s.authorLogin(pr, logins), reviewsByPR[pr.ID], eventsByPR[pr.ID], now))
+	}
+
+	return s.prCycleMetricsRepo.ReplaceByRepositoryID(githubRepositoryID, metrics)
+}
+
+// calculatePRCycleMetrics derives the lifecycle metrics of a pull request. Only reviews by someone
+// other than the author that were submitted before the pull request was closed count. A review
+// round is the set of reviews left on the same head commit.
+func calculatePRCycleMetrics(pr *models.PullRequest, authorLogin string, reviews []*models.PRReview, events []*models.PRDraftEvent, now time.Time) *models.PRCycleMetrics {
+	metrics := &models.PRCycleMetrics{
+		PullRequestID: pr.ID,
+		RepositoryID:  pr.RepositoryID,
+		AuthorLogin:   authorLogin,
+		OpenedAt:      *pr.GithubCreatedAt,
+		MergedAt:      pr.MergedAt,
+		ClosedAt:      pr.ClosedAt,
+		Additions:     pr.Additions,
+		Deletions:     pr.Deletions,
+		ChangedFiles:  pr.ChangedFiles,
+		CommitCount:   pr.CommitCount,
+	}
+
+	end := now
+	if pr.MergedAt != nil {
+		end = *pr.MergedAt
+	} else if pr.ClosedAt != nil {
+		end = *pr.ClosedAt
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+	drafts := draftSpans(metrics.OpenedAt, pr.Draft, events, end)
+	for _, span := range drafts {
+		metrics.DraftSeconds += int64(span.end.Sub(span.start) / time.Second)
+	}
+
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviewTime(reviews[i]).Before(reviewTime(reviews[j]))
+	})
+	rounds := make(map[string]bool)
+	for _, review := range reviews {
+		submittedAt := review.SubmittedAt
+		if submittedAt == nil || review.State == "PENDING" || strings.EqualFold(review.ReviewerLogin, metrics.AuthorLogin) {
+			continue
+		}
+		if submittedAt.After(end) {
+			continue
+		}
+
+		if metrics.FirstReviewAt == nil {
+			metrics.FirstReviewAt = submittedAt
+		}
+		rounds[review.CommitID] = true
+		if review.State == "APPROVED" {
+			if metrics.FirstApprovalAt == nil {
+				metrics.FirstApprovalAt = submittedAt
+			}
+			metrics.LastApprovalAt = submittedAt
+		}
+	}
+	metrics.ReviewRounds = len(rounds)
+
+	if metrics.FirstReviewAt != nil {
+		metrics.TimeToFirstReview = activeSeconds(metrics.OpenedAt, *metrics.FirstReviewAt, drafts)
+	}
+	if metrics.FirstApprovalAt != nil {
+		metrics.TimeToApproval = activeSeconds(metrics.OpenedAt, *metrics.FirstApprovalAt, drafts)
+	}
+	if pr.MergedAt != nil {
+		metrics.CycleTime = activeSeconds(metrics.OpenedAt, *pr.MergedAt, drafts)
+		if metrics.LastApprovalAt != nil {
+			metrics.ApprovalToMerge = activeSeconds(*metrics.LastApprovalAt, *pr.MergedAt, drafts)
+		}
+	}
+
+	return metrics
+}
+
+// authorLogin returns the login of the author of a pull request, caching the logins by person ID
+func (s *PRCycleMetricsService) authorLogin(pr *models.PullRequest, logins map[string]string) string {
+	if pr.AuthorID == nil {
+		return ""
+	}
+	login, ok := logins[*pr.AuthorID]
+	if !ok {
+		if person, err := s.githubPersonRepo.GetByID(*pr.AuthorID); err == nil {
+			login = person.Username
+		}
+		logins[*pr.AuthorID] = login
+	}
+	return login
+}
+
+func reviewTime(review *models.PRReview) time.Time {
+	if review.SubmittedAt == nil {
+		return time.Time{}
+	}
+	return *review.SubmittedAt
+}
+
+// timeSpan is the half-open interval [start, end)
+type timeSpan struct {
+	start time.Time
+	end   time.Time
+}
+
+// draftSpans returns the periods a pull request spent as draft until end. A pull request opened as
+// draft has "ready for review" as its first transition; without any recorded transition the current
+// draft flag is all there is to go on.
+func draftSpans(openedAt time.Time, draft bool, events []*models.PRDraftEvent, end time.Time) []timeSpan {
+	isDraft := draft
+	if len(events) > 0 {
+		isDraft = events[0].EventType == models.PRDraftEventReadyForReview
+	}
+
+	var spans []timeSpan
+	draftSince := openedAt
+	for _, event := range events {
+		if event.OccurredAt.After(end) {
+			break
+		}
+		switch event.EventType {
+		case models.PRDraftEventConvertToDraft:
+			if !isDraft {
+				isDraft = true
+				draftSince = event.OccurredAt
+			}
+		case models.PRDraftEventReadyForReview:
+			if isDraft {
+				spans = append(spans, timeSpan{start: draftSince, end: event.OccurredAt})
+				isDraft = false
+			}
+		}
+	}
+	if isDraft && end.After(draftSince) {
+		spans = append(spans, timeSpan{start: draftSince, end: end})
+	}
+
+	return spans
+}
+
+// activeSeconds returns the seconds between from and to that fall outside the draft spans
+func activeSeconds(from, to time.Time, drafts []timeSpan) *int64 {
+	var seconds int64
+	if to.After(from) {
+		duration := to.Sub(from)
+		for _, span := range drafts {
+			start, end := span.start, span.end
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				duration -= end.Sub(start)
+			}
+		}
+		seconds = int64(duration / time.Second)
+	}
+	return &seconds
+}
+
+// GetAllTimeReportByProject aggregates the cycle-time metrics of all pull requests of a project
+func (s *PRCycleMetricsService) GetAllTimeReportByProject(projectID string, filter *models.RepositoryFilter) (*models.PRCycleReport, error) {
+	return s.getReportByProject(projectID, time.Time{}, time.Time{}, filter)
+}
+
+// GetYearlyReportByProject aggregates the cycle-time metrics of a project for a year
+func (s *PRCycleMetricsService) GetYearlyReportByProject(projectID string, year int, filter *models.RepositoryFilter) (*models.PRCycleReport, error) {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(1, 0, 0), filter)
+}
+
+// GetMonthlyReportByProject aggregates the cycle-time metrics of a project for a month
+func (s *PRCycleMetricsService) GetMonthlyReportByProject(projectID string, year, month int, filter *models.RepositoryFilter) (*models.PRCycleReport, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(0, 1, 0), filter)
+}
+
+// GetWeeklyReportByProject aggregates the cycle-time metrics of a project for a week numbered like the weekly reports
+func (s *PRCycleMetricsService) GetWeeklyReportByProject(projectID string, year, week int, filter *models.RepositoryFilter) (*models.PRCycleReport, error) {
+	start, end := weekRange(year, week)
+	return s.getReportByProject(projectID, start, end, filter)
+}
+
+// GetDailyReportByProject aggregates the cycle-time metrics of a project for a day
+func (s *PRCycleMetricsService) GetDailyReportByProject(projectID string, date time.Time, filter *models.RepositoryFilter) (*models.PRCycleReport, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(0, 0, 1), filter)
+}
+
+// weekRange returns the bounds of a week numbered like SQLite's %W, where week 1 starts on the
+// first Monday of the year and the days before it belong to week 0
+func weekRange(year, week int) (time.Time, time.Time) {
+	jan1 := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	firstMonday := jan1.AddDate(0, 0, (8-int(jan1.Weekday()))%7)
+	if week <= 0 {
+		return jan1, firstMonday
+	}
+	start := firstMonday.AddDate(0, 0, (week-1)*7)
+	return start, start.AddDate(0, 0, 7)
+}
+
+// getReportByProject aggregates the metrics of a project within [start, end). Each metric counts in the
+// period its last event falls in: the first review, the first approval or the merge. Sizes count in the
+// period the pull request was opened in. Zero bounds include everything.
+func (s *PRCycleMetricsService) getReportByProject(projectID string, start, end time.Time, filter *models.RepositoryFilter) (*models.PRCycleReport, error) {
+	metrics, err := s.prCycleMetricsRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	metrics = filterByRepository(metrics, filter, func(m *models.PRCycleMetrics) string { return m.RepositoryID })
+
+	inPeriod := periodFilter(start, end)
+
+	project := &cycleSamples{}
+	people := make(map[string]*cycleSamples)
+	repos := make(map[string]*cycleSamples)
+	var opened []*models.PRCycleMetrics
+	for _, m := range metrics {
+		project.add(m, inPeriod)
+		if m.LinesChanged() != nil && inPeriod(&m.OpenedAt) {
+			opened = append(opened, m)
+		}
+
+		if m.AuthorLogin != "" {
+			login := strings.ToLower(m.AuthorLogin)
+			if people[login] == nil {
+				people[login] = &cycleSamples{}
+			}
+			people[login].add(m, inPeriod)
+		}
+
+		if repos[m.RepositoryID] == nil {
+			repos[m.RepositoryID] = &cycleSamples{}
+		}
+		repos[m.RepositoryID].add(m, inPeriod)
+	}
+
+	report := &models.PRCycleReport{Project: project.stats("All repositories")}
+	if len(opened) > 0 {
+		report.SizeBuckets, report.SizeReviewCorrelation, report.SizeCycleTimeCorrelation = sizeDistribution(opened)
+	}
+
+	projectPeople, err := s.githubPersonRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	personIDs := make(map[string]string, len(projectPeople))
+	for _, person := range projectPeople {
+		personIDs[strings.ToLower(person.Username)] = person.ID
+	}
+	for login, samples := range people {
+		if samples.empty() {
+			continue
+		}
+		stats := samples.stats(login)
+		stats.GitHubPersonID = personIDs[login]
+		report.People = append(report.People, stats)
+	}
+
+	for repositoryID, samples := range repos {
+		if samples.empty() {
+			continue
+		}
+		name := repositoryID
+		if repo, err := s.githubRepoRepo.GetByID(repositoryID); err == nil {
+			name = repo.FullName
+		}
+		stats := samples.stats(name)
+		stats.RepositoryID = repositoryID
+		report.Repositories = append(report.Repositories, stats)
+	}
+
+	sortCycleStats(report.People)
+	sortCycleStats(report.Repositories)
+
+	return report, nil
+}
+
+// sortCycleStats orders aggregates by the number of merged pull requests, then by name
+func sortCycleStats(stats []*models.PRCycleStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].CycleTime.Count != stats[j].CycleTime.Count {
+			return stats[i].CycleTime.Count > stats[j].CycleTime.Count
+		}
+		return stats[i].Name < stats[j].Name
+	})
+}
+
+// cycleSamples collects the metric values of a set of pull requests
+type cycleSamples struct {
+	timeToFirstReview []float64
+	timeToApproval    []float64
+	approvalToMerge   []float64
+	cycleTime         []float64
+	reviewRounds      []float64
+	size              []float64
+}
+
+// add records the metrics of a pull request whose events fall in the period
+func (c *cycleSamples) add(m *models.PRCycleMetrics, inPeriod func(*time.Time) bool) {
+	if lines := m.LinesChanged(); lines != nil && inPeriod(&m.OpenedAt) {
+		c.size = append(c.size, float64(*lines))
+	}
+	if m.TimeToFirstReview != nil && inPeriod(m.FirstReviewAt) {
+		c.timeToFirstReview = append(c.timeToFirstReview, float64(*m.TimeToFirstReview))
+	}
+	if m.TimeToApproval != nil && inPeriod(m.FirstApprovalAt) {
+		c.timeToApproval = append(c.timeToApproval, float64(*m.TimeToApproval))
+	}
+	if !inPeriod(m.MergedAt) {
+		return
+	}
+	if m.ApprovalToMerge != nil {
+		c.approvalToMerge = append(c.approvalToMerge, float64(*m.ApprovalToMerge))
+	}
+	if m.CycleTime != nil {
+		c.cycleTime = append(c.cycleTime, float64(*m.CycleTime))
+	}
+	c.reviewRounds = append(c.reviewRounds, float64(m.ReviewRounds))
+}
+
+func (c *cycleSamples) empty() bool {
+	return len(c.timeToFirstReview) == 0 && len(c.timeToApproval) == 0 && len(c.cycleTime) == 0 && len(c.size) == 0
+}
+
+func (c *cycleSamples) stats(name string) *models.PRCycleStats {
+	return &models.PRCycleStats{
+		Name:              name,
+		TimeToFirstReview: summarize(c.timeToFirstReview),
+		TimeToApproval:    summarize(c.timeToApproval),
+		ApprovalToMerge:   summarize(c.approvalToMerge),
+		CycleTime:         summarize(c.cycleTime),
+		ReviewRounds:      summarize(c.reviewRounds),
+		Size:              summarize(c.size),
+	}
+}
+
+// prSizeBuckets are the upper bounds (exclusive) of lines changed for each size label
+var prSizeBuckets = []struct {
+	label string
+	max   int
+}{
+	{"XS (< 10 lines)", 10},
+	{"S (10-49)", 50},
+	{"M (50-249)", 250},
+	{"L (250-999)", 1000},
+	{"XL (1000+)", math.MaxInt},
+}
+
+// sizeDistribution buckets pull requests by lines changed and correlates their size with the time to
+// first review and the cycle time
+func sizeDistribution(metrics []*models.PRCycleMetrics) ([]*models.PRSizeBucket, *float64, *float64) {
+	reviewSamples := make([][]float64, len(prSizeBuckets))
+	cycleTimeSamples := make([][]float64, len(prSizeBuckets))
+	buckets := make([]*models.PRSizeBucket, len(prSizeBuckets))
+	for i, bucket := range prSizeBuckets {
+		buckets[i] = &models.PRSizeBucket{Label: bucket.label}
+	}
+
+	var reviewSizes, reviewTimes, cycleSizes, cycleTimes []float64
+	for _, m := range metrics {
+		lines := *m.LinesChanged()
+		i := 0
+		for lines >= prSizeBuckets[i].max {
+			i++
+		}
+		buckets[i].PullRequests++
+
+		if m.TimeToFirstReview != nil {
+			reviewSamples[i] = append(reviewSamples[i], float64(*m.TimeToFirstReview))
+			reviewSizes = append(reviewSizes, float64(lines))
+			reviewTimes = append(reviewTimes, float64(*m.TimeToFirstReview))
+		}
+		if m.CycleTime != nil {
+			cycleTimeSamples[i] = append(cycleTimeSamples[i], float64(*m.CycleTime))
+			cycleSizes = append(cycleSizes, float64(lines))
+			cycleTimes = append(cycleTimes, float64(*m.CycleTime))
+		}
+	}
+
+	for i, bucket := range buckets {
+		bucket.TimeToFirstReview = summarize(reviewSamples[i])
+		bucket.CycleTime = summarize(cycleTimeSamples[i])
+	}
+
+	return buckets, spearman(reviewSizes, reviewTimes), spearman(cycleSizes, cycleTimes)
+}
+
+// spearman returns the rank correlation of two samples, or nil when there are fewer than three pairs
+// or one of the samples is constant
+func spearman(x, y []float64) *float64 {
+	if len(x) < 3 || len(x) != len(y) {
+		return nil
+	}
+	rx, ry := ranks(x), ranks(y)
+
+	n := float64(len(rx))
+	var meanX, meanY float64
+	for i := range rx {
+		meanX += rx[i] / n
+		meanY += ry[i] / n
+	}
+	var cov, varX, varY float64
+	for i := range rx {
+		dx, dy := rx[i]-meanX, ry[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return nil
+	}
+
+	rho := cov / math.Sqrt(varX*varY)
+	return &rho
+}
+
+// ranks returns the 1-based ranks of the values, giving ties their average rank
+func ranks(values []float64) []float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return values[order[a]] < values[order[b]]
+	})
+
+	result := make([]float64, len(values))
+	for i := 0; i < len(order); {
+		j := i
+		for j+1 < len(order) && values[order[j+1]] == values[order[i]] {
+			j++
+		}
+		rank := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			result[order[k]] = rank
+		}
+		i = j + 1
+	}
+	return result
+}
+
+// summarize returns the count, median and 90th percentile of the values
+func summarize(values []float64) models.MetricSummary {
+	if len(values) == 0 {
+		return models.MetricSummary{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return models.MetricSummary{
+		Count:  len(sorted),
+		Median: percentile(sorted, 50),
+		P90:    percentile(sorted, 90),
+	}
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating between the closest ranks
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculatePRCycleMetrics(t *testing.T) {
+	opened := time.Date(2025, 8, 4, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		t := opened.Add(time.Duration(hours) * time.Hour)
+		return &t
+	}
+	review := func(login, state, commit string, hours int) *models.PRReview {
+		return &models.PRReview{ReviewerLogin: login, State: state, CommitID: commit, SubmittedAt: at(hours)}
+	}
+	event := func(eventType string, hours int) *models.PRDraftEvent {
+		return &models.PRDraftEvent{EventType: eventType, OccurredAt: *at(hours)}
+	}
+	hours := func(h int64) *int64 {
+		seconds := h * 3600
+		return &seconds
+	}
+
+	testCases := []struct {
+		name              string
+		pr                *models.PullRequest
+		reviews           []*models.PRReview
+		events            []*models.PRDraftEvent
+		timeToFirstReview *int64
+		timeToApproval    *int64
+		approvalToMerge   *int64
+		cycleTime         *int64
+		reviewRounds      int
+		draftSeconds      int64
+	}{
+		{
+			name: "Merged after two rounds",
+			pr:   &models.PullRequest{GithubCreatedAt: at(0), MergedAt: at(30), ClosedAt: at(30)},
+			reviews: []*models.PRReview{
+				review("bob", "APPROVED", "b", 24),
+				review("bob", "CHANGES_REQUESTED", "a", 4),
+				review("alice", "COMMENTED", "a", 5),
+				review("carol", "PENDING", "b", 25),
+			},
+			timeToFirstReview: hours(4),
+			timeToApproval:    hours(24),
+			approvalToMerge:   hours(6),
+			cycleTime:         hours(30),
+			reviewRounds:      2,
+		},
+		{
+			name:              "Opened as draft",
+			pr:                &models.PullRequest{GithubCreatedAt: at(0), MergedAt: at(20), ClosedAt: at(20)},
+			reviews:           []*models.PRReview{review("bob", "APPROVED", "a", 18)},
+			events:            []*models.PRDraftEvent{event(models.PRDraftEventReadyForReview, 10)},
+			timeToFirstReview: hours(8),
+			timeToApproval:    hours(8),
+			approvalToMerge:   hours(2),
+			cycleTime:         hours(10),
+			reviewRounds:      1,
+			draftSeconds:      10 * 3600,
+		},
+		{
+			name: "Converted back to draft",
+			pr:   &models.PullRequest{GithubCreatedAt: at(0), MergedAt: at(20), ClosedAt: at(20)},
+			events: []*models.PRDraftEvent{
+				event(models.PRDraftEventReadyForReview, 12),
+				event(models.PRDraftEventConvertToDraft, 4),
+			},
+			cycleTime:    hours(12),
+			draftSeconds: 8 * 3600,
+		},
+		{
+			name:              "Closed without merge",
+			pr:                &models.PullRequest{GithubCreatedAt: at(0), ClosedAt: at(10)},
+			reviews:           []*models.PRReview{review("bob", "COMMENTED", "a", 2), review("bob", "APPROVED", "a", 12)},
+			timeToFirstReview: hours(2),
+			reviewRounds:      1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			metrics := calculatePRCycleMetrics(tc.pr, "alice", tc.reviews, tc.events, *at(100))
+
+			assert.Equal(t, "alice", metrics.AuthorLogin)
+			assert.Equal(t, tc.timeToFirstReview, metrics.TimeToFirstReview)
+			assert.Equal(t, tc.timeToApproval, metrics.TimeToApproval)
+			assert.Equal(t, tc.approvalToMerge, metrics.ApprovalToMerge)
+			assert.Equal(t, tc.cycleTime, metrics.CycleTime)
+			assert.Equal(t, tc.reviewRounds, metrics.ReviewRounds)
+			assert.Equal(t, tc.draftSeconds, metrics.DraftSeconds)
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []float64
+		expected models.MetricSummary
+	}{
+		{
+			name:     "No values",
+			values:   nil,
+			expected: models.MetricSummary{},
+		},
+		{
+			name:     "Single value",
+			values:   []float64{7},
+			expected: models.MetricSummary{Count: 1, Median: 7, P90: 7},
+		},
+		{
+			name:     "Interpolates between ranks",
+			values:   []float64{10, 1, 4, 2, 3, 5, 6, 7, 8, 9},
+			expected: models.MetricSummary{Count: 10, Median: 5.5, P90: 9.1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			summary := summarize(tc.values)
+			assert.Equal(t, tc.expected.Count, summary.Count)
+			assert.InDelta(t, tc.expected.Median, summary.Median, 0.0001)
+			assert.InDelta(t, tc.expected.P90, summary.P90, 0.0001)
+		})
+	}
+}
+
+func TestWeekRange(t *testing.T) {
+	// 2025-01-01 is a Wednesday, so week 1 starts on Monday 2025-01-06
+	start, end := weekRange(2025, 0)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = weekRange(2025, 32)
+	assert.Equal(t, time.Date(2025, 8, 11, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2025, 8, 18, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestSpearman(t *testing.T) {
+	testCases := []struct {
+		name     string
+		x        []float64
+		y        []float64
+		expected *float64
+	}{
+		{
+			name: "Too few pairs",
+			x:    []float64{1, 2},
+			y:    []float64{1, 2},
+		},
+		{
+			name: "Constant sample",
+			x:    []float64{1, 2, 3},
+			y:    []float64{5, 5, 5},
+		},
+		{
+			name:     "Monotonic increase",
+			x:        []float64{10, 200, 30, 4000},
+			y:        []float64{1, 3, 2, 50},
+			expected: func() *float64 { v := 1.0; return &v }(),
+		},
+		{
+			name:     "Opposite order with ties",
+			x:        []float64{1, 2, 2, 3},
+			y:        []float64{4, 3, 3, 1},
+			expected: func() *float64 { v := -1.0; return &v }(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rho := spearman(tc.x, tc.y)
+			if tc.expected == nil {
+				assert.Nil(t, rho)
+				return
+			}
+			assert.NotNil(t, rho)
+			assert.InDelta(t, *tc.expected, *rho, 0.0001)
+		})
+	}
+}
+
+func TestSizeDistribution(t *testing.T) {
+	metric := func(additions, deletions int, firstReview int64) *models.PRCycleMetrics {
+		return &models.PRCycleMetrics{Additions: &additions, Deletions: &deletions, TimeToFirstReview: &firstReview}
+	}
+
+	buckets, reviewCorrelation, cycleTimeCorrelation := sizeDistribution([]*models.PRCycleMetrics{
+		metric(3, 2, 60),
+		metric(30, 10, 120),
+		metric(40, 9, 300),
+		metric(900, 100, 3600),
+	})
+
+	counts := make([]int, len(buckets))
+	for i, bucket := range buckets {
+		counts[i] = bucket.PullRequests
+	}
+	assert.Equal(t, []int{1, 2, 0, 0, 1}, counts)
+	assert.Equal(t, models.MetricSummary{Count: 2, Median: 210, P90: 282}, buckets[1].TimeToFirstReview)
+	if assert.NotNil(t, reviewCorrelation) {
+		assert.InDelta(t, 1.0, *reviewCorrelation, 0.0001)
+	}
+	assert.Nil(t, cycleTimeCorrelation)
+}
@@ -189,3 +189,25 @@ func (s *ProjectCollaboratorService) GetProjectAccessType(projectID, userID stri
 
 	return "none", nil
 }
+
+// IsSharingGitHubToken reports whether a collaborator lets background jobs use their GitHub token
+func (s *ProjectCollaboratorService) IsSharingGitHubToken(projectID, userID string) (bool, error) {
+	collaborator, err := s.collaboratorRepo.GetByProjectAndUserID(projectID, userID)
+	if err != nil {
+		return false, err
+	}
+	return collaborator.ShareGitHubToken, nil
+}
+
+// SetShareGitHubToken records a collaborator's consent to spend their GitHub rate limit on the project's background jobs
+func (s *ProjectCollaboratorService) SetShareGitHubToken(projectID, userID string, share bool) error {
+	isCollaborator, err := s.IsUserCollaborator(projectID, userID)
+	if err != nil {
+		return fmt.Errorf("error checking collaboration: %w", err)
+	}
+	if !isCollaborator {
+		return fmt.Errorf("user is not a collaborator on this project")
+	}
+
+	return s.collaboratorRepo.UpdateShareGitHubToken(projectID, userID, share)
+}
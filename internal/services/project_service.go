@@ -103,6 +103,20 @@ func (s *ProjectService) UpdateProject(project *models.Project) error {
 	return s.projectRepo.Update(project)
 }
 
+// SetGitHubIngestion selects whether pull requests of a project are fetched with the REST or GraphQL API
+func (s *ProjectService) SetGitHubIngestion(id, mode string) error {
+	if !models.IsValidGitHubIngestion(mode) {
+		return &models.ValidationError{Field: "github_ingestion", Message: "Unknown ingestion mode"}
+	}
+
+	return s.projectRepo.UpdateGitHubIngestion(id, mode)
+}
+
+// SetGitHubSync saves which GitHub data of a project is fetched with its updates
+func (s *ProjectService) SetGitHubSync(project *models.Project) error {
+	return s.projectRepo.UpdateGitHubSync(project)
+}
+
 // DeleteProject performs a soft delete of a project
 func (s *ProjectService) DeleteProject(id string) error {
 	if id == "" {
@@ -84,8 +84,9 @@ func (s *ProjectUpdateSettingsService) UpdateProjectUpdateSettings(projectID str
 	return existing, nil
 }
 
-// UpsertProjectUpdateSettings creates or updates project update settings
-func (s *ProjectUpdateSettingsService) UpsertProjectUpdateSettings(projectID string, isEnabled bool, hour int) (*models.ProjectUpdateSettings, error) {
+// UpsertProjectUpdateSettings creates or updates project update settings. An empty repository group
+// schedules all tracked repositories.
+func (s *ProjectUpdateSettingsService) UpsertProjectUpdateSettings(projectID string, isEnabled bool, hour int, repositoryGroup string) (*models.ProjectUpdateSettings, error) {
 	// Validate input
 	if projectID == "" {
 		return nil, &models.ValidationError{Message: "Project ID is required"}
@@ -96,6 +97,9 @@ func (s *ProjectUpdateSettingsService) UpsertProjectUpdateSettings(projectID str
 
 	// Create or update settings
 	settings := models.NewProjectUpdateSettings(projectID, isEnabled, hour)
+	if repositoryGroup != "" {
+		settings.RepositoryGroup = &repositoryGroup
+	}
 	if err := settings.Validate(); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+	"github.com/google/go-github/v57/github"
+)
+
+type RepositoryDiscoveryService struct {
+	discoveryRepo     *repositories.RepositoryDiscoveryRepository
+	githubRepoService *GitHubRepositoryService
+}
+
+func NewRepositoryDiscoveryService(
+	discoveryRepo *repositories.RepositoryDiscoveryRepository,
+	githubRepoService *GitHubRepositoryService,
+) *RepositoryDiscoveryService {
+	return &RepositoryDiscoveryService{
+		discoveryRepo:     discoveryRepo,
+		githubRepoService: githubRepoService,
+	}
+}
+
+// AddSource binds a project to a GitHub organization, or to a single team when teamSlug is set
+func (s *RepositoryDiscoveryService) AddSource(projectID, organization, teamSlug string, autoTrack bool) (*models.RepositoryDiscoverySource, error) {
+	organization = strings.TrimSpace(organization)
+	teamSlug = strings.TrimSpace(teamSlug)
+
+	var slug *string
+	if teamSlug != "" {
+		slug = &teamSlug
+	}
+
+	source := models.NewRepositoryDiscoverySource(projectID, organization, slug, autoTrack)
+	if err := source.Validate(); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.discoveryRepo.ExistsSource(projectID, organization, slug)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, &models.ValidationError{Message: "This organization or team is already configured for the project"}
+	}
+
+	if err := s.discoveryRepo.CreateSource(source); err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// GetSources retrieves all discovery sources for a project
+func (s *RepositoryDiscoveryService) GetSources(projectID string) ([]*models.RepositoryDiscoverySource, error) {
+	if projectID == "" {
+		return nil, &models.ValidationError{Message: "Project ID is required"}
+	}
+
+	return s.discoveryRepo.GetSourcesByProjectID(projectID)
+}
+
+// DeleteSource removes a discovery source from a project
+func (s *RepositoryDiscoveryService) DeleteSource(projectID, sourceID string) error {
+	if sourceID == "" {
+		return &models.ValidationError{Message: "ID is required"}
+	}
+
+	return s.discoveryRepo.DeleteSource(projectID, sourceID)
+}
+
+// AddRule adds an include or exclude rule to a project
+func (s *RepositoryDiscoveryService) AddRule(projectID, action, field, value string) (*models.RepositoryDiscoveryRule, error) {
+	rule := models.NewRepositoryDiscoveryRule(projectID, action, field, strings.TrimSpace(value))
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.discoveryRepo.CreateRule(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// GetRules retrieves all discovery rules for a project
+func (s *RepositoryDiscoveryService) GetRules(projectID string) ([]*models.RepositoryDiscoveryRule, error) {
+	if projectID == "" {
+		return nil, &models.ValidationError{Message: "Project ID is required"}
+	}
+
+	return s.discoveryRepo.GetRulesByProjectID(projectID)
+}
+
+// DeleteRule removes a discovery rule from a project
+func (s *RepositoryDiscoveryService) DeleteRule(projectID, ruleID string) error {
+	if ruleID == "" {
+		return &models.ValidationError{Message: "ID is required"}
+	}
+
+	return s.discoveryRepo.DeleteRule(projectID, ruleID)
+}
+
+// HasSources reports whether the project is bound to at least one organization or team
+func (s *RepositoryDiscoveryService) HasSources(projectID string) (bool, error) {
+	sources, err := s.GetSources(projectID)
+	if err != nil {
+		return false, err
+	}
+	return len(sources) > 0, nil
+}
+
+// DiscoverProjectRepositories lists repositories of every configured organization or team,
+// applies the project's rules and attaches matching repositories to the project.
+// Newly attached repositories are tracked when their source has auto-tracking enabled.
+// It returns the number of repositories that were newly attached.
+func (s *RepositoryDiscoveryService) DiscoverProjectRepositories(projectID, token string) (int, error) {
+	if token == "" {
+		return 0, fmt.Errorf("GitHub token is required")
+	}
+
+	sources, err := s.GetSources(projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get discovery sources: %w", err)
+	}
+	if len(sources) == 0 {
+		return 0, nil
+	}
+
+	rules, err := s.GetRules(projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get discovery rules: %w", err)
+	}
+
+	githubClient := s.githubRepoService.createGitHubClient(token)
+	ctx := context.Background()
+
+	attached := 0
+	for _, source := range sources {
+		repos, err := s.listSourceRepositories(ctx, githubClient, source)
+		if err != nil {
+			log.Printf("Error listing repositories for %s: %v", describeSource(source), err)
+			continue
+		}
+
+		for _, repo := range repos {
+			githubRepo, err := s.githubRepoService.upsertRepository(repo)
+			if err != nil {
+				log.Printf("Error processing repository %s: %v", repo.GetFullName(), err)
+				continue
+			}
+
+			if !MatchesDiscoveryRules(githubRepo, rules) {
+				continue
+			}
+
+			created, err := s.githubRepoService.attachToProject(projectID, githubRepo.ID, source.AutoTrack)
+			if err != nil {
+				log.Printf("Error attaching repository %s to project %s: %v", githubRepo.FullName, projectID, err)
+				continue
+			}
+			if created {
+				attached++
+			}
+		}
+	}
+
+	return attached, nil
+}
+
+// listSourceRepositories lists all repositories of an organization or team
+func (s *RepositoryDiscoveryService) listSourceRepositories(ctx context.Context, client *github.Client, source *models.RepositoryDiscoverySource) ([]*github.Repository, error) {
+	var allRepos []*github.Repository
+
+	if source.TeamSlug != nil {
+		opt := &github.ListOptions{PerPage: 100}
+		for {
+			repos, resp, err := client.Teams.ListTeamReposBySlug(ctx, source.Organization, *source.TeamSlug, opt)
+			if err != nil {
+				return nil, err
+			}
+			allRepos = append(allRepos, repos...)
+			if resp.NextPage == 0 {
+				break
+			}
+			opt.Page = resp.NextPage
+		}
+		return allRepos, nil
+	}
+
+	opt := &github.RepositoryListByOrgOptions{
+		Type:        "all",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		repos, resp, err := client.Repositories.ListByOrg(ctx, source.Organization, opt)
+		if err != nil {
+			return nil, err
+		}
+		allRepos = append(allRepos, repos...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return allRepos, nil
+}
+
+// describeSource returns a human readable name for a discovery source
+func describeSource(source *models.RepositoryDiscoverySource) string {
+	if source.TeamSlug != nil {
+		return source.Organization + "/" + *source.TeamSlug
+	}
+	return source.Organization
+}
+
+// MatchesDiscoveryRules reports whether a repository passes the given rules.
+// A repository matching any exclude rule is rejected. When include rules exist,
+// the repository must also match at least one of them.
+func MatchesDiscoveryRules(repo *models.GitHubRepository, rules []*models.RepositoryDiscoveryRule) bool {
+	hasIncludeRules := false
+	included := false
+
+	for _, rule := range rules {
+		switch rule.Action {
+		case models.DiscoveryRuleExclude:
+			if rule.Matches(repo) {
+				return false
+			}
+		case models.DiscoveryRuleInclude:
+			hasIncludeRules = true
+			if rule.Matches(repo) {
+				included = true
+			}
+		}
+	}
+
+	return !hasIncludeRules || included
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchesDiscoveryRules(t *testing.T) {
+	goLang := "Go"
+	repo := &models.GitHubRepository{
+		Name:       "service-billing",
+		FullName:   "acme/service-billing",
+		Language:   &goLang,
+		Topics:     []string{"backend", "payments"},
+		IsArchived: false,
+		IsFork:     false,
+	}
+	archivedFork := &models.GitHubRepository{
+		Name:       "legacy-ui",
+		FullName:   "acme/legacy-ui",
+		IsArchived: true,
+		IsFork:     true,
+	}
+
+	rule := func(action, field, value string) *models.RepositoryDiscoveryRule {
+		return models.NewRepositoryDiscoveryRule("project", action, field, value)
+	}
+
+	testCases := []struct {
+		name     string
+		repo     *models.GitHubRepository
+		rules    []*models.RepositoryDiscoveryRule
+		expected bool
+	}{
+		{
+			name:     "No rules includes everything",
+			repo:     archivedFork,
+			rules:    nil,
+			expected: true,
+		},
+		{
+			name:     "Include name regex matches",
+			repo:     repo,
+			rules:    []*models.RepositoryDiscoveryRule{rule("include", "name", "^service-")},
+			expected: true,
+		},
+		{
+			name:     "Include name regex does not match",
+			repo:     archivedFork,
+			rules:    []*models.RepositoryDiscoveryRule{rule("include", "name", "^service-")},
+			expected: false,
+		},
+		{
+			name: "Any include rule is enough",
+			repo: repo,
+			rules: []*models.RepositoryDiscoveryRule{
+				rule("include", "topic", "frontend"),
+				rule("include", "language", "go"),
+			},
+			expected: true,
+		},
+		{
+			name: "Exclude wins over include",
+			repo: repo,
+			rules: []*models.RepositoryDiscoveryRule{
+				rule("include", "name", "^service-"),
+				rule("exclude", "topic", "Payments"),
+			},
+			expected: false,
+		},
+		{
+			name:     "Exclude archived",
+			repo:     archivedFork,
+			rules:    []*models.RepositoryDiscoveryRule{rule("exclude", "archived", "true")},
+			expected: false,
+		},
+		{
+			name:     "Exclude forks keeps non-forks",
+			repo:     repo,
+			rules:    []*models.RepositoryDiscoveryRule{rule("exclude", "fork", "true")},
+			expected: true,
+		},
+		{
+			name:     "Language rule ignores repositories without language",
+			repo:     archivedFork,
+			rules:    []*models.RepositoryDiscoveryRule{rule("include", "language", "Go")},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, MatchesDiscoveryRules(tc.repo, tc.rules))
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+// RepositoryGroupService manages the repository groups of a project and the repository filters of its reports
+type RepositoryGroupService struct {
+	projectRepoRepo *repositories.ProjectRepositoryRepository
+	githubRepoRepo  *repositories.GitHubRepositoryRepository
+}
+
+func NewRepositoryGroupService(
+	projectRepoRepo *repositories.ProjectRepositoryRepository,
+	githubRepoRepo *repositories.GitHubRepositoryRepository,
+) *RepositoryGroupService {
+	return &RepositoryGroupService{
+		projectRepoRepo: projectRepoRepo,
+		githubRepoRepo:  githubRepoRepo,
+	}
+}
+
+// GetGroups retrieves the names of the repository groups of a project
+func (s *RepositoryGroupService) GetGroups(projectID string) ([]string, error) {
+	return s.projectRepoRepo.GetGroupNamesByProjectID(projectID)
+}
+
+// SetRepositoryGroups replaces the groups of a repository of a project with a comma separated list of names
+func (s *RepositoryGroupService) SetRepositoryGroups(projectID, projectRepositoryID, groups string) error {
+	projectRepo, err := s.projectRepoRepo.GetByID(projectRepositoryID)
+	if err != nil || projectRepo.ProjectID != projectID {
+		return &models.ValidationError{Field: "repository_id", Message: "Repository not found in project"}
+	}
+
+	names, err := parseRepositoryGroups(groups)
+	if err != nil {
+		return err
+	}
+
+	// Reuse the spelling of existing groups so that names differing only in case stay one group
+	existing, err := s.GetGroups(projectID)
+	if err != nil {
+		return err
+	}
+	for i, name := range names {
+		for _, group := range existing {
+			if strings.EqualFold(group, name) {
+				names[i] = group
+				break
+			}
+		}
+	}
+	return s.projectRepoRepo.SetGroups(projectRepo.ID, names)
+}
+
+// ValidateGroup checks that a repository group exists in a project. An empty name stands for all
+// repositories and is valid.
+func (s *RepositoryGroupService) ValidateGroup(projectID, name string) error {
+	if name == "" {
+		return nil
+	}
+	groups, err := s.GetGroups(projectID)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if strings.EqualFold(group, name) {
+			return nil
+		}
+	}
+	return &models.ValidationError{Field: "repository_group", Message: fmt.Sprintf("Repository group %q not found", name)}
+}
+
+// GetFilterOptions retrieves the groups and repositories reports of a project can be filtered by and
+// builds the filter of a group and a project repository ID, either of which may be empty. The filter is
+// left nil when both are empty.
+func (s *RepositoryGroupService) GetFilterOptions(projectID, group, repositoryID string) (*models.RepositoryFilterOptions, error) {
+	projectRepos, err := s.projectRepoRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.GetGroups(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &models.RepositoryFilterOptions{Groups: groups}
+	names := make(map[string]string)
+	for _, projectRepo := range projectRepos {
+		name := projectRepo.GithubRepoID
+		if repo, err := s.githubRepoRepo.GetByID(projectRepo.GithubRepoID); err == nil {
+			name = repo.FullName
+		}
+		names[projectRepo.ID] = name
+		options.Repositories = append(options.Repositories, &models.RepositoryOption{
+			ID:     projectRepo.ID,
+			Name:   name,
+			Groups: projectRepo.Groups,
+		})
+	}
+	sort.Slice(options.Repositories, func(i, j int) bool {
+		return strings.ToLower(options.Repositories[i].Name) < strings.ToLower(options.Repositories[j].Name)
+	})
+
+	if group == "" && repositoryID == "" {
+		return options, nil
+	}
+
+	var label []string
+	if group != "" {
+		if err := s.ValidateGroup(projectID, group); err != nil {
+			return nil, err
+		}
+		for _, name := range groups {
+			if strings.EqualFold(name, group) {
+				group = name
+				break
+			}
+		}
+		label = append(label, group)
+	}
+	if repositoryID != "" {
+		name, ok := names[repositoryID]
+		if !ok {
+			return nil, fmt.Errorf("repository %s not found in project", repositoryID)
+		}
+		label = append(label, name)
+	}
+
+	options.Filter = models.NewRepositoryFilter(group, repositoryID, projectRepos)
+	options.Filter.Label = strings.Join(label, " / ")
+	return options, nil
+}
+
+// parseRepositoryGroups splits a comma or newline separated list of group names, dropping blanks and duplicates
+func parseRepositoryGroups(input string) ([]string, error) {
+	seen := make(map[string]bool)
+	var names []string
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == '\n' }) {
+		name := strings.TrimSpace(field)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		if len(name) > 50 {
+			return nil, &models.ValidationError{Field: "groups", Message: "Group names must be at most 50 characters"}
+		}
+		seen[strings.ToLower(name)] = true
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return strings.ToLower(names[i]) < strings.ToLower(names[j]) })
+	return names, nil
+}
+
+// filterPeopleStatistics keeps the daily people statistics of the repositories a filter includes
+func filterPeopleStatistics(stats []*models.PeopleStatistics, filter *models.RepositoryFilter) []*models.PeopleStatistics {
+	if filter == nil {
+		return stats
+	}
+	var filtered []*models.PeopleStatistics
+	for _, stat := range stats {
+		if filter.IncludesRepository(stat.RepositoryID) {
+			filtered = append(filtered, stat)
+		}
+	}
+	return filtered
+}
+
+// filterByRepository keeps the items of the GitHub repositories a filter includes
+func filterByRepository[T any](items []T, filter *models.RepositoryFilter, repositoryID func(T) string) []T {
+	if filter == nil {
+		return items
+	}
+	var filtered []T
+	for _, item := range items {
+		if filter.IncludesGithubRepository(repositoryID(item)) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
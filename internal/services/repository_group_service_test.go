@@ -0,0 +1,50 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRepositoryGroups(t *testing.T) {
+	names, err := parseRepositoryGroups(" backend, Mobile\nbackend ,, frontend\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"backend", "frontend", "Mobile"}, names)
+
+	names, err = parseRepositoryGroups("  ")
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+
+	_, err = parseRepositoryGroups(strings.Repeat("x", 51))
+	assert.IsType(t, &models.ValidationError{}, err)
+}
+
+func TestRepositoryFilter(t *testing.T) {
+	repos := []*models.ProjectRepository{
+		{ID: "p1", GithubRepoID: "g1", Groups: []string{"Backend"}},
+		{ID: "p2", GithubRepoID: "g2", Groups: []string{"backend", "mobile"}},
+		{ID: "p3", GithubRepoID: "g3"},
+	}
+
+	var none *models.RepositoryFilter
+	assert.True(t, none.IncludesRepository("p3"))
+	assert.True(t, none.IncludesGithubRepository("g3"))
+
+	group := models.NewRepositoryFilter("BACKEND", "", repos)
+	assert.True(t, group.IncludesRepository("p1"))
+	assert.True(t, group.IncludesGithubRepository("g2"))
+	assert.False(t, group.IncludesRepository("p3"))
+
+	single := models.NewRepositoryFilter("mobile", "p1", repos)
+	assert.False(t, single.IncludesGithubRepository("g1"))
+	assert.False(t, single.IncludesGithubRepository("g2"))
+
+	stats := []*models.PeopleStatistics{{RepositoryID: "p1"}, {RepositoryID: "p3"}}
+	assert.Len(t, filterPeopleStatistics(stats, group), 1)
+	assert.Len(t, filterPeopleStatistics(stats, nil), 2)
+
+	runs := []string{"g1", "g2", "g3"}
+	assert.Equal(t, []string{"g1", "g2"}, filterByRepository(runs, group, func(id string) string { return id }))
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+// RepositoryScoreSettingsService manages the per-repository score weighting of a project and queues
+// the recalculation of a repository's statistics whenever its weighting changes
+type RepositoryScoreSettingsService struct {
+	repositoryScoreSettingsRepo *repositories.RepositoryScoreSettingsRepository
+	scoreSettingsRepo           *repositories.ScoreSettingsRepository
+	projectRepoRepo             *repositories.ProjectRepositoryRepository
+	githubRepoRepo              *repositories.GitHubRepositoryRepository
+	jobService                  *JobService
+}
+
+func NewRepositoryScoreSettingsService(
+	repositoryScoreSettingsRepo *repositories.RepositoryScoreSettingsRepository,
+	scoreSettingsRepo *repositories.ScoreSettingsRepository,
+	projectRepoRepo *repositories.ProjectRepositoryRepository,
+	githubRepoRepo *repositories.GitHubRepositoryRepository,
+	jobService *JobService,
+) *RepositoryScoreSettingsService {
+	return &RepositoryScoreSettingsService{
+		repositoryScoreSettingsRepo: repositoryScoreSettingsRepo,
+		scoreSettingsRepo:           scoreSettingsRepo,
+		projectRepoRepo:             projectRepoRepo,
+		githubRepoRepo:              githubRepoRepo,
+		jobService:                  jobService,
+	}
+}
+
+// GetByProjectID retrieves the score settings of the repositories of a project, sorted by repository name
+func (s *RepositoryScoreSettingsService) GetByProjectID(projectID string) ([]*models.RepositoryScoreSettings, error) {
+	settings, err := s.repositoryScoreSettingsRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, setting := range settings {
+		setting.RepositoryName = setting.ProjectRepositoryID
+		projectRepo, err := s.projectRepoRepo.GetByID(setting.ProjectRepositoryID)
+		if err != nil {
+			continue
+		}
+		if repo, err := s.githubRepoRepo.GetByID(projectRepo.GithubRepoID); err == nil {
+			setting.RepositoryName = repo.FullName
+		}
+	}
+	sort.Slice(settings, func(i, j int) bool {
+		return strings.ToLower(settings[i].RepositoryName) < strings.ToLower(settings[j].RepositoryName)
+	})
+
+	return settings, nil
+}
+
+// NewSettings creates repository score settings that start from the weights of the project
+func (s *RepositoryScoreSettingsService) NewSettings(projectID, projectRepositoryID string) *models.RepositoryScoreSettings {
+	projectSettings, err := s.scoreSettingsRepo.GetByProjectID(projectID)
+	if err != nil {
+		projectSettings = models.NewScoreSettings(projectID)
+	}
+	return models.NewRepositoryScoreSettings(projectRepositoryID, projectSettings)
+}
+
+// UpdateSettings validates and stores the score settings of a repository of a project and queues the
+// recalculation of its statistics
+func (s *RepositoryScoreSettingsService) UpdateSettings(projectID string, settings *models.RepositoryScoreSettings) error {
+	if err := s.checkRepository(projectID, settings.ProjectRepositoryID); err != nil {
+		return err
+	}
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.repositoryScoreSettingsRepo.Upsert(settings); err != nil {
+		return err
+	}
+	return s.recalculate(projectID, settings.ProjectRepositoryID)
+}
+
+// ResetSettings drops the score settings of a repository of a project, so that it's scored with the
+// project's weights again, and queues the recalculation of its statistics
+func (s *RepositoryScoreSettingsService) ResetSettings(projectID, projectRepositoryID string) error {
+	if err := s.checkRepository(projectID, projectRepositoryID); err != nil {
+		return err
+	}
+
+	if _, err := s.repositoryScoreSettingsRepo.GetByProjectRepositoryID(projectRepositoryID); err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err := s.repositoryScoreSettingsRepo.DeleteByProjectRepositoryID(projectRepositoryID); err != nil {
+		return err
+	}
+	return s.recalculate(projectID, projectRepositoryID)
+}
+
+// checkRepository checks that a project repository belongs to a project
+func (s *RepositoryScoreSettingsService) checkRepository(projectID, projectRepositoryID string) error {
+	projectRepo, err := s.projectRepoRepo.GetByID(projectRepositoryID)
+	if err != nil || projectRepo.ProjectID != projectID {
+		return &models.ValidationError{Field: "project_repository_id", Message: "Repository not found in project"}
+	}
+	return nil
+}
+
+// recalculate queues a stats job for a project repository, reusing one that is already pending
+func (s *RepositoryScoreSettingsService) recalculate(projectID, projectRepositoryID string) error {
+	_, err := s.jobService.EnqueueRepositoryJobs(projectID, projectRepositoryID, models.JobTypeStats)
+	return err
+}
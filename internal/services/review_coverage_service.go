@@ -0,0 +1,236 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+// ReviewCoverageService reports how much code reaches the default branch without review: pull requests
+// merged without approval or by their own author, and commits that aren't part of any pull request
+type ReviewCoverageService struct {
+	pullRequestRepo *repositories.PullRequestRepository
+	prReviewRepo    *repositories.PRReviewRepository
+	commitRepo      *repositories.CommitRepository
+	githubRepoRepo  *repositories.GitHubRepositoryRepository
+}
+
+func NewReviewCoverageService(
+	pullRequestRepo *repositories.PullRequestRepository,
+	prReviewRepo *repositories.PRReviewRepository,
+	commitRepo *repositories.CommitRepository,
+	githubRepoRepo *repositories.GitHubRepositoryRepository,
+) *ReviewCoverageService {
+	return &ReviewCoverageService{
+		pullRequestRepo: pullRequestRepo,
+		prReviewRepo:    prReviewRepo,
+		commitRepo:      commitRepo,
+		githubRepoRepo:  githubRepoRepo,
+	}
+}
+
+// GetAllTimeReportByProject reports the review coverage of a project over all time
+func (s *ReviewCoverageService) GetAllTimeReportByProject(projectID string, filter *models.RepositoryFilter) (*models.ReviewCoverageReport, error) {
+	return s.getReportByProject(projectID, time.Time{}, time.Time{}, filter)
+}
+
+// GetYearlyReportByProject reports the review coverage of a project for a year
+func (s *ReviewCoverageService) GetYearlyReportByProject(projectID string, year int, filter *models.RepositoryFilter) (*models.ReviewCoverageReport, error) {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(1, 0, 0), filter)
+}
+
+// GetMonthlyReportByProject reports the review coverage of a project for a month
+func (s *ReviewCoverageService) GetMonthlyReportByProject(projectID string, year, month int, filter *models.RepositoryFilter) (*models.ReviewCoverageReport, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(0, 1, 0), filter)
+}
+
+// GetWeeklyReportByProject reports the review coverage of a project for a week numbered like the weekly reports
+func (s *ReviewCoverageService) GetWeeklyReportByProject(projectID string, year, week int, filter *models.RepositoryFilter) (*models.ReviewCoverageReport, error) {
+	start, end := weekRange(year, week)
+	return s.getReportByProject(projectID, start, end, filter)
+}
+
+// GetDailyReportByProject reports the review coverage of a project for a day
+func (s *ReviewCoverageService) GetDailyReportByProject(projectID string, date time.Time, filter *models.RepositoryFilter) (*models.ReviewCoverageReport, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(0, 0, 1), filter)
+}
+
+// getReportByProject reports the review coverage of a project for [start, end), in total and per repository
+func (s *ReviewCoverageService) getReportByProject(projectID string, start, end time.Time, filter *models.RepositoryFilter) (*models.ReviewCoverageReport, error) {
+	pullRequests, err := s.pullRequestRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.prReviewRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	links, err := s.commitRepo.GetPullRequestLinksByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	inPeriod := periodFilter(start, end)
+	byRepository := countReviewCoverage(pullRequests, reviews, links, func(repositoryID string, at time.Time) string {
+		if !inPeriod(&at) || !filter.IncludesGithubRepository(repositoryID) {
+			return ""
+		}
+		return repositoryID
+	})
+
+	report := &models.ReviewCoverageReport{Project: &models.ReviewCoverageStats{Name: "All repositories"}}
+	for repositoryID, stats := range byRepository {
+		stats.RepositoryID = repositoryID
+		stats.Name = repositoryID
+		if repo, err := s.githubRepoRepo.GetByID(repositoryID); err == nil {
+			stats.Name = repo.FullName
+		}
+		addReviewCoverage(report.Project, stats)
+		report.Repositories = append(report.Repositories, stats)
+	}
+	setReviewCoverageRates(report.Project)
+
+	sort.Slice(report.Repositories, func(i, j int) bool {
+		a, b := report.Repositories[i], report.Repositories[j]
+		if a.MergedPullRequests+a.Commits != b.MergedPullRequests+b.Commits {
+			return a.MergedPullRequests+a.Commits > b.MergedPullRequests+b.Commits
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+
+	return report, nil
+}
+
+// GetMonthlyReportByRepository reports the review coverage of a repository per month, newest first, and in total
+func (s *ReviewCoverageService) GetMonthlyReportByRepository(repositoryID string) (*models.ReviewCoverageStats, []*models.ReviewCoverageStats, error) {
+	pullRequests, err := s.pullRequestRepo.GetByRepositoryID(repositoryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	reviews, err := s.prReviewRepo.GetByRepositoryID(repositoryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	links, err := s.commitRepo.GetPullRequestLinksByRepositoryID(repositoryID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byMonth := countReviewCoverage(pullRequests, reviews, links, func(_ string, at time.Time) string {
+		return at.UTC().Format("2006-01")
+	})
+
+	total := &models.ReviewCoverageStats{RepositoryID: repositoryID, Name: "All time"}
+	var months []*models.ReviewCoverageStats
+	for month, stats := range byMonth {
+		stats.Name = month
+		addReviewCoverage(total, stats)
+		months = append(months, stats)
+	}
+	setReviewCoverageRates(total)
+
+	sort.Slice(months, func(i, j int) bool {
+		return months[i].Name > months[j].Name
+	})
+
+	return total, months, nil
+}
+
+// countReviewCoverage counts merged pull requests by the group of their merge time and commits by the
+// group of their commit date. Items whose group is empty are left out.
+func countReviewCoverage(pullRequests []*models.PullRequest, reviews []*models.PRReview, links []*models.CommitPullRequestLink, group func(repositoryID string, at time.Time) string) map[string]*models.ReviewCoverageStats {
+	approvedAt := make(map[string][]time.Time)
+	for _, review := range reviews {
+		if review.State == "APPROVED" && review.SubmittedAt != nil {
+			approvedAt[review.PullRequestID] = append(approvedAt[review.PullRequestID], *review.SubmittedAt)
+		}
+	}
+
+	stats := make(map[string]*models.ReviewCoverageStats)
+	get := func(key string) *models.ReviewCoverageStats {
+		if stats[key] == nil {
+			stats[key] = &models.ReviewCoverageStats{}
+		}
+		return stats[key]
+	}
+
+	for _, pr := range pullRequests {
+		if pr.MergedAt == nil {
+			continue
+		}
+		key := group(pr.RepositoryID, *pr.MergedAt)
+		if key == "" {
+			continue
+		}
+
+		coverage := get(key)
+		coverage.MergedPullRequests++
+		if !approvedBefore(approvedAt[pr.ID], *pr.MergedAt) {
+			coverage.Unapproved++
+		}
+		if pr.MergedByID != nil {
+			coverage.MergerKnown++
+			if pr.AuthorID != nil && *pr.AuthorID == *pr.MergedByID {
+				coverage.SelfMerged++
+			}
+		}
+	}
+
+	for _, link := range links {
+		key := group(link.RepositoryID, link.CommitDate)
+		if key == "" {
+			continue
+		}
+
+		coverage := get(key)
+		coverage.Commits++
+		if !link.Linked {
+			coverage.DirectCommits++
+		}
+	}
+
+	for _, coverage := range stats {
+		setReviewCoverageRates(coverage)
+	}
+
+	return stats
+}
+
+// approvedBefore tells whether any of the approvals was submitted at or before the merge
+func approvedBefore(approvals []time.Time, mergedAt time.Time) bool {
+	for _, approvedAt := range approvals {
+		if !approvedAt.After(mergedAt) {
+			return true
+		}
+	}
+	return false
+}
+
+func addReviewCoverage(total, stats *models.ReviewCoverageStats) {
+	total.MergedPullRequests += stats.MergedPullRequests
+	total.Unapproved += stats.Unapproved
+	total.MergerKnown += stats.MergerKnown
+	total.SelfMerged += stats.SelfMerged
+	total.Commits += stats.Commits
+	total.DirectCommits += stats.DirectCommits
+}
+
+// setReviewCoverageRates sets the rates of the stats, leaving out the ones without anything to count
+func setReviewCoverageRates(stats *models.ReviewCoverageStats) {
+	rate := func(count, total int) *float64 {
+		if total == 0 {
+			return nil
+		}
+		value := float64(count) / float64(total)
+		return &value
+	}
+	stats.UnapprovedRate = rate(stats.Unapproved, stats.MergedPullRequests)
+	stats.SelfMergedRate = rate(stats.SelfMerged, stats.MergerKnown)
+	stats.DirectCommitRate = rate(stats.DirectCommits, stats.Commits)
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountReviewCoverage(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2025, 8, d, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+	alice, bob := "alice", "bob"
+
+	pullRequests := []*models.PullRequest{
+		// Approved before the merge, merged by someone else
+		{ID: "pr1", RepositoryID: "api", AuthorID: &alice, MergedByID: &bob, MergedAt: day(3)},
+		// Approved only after the merge, merged by its author
+		{ID: "pr2", RepositoryID: "api", AuthorID: &alice, MergedByID: &alice, MergedAt: day(4)},
+		// Merger unknown
+		{ID: "pr3", RepositoryID: "web", AuthorID: &bob, MergedAt: day(5)},
+		// Not merged
+		{ID: "pr4", RepositoryID: "web", AuthorID: &bob},
+		// Outside the period
+		{ID: "pr5", RepositoryID: "web", AuthorID: &bob, MergedByID: &bob, MergedAt: day(20)},
+	}
+	reviews := []*models.PRReview{
+		{PullRequestID: "pr1", State: "COMMENTED", SubmittedAt: day(1)},
+		{PullRequestID: "pr1", State: "APPROVED", SubmittedAt: day(2)},
+		{PullRequestID: "pr2", State: "APPROVED", SubmittedAt: day(5)},
+		{PullRequestID: "pr3", State: "DISMISSED", SubmittedAt: day(4)},
+	}
+	links := []*models.CommitPullRequestLink{
+		{RepositoryID: "api", CommitDate: *day(3), Linked: true},
+		{RepositoryID: "api", CommitDate: *day(4), Linked: false},
+		{RepositoryID: "web", CommitDate: *day(5), Linked: true},
+		{RepositoryID: "web", CommitDate: *day(21), Linked: false},
+	}
+
+	inPeriod := periodFilter(*day(1), *day(15))
+	stats := countReviewCoverage(pullRequests, reviews, links, func(repositoryID string, at time.Time) string {
+		if !inPeriod(&at) {
+			return ""
+		}
+		return repositoryID
+	})
+
+	if assert.Contains(t, stats, "api") {
+		api := stats["api"]
+		assert.Equal(t, 2, api.MergedPullRequests)
+		assert.Equal(t, 1, api.Unapproved)
+		assert.Equal(t, 2, api.MergerKnown)
+		assert.Equal(t, 1, api.SelfMerged)
+		assert.Equal(t, 2, api.Commits)
+		assert.Equal(t, 1, api.DirectCommits)
+		assert.InDelta(t, 0.5, *api.UnapprovedRate, 0.0001)
+		assert.InDelta(t, 0.5, *api.SelfMergedRate, 0.0001)
+		assert.InDelta(t, 0.5, *api.DirectCommitRate, 0.0001)
+	}
+	if assert.Contains(t, stats, "web") {
+		web := stats["web"]
+		assert.Equal(t, 1, web.MergedPullRequests)
+		assert.Equal(t, 1, web.Unapproved)
+		assert.Equal(t, 0, web.MergerKnown)
+		assert.Nil(t, web.SelfMergedRate)
+		assert.Equal(t, 1, web.Commits)
+		assert.Equal(t, 0, web.DirectCommits)
+	}
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+// ReviewerReportService breaks reviews down by outcome and reports how the review load is spread across people
+type ReviewerReportService struct {
+	pullRequestRepo     *repositories.PullRequestRepository
+	prReviewRepo        *repositories.PRReviewRepository
+	prReviewRequestRepo *repositories.PRReviewRequestRepository
+	githubPersonRepo    *repositories.GithubPersonRepository
+	botService          *BotService
+}
+
+func NewReviewerReportService(
+	pullRequestRepo *repositories.PullRequestRepository,
+	prReviewRepo *repositories.PRReviewRepository,
+	prReviewRequestRepo *repositories.PRReviewRequestRepository,
+	githubPersonRepo *repositories.GithubPersonRepository,
+	botService *BotService,
+) *ReviewerReportService {
+	return &ReviewerReportService{
+		pullRequestRepo:     pullRequestRepo,
+		prReviewRepo:        prReviewRepo,
+		prReviewRequestRepo: prReviewRequestRepo,
+		githubPersonRepo:    githubPersonRepo,
+		botService:          botService,
+	}
+}
+
+// GetAllTimeReportByProject reports the reviewers of all pull requests of a project
+func (s *ReviewerReportService) GetAllTimeReportByProject(projectID string, filter *models.RepositoryFilter) (*models.ReviewerReport, error) {
+	return s.getReportByProject(projectID, time.Time{}, time.Time{}, filter)
+}
+
+// GetYearlyReportByProject reports the reviewers of a project for a year
+func (s *ReviewerReportService) GetYearlyReportByProject(projectID string, year int, filter *models.RepositoryFilter) (*models.ReviewerReport, error) {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(1, 0, 0), filter)
+}
+
+// GetMonthlyReportByProject reports the reviewers of a project for a month
+func (s *ReviewerReportService) GetMonthlyReportByProject(projectID string, year, month int, filter *models.RepositoryFilter) (*models.ReviewerReport, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(0, 1, 0), filter)
+}
+
+// GetWeeklyReportByProject reports the reviewers of a project for a week numbered like the weekly reports
+func (s *ReviewerReportService) GetWeeklyReportByProject(projectID string, year, week int, filter *models.RepositoryFilter) (*models.ReviewerReport, error) {
+	start, end := weekRange(year, week)
+	return s.getReportByProject(projectID, start, end, filter)
+}
+
+// GetDailyReportByProject reports the reviewers of a project for a day
+func (s *ReviewerReportService) GetDailyReportByProject(projectID string, date time.Time, filter *models.RepositoryFilter) (*models.ReviewerReport, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return s.getReportByProject(projectID, start, start.AddDate(0, 0, 1), filter)
+}
+
+// getReportByProject loads the pull requests, reviews and review requests of a project and reports them for [start, end)
+func (s *ReviewerReportService) getReportByProject(projectID string, start, end time.Time, filter *models.RepositoryFilter) (*models.ReviewerReport, error) {
+	pullRequests, err := s.pullRequestRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.prReviewRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := s.prReviewRequestRepo.GetRequestedReviewersByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	projectPeople, err := s.githubPersonRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	botSettings, err := s.botService.GetSettings(projectID)
+	if err != nil {
+		return nil, err
+	}
+	pullRequests = filterByRepository(pullRequests, filter, func(pr *models.PullRequest) string { return pr.RepositoryID })
+	reviews = filterByRepository(reviews, filter, func(r *models.PRReview) string { return r.RepositoryID })
+	requests = filterByRepository(requests, filter, func(r *models.PRRequestedReviewer) string { return r.RepositoryID })
+
+	people := newReviewerPeople(projectPeople)
+	// Reviewers and requested reviewers are normally linked to the project, look up the ones that aren't
+	for _, review := range reviews {
+		if _, ok := people.byGithubUserID[review.ReviewerID]; !ok {
+			person, err := s.githubPersonRepo.GetByGithubUserID(review.ReviewerID)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			people.add(person, review.ReviewerID)
+		}
+	}
+	for _, request := range requests {
+		if _, ok := people.byID[request.GithubPersonID]; !ok {
+			person, err := s.githubPersonRepo.GetByID(request.GithubPersonID)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			people.add(person, 0)
+		}
+	}
+
+	return buildReviewerReport(pullRequests, reviews, requests, people, botSettings, periodFilter(start, end)), nil
+}
+
+// reviewerPeople indexes GitHub people by ID and by GitHub user ID. Unknown people are stored as nil.
+// Members are the people of the project, who share the review load whether they reviewed or not.
+type reviewerPeople struct {
+	byID           map[string]*models.GithubPerson
+	byGithubUserID map[int]*models.GithubPerson
+	members        []*models.GithubPerson
+}
+
+func newReviewerPeople(people []*models.GithubPerson) *reviewerPeople {
+	index := &reviewerPeople{
+		byID:           make(map[string]*models.GithubPerson),
+		byGithubUserID: make(map[int]*models.GithubPerson),
+		members:        people,
+	}
+	for _, person := range people {
+		index.add(person, person.GithubUserID)
+	}
+	return index
+}
+
+func (p *reviewerPeople) add(person *models.GithubPerson, githubUserID int) {
+	if githubUserID != 0 {
+		p.byGithubUserID[githubUserID] = person
+	}
+	if person != nil {
+		p.byID[person.ID] = person
+		p.byGithubUserID[person.GithubUserID] = person
+	}
+}
+
+// periodFilter returns whether a time falls in [start, end). Zero bounds include everything.
+func periodFilter(start, end time.Time) func(*time.Time) bool {
+	return func(t *time.Time) bool {
+		if t == nil {
+			return false
+		}
+		if start.IsZero() && end.IsZero() {
+			return true
+		}
+		return !t.Before(start) && t.Before(end)
+	}
+}
+
+// buildReviewerReport counts the reviews submitted in the period by outcome, and the review requests on
+// pull requests opened in the period. Pending reviews and reviews of one's own pull requests don't count.
+// A request is completed by a review at any time; open requests are the ones still waiting on open pull
+// requests, whatever the period.
+func buildReviewerReport(pullRequests []*models.PullRequest, reviews []*models.PRReview, requests []*models.PRRequestedReviewer, people *reviewerPeople, bots *models.BotSettings, inPeriod func(*time.Time) bool) *models.ReviewerReport {
+	prByID := make(map[string]*models.PullRequest, len(pullRequests))
+	for _, pr := range pullRequests {
+		prByID[pr.ID] = pr
+	}
+
+	stats := make(map[string]*models.ReviewerStats)
+	isBot := make(map[string]bool)
+	get := func(key string, person *models.GithubPerson, login string) *models.ReviewerStats {
+		if stats[key] == nil {
+			stats[key] = &models.ReviewerStats{Username: login}
+			if person != nil {
+				stats[key].GithubPersonID = person.ID
+				stats[key].Username = person.Username
+				isBot[key] = bots.IsBotPerson(person)
+			} else {
+				isBot[key] = bots.IsBotPerson(&models.GithubPerson{Username: login})
+			}
+		}
+		return stats[key]
+	}
+	isAuthor := func(pr *models.PullRequest, person *models.GithubPerson) bool {
+		return person != nil && pr.AuthorID != nil && *pr.AuthorID == person.ID
+	}
+
+	reviewed := make(map[string]map[string]bool)
+	reviewedInPeriod := make(map[string]map[string]bool)
+	for _, review := range reviews {
+		pr := prByID[review.PullRequestID]
+		person := people.byGithubUserID[review.ReviewerID]
+		if pr == nil || review.State == "PENDING" || isAuthor(pr, person) {
+			continue
+		}
+
+		key := reviewerKey(person, review.ReviewerLogin)
+		if reviewed[key] == nil {
+			reviewed[key] = make(map[string]bool)
+		}
+		reviewed[key][pr.ID] = true
+		if !inPeriod(review.SubmittedAt) {
+			continue
+		}
+
+		reviewer := get(key, person, review.ReviewerLogin)
+		reviewer.Reviews++
+		switch review.State {
+		case "APPROVED":
+			reviewer.Approved++
+		case "CHANGES_REQUESTED":
+			reviewer.ChangesRequested++
+		case "COMMENTED":
+			reviewer.Commented++
+		case "DISMISSED":
+			reviewer.Dismissed++
+		}
+		if reviewedInPeriod[key] == nil {
+			reviewedInPeriod[key] = make(map[string]bool)
+		}
+		reviewedInPeriod[key][pr.ID] = true
+	}
+
+	for _, request := range requests {
+		pr := prByID[request.PullRequestID]
+		person := people.byID[request.GithubPersonID]
+		if pr == nil || person == nil || isAuthor(pr, person) {
+			continue
+		}
+
+		key := reviewerKey(person, "")
+		done := reviewed[key][pr.ID]
+		if inPeriod(pr.GithubCreatedAt) {
+			reviewer := get(key, person, "")
+			reviewer.Requested++
+			if done {
+				reviewer.RequestedCompleted++
+			}
+		}
+		if pr.State == "open" && !done {
+			get(key, person, "").OpenRequests++
+		}
+	}
+
+	report := &models.ReviewerReport{}
+	var load []float64
+	for key, reviewer := range stats {
+		reviewer.PullRequests = len(reviewedInPeriod[key])
+		report.PullRequests += reviewer.PullRequests
+		if reviewer.Requested > 0 {
+			rate := float64(reviewer.RequestedCompleted) / float64(reviewer.Requested)
+			reviewer.CompletionRate = &rate
+		}
+		// Bots don't share the review load, whether they reviewed or not
+		if (reviewer.Reviews > 0 || reviewer.Requested > 0) && !isBot[key] {
+			load = append(load, float64(reviewer.PullRequests))
+		}
+		report.Reviewers = append(report.Reviewers, reviewer)
+	}
+	// Members who reviewed nothing and weren't asked to carry none of the load
+	for _, member := range people.members {
+		reviewer := stats[reviewerKey(member, "")]
+		if (reviewer == nil || (reviewer.Reviews == 0 && reviewer.Requested == 0)) && !bots.IsBotPerson(member) {
+			load = append(load, 0)
+		}
+	}
+	for _, reviewer := range report.Reviewers {
+		if report.PullRequests > 0 {
+			reviewer.Share = float64(reviewer.PullRequests) / float64(report.PullRequests)
+		}
+	}
+	report.Gini = gini(load)
+
+	sort.Slice(report.Reviewers, func(i, j int) bool {
+		a, b := report.Reviewers[i], report.Reviewers[j]
+		if a.PullRequests != b.PullRequests {
+			return a.PullRequests > b.PullRequests
+		}
+		if a.Requested != b.Requested {
+			return a.Requested > b.Requested
+		}
+		return strings.ToLower(a.Username) < strings.ToLower(b.Username)
+	})
+
+	return report
+}
+
+// reviewerKey identifies a reviewer by person ID, falling back to the login for people that aren't stored
+func reviewerKey(person *models.GithubPerson, login string) string {
+	if person != nil {
+		return person.ID
+	}
+	return "login:" + strings.ToLower(login)
+}
+
+// gini returns the Gini coefficient of the values, or nil when there are fewer than two values or all are zero
+func gini(values []float64) *float64 {
+	if len(values) < 2 {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	var sum, weighted float64
+	for i, value := range sorted {
+		sum += value
+		weighted += float64(i+1) * value
+	}
+	if sum == 0 {
+		return nil
+	}
+
+	n := float64(len(sorted))
+	g := 2*weighted/(n*sum) - (n+1)/n
+	return &g
+}
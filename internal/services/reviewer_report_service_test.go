@@ -0,0 +1,160 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGini(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []float64
+		expected *float64
+	}{
+		{
+			name:   "Single reviewer",
+			values: []float64{5},
+		},
+		{
+			name:   "No reviews",
+			values: []float64{0, 0, 0},
+		},
+		{
+			name:     "Even load",
+			values:   []float64{4, 4, 4, 4},
+			expected: func() *float64 { v := 0.0; return &v }(),
+		},
+		{
+			name:     "One reviewer does everything",
+			values:   []float64{0, 0, 0, 12},
+			expected: func() *float64 { v := 0.75; return &v }(),
+		},
+		{
+			name:     "Uneven load",
+			values:   []float64{1, 2, 3},
+			expected: func() *float64 { v := 2.0 / 9; return &v }(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := gini(tc.values)
+			if tc.expected == nil {
+				assert.Nil(t, g)
+				return
+			}
+			if assert.NotNil(t, g) {
+				assert.InDelta(t, *tc.expected, *g, 0.0001)
+			}
+		})
+	}
+}
+
+func TestBuildReviewerReport(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2025, 8, d, 12, 0, 0, 0, time.UTC)
+		return &t
+	}
+	alice := &models.GithubPerson{ID: "alice", GithubUserID: 1, Username: "alice"}
+	bob := &models.GithubPerson{ID: "bob", GithubUserID: 2, Username: "bob"}
+	carol := &models.GithubPerson{ID: "carol", GithubUserID: 3, Username: "carol"}
+	people := newReviewerPeople([]*models.GithubPerson{alice, bob, carol})
+
+	pullRequests := []*models.PullRequest{
+		{ID: "pr1", AuthorID: &alice.ID, State: "closed", GithubCreatedAt: day(4)},
+		{ID: "pr2", AuthorID: &alice.ID, State: "open", GithubCreatedAt: day(5)},
+		{ID: "pr3", AuthorID: &bob.ID, State: "open", GithubCreatedAt: day(20)},
+	}
+	reviews := []*models.PRReview{
+		{PullRequestID: "pr1", ReviewerID: 2, ReviewerLogin: "bob", State: "CHANGES_REQUESTED", SubmittedAt: day(4)},
+		{PullRequestID: "pr1", ReviewerID: 2, ReviewerLogin: "bob", State: "APPROVED", SubmittedAt: day(5)},
+		{PullRequestID: "pr2", ReviewerID: 1, ReviewerLogin: "alice", State: "COMMENTED", SubmittedAt: day(5)},
+		{PullRequestID: "pr2", ReviewerID: 3, ReviewerLogin: "carol", State: "PENDING", SubmittedAt: day(6)},
+		{PullRequestID: "pr3", ReviewerID: 3, ReviewerLogin: "carol", State: "DISMISSED", SubmittedAt: day(21)},
+		{PullRequestID: "pr2", ReviewerID: 9, ReviewerLogin: "ghost", State: "COMMENTED", SubmittedAt: day(6)},
+	}
+	requests := []*models.PRRequestedReviewer{
+		{PullRequestID: "pr1", GithubPersonID: "bob"},
+		{PullRequestID: "pr2", GithubPersonID: "bob"},
+		{PullRequestID: "pr2", GithubPersonID: "carol"},
+		{PullRequestID: "pr3", GithubPersonID: "carol"},
+	}
+
+	// The first week of August leaves out pr3 and the review on it
+	report := buildReviewerReport(pullRequests, reviews, requests, people, &models.BotSettings{}, periodFilter(*day(1), *day(8)))
+
+	byName := make(map[string]*models.ReviewerStats)
+	for _, reviewer := range report.Reviewers {
+		byName[reviewer.Username] = reviewer
+	}
+	assert.Len(t, report.Reviewers, 3)
+	assert.Equal(t, 2, report.PullRequests)
+
+	assert.Equal(t, "bob", report.Reviewers[0].Username)
+	assert.Equal(t, 1, byName["bob"].Approved)
+	assert.Equal(t, 1, byName["bob"].ChangesRequested)
+	assert.Equal(t, 2, byName["bob"].Reviews)
+	assert.Equal(t, 1, byName["bob"].PullRequests)
+	assert.Equal(t, 2, byName["bob"].Requested)
+	assert.Equal(t, 1, byName["bob"].RequestedCompleted)
+	assert.InDelta(t, 0.5, *byName["bob"].CompletionRate, 0.0001)
+	assert.Equal(t, 1, byName["bob"].OpenRequests)
+	assert.InDelta(t, 0.5, byName["bob"].Share, 0.0001)
+
+	assert.Equal(t, "", byName["ghost"].GithubPersonID)
+	assert.Equal(t, 1, byName["ghost"].Commented)
+
+	// Carol was asked for pr2 in the period and her pending review leaves the request open
+	assert.Equal(t, 0, byName["carol"].Reviews)
+	assert.Equal(t, 1, byName["carol"].Requested)
+	assert.Equal(t, 1, byName["carol"].OpenRequests)
+	assert.Nil(t, byName["alice"])
+
+	// Alice only commented on her own pull request, so she counts as reviewing none
+	if assert.NotNil(t, report.Gini) {
+		assert.InDelta(t, 0.5, *report.Gini, 0.0001)
+	}
+}
+
+func TestReviewerLoadCountsIdleMembers(t *testing.T) {
+	at := time.Date(2025, 8, 4, 12, 0, 0, 0, time.UTC)
+	botType := "Bot"
+	alice := &models.GithubPerson{ID: "alice", GithubUserID: 1, Username: "alice"}
+	bob := &models.GithubPerson{ID: "bob", GithubUserID: 2, Username: "bob"}
+	idle := &models.GithubPerson{ID: "idle", GithubUserID: 3, Username: "idle"}
+	bot := &models.GithubPerson{ID: "bot", GithubUserID: 4, Username: "renovate", Type: &botType}
+
+	pullRequests := []*models.PullRequest{
+		{ID: "pr1", AuthorID: &alice.ID, State: "closed", GithubCreatedAt: &at},
+		{ID: "pr2", AuthorID: &alice.ID, State: "closed", GithubCreatedAt: &at},
+	}
+	reviews := []*models.PRReview{
+		{PullRequestID: "pr1", ReviewerID: 2, ReviewerLogin: "bob", State: "APPROVED", SubmittedAt: &at},
+		{PullRequestID: "pr2", ReviewerID: 2, ReviewerLogin: "bob", State: "APPROVED", SubmittedAt: &at},
+		{PullRequestID: "pr1", ReviewerID: 4, ReviewerLogin: "renovate", State: "COMMENTED", SubmittedAt: &at},
+		{PullRequestID: "pr2", ReviewerID: 4, ReviewerLogin: "renovate", State: "COMMENTED", SubmittedAt: &at},
+	}
+
+	// Bob reviews everything, alice and the idle member review nothing; the bot doesn't count even
+	// though it reviewed
+	people := newReviewerPeople([]*models.GithubPerson{alice, bob, idle, bot})
+	report := buildReviewerReport(pullRequests, reviews, nil, people, &models.BotSettings{}, periodFilter(time.Time{}, time.Time{}))
+
+	assert.Len(t, report.Reviewers, 2)
+	if assert.NotNil(t, report.Gini) {
+		assert.InDelta(t, 2.0/3, *report.Gini, 0.0001)
+	}
+
+	// Bot patterns of the project apply as well
+	report = buildReviewerReport(pullRequests, reviews, nil, people, &models.BotSettings{Patterns: "idle"}, periodFilter(time.Time{}, time.Time{}))
+	if assert.NotNil(t, report.Gini) {
+		assert.InDelta(t, 0.5, *report.Gini, 0.0001)
+	}
+
+	// With only the reviewer in the project there is no spread to measure
+	report = buildReviewerReport(pullRequests, reviews[:2], nil, newReviewerPeople([]*models.GithubPerson{bob}), &models.BotSettings{}, periodFilter(time.Time{}, time.Time{}))
+	assert.Nil(t, report.Gini)
+}
@@ -12,17 +12,35 @@ type SchedulerService struct {
 	projectUpdateSettingsRepo *repositories.ProjectUpdateSettingsRepository
 	jobRepo                   *repositories.JobRepository
 	githubRepoService         *GitHubRepositoryService
+	discoveryService          *RepositoryDiscoveryService
+	githubClientPool          *GitHubClientPool
+	teamService               *TeamService
+	githubCacheRepo           *repositories.GitHubHTTPCacheRepository
+	githubCacheRetention      time.Duration
+	projectRepo               *repositories.ProjectRepository
 }
 
 func NewSchedulerService(
 	projectUpdateSettingsRepo *repositories.ProjectUpdateSettingsRepository,
 	jobRepo *repositories.JobRepository,
 	githubRepoService *GitHubRepositoryService,
+	discoveryService *RepositoryDiscoveryService,
+	githubClientPool *GitHubClientPool,
+	teamService *TeamService,
+	githubCacheRepo *repositories.GitHubHTTPCacheRepository,
+	githubCacheRetentionDays int,
+	projectRepo *repositories.ProjectRepository,
 ) *SchedulerService {
 	return &SchedulerService{
 		projectUpdateSettingsRepo: projectUpdateSettingsRepo,
 		jobRepo:                   jobRepo,
 		githubRepoService:         githubRepoService,
+		discoveryService:          discoveryService,
+		githubClientPool:          githubClientPool,
+		teamService:               teamService,
+		githubCacheRepo:           githubCacheRepo,
+		githubCacheRetention:      time.Duration(githubCacheRetentionDays) * 24 * time.Hour,
+		projectRepo:               projectRepo,
 	}
 }
 
@@ -45,12 +63,15 @@ func (s *SchedulerService) StartScheduler() {
 			for _, setting := range settings {
 				if setting.Hour == currentHour {
 					log.Printf("Scheduling automatic update for project %s at hour %d", setting.ProjectID, setting.Hour)
-					if err := s.scheduleProjectUpdate(setting.ProjectID); err != nil {
+					if err := s.scheduleProjectUpdate(setting.ProjectID, setting.RepositoryGroup); err != nil {
 						log.Printf("Error scheduling update for project %s: %v", setting.ProjectID, err)
 					}
 				}
 			}
 
+			// Drop cached GitHub responses nobody revalidated within the retention period
+			s.pruneGitHubCache(now)
+
 			// Sleep until the next hour
 			nextHour := now.Add(1 * time.Hour)
 			nextHour = time.Date(nextHour.Year(), nextHour.Month(), nextHour.Day(), nextHour.Hour(), 0, 0, 0, nextHour.Location())
@@ -60,20 +81,61 @@ func (s *SchedulerService) StartScheduler() {
 	}()
 }
 
-// scheduleProjectUpdate schedules the "Update All" jobs for a project
-func (s *SchedulerService) scheduleProjectUpdate(projectID string) error {
+// pruneGitHubCache deletes the cached GitHub responses that haven't been revalidated within the
+// retention period, so that responses of URLs no longer fetched don't pile up
+func (s *SchedulerService) pruneGitHubCache(now time.Time) {
+	if s.githubCacheRetention <= 0 {
+		return
+	}
+	deleted, err := s.githubCacheRepo.DeleteOlderThan(now.Add(-s.githubCacheRetention))
+	if err != nil {
+		log.Printf("Error pruning GitHub response cache: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Pruned %d cached GitHub responses", deleted)
+	}
+}
+
+// scheduleProjectUpdate schedules the "Update All" jobs for a project, limited to the repositories of a
+// repository group when it is set
+func (s *SchedulerService) scheduleProjectUpdate(projectID string, repositoryGroup *string) error {
+	token, err := s.githubClientPool.ProjectToken(projectID)
+	if err != nil {
+		return err
+	}
+
+	// Pick up repositories added to the project's organizations or teams since the last run
+	if err := s.discoverRepositories(projectID, token); err != nil {
+		log.Printf("Error discovering repositories for project %s: %v", projectID, err)
+	}
+
+	// Keep the organization teams and their members current for team reports
+	if synced, err := s.teamService.SyncProjectTeams(projectID, token); err != nil {
+		log.Printf("Error syncing teams for project %s: %v", projectID, err)
+	} else if synced > 0 {
+		log.Printf("Synced %d teams for project %s", synced, projectID)
+	}
+
 	// Get all tracked repositories for this project
 	repositories, err := s.githubRepoService.GetProjectRepositories(projectID)
 	if err != nil {
 		return err
 	}
 
-	// Filter to only tracked repositories
+	// Filter to only tracked repositories of the scheduled group that still accept new jobs
 	var trackedRepos []*models.ProjectRepository
 	for _, repo := range repositories {
-		if repo.IsTracked {
-			trackedRepos = append(trackedRepos, repo)
+		if !repo.IsTracked {
+			continue
+		}
+		if repositoryGroup != nil && !repo.InGroup(*repositoryGroup) {
+			continue
+		}
+		if !s.isRepositorySchedulable(repo, token) {
+			continue
 		}
+		trackedRepos = append(trackedRepos, repo)
 	}
 
 	if len(trackedRepos) == 0 {
@@ -81,49 +143,75 @@ func (s *SchedulerService) scheduleProjectUpdate(projectID string) error {
 		return nil
 	}
 
-	// Create jobs in the correct order with dependencies
-	// Each repository gets its own chain: clone -> commit -> pull_request -> stats
+	project, err := s.projectRepo.GetByID(projectID)
+	if err != nil {
+		return err
+	}
 
-	// Step 1: Create clone jobs for all tracked repositories
+	// Each repository gets its own chain: clone -> commit -> pull_request -> issue -> ci -> deployment -> stats,
+	// leaving out the jobs the project doesn't sync
+	jobTypes := project.SyncedJobTypes(
+		models.JobTypeClone, models.JobTypeCommit, models.JobTypePullRequest,
+		models.JobTypeIssue, models.JobTypeCI, models.JobTypeDeployment, models.JobTypeStats,
+	)
 	for _, repo := range trackedRepos {
-		cloneJob := models.NewJob(projectID, models.JobTypeClone)
-		cloneJob.ProjectRepositoryID = &repo.ID
-		if err := s.jobRepo.Create(cloneJob); err != nil {
-			log.Printf("Failed to create clone job for repository %s: %v", repo.ID, err)
-			continue
-		}
-		log.Printf("Created automatic clone job %s for repository %s", cloneJob.ID, repo.ID)
-
-		// Step 2: Create commit job that depends on this clone job
-		commitJob := models.NewJob(projectID, models.JobTypeCommit)
-		commitJob.ProjectRepositoryID = &repo.ID
-		commitJob.DependsOn = &cloneJob.ID
-		if err := s.jobRepo.Create(commitJob); err != nil {
-			log.Printf("Failed to create commit job for repository %s: %v", repo.ID, err)
-			continue
-		}
-		log.Printf("Created automatic commit job %s for repository %s (depends on %s)", commitJob.ID, repo.ID, cloneJob.ID)
-
-		// Step 3: Create pull request job that depends on this commit job
-		pullRequestJob := models.NewJob(projectID, models.JobTypePullRequest)
-		pullRequestJob.ProjectRepositoryID = &repo.ID
-		pullRequestJob.DependsOn = &commitJob.ID
-		if err := s.jobRepo.Create(pullRequestJob); err != nil {
-			log.Printf("Failed to create pull request job for repository %s: %v", repo.ID, err)
-			continue
-		}
-		log.Printf("Created automatic pull request job %s for repository %s (depends on %s)", pullRequestJob.ID, repo.ID, commitJob.ID)
-
-		// Step 4: Create stats job that depends on this pull request job
-		statsJob := models.NewJob(projectID, models.JobTypeStats)
-		statsJob.ProjectRepositoryID = &repo.ID
-		statsJob.DependsOn = &pullRequestJob.ID
-		if err := s.jobRepo.Create(statsJob); err != nil {
-			log.Printf("Failed to create stats job for repository %s: %v", repo.ID, err)
-			continue
+		var dependsOn *string
+		for _, jobType := range jobTypes {
+			job := models.NewJob(projectID, jobType)
+			job.ProjectRepositoryID = &repo.ID
+			job.DependsOn = dependsOn
+			if err := s.jobRepo.Create(job); err != nil {
+				log.Printf("Failed to create %s job for repository %s: %v", jobType, repo.ID, err)
+				break
+			}
+			if dependsOn != nil {
+				log.Printf("Created automatic %s job %s for repository %s (depends on %s)", jobType, job.ID, repo.ID, *dependsOn)
+			} else {
+				log.Printf("Created automatic %s job %s for repository %s", jobType, job.ID, repo.ID)
+			}
+			dependsOn = &job.ID
 		}
-		log.Printf("Created automatic stats job %s for repository %s (depends on %s)", statsJob.ID, repo.ID, pullRequestJob.ID)
 	}
 
 	return nil
 }
+
+// discoverRepositories runs organization/team discovery for a project
+func (s *SchedulerService) discoverRepositories(projectID, token string) error {
+	hasSources, err := s.discoveryService.HasSources(projectID)
+	if err != nil || !hasSources {
+		return err
+	}
+
+	attached, err := s.discoveryService.DiscoverProjectRepositories(projectID, token)
+	if err != nil {
+		return err
+	}
+
+	if attached > 0 {
+		log.Printf("Discovered %d new repositories for project %s", attached, projectID)
+	}
+	return nil
+}
+
+// isRepositorySchedulable reports whether a repository is still active, refreshing its
+// state from GitHub once a day. Renames are applied by the refresh; archived, deleted
+// and inaccessible repositories are skipped but keep their history.
+func (s *SchedulerService) isRepositorySchedulable(projectRepo *models.ProjectRepository, token string) bool {
+	githubRepo, err := s.githubRepoService.GetGitHubRepository(projectRepo.GithubRepoID)
+	if err != nil {
+		log.Printf("Failed to get GitHub repository for %s: %v", projectRepo.ID, err)
+		return false
+	}
+
+	if err := s.githubRepoService.RefreshRepositoryStatusIfDue(githubRepo, token); err != nil {
+		// Keep scheduling on transient errors; the jobs report their own failures
+		log.Printf("Failed to refresh status of repository %s: %v", githubRepo.FullName, err)
+	}
+
+	if !githubRepo.IsActive() {
+		log.Printf("Skipping repository %s: archived, deleted or inaccessible on GitHub", githubRepo.FullName)
+		return false
+	}
+	return true
+}
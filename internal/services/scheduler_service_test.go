@@ -0,0 +1,73 @@
+package services
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneGitHubCache(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	migration, err := os.ReadFile("../../migrations/026_create_github_http_cache.sql")
+	require.NoError(t, err)
+	_, err = db.Exec(string(migration))
+	require.NoError(t, err)
+
+	cacheRepo := repositories.NewGitHubHTTPCacheRepository(db)
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	store := func(key string, validated time.Time) {
+		require.NoError(t, cacheRepo.Upsert(&models.GitHubHTTPCacheEntry{
+			CacheKey: key, URL: "https://api.github.com/" + key, CreatedAt: validated, UpdatedAt: validated,
+		}))
+	}
+	store("stale", now.AddDate(0, 0, -31))
+	store("fresh", now.AddDate(0, 0, -29))
+	// Stored in another time zone, a day within the retention period
+	store("local", now.AddDate(0, 0, -1).In(time.FixedZone("UTC+14", 14*60*60)))
+
+	scheduler := NewSchedulerService(nil, nil, nil, nil, nil, nil, cacheRepo, 30, nil)
+	scheduler.pruneGitHubCache(now)
+
+	_, err = cacheRepo.Get("stale")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	for _, key := range []string{"fresh", "local"} {
+		_, err = cacheRepo.Get(key)
+		assert.NoError(t, err, key)
+	}
+
+	// Pruning is off without a retention period
+	store("stale", now.AddDate(-1, 0, 0))
+	NewSchedulerService(nil, nil, nil, nil, nil, nil, cacheRepo, 0, nil).pruneGitHubCache(now)
+	_, err = cacheRepo.Get("stale")
+	assert.NoError(t, err)
+}
+
+func TestSyncedJobTypes(t *testing.T) {
+	chain := []models.JobType{
+		models.JobTypeClone, models.JobTypeCommit, models.JobTypePullRequest,
+		models.JobTypeIssue, models.JobTypeCI, models.JobTypeDeployment, models.JobTypeStats,
+	}
+
+	project := &models.Project{SyncIssues: true, SyncCI: true, SyncDeployments: true}
+	assert.Equal(t, chain, project.SyncedJobTypes(chain...))
+
+	// Scheduled updates leave out what the project doesn't sync
+	project = &models.Project{SyncIssues: true}
+	assert.Equal(t, []models.JobType{
+		models.JobTypeClone, models.JobTypeCommit, models.JobTypePullRequest, models.JobTypeIssue, models.JobTypeStats,
+	}, project.SyncedJobTypes(chain...))
+	assert.False(t, project.SyncsJobType(models.JobTypeCI))
+	assert.False(t, project.SyncsJobType(models.JobTypeDeployment))
+	assert.True(t, project.SyncsJobType(models.JobTypeClone))
+}
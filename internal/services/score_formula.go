@@ -0,0 +1,388 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/alimgiray/gscope/internal/models"
+)
+
+// Score formulas are arithmetic expressions over the daily activity variables of models.ScoreFormulaVariables:
+// numbers, variables, + - * / and ^, parentheses and calls of the functions below. There are no loops or
+// assignments, and the size of formulas is bounded, so evaluating one is always cheap.
+const (
+	maxScoreFormulaLength = 1000
+	maxScoreFormulaDepth  = 32
+	maxScoreFormulaNodes  = 200
+	// maxFormulaScore bounds the score of one daily row so that runaway formulas can't overflow totals
+	maxFormulaScore = 1e9
+)
+
+// scoreFormulaFunction is a function score formulas can call
+type scoreFormulaFunction struct {
+	minArgs, maxArgs int // maxArgs is -1 for any number of arguments
+	call             func(args []float64) float64
+}
+
+var scoreFormulaFunctions = map[string]scoreFormulaFunction{
+	"log": {1, 1, func(args []float64) float64 {
+		if args[0] <= 0 {
+			return 0
+		}
+		return math.Log(args[0])
+	}},
+	"sqrt": {1, 1, func(args []float64) float64 {
+		if args[0] < 0 {
+			return 0
+		}
+		return math.Sqrt(args[0])
+	}},
+	"min": {2, -1, func(args []float64) float64 {
+		result := args[0]
+		for _, arg := range args[1:] {
+			result = math.Min(result, arg)
+		}
+		return result
+	}},
+	"max": {2, -1, func(args []float64) float64 {
+		result := args[0]
+		for _, arg := range args[1:] {
+			result = math.Max(result, arg)
+		}
+		return result
+	}},
+	"cap":   {2, 2, func(args []float64) float64 { return math.Min(args[0], args[1]) }},
+	"abs":   {1, 1, func(args []float64) float64 { return math.Abs(args[0]) }},
+	"round": {1, 1, func(args []float64) float64 { return math.Round(args[0]) }},
+	"floor": {1, 1, func(args []float64) float64 { return math.Floor(args[0]) }},
+	"ceil":  {1, 1, func(args []float64) float64 { return math.Ceil(args[0]) }},
+}
+
+// scoreFormula is a parsed score formula
+type scoreFormula struct {
+	root formulaNode
+}
+
+// Evaluate computes the score of a set of variables, rounded to an integer. Undefined results, such as
+// a division by zero, score 0.
+func (f *scoreFormula) Evaluate(variables map[string]float64) int {
+	value := f.root.eval(variables)
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0
+	}
+	return int(math.Round(math.Max(-maxFormulaScore, math.Min(maxFormulaScore, value))))
+}
+
+type formulaNode interface {
+	eval(variables map[string]float64) float64
+}
+
+type numberNode float64
+
+func (n numberNode) eval(map[string]float64) float64 { return float64(n) }
+
+type variableNode string
+
+func (n variableNode) eval(variables map[string]float64) float64 { return variables[string(n)] }
+
+type negateNode struct{ operand formulaNode }
+
+func (n negateNode) eval(variables map[string]float64) float64 { return -n.operand.eval(variables) }
+
+type binaryNode struct {
+	op          byte
+	left, right formulaNode
+}
+
+func (n binaryNode) eval(variables map[string]float64) float64 {
+	left, right := n.left.eval(variables), n.right.eval(variables)
+	switch n.op {
+	case '+':
+		return left + right
+	case '-':
+		return left - right
+	case '*':
+		return left * right
+	case '/':
+		if right == 0 {
+			return 0
+		}
+		return left / right
+	default: // '^'
+		return math.Pow(left, right)
+	}
+}
+
+type callNode struct {
+	function scoreFormulaFunction
+	args     []formulaNode
+}
+
+func (n callNode) eval(variables map[string]float64) float64 {
+	args := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		args[i] = arg.eval(variables)
+	}
+	return n.function.call(args)
+}
+
+// parseScoreFormula parses and validates a score formula, returning a ValidationError that points at
+// the position of the first problem
+func parseScoreFormula(expression string) (*scoreFormula, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, formulaError(0, "formula is empty")
+	}
+	if len(expression) > maxScoreFormulaLength {
+		return nil, formulaError(0, fmt.Sprintf("formula is longer than %d characters", maxScoreFormulaLength))
+	}
+
+	tokens, err := tokenizeScoreFormula(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &formulaParser{tokens: tokens}
+	root, err := p.parseExpression(0)
+	if err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token.kind != tokenEnd {
+		return nil, formulaError(token.pos, fmt.Sprintf("unexpected %q", token.text))
+	}
+	return &scoreFormula{root: root}, nil
+}
+
+func formulaError(pos int, message string) error {
+	return &models.ValidationError{Field: "expression", Message: fmt.Sprintf("Invalid formula at position %d: %s", pos+1, message)}
+}
+
+type formulaTokenKind int
+
+const (
+	tokenEnd formulaTokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator // + - * / ^ ( ) ,
+)
+
+type formulaToken struct {
+	kind formulaTokenKind
+	text string
+	pos  int
+}
+
+func tokenizeScoreFormula(expression string) ([]formulaToken, error) {
+	var tokens []formulaToken
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.IndexByte("+-*/^(),", c) >= 0:
+			tokens = append(tokens, formulaToken{tokenOperator, string(c), i})
+			i++
+		case c >= '0' && c <= '9' || c == '.':
+			start := i
+			for i < len(expression) && (expression[i] >= '0' && expression[i] <= '9' || expression[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, formulaToken{tokenNumber, expression[start:i], start})
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := i
+			for i < len(expression) && (expression[i] == '_' || expression[i] >= 'a' && expression[i] <= 'z' ||
+				expression[i] >= 'A' && expression[i] <= 'Z' || expression[i] >= '0' && expression[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, formulaToken{tokenIdent, strings.ToLower(expression[start:i]), start})
+		default:
+			return nil, formulaError(i, fmt.Sprintf("unexpected character %q", c))
+		}
+	}
+	return append(tokens, formulaToken{kind: tokenEnd, text: "end of formula", pos: len(expression)}), nil
+}
+
+type formulaParser struct {
+	tokens []formulaToken
+	next   int
+	nodes  int
+}
+
+func (p *formulaParser) peek() formulaToken {
+	return p.tokens[p.next]
+}
+
+func (p *formulaParser) take() formulaToken {
+	token := p.tokens[p.next]
+	if token.kind != tokenEnd {
+		p.next++
+	}
+	return token
+}
+
+func (p *formulaParser) isOperator(text string) bool {
+	token := p.peek()
+	return token.kind == tokenOperator && token.text == text
+}
+
+func (p *formulaParser) node(pos int) error {
+	p.nodes++
+	if p.nodes > maxScoreFormulaNodes {
+		return formulaError(pos, "formula is too complex")
+	}
+	return nil
+}
+
+// parseExpression parses a sum of terms
+func (p *formulaParser) parseExpression(depth int) (formulaNode, error) {
+	if depth > maxScoreFormulaDepth {
+		return nil, formulaError(p.peek().pos, "formula is nested too deeply")
+	}
+	left, err := p.parseTerm(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+") || p.isOperator("-") {
+		op := p.take()
+		right, err := p.parseTerm(depth)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.node(op.pos); err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op.text[0], left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseTerm parses a product of factors
+func (p *formulaParser) parseTerm(depth int) (formulaNode, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*") || p.isOperator("/") {
+		op := p.take()
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.node(op.pos); err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op.text[0], left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary parses a negation or a power
+func (p *formulaParser) parseUnary(depth int) (formulaNode, error) {
+	if p.isOperator("-") {
+		op := p.take()
+		if depth > maxScoreFormulaDepth {
+			return nil, formulaError(op.pos, "formula is nested too deeply")
+		}
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.node(op.pos); err != nil {
+			return nil, err
+		}
+		return negateNode{operand: operand}, nil
+	}
+
+	base, err := p.parsePrimary(depth)
+	if err != nil {
+		return nil, err
+	}
+	if p.isOperator("^") {
+		op := p.take()
+		// Powers are right associative: 2^3^2 is 2^(3^2)
+		exponent, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.node(op.pos); err != nil {
+			return nil, err
+		}
+		return binaryNode{op: '^', left: base, right: exponent}, nil
+	}
+	return base, nil
+}
+
+// parsePrimary parses a number, a variable, a function call or a parenthesized expression
+func (p *formulaParser) parsePrimary(depth int) (formulaNode, error) {
+	token := p.take()
+	if err := p.node(token.pos); err != nil {
+		return nil, err
+	}
+
+	switch token.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, formulaError(token.pos, fmt.Sprintf("invalid number %q", token.text))
+		}
+		return numberNode(value), nil
+
+	case tokenIdent:
+		if !p.isOperator("(") {
+			if !isScoreFormulaVariable(token.text) {
+				return nil, formulaError(token.pos, fmt.Sprintf("unknown variable %q", token.text))
+			}
+			return variableNode(token.text), nil
+		}
+
+		function, ok := scoreFormulaFunctions[token.text]
+		if !ok {
+			return nil, formulaError(token.pos, fmt.Sprintf("unknown function %q", token.text))
+		}
+		p.take()
+		var args []formulaNode
+		if !p.isOperator(")") {
+			for {
+				arg, err := p.parseExpression(depth + 1)
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+				if !p.isOperator(",") {
+					break
+				}
+				p.take()
+			}
+		}
+		if next := p.take(); next.kind != tokenOperator || next.text != ")" {
+			return nil, formulaError(next.pos, fmt.Sprintf("expected \")\" instead of %q", next.text))
+		}
+		if len(args) < function.minArgs || function.maxArgs >= 0 && len(args) > function.maxArgs {
+			return nil, formulaError(token.pos, fmt.Sprintf("wrong number of arguments for %s", token.text))
+		}
+		return callNode{function: function, args: args}, nil
+
+	case tokenOperator:
+		if token.text == "(" {
+			inner, err := p.parseExpression(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if next := p.take(); next.kind != tokenOperator || next.text != ")" {
+				return nil, formulaError(next.pos, fmt.Sprintf("expected \")\" instead of %q", next.text))
+			}
+			return inner, nil
+		}
+	}
+
+	return nil, formulaError(token.pos, fmt.Sprintf("unexpected %q", token.text))
+}
+
+func isScoreFormulaVariable(name string) bool {
+	for _, variable := range models.ScoreFormulaVariables {
+		if variable.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+const (
+	// DefaultScoreFormulaPreviewDays is how far back formula previews look by default
+	DefaultScoreFormulaPreviewDays = 90
+	maxScoreFormulaPreviewDays     = 365
+)
+
+// ScoreFormulaService manages the versions of the score formula of a project, previews formulas against
+// past activity and records changes of the active formula as score settings versions, which recalculate
+// the statistics
+type ScoreFormulaService struct {
+	scoreFormulaRepo     *repositories.ScoreFormulaRepository
+	peopleStatsService   *PeopleStatisticsService
+	scoreSettingsService *ScoreSettingsService
+}
+
+func NewScoreFormulaService(
+	scoreFormulaRepo *repositories.ScoreFormulaRepository,
+	peopleStatsService *PeopleStatisticsService,
+	scoreSettingsService *ScoreSettingsService,
+) *ScoreFormulaService {
+	return &ScoreFormulaService{
+		scoreFormulaRepo:     scoreFormulaRepo,
+		peopleStatsService:   peopleStatsService,
+		scoreSettingsService: scoreSettingsService,
+	}
+}
+
+// GetFormulas retrieves the versions of the score formula of a project, newest first
+func (s *ScoreFormulaService) GetFormulas(projectID string) ([]*models.ScoreFormula, error) {
+	return s.scoreFormulaRepo.GetByProjectID(projectID)
+}
+
+// GetActiveFormula retrieves the active score formula of a project, nil when it's scored with weights
+func (s *ScoreFormulaService) GetActiveFormula(projectID string) (*models.ScoreFormula, error) {
+	formula, err := s.scoreFormulaRepo.GetActiveByProjectID(projectID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return formula, err
+}
+
+// ValidateFormula checks that an expression is a valid score formula
+func (s *ScoreFormulaService) ValidateFormula(expression string) error {
+	_, err := parseScoreFormula(expression)
+	return err
+}
+
+// PreviewFormula compares the scores of the people of a project over the last days with their scores
+// under a formula
+func (s *ScoreFormulaService) PreviewFormula(projectID, expression string, days int) ([]*models.ScoreFormulaPreview, error) {
+	formula, err := parseScoreFormula(expression)
+	if err != nil {
+		return nil, err
+	}
+	if days <= 0 || days > maxScoreFormulaPreviewDays {
+		return nil, &models.ValidationError{Field: "days", Message: "Preview period must be between 1 and 365 days"}
+	}
+	return s.peopleStatsService.previewScoreFormula(projectID, formula, days)
+}
+
+// SaveFormula stores a formula as the next version of the score formula of a project. Activating it
+// scores the days from effectiveFrom on with it, or all of history when effectiveFrom is nil, and queues
+// the recalculation of the project's statistics.
+func (s *ScoreFormulaService) SaveFormula(projectID, userID, expression, note string, activate bool, effectiveFrom *time.Time) (*models.ScoreFormula, error) {
+	expression = strings.TrimSpace(expression)
+	if err := s.ValidateFormula(expression); err != nil {
+		return nil, err
+	}
+	if err := validateEffectiveFrom(effectiveFrom); err != nil {
+		return nil, err
+	}
+
+	formula := models.NewScoreFormula(projectID, expression, strings.TrimSpace(note), &userID)
+	formula.IsActive = activate
+	if err := s.scoreFormulaRepo.Create(formula); err != nil {
+		return nil, err
+	}
+
+	if activate {
+		if _, err := s.scoreSettingsService.SetScoreFormula(projectID, &formula.ID, effectiveFrom, userID); err != nil {
+			return nil, err
+		}
+	}
+	return formula, nil
+}
+
+// ActivateFormula makes a version the active score formula of a project, or goes back to scoring with
+// weights when formulaID is empty, for the days from effectiveFrom on or all of history when it is nil,
+// and queues the recalculation of the project's statistics
+func (s *ScoreFormulaService) ActivateFormula(projectID, formulaID, userID string, effectiveFrom *time.Time) error {
+	if err := validateEffectiveFrom(effectiveFrom); err != nil {
+		return err
+	}
+
+	if err := s.scoreFormulaRepo.SetActive(projectID, formulaID); err != nil {
+		if err == sql.ErrNoRows {
+			return &models.ValidationError{Field: "formula_id", Message: "Score formula version not found"}
+		}
+		return err
+	}
+
+	var scoreFormulaID *string
+	if formulaID != "" {
+		scoreFormulaID = &formulaID
+	}
+	_, err := s.scoreSettingsService.SetScoreFormula(projectID, scoreFormulaID, effectiveFrom, userID)
+	return err
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreFormula(t *testing.T) {
+	variables := map[string]float64{
+		"commits":   4,
+		"additions": 250,
+		"deletions": 50,
+		"reviews":   3,
+	}
+
+	testCases := []struct {
+		expression string
+		expected   int
+	}{
+		{"commits * 10 + reviews * 5", 55},
+		{"(commits + reviews) * 2", 14},
+		{"-commits + 10", 6},
+		{"2 ^ 3 ^ 2", 512},
+		{"additions / deletions", 5},
+		{"additions / pull_requests", 0},
+		{"cap(additions, 100) + min(commits, reviews) + max(1, 2, 3)", 106},
+		{"round(sqrt(additions + deletions))", 17},
+		{"log(0) + sqrt(-1)", 0},
+		{"floor(2.7) + ceil(2.2) + abs(-1)", 6},
+		{"COMMITS * Max(1, 2)", 8},
+		{"1000000000000 * commits", int(maxFormulaScore)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expression, func(t *testing.T) {
+			formula, err := parseScoreFormula(tc.expression)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expected, formula.Evaluate(variables))
+		})
+	}
+}
+
+func TestParseScoreFormulaErrors(t *testing.T) {
+	testCases := []struct {
+		expression string
+		message    string
+	}{
+		{"", "formula is empty"},
+		{"commits +", "position 10"},
+		{"commits * lines", "unknown variable \"lines\""},
+		{"median(commits)", "unknown function \"median\""},
+		{"cap(commits)", "wrong number of arguments for cap"},
+		{"(commits + 1", "expected \")\""},
+		{"commits $ 2", "unexpected character '$'"},
+		{"commits 2", "unexpected \"2\""},
+		{strings.Repeat("(", 40) + "1" + strings.Repeat(")", 40), "nested"},
+		{strings.Repeat("commits+", 150) + "1", "longer than"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.message, func(t *testing.T) {
+			_, err := parseScoreFormula(tc.expression)
+			assert.Error(t, err)
+
+			var validationErr *models.ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "expression", validationErr.Field)
+			assert.Contains(t, err.Error(), tc.message)
+		})
+	}
+}
+
+func TestCountCoAuthoredCommits(t *testing.T) {
+	day := time.Date(2025, 8, 20, 12, 0, 0, 0, time.UTC)
+	alice, bob := "alice@example.com", "bob@example.com"
+
+	commits := []*models.Commit{
+		{AuthorEmail: &bob, CommitDate: day, Message: "Add API\n\nCo-authored-by: Alice <Alice@Example.com>"},
+		{AuthorEmail: &bob, CommitDate: day, Message: "Fix API\n\nco-authored-by: Carol <carol@example.com>"},
+		// Own commit
+		{AuthorEmail: &alice, CommitDate: day, Message: "Tests\n\nCo-authored-by: Alice <alice@example.com>"},
+		// Revert
+		{AuthorEmail: &bob, CommitDate: day, Message: "Revert \"Add API\"\n\nCo-authored-by: Alice <alice@example.com>"},
+		// Another day
+		{AuthorEmail: &bob, CommitDate: day.AddDate(0, 0, 1), Message: "Docs\n\nCo-authored-by: Alice <alice@example.com>"},
+	}
+
+	assert.Equal(t, []string{"alice@example.com"}, coAuthorEmails(commits[0].Message))
+	assert.Equal(t, 1, countCoAuthoredCommits(commits, []string{alice}, day))
+	assert.Equal(t, 0, countCoAuthoredCommits(commits, []string{bob}, day))
+}
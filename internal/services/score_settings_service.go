@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"time"
 
 	"github.com/alimgiray/gscope/internal/models"
 	"github.com/alimgiray/gscope/internal/repositories"
@@ -10,11 +11,13 @@ import (
 
 type ScoreSettingsService struct {
 	scoreSettingsRepo *repositories.ScoreSettingsRepository
+	jobService        *JobService
 }
 
-func NewScoreSettingsService(scoreSettingsRepo *repositories.ScoreSettingsRepository) *ScoreSettingsService {
+func NewScoreSettingsService(scoreSettingsRepo *repositories.ScoreSettingsRepository, jobService *JobService) *ScoreSettingsService {
 	return &ScoreSettingsService{
 		scoreSettingsRepo: scoreSettingsRepo,
+		jobService:        jobService,
 	}
 }
 
@@ -30,7 +33,12 @@ func (s *ScoreSettingsService) CreateScoreSettings(projectID string) error {
 	}
 
 	settings := models.NewScoreSettings(projectID)
-	return s.scoreSettingsRepo.Create(settings)
+	if err := s.scoreSettingsRepo.Create(settings); err != nil {
+		return err
+	}
+
+	// The defaults are the first version, covering all of history
+	return s.scoreSettingsRepo.CreateVersion(models.NewScoreSettingsVersion(settings, nil, nil))
 }
 
 // GetScoreSettingsByProjectID retrieves score settings for a project
@@ -47,24 +55,124 @@ func (s *ScoreSettingsService) GetScoreSettingsByProjectID(projectID string) (*m
 	return s.scoreSettingsRepo.GetByProjectID(projectID)
 }
 
-// UpdateScoreSettings updates score settings for a project
-func (s *ScoreSettingsService) UpdateScoreSettings(settings *models.ScoreSettings) error {
+// UpdateScoreSettings saves new score settings for a project as its next version, changed by a user.
+// The version scores the days from effectiveFrom on, keeping the scores of earlier days, or all of history
+// when effectiveFrom is nil. The statistics are recalculated by a stats job, which the version records.
+// While a score formula is active the weights don't score any day: they are kept, in effect over the
+// same days as the formula, for when it is deactivated, and nothing is recalculated.
+// Saving the weights of the latest version again does nothing and returns nil.
+func (s *ScoreSettingsService) UpdateScoreSettings(settings *models.ScoreSettings, effectiveFrom *time.Time, changedBy string) (*models.ScoreSettingsVersion, error) {
 	if settings.ProjectID == "" {
-		return errors.New("project ID is required")
+		return nil, errors.New("project ID is required")
 	}
 
 	// Validate UUID format
 	if _, err := uuid.Parse(settings.ProjectID); err != nil {
-		return errors.New("invalid project ID format")
+		return nil, errors.New("invalid project ID format")
 	}
 
 	// Validate score values (should be positive)
 	if settings.Additions < 0 || settings.Deletions < 0 || settings.Commits < 0 ||
-		settings.PullRequests < 0 || settings.Comments < 0 {
-		return errors.New("score values must be non-negative")
+		settings.PullRequests < 0 || settings.Comments < 0 ||
+		settings.ReviewComments < 0 || settings.IssueComments < 0 ||
+		settings.IssuesOpened < 0 || settings.IssuesClosed < 0 {
+		return nil, &models.ValidationError{Field: "scores", Message: "score values must be non-negative"}
+	}
+
+	if err := validateEffectiveFrom(effectiveFrom); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.scoreSettingsRepo.GetVersionsByProjectID(settings.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	version := models.NewScoreSettingsVersion(settings, effectiveFrom, &changedBy)
+	if len(versions) > 0 {
+		latest := versions[len(versions)-1]
+		if len(version.ChangesFrom(latest)) == 0 {
+			return nil, nil
+		}
+		if latest.HasFormula() {
+			version.EffectiveFrom = latest.EffectiveFrom
+			version.ScoreFormulaID = latest.ScoreFormulaID
+			if err := s.scoreSettingsRepo.CreateVersion(version); err != nil {
+				return nil, err
+			}
+			return version, nil
+		}
+	}
+
+	if err := s.createVersion(version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// SetScoreFormula adds a version of the score settings of a project, changed by a user, that scores the
+// days from effectiveFrom on with a score formula, or with the current weights again when formulaID is
+// nil. Like a change of the weights, earlier days keep their scores unless effectiveFrom is nil, and the
+// statistics are recalculated by a stats job.
+func (s *ScoreSettingsService) SetScoreFormula(projectID string, formulaID *string, effectiveFrom *time.Time, changedBy string) (*models.ScoreSettingsVersion, error) {
+	if err := validateEffectiveFrom(effectiveFrom); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.GetScoreSettingsByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	version := models.NewScoreSettingsVersion(settings, effectiveFrom, &changedBy)
+	version.ScoreFormulaID = formulaID
+	if err := s.createVersion(version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// validateEffectiveFrom checks the effective date of a new version. Scores of future days don't exist
+// yet, so a version can't start after today.
+func validateEffectiveFrom(effectiveFrom *time.Time) error {
+	if effectiveFrom != nil && effectiveFrom.After(time.Now()) {
+		return &models.ValidationError{Field: "effective_from", Message: "Effective date can't be in the future"}
+	}
+	return nil
+}
+
+// createVersion stores a version and queues the stats job that recalculates the project's statistics
+// with it, which the version records
+func (s *ScoreSettingsService) createVersion(version *models.ScoreSettingsVersion) error {
+	if err := s.scoreSettingsRepo.CreateVersion(version); err != nil {
+		return err
 	}
 
-	return s.scoreSettingsRepo.Update(settings)
+	job, err := s.jobService.EnqueueProjectStatsJob(version.ProjectID)
+	if err != nil {
+		return err
+	}
+	if err := s.scoreSettingsRepo.SetVersionRecalculationJob(version.ID, job.ID); err != nil {
+		return err
+	}
+	version.RecalculationJobID = &job.ID
+	return nil
+}
+
+// GetScoreSettingsVersions retrieves the versions of the score settings of a project, newest first, with
+// the weights each one changed
+func (s *ScoreSettingsService) GetScoreSettingsVersions(projectID string) ([]*models.ScoreSettingsVersion, error) {
+	versions, err := s.scoreSettingsRepo.GetVersionsByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(versions); i++ {
+		versions[i].Changes = versions[i].ChangesFrom(versions[i-1])
+	}
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	return versions, nil
 }
 
 // DeleteScoreSettings deletes score settings for a project
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"database/sql"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+)
+
+// StalePullRequestService reports open pull requests that are aged, waiting on review past the SLA,
+// idle drafts or blocked on change requests
+type StalePullRequestService struct {
+	stalePRSettingsRepo *repositories.StalePRSettingsRepository
+	pullRequestRepo     *repositories.PullRequestRepository
+	prReviewRepo        *repositories.PRReviewRepository
+	prReviewRequestRepo *repositories.PRReviewRequestRepository
+	prDraftEventRepo    *repositories.PRDraftEventRepository
+	githubPersonRepo    *repositories.GithubPersonRepository
+	githubRepoRepo      *repositories.GitHubRepositoryRepository
+}
+
+func NewStalePullRequestService(
+	stalePRSettingsRepo *repositories.StalePRSettingsRepository,
+	pullRequestRepo *repositories.PullRequestRepository,
+	prReviewRepo *repositories.PRReviewRepository,
+	prReviewRequestRepo *repositories.PRReviewRequestRepository,
+	prDraftEventRepo *repositories.PRDraftEventRepository,
+	githubPersonRepo *repositories.GithubPersonRepository,
+	githubRepoRepo *repositories.GitHubRepositoryRepository,
+) *StalePullRequestService {
+	return &StalePullRequestService{
+		stalePRSettingsRepo: stalePRSettingsRepo,
+		pullRequestRepo:     pullRequestRepo,
+		prReviewRepo:        prReviewRepo,
+		prReviewRequestRepo: prReviewRequestRepo,
+		prDraftEventRepo:    prDraftEventRepo,
+		githubPersonRepo:    githubPersonRepo,
+		githubRepoRepo:      githubRepoRepo,
+	}
+}
+
+// GetSettings retrieves the stale pull request settings of a project, falling back to the defaults
+func (s *StalePullRequestService) GetSettings(projectID string) (*models.StalePRSettings, error) {
+	settings, err := s.stalePRSettingsRepo.GetByProjectID(projectID)
+	if err == sql.ErrNoRows {
+		return models.NewStalePRSettings(projectID), nil
+	}
+	return settings, err
+}
+
+// UpdateSettings validates and stores the stale pull request settings of a project
+func (s *StalePullRequestService) UpdateSettings(projectID string, maxAgeDays, reviewSLAHours, draftIdleDays int) (*models.StalePRSettings, error) {
+	settings := models.NewStalePRSettings(projectID)
+	settings.MaxAgeDays = maxAgeDays
+	settings.ReviewSLAHours = reviewSLAHours
+	settings.DraftIdleDays = draftIdleDays
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.stalePRSettingsRepo.Upsert(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// GetReportByProject lists the open pull requests of a project past one of its stale thresholds
+func (s *StalePullRequestService) GetReportByProject(projectID string) (*models.StalePRReport, error) {
+	settings, err := s.GetSettings(projectID)
+	if err != nil {
+		return nil, err
+	}
+	pullRequests, err := s.pullRequestRepo.GetOpenByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.prReviewRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := s.prReviewRequestRepo.GetRequestedReviewersByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.prDraftEventRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	projectPeople, err := s.githubPersonRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	people := newReviewerPeople(projectPeople)
+	for _, review := range reviews {
+		if _, ok := people.byGithubUserID[review.ReviewerID]; !ok {
+			person, err := s.githubPersonRepo.GetByGithubUserID(review.ReviewerID)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			people.add(person, review.ReviewerID)
+		}
+	}
+	for _, pr := range pullRequests {
+		if pr.AuthorID == nil {
+			continue
+		}
+		if _, ok := people.byID[*pr.AuthorID]; !ok {
+			person, err := s.githubPersonRepo.GetByID(*pr.AuthorID)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			people.add(person, 0)
+		}
+	}
+	for _, request := range requests {
+		if _, ok := people.byID[request.GithubPersonID]; !ok {
+			person, err := s.githubPersonRepo.GetByID(request.GithubPersonID)
+			if err != nil && err != sql.ErrNoRows {
+				return nil, err
+			}
+			people.add(person, 0)
+		}
+	}
+
+	now := time.Now()
+	report := &models.StalePRReport{
+		Settings:         settings,
+		GeneratedAt:      now,
+		OpenPullRequests: len(pullRequests),
+		PullRequests:     findStalePullRequests(pullRequests, reviews, requests, events, people, settings, now),
+	}
+
+	repos := make(map[string]*models.GitHubRepository)
+	for _, stale := range report.PullRequests {
+		repositoryID := stale.Repository
+		if _, ok := repos[repositoryID]; !ok {
+			repo, err := s.githubRepoRepo.GetByID(repositoryID)
+			if err != nil {
+				repo = nil
+			}
+			repos[repositoryID] = repo
+		}
+		if repo := repos[repositoryID]; repo != nil {
+			stale.Repository = repo.FullName
+			stale.URL = strings.TrimSuffix(repo.URL, "/") + "/pull/" + strconv.Itoa(stale.Number)
+		}
+	}
+
+	return report, nil
+}
+
+// findStalePullRequests checks open pull requests against the stale thresholds. The repository of the
+// entries is left as the repository ID. Reviews by the author and pending reviews don't count; the
+// latest approval or change request of a reviewer decides whether they still request changes.
+func findStalePullRequests(pullRequests []*models.PullRequest, reviews []*models.PRReview, requests []*models.PRRequestedReviewer, events []*models.PRDraftEvent, people *reviewerPeople, settings *models.StalePRSettings, now time.Time) []*models.StalePullRequest {
+	reviewsByPR := make(map[string][]*models.PRReview)
+	for _, review := range reviews {
+		reviewsByPR[review.PullRequestID] = append(reviewsByPR[review.PullRequestID], review)
+	}
+	requestsByPR := make(map[string][]*models.PRRequestedReviewer)
+	for _, request := range requests {
+		requestsByPR[request.PullRequestID] = append(requestsByPR[request.PullRequestID], request)
+	}
+	eventsByPR := make(map[string][]*models.PRDraftEvent)
+	for _, event := range events {
+		eventsByPR[event.PullRequestID] = append(eventsByPR[event.PullRequestID], event)
+	}
+
+	maxAge := time.Duration(settings.MaxAgeDays) * 24 * time.Hour
+	reviewSLA := time.Duration(settings.ReviewSLAHours) * time.Hour
+	draftIdle := time.Duration(settings.DraftIdleDays) * 24 * time.Hour
+
+	var stale []*models.StalePullRequest
+	for _, pr := range pullRequests {
+		if pr.State != "open" || pr.GithubCreatedAt == nil {
+			continue
+		}
+
+		var author *models.GithubPerson
+		if pr.AuthorID != nil {
+			author = people.byID[*pr.AuthorID]
+		}
+		entry := &models.StalePullRequest{
+			PullRequestID: pr.ID,
+			Repository:    pr.RepositoryID,
+			Number:        pr.GithubPRNumber,
+			Title:         pr.Title,
+			Draft:         pr.Draft,
+			OpenedAt:      *pr.GithubCreatedAt,
+			AgeSeconds:    int64(now.Sub(*pr.GithubCreatedAt) / time.Second),
+		}
+		if author != nil {
+			entry.Owner = author.Username
+		}
+		if pr.GithubUpdatedAt != nil {
+			entry.IdleSeconds = int64(now.Sub(*pr.GithubUpdatedAt) / time.Second)
+		}
+
+		sortedReviews := reviewsByPR[pr.ID]
+		sort.SliceStable(sortedReviews, func(i, j int) bool {
+			return reviewTime(sortedReviews[i]).Before(reviewTime(sortedReviews[j]))
+		})
+		reviewed := make(map[string]bool)
+		decision := make(map[string]string)
+		logins := make(map[string]string)
+		approved := false
+		for _, review := range sortedReviews {
+			reviewer := people.byGithubUserID[review.ReviewerID]
+			if review.State == "PENDING" || review.SubmittedAt == nil || (reviewer != nil && author != nil && reviewer.ID == author.ID) {
+				continue
+			}
+			key := reviewerKey(reviewer, review.ReviewerLogin)
+			reviewed[key] = true
+			logins[key] = review.ReviewerLogin
+			switch review.State {
+			case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+				decision[key] = review.State
+			}
+		}
+		for key, state := range decision {
+			switch state {
+			case "CHANGES_REQUESTED":
+				entry.ChangesRequestedBy = append(entry.ChangesRequestedBy, logins[key])
+			case "APPROVED":
+				approved = true
+			}
+		}
+		sort.Strings(entry.ChangesRequestedBy)
+		entry.ChangesRequested = len(entry.ChangesRequestedBy) > 0
+
+		drafts := draftSpans(entry.OpenedAt, pr.Draft, eventsByPR[pr.ID], now)
+		if len(reviewed) == 0 && !pr.Draft {
+			entry.WaitingForReviewSeconds = activeSeconds(entry.OpenedAt, now, drafts)
+			entry.ReviewOverdue = time.Duration(*entry.WaitingForReviewSeconds)*time.Second > reviewSLA
+		}
+		entry.Aged = now.Sub(entry.OpenedAt) > maxAge
+		entry.IdleDraft = pr.Draft && pr.GithubUpdatedAt != nil && now.Sub(*pr.GithubUpdatedAt) > draftIdle
+
+		waitingOnOwner := func() {
+			if entry.Owner != "" {
+				entry.WaitingOn = []string{entry.Owner}
+			}
+		}
+		switch {
+		case pr.Draft, entry.ChangesRequested:
+			waitingOnOwner()
+		default:
+			for _, request := range requestsByPR[pr.ID] {
+				person := people.byID[request.GithubPersonID]
+				if person == nil || reviewed[person.ID] || (author != nil && person.ID == author.ID) {
+					continue
+				}
+				entry.WaitingOn = append(entry.WaitingOn, person.Username)
+			}
+			sort.Strings(entry.WaitingOn)
+			if len(entry.WaitingOn) == 0 && approved {
+				waitingOnOwner()
+			}
+		}
+
+		if entry.Aged || entry.ReviewOverdue || entry.IdleDraft || entry.ChangesRequested {
+			stale = append(stale, entry)
+		}
+	}
+
+	sort.SliceStable(stale, func(i, j int) bool {
+		return stale[i].AgeSeconds > stale[j].AgeSeconds
+	})
+
+	return stale
+}
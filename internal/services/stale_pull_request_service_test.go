@@ -0,0 +1,88 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindStalePullRequests(t *testing.T) {
+	now := time.Date(2025, 8, 30, 12, 0, 0, 0, time.UTC)
+	ago := func(hours int) *time.Time {
+		t := now.Add(-time.Duration(hours) * time.Hour)
+		return &t
+	}
+	alice := &models.GithubPerson{ID: "alice", GithubUserID: 1, Username: "alice"}
+	bob := &models.GithubPerson{ID: "bob", GithubUserID: 2, Username: "bob"}
+	carol := &models.GithubPerson{ID: "carol", GithubUserID: 3, Username: "carol"}
+	people := newReviewerPeople([]*models.GithubPerson{alice, bob, carol})
+	settings := &models.StalePRSettings{MaxAgeDays: 14, ReviewSLAHours: 24, DraftIdleDays: 7}
+
+	pullRequests := []*models.PullRequest{
+		// Fresh and reviewed, not stale
+		{ID: "fresh", State: "open", AuthorID: &alice.ID, GithubCreatedAt: ago(10), GithubUpdatedAt: ago(1)},
+		// Waiting on its requested reviewers past the SLA, the author's own review doesn't count
+		{ID: "waiting", State: "open", AuthorID: &alice.ID, GithubCreatedAt: ago(48), GithubUpdatedAt: ago(2)},
+		// Spent most of its life as a draft, so still within the SLA
+		{ID: "was-draft", State: "open", AuthorID: &alice.ID, GithubCreatedAt: ago(100), GithubUpdatedAt: ago(2)},
+		// Idle draft
+		{ID: "draft", State: "open", Draft: true, AuthorID: &bob.ID, GithubCreatedAt: ago(24 * 10), GithubUpdatedAt: ago(24 * 8)},
+		// Old, changes requested by carol who hasn't approved since, bob approved
+		{ID: "blocked", State: "open", AuthorID: &bob.ID, GithubCreatedAt: ago(24 * 20), GithubUpdatedAt: ago(5)},
+		// Old and approved, waiting on its author to merge
+		{ID: "approved", State: "open", AuthorID: &carol.ID, GithubCreatedAt: ago(24 * 15), GithubUpdatedAt: ago(5)},
+	}
+	reviews := []*models.PRReview{
+		{PullRequestID: "fresh", ReviewerID: 2, ReviewerLogin: "bob", State: "COMMENTED", SubmittedAt: ago(5)},
+		{PullRequestID: "waiting", ReviewerID: 1, ReviewerLogin: "alice", State: "COMMENTED", SubmittedAt: ago(40)},
+		{PullRequestID: "blocked", ReviewerID: 3, ReviewerLogin: "carol", State: "APPROVED", SubmittedAt: ago(24 * 19)},
+		{PullRequestID: "blocked", ReviewerID: 3, ReviewerLogin: "carol", State: "CHANGES_REQUESTED", SubmittedAt: ago(24 * 18)},
+		{PullRequestID: "blocked", ReviewerID: 3, ReviewerLogin: "carol", State: "COMMENTED", SubmittedAt: ago(24 * 17)},
+		{PullRequestID: "blocked", ReviewerID: 1, ReviewerLogin: "alice", State: "APPROVED", SubmittedAt: ago(24 * 17)},
+		{PullRequestID: "approved", ReviewerID: 2, ReviewerLogin: "bob", State: "APPROVED", SubmittedAt: ago(24 * 14)},
+	}
+	requests := []*models.PRRequestedReviewer{
+		{PullRequestID: "waiting", GithubPersonID: "carol"},
+		{PullRequestID: "waiting", GithubPersonID: "bob"},
+		{PullRequestID: "approved", GithubPersonID: "bob"},
+	}
+	events := []*models.PRDraftEvent{
+		{PullRequestID: "was-draft", EventType: models.PRDraftEventReadyForReview, OccurredAt: *ago(10)},
+	}
+
+	stale := findStalePullRequests(pullRequests, reviews, requests, events, people, settings, now)
+
+	byID := make(map[string]*models.StalePullRequest)
+	var order []string
+	for _, entry := range stale {
+		byID[entry.PullRequestID] = entry
+		order = append(order, entry.PullRequestID)
+	}
+	assert.Equal(t, []string{"blocked", "approved", "draft", "waiting"}, order)
+
+	waiting := byID["waiting"]
+	assert.True(t, waiting.ReviewOverdue)
+	assert.False(t, waiting.Aged)
+	assert.Equal(t, int64(48*3600), *waiting.WaitingForReviewSeconds)
+	assert.Equal(t, "alice", waiting.Owner)
+	assert.Equal(t, []string{"bob", "carol"}, waiting.WaitingOn)
+
+	draft := byID["draft"]
+	assert.True(t, draft.IdleDraft)
+	assert.False(t, draft.ReviewOverdue)
+	assert.Nil(t, draft.WaitingForReviewSeconds)
+	assert.Equal(t, []string{"bob"}, draft.WaitingOn)
+
+	blocked := byID["blocked"]
+	assert.True(t, blocked.Aged)
+	assert.True(t, blocked.ChangesRequested)
+	assert.Equal(t, []string{"carol"}, blocked.ChangesRequestedBy)
+	assert.Equal(t, []string{"bob"}, blocked.WaitingOn)
+
+	approved := byID["approved"]
+	assert.True(t, approved.Aged)
+	assert.False(t, approved.ChangesRequested)
+	assert.Equal(t, []string{"carol"}, approved.WaitingOn)
+}
@@ -0,0 +1,599 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+	"github.com/google/go-github/v57/github"
+)
+
+// TeamService syncs the GitHub organization teams of a project and rolls people statistics up by team
+type TeamService struct {
+	projectTeamRepo   *repositories.ProjectTeamRepository
+	githubTeamRepo    *repositories.GithubTeamRepository
+	githubPersonRepo  *repositories.GithubPersonRepository
+	githubRepoRepo    *repositories.GitHubRepositoryRepository
+	githubRepoService *GitHubRepositoryService
+	discoveryService  *RepositoryDiscoveryService
+	peopleStatsRepo   *repositories.PeopleStatisticsRepository
+}
+
+func NewTeamService(
+	projectTeamRepo *repositories.ProjectTeamRepository,
+	githubTeamRepo *repositories.GithubTeamRepository,
+	githubPersonRepo *repositories.GithubPersonRepository,
+	githubRepoRepo *repositories.GitHubRepositoryRepository,
+	githubRepoService *GitHubRepositoryService,
+	discoveryService *RepositoryDiscoveryService,
+	peopleStatsRepo *repositories.PeopleStatisticsRepository,
+) *TeamService {
+	return &TeamService{
+		projectTeamRepo:   projectTeamRepo,
+		githubTeamRepo:    githubTeamRepo,
+		githubPersonRepo:  githubPersonRepo,
+		githubRepoRepo:    githubRepoRepo,
+		githubRepoService: githubRepoService,
+		discoveryService:  discoveryService,
+		peopleStatsRepo:   peopleStatsRepo,
+	}
+}
+
+// GetTeams retrieves the teams of a project with their members
+func (s *TeamService) GetTeams(projectID string) ([]*models.ProjectTeam, error) {
+	return s.projectTeamRepo.GetByProjectID(projectID)
+}
+
+// CreateTeam creates a team defined in the project
+func (s *TeamService) CreateTeam(projectID, name string) (*models.ProjectTeam, error) {
+	team := models.NewProjectTeam(projectID, strings.TrimSpace(name))
+	if err := team.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.projectTeamRepo.Create(team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// DeleteTeam deletes a team defined in the project. Synced teams follow GitHub.
+func (s *TeamService) DeleteTeam(projectID, teamID string) error {
+	team, err := s.getManualTeam(projectID, teamID)
+	if err != nil {
+		return err
+	}
+	return s.projectTeamRepo.Delete(team.ID)
+}
+
+// AddMember adds a person to a team defined in the project for a period given as YYYY-MM-DD dates,
+// either of which may be empty to leave it open. Periods of the same person in a team may not overlap.
+func (s *TeamService) AddMember(projectID, teamID, githubPersonID, startDate, endDate string) (*models.ProjectTeamMember, error) {
+	team, err := s.getManualTeam(projectID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.githubPersonRepo.GetByID(githubPersonID); err != nil {
+		return nil, &models.ValidationError{Field: "github_person_id", Message: "Person not found"}
+	}
+
+	start, end, err := parseMembershipDates(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	member := models.NewProjectTeamMember(team.ID, githubPersonID, start, end)
+	if err := validateMembership(team, member); err != nil {
+		return nil, err
+	}
+
+	if err := s.projectTeamRepo.CreateMember(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// UpdateMember changes the period of a membership, typically to end it when the person moves on
+func (s *TeamService) UpdateMember(projectID, teamID, memberID, startDate, endDate string) error {
+	team, err := s.getManualTeam(projectID, teamID)
+	if err != nil {
+		return err
+	}
+	member := findMembership(team, memberID)
+	if member == nil {
+		return &models.ValidationError{Field: "member_id", Message: "Membership not found"}
+	}
+
+	start, end, err := parseMembershipDates(startDate, endDate)
+	if err != nil {
+		return err
+	}
+	member.StartDate, member.EndDate = start, end
+	if err := validateMembership(team, member); err != nil {
+		return err
+	}
+
+	return s.projectTeamRepo.UpdateMember(member)
+}
+
+// RemoveMember deletes a membership. Ending it keeps the person's past work in the team instead.
+func (s *TeamService) RemoveMember(projectID, teamID, memberID string) error {
+	team, err := s.getManualTeam(projectID, teamID)
+	if err != nil {
+		return err
+	}
+	if findMembership(team, memberID) == nil {
+		return &models.ValidationError{Field: "member_id", Message: "Membership not found"}
+	}
+	return s.projectTeamRepo.DeleteMember(memberID)
+}
+
+// getManualTeam retrieves a team defined in the project
+func (s *TeamService) getManualTeam(projectID, teamID string) (*models.ProjectTeam, error) {
+	team, err := s.projectTeamRepo.GetByID(teamID)
+	if err == sql.ErrNoRows || (err == nil && team.ProjectID != projectID) {
+		return nil, &models.ValidationError{Field: "team_id", Message: "Team not found"}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !team.IsManual() {
+		return nil, &models.ValidationError{Field: "team_id", Message: "Teams synced from GitHub are managed on GitHub"}
+	}
+	return team, nil
+}
+
+// GetAllTimeTeamReport rolls all-time people statistics up by team and narrows them down to the
+// selected team, if any
+func (s *TeamService) GetAllTimeTeamReport(projectID, teamID string, stats []*models.GitHubPersonStats, filter *models.RepositoryFilter) (*models.TeamReport, []*models.GitHubPersonStats, error) {
+	return s.getTeamReport(projectID, teamID, time.Time{}, time.Time{}, stats, filter)
+}
+
+// GetYearlyTeamReport rolls the people statistics of a year up by team
+func (s *TeamService) GetYearlyTeamReport(projectID, teamID string, year int, stats []*models.GitHubPersonStats, filter *models.RepositoryFilter) (*models.TeamReport, []*models.GitHubPersonStats, error) {
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return s.getTeamReport(projectID, teamID, start, start.AddDate(1, 0, 0), stats, filter)
+}
+
+// GetMonthlyTeamReport rolls the people statistics of a month up by team
+func (s *TeamService) GetMonthlyTeamReport(projectID, teamID string, year, month int, stats []*models.GitHubPersonStats, filter *models.RepositoryFilter) (*models.TeamReport, []*models.GitHubPersonStats, error) {
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return s.getTeamReport(projectID, teamID, start, start.AddDate(0, 1, 0), stats, filter)
+}
+
+// GetWeeklyTeamReport rolls the people statistics of a week up by team
+func (s *TeamService) GetWeeklyTeamReport(projectID, teamID string, year, week int, stats []*models.GitHubPersonStats, filter *models.RepositoryFilter) (*models.TeamReport, []*models.GitHubPersonStats, error) {
+	start, end := weekRange(year, week)
+	return s.getTeamReport(projectID, teamID, start, end, stats, filter)
+}
+
+// GetDailyTeamReport rolls the people statistics of a day up by team
+func (s *TeamService) GetDailyTeamReport(projectID, teamID string, date time.Time, stats []*models.GitHubPersonStats, filter *models.RepositoryFilter) (*models.TeamReport, []*models.GitHubPersonStats, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return s.getTeamReport(projectID, teamID, start, start.AddDate(0, 0, 1), stats, filter)
+}
+
+// getTeamReport builds the team rollups of the people statistics of [start, end) in the repositories of
+// a filter and, when teamID is set, narrows the statistics down to that team. Work is attributed by the
+// day it was done, so a person counts towards a team only for the days they were in it. The statistics
+// are returned unchanged on error.
+func (s *TeamService) getTeamReport(projectID, teamID string, start, end time.Time, stats []*models.GitHubPersonStats, filter *models.RepositoryFilter) (*models.TeamReport, []*models.GitHubPersonStats, error) {
+	teams, err := s.GetTeams(projectID)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	report := &models.TeamReport{Teams: teams}
+	for _, team := range teams {
+		if team.ID == teamID {
+			report.SelectedTeam = team
+		}
+	}
+	if teamID != "" && report.SelectedTeam == nil {
+		return nil, stats, fmt.Errorf("team %s not found in project", teamID)
+	}
+	if len(teams) == 0 {
+		return report, stats, nil
+	}
+
+	allDaily, err := s.peopleStatsRepo.GetByProjectID(projectID)
+	if err != nil {
+		return nil, stats, err
+	}
+	inPeriod := periodFilter(start, end)
+	var daily []*models.PeopleStatistics
+	for _, row := range filterPeopleStatistics(allDaily, filter) {
+		if inPeriod(&row.StatDate) {
+			daily = append(daily, row)
+		}
+	}
+
+	report.TeamStats = buildTeamStats(teams, stats, daily, start, end)
+	if report.SelectedTeam != nil {
+		stats = filterStatsByTeam(report.SelectedTeam, stats, daily, start, end)
+	}
+	return report, stats, nil
+}
+
+// SyncProjectTeams imports the teams and memberships of the organizations a project covers: those of
+// its discovery sources and the owners of its repositories. Teams deleted on GitHub are removed from
+// the project. It returns the number of synced teams.
+func (s *TeamService) SyncProjectTeams(projectID, token string) (int, error) {
+	if token == "" {
+		return 0, fmt.Errorf("GitHub token is required")
+	}
+
+	sources, err := s.discoveryService.GetSources(projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get discovery sources: %w", err)
+	}
+	projectRepos, err := s.githubRepoService.GetProjectRepositories(projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get project repositories: %w", err)
+	}
+	var fullNames []string
+	for _, projectRepo := range projectRepos {
+		if githubRepo, err := s.githubRepoRepo.GetByID(projectRepo.GithubRepoID); err == nil {
+			fullNames = append(fullNames, githubRepo.FullName)
+		}
+	}
+
+	githubClient := s.githubRepoService.createGitHubClient(token)
+	ctx := context.Background()
+
+	synced := 0
+	for _, organization := range projectOrganizations(sources, fullNames) {
+		count, err := s.syncOrganizationTeams(ctx, githubClient, projectID, organization)
+		if err != nil {
+			log.Printf("Error syncing teams of %s for project %s: %v", organization, projectID, err)
+			continue
+		}
+		synced += count
+	}
+
+	return synced, nil
+}
+
+// syncOrganizationTeams imports the teams of one organization into a project. Repository owners that
+// are users rather than organizations have no teams and are skipped.
+func (s *TeamService) syncOrganizationTeams(ctx context.Context, client *github.Client, projectID, organization string) (int, error) {
+	var githubTeams []*github.Team
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		teams, resp, err := client.Teams.ListTeams(ctx, organization, opt)
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return 0, nil
+			}
+			return 0, err
+		}
+		githubTeams = append(githubTeams, teams...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	var syncedIDs []string
+	for _, githubTeam := range githubTeams {
+		team, err := s.syncTeam(ctx, client, projectID, organization, githubTeam)
+		if err != nil {
+			log.Printf("Error syncing team %s/%s: %v", organization, githubTeam.GetSlug(), err)
+			continue
+		}
+		syncedIDs = append(syncedIDs, team.ID)
+	}
+
+	removed, err := s.projectTeamRepo.DeleteGithubTeamsNotIn(projectID, organization, syncedIDs)
+	if err != nil {
+		return len(syncedIDs), err
+	}
+	if removed > 0 {
+		log.Printf("Removed %d teams of %s no longer on GitHub from project %s", removed, organization, projectID)
+	}
+
+	return len(syncedIDs), nil
+}
+
+// syncTeam stores a GitHub team with its members and attaches it to the project
+func (s *TeamService) syncTeam(ctx context.Context, client *github.Client, projectID, organization string, githubTeam *github.Team) (*models.GithubTeam, error) {
+	team := &models.GithubTeam{
+		GithubTeamID: githubTeam.GetID(),
+		Slug:         githubTeam.GetSlug(),
+		Name:         githubTeam.Name,
+		HTMLURL:      githubTeam.HTMLURL,
+		Organization: &organization,
+	}
+	if err := s.githubTeamRepo.Upsert(team); err != nil {
+		return nil, err
+	}
+
+	var memberIDs []string
+	opt := &github.TeamListTeamMembersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		members, resp, err := client.Teams.ListTeamMembersBySlug(ctx, organization, team.Slug, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			personID, err := s.memberPersonID(member)
+			if err != nil {
+				return nil, err
+			}
+			memberIDs = append(memberIDs, personID)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	if err := s.githubTeamRepo.ReplaceMembers(team.ID, memberIDs); err != nil {
+		return nil, err
+	}
+
+	name := githubTeam.GetName()
+	if name == "" {
+		name = team.Slug
+	}
+	projectTeam, err := s.projectTeamRepo.GetByProjectAndGithubTeam(projectID, team.ID)
+	switch {
+	case err == sql.ErrNoRows:
+		projectTeam = models.NewProjectTeam(projectID, name)
+		projectTeam.GithubTeamID = &team.ID
+		err = s.projectTeamRepo.Create(projectTeam)
+	case err == nil && projectTeam.Name != name:
+		projectTeam.Name = name
+		err = s.projectTeamRepo.Update(projectTeam)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// memberPersonID returns the ID of the GitHub person of a team member, creating the person when it was
+// never seen. Team members are not added to the project's people; they show up once they contribute.
+func (s *TeamService) memberPersonID(member *github.User) (string, error) {
+	person, err := s.githubPersonRepo.GetByGithubUserID(int(member.GetID()))
+	if err == nil {
+		return person.ID, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", err
+	}
+
+	person = &models.GithubPerson{
+		GithubUserID: int(member.GetID()),
+		Username:     member.GetLogin(),
+		AvatarURL:    member.AvatarURL,
+		ProfileURL:   member.HTMLURL,
+		Type:         member.Type,
+	}
+	if err := s.githubPersonRepo.Create(person); err != nil {
+		return "", err
+	}
+	return person.ID, nil
+}
+
+// projectOrganizations returns the organizations of the discovery sources and the owners of the
+// repositories, without duplicates
+func projectOrganizations(sources []*models.RepositoryDiscoverySource, repoFullNames []string) []string {
+	seen := make(map[string]bool)
+	var organizations []string
+	add := func(organization string) {
+		key := strings.ToLower(organization)
+		if organization == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		organizations = append(organizations, organization)
+	}
+
+	for _, source := range sources {
+		add(source.Organization)
+	}
+	for _, fullName := range repoFullNames {
+		owner, _, found := strings.Cut(fullName, "/")
+		if found {
+			add(owner)
+		}
+	}
+
+	sort.Slice(organizations, func(i, j int) bool {
+		return strings.ToLower(organizations[i]) < strings.ToLower(organizations[j])
+	})
+	return organizations
+}
+
+// parseMembershipDates parses the optional YYYY-MM-DD dates of a membership
+func parseMembershipDates(startDate, endDate string) (*time.Time, *time.Time, error) {
+	parse := func(field, value string) (*time.Time, error) {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return nil, nil
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, &models.ValidationError{Field: field, Message: "Dates must be in YYYY-MM-DD format"}
+		}
+		return &date, nil
+	}
+
+	start, err := parse("start_date", startDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	end, err := parse("end_date", endDate)
+	if err != nil {
+		return nil, nil, err
+	}
+	return start, end, nil
+}
+
+// validateMembership validates a membership and checks it doesn't overlap another period of the same
+// person in the team
+func validateMembership(team *models.ProjectTeam, member *models.ProjectTeamMember) error {
+	if err := member.Validate(); err != nil {
+		return err
+	}
+
+	for _, other := range team.Members {
+		if other.ID == member.ID || other.GithubPersonID != member.GithubPersonID {
+			continue
+		}
+		start, end := time.Time{}, time.Time{}
+		if member.StartDate != nil {
+			start = *member.StartDate
+		}
+		if member.EndDate != nil {
+			end = member.EndDate.AddDate(0, 0, 1)
+		}
+		if other.Overlaps(start, end) {
+			return &models.ValidationError{Field: "start_date", Message: "The person is already in the team for part of this period"}
+		}
+	}
+	return nil
+}
+
+// findMembership returns the membership of a team with the given ID
+func findMembership(team *models.ProjectTeam, memberID string) *models.ProjectTeamMember {
+	for _, member := range team.Members {
+		if member.ID != "" && member.ID == memberID {
+			return member
+		}
+	}
+	return nil
+}
+
+// periodMemberships returns the memberships of a team that cover part of [start, end) by person
+func periodMemberships(team *models.ProjectTeam, start, end time.Time) map[string][]*models.ProjectTeamMember {
+	memberships := make(map[string][]*models.ProjectTeamMember)
+	for _, member := range team.Members {
+		if member.Overlaps(start, end) {
+			memberships[member.GithubPersonID] = append(memberships[member.GithubPersonID], member)
+		}
+	}
+	return memberships
+}
+
+// activeOn tells whether any of the memberships covers a day
+func activeOn(memberships []*models.ProjectTeamMember, date time.Time) bool {
+	for _, member := range memberships {
+		if member.ActiveOn(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// addDailyStats adds a day of statistics to the aggregated statistics of a person
+func addDailyStats(stats *models.GitHubPersonStats, row *models.PeopleStatistics) {
+	stats.TotalCommits += row.Commits
+	stats.TotalAdditions += row.Additions
+	stats.TotalDeletions += row.Deletions
+	stats.TotalComments += row.Comments
+	stats.TotalPullRequests += row.PullRequests
+	stats.TotalScore += row.Score
+}
+
+// filterStatsByTeam keeps the statistics of the people who were in a team during [start, end), counting
+// only the days they were in it. Bots keep no score and stay at the end.
+func filterStatsByTeam(team *models.ProjectTeam, stats []*models.GitHubPersonStats, daily []*models.PeopleStatistics, start, end time.Time) []*models.GitHubPersonStats {
+	memberships := periodMemberships(team, start, end)
+
+	sums := make(map[string]*models.GitHubPersonStats)
+	for _, row := range daily {
+		if !activeOn(memberships[row.GithubPersonID], row.StatDate) {
+			continue
+		}
+		if sums[row.GithubPersonID] == nil {
+			sums[row.GithubPersonID] = &models.GitHubPersonStats{}
+		}
+		addDailyStats(sums[row.GithubPersonID], row)
+	}
+
+	var people, bots []*models.GitHubPersonStats
+	for _, stat := range stats {
+		if stat.IsAutomation || stat.GitHubPerson == nil || len(memberships[stat.GitHubPerson.ID]) == 0 {
+			continue
+		}
+		filtered := &models.GitHubPersonStats{GitHubPerson: stat.GitHubPerson, IsBot: stat.IsBot}
+		if sum := sums[stat.GitHubPerson.ID]; sum != nil {
+			filtered.TotalCommits = sum.TotalCommits
+			filtered.TotalAdditions = sum.TotalAdditions
+			filtered.TotalDeletions = sum.TotalDeletions
+			filtered.TotalComments = sum.TotalComments
+			filtered.TotalPullRequests = sum.TotalPullRequests
+			filtered.TotalScore = sum.TotalScore
+		}
+		if filtered.IsBot {
+			filtered.TotalScore = 0
+			bots = append(bots, filtered)
+			continue
+		}
+		people = append(people, filtered)
+	}
+
+	sort.SliceStable(people, func(i, j int) bool {
+		return people[i].TotalScore > people[j].TotalScore
+	})
+	return append(people, bots...)
+}
+
+// buildTeamStats rolls the daily statistics of [start, end) up by team, counting the days each person
+// was in the team and leaving bots and people outside the statistics out. Teams are sorted by score. A
+// person in several teams at once counts towards each of them.
+func buildTeamStats(teams []*models.ProjectTeam, stats []*models.GitHubPersonStats, daily []*models.PeopleStatistics, start, end time.Time) []*models.TeamStats {
+	counted := make(map[string]bool)
+	for _, stat := range stats {
+		if stat.IsBot || stat.IsAutomation || stat.GitHubPerson == nil {
+			continue
+		}
+		counted[stat.GitHubPerson.ID] = true
+	}
+
+	var teamStats []*models.TeamStats
+	for _, team := range teams {
+		rollup := &models.TeamStats{Team: team}
+		memberships := periodMemberships(team, start, end)
+		for personID := range memberships {
+			if counted[personID] {
+				rollup.Members++
+			}
+		}
+
+		sum := &models.GitHubPersonStats{}
+		active := make(map[string]bool)
+		for _, row := range daily {
+			if !counted[row.GithubPersonID] || !activeOn(memberships[row.GithubPersonID], row.StatDate) {
+				continue
+			}
+			addDailyStats(sum, row)
+			active[row.GithubPersonID] = true
+		}
+
+		rollup.ActiveMembers = len(active)
+		rollup.TotalCommits = sum.TotalCommits
+		rollup.TotalAdditions = sum.TotalAdditions
+		rollup.TotalDeletions = sum.TotalDeletions
+		rollup.TotalComments = sum.TotalComments
+		rollup.TotalPullRequests = sum.TotalPullRequests
+		rollup.TotalScore = sum.TotalScore
+		if rollup.ActiveMembers > 0 {
+			rollup.AverageScore = float64(rollup.TotalScore) / float64(rollup.ActiveMembers)
+		}
+		teamStats = append(teamStats, rollup)
+	}
+
+	sort.SliceStable(teamStats, func(i, j int) bool {
+		return teamStats[i].TotalScore > teamStats[j].TotalScore
+	})
+	return teamStats
+}
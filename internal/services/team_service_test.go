@@ -0,0 +1,125 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectOrganizations(t *testing.T) {
+	team := "platform"
+	sources := []*models.RepositoryDiscoverySource{
+		{Organization: "acme"},
+		{Organization: "acme", TeamSlug: &team},
+	}
+
+	organizations := projectOrganizations(sources, []string{"Acme/api", "widgets/web", "alice/dotfiles", "broken"})
+	assert.Equal(t, []string{"acme", "alice", "widgets"}, organizations)
+}
+
+func TestBuildTeamStats(t *testing.T) {
+	person := func(id string) *models.GithubPerson {
+		return &models.GithubPerson{ID: id, Username: id}
+	}
+	day := func(d int) time.Time {
+		return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+	members := func(teamID string, ids ...string) []*models.ProjectTeamMember {
+		var members []*models.ProjectTeamMember
+		for _, id := range ids {
+			members = append(members, &models.ProjectTeamMember{ProjectTeamID: teamID, GithubPersonID: id})
+		}
+		return members
+	}
+	stats := []*models.GitHubPersonStats{
+		{GitHubPerson: person("alice"), TotalCommits: 10, TotalScore: 100},
+		{GitHubPerson: person("bob"), TotalCommits: 4, TotalPullRequests: 2, TotalScore: 60},
+		{GitHubPerson: person("carol"), TotalCommits: 1, TotalScore: 10},
+		{GitHubPerson: person("renovate"), TotalCommits: 30, IsBot: true},
+		{GitHubPerson: &models.GithubPerson{Username: "Automation"}, TotalCommits: 5, IsAutomation: true},
+	}
+	daily := []*models.PeopleStatistics{
+		{GithubPersonID: "alice", StatDate: day(3), Commits: 10, Score: 100},
+		{GithubPersonID: "bob", StatDate: day(5), Commits: 4, PullRequests: 2, Score: 60},
+		{GithubPersonID: "carol", StatDate: day(20), Commits: 1, Score: 10},
+		{GithubPersonID: "renovate", StatDate: day(1), Commits: 30},
+	}
+	backend := &models.ProjectTeam{ID: "backend", Name: "Backend", Members: members("backend", "alice", "bob", "renovate", "dave")}
+	frontend := &models.ProjectTeam{ID: "frontend", Name: "Frontend", Members: members("frontend", "bob", "carol")}
+	empty := &models.ProjectTeam{ID: "empty", Name: "Empty"}
+	start, end := day(1), day(1).AddDate(0, 1, 0)
+
+	teamStats := buildTeamStats([]*models.ProjectTeam{empty, frontend, backend}, stats, daily, start, end)
+	if assert.Len(t, teamStats, 3) {
+		assert.Equal(t, "backend", teamStats[0].Team.ID)
+		assert.Equal(t, 2, teamStats[0].Members)
+		assert.Equal(t, 2, teamStats[0].ActiveMembers)
+		assert.Equal(t, 14, teamStats[0].TotalCommits)
+		assert.Equal(t, 160, teamStats[0].TotalScore)
+		assert.Equal(t, 80.0, teamStats[0].AverageScore)
+
+		assert.Equal(t, "frontend", teamStats[1].Team.ID)
+		assert.Equal(t, 70, teamStats[1].TotalScore)
+
+		assert.Equal(t, 0, teamStats[2].ActiveMembers)
+		assert.Equal(t, 0.0, teamStats[2].AverageScore)
+	}
+
+	filtered := filterStatsByTeam(backend, stats, daily, start, end)
+	if assert.Len(t, filtered, 3) {
+		assert.Equal(t, "alice", filtered[0].GitHubPerson.ID)
+		assert.Equal(t, "bob", filtered[1].GitHubPerson.ID)
+		assert.Equal(t, "renovate", filtered[2].GitHubPerson.ID)
+		assert.Equal(t, 0, filtered[2].TotalScore)
+	}
+}
+
+func TestTeamMembershipDates(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	person := &models.GithubPerson{ID: "alice", Username: "alice"}
+	stats := []*models.GitHubPersonStats{{GitHubPerson: person, TotalCommits: 6, TotalScore: 70}}
+	daily := []*models.PeopleStatistics{
+		{GithubPersonID: "alice", StatDate: *day(5), Commits: 1, Score: 10},
+		{GithubPersonID: "alice", StatDate: *day(15), Commits: 2, Score: 20},
+		{GithubPersonID: "alice", StatDate: *day(16), Commits: 3, Score: 40},
+	}
+
+	// Alice moves from payments to checkout on the 16th
+	payments := &models.ProjectTeam{ID: "payments", Members: []*models.ProjectTeamMember{
+		{ID: "m1", ProjectTeamID: "payments", GithubPersonID: "alice", EndDate: day(15)},
+	}}
+	checkout := &models.ProjectTeam{ID: "checkout", Members: []*models.ProjectTeamMember{
+		{ID: "m2", ProjectTeamID: "checkout", GithubPersonID: "alice", StartDate: day(16)},
+	}}
+	start, end := *day(1), day(1).AddDate(0, 1, 0)
+
+	teamStats := buildTeamStats([]*models.ProjectTeam{payments, checkout}, stats, daily, start, end)
+	if assert.Len(t, teamStats, 2) {
+		assert.Equal(t, "checkout", teamStats[0].Team.ID)
+		assert.Equal(t, 40, teamStats[0].TotalScore)
+		assert.Equal(t, "payments", teamStats[1].Team.ID)
+		assert.Equal(t, 3, teamStats[1].TotalCommits)
+	}
+
+	filtered := filterStatsByTeam(payments, stats, daily, start, end)
+	if assert.Len(t, filtered, 1) {
+		assert.Equal(t, 30, filtered[0].TotalScore)
+	}
+	assert.Empty(t, filterStatsByTeam(checkout, stats, daily, *day(1), *day(16)))
+
+	// A second stint may not overlap the first one
+	overlapping := models.NewProjectTeamMember("payments", "alice", day(10), day(20))
+	assert.Error(t, validateMembership(payments, overlapping))
+	later := models.NewProjectTeamMember("payments", "alice", day(20), nil)
+	assert.NoError(t, validateMembership(payments, later))
+	backwards := models.NewProjectTeamMember("payments", "alice", day(25), day(20))
+	assert.Error(t, validateMembership(payments, backwards))
+
+	_, _, err := parseMembershipDates("2025-03-01", "March 5")
+	assert.Error(t, err)
+}
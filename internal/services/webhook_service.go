@@ -0,0 +1,164 @@
+package services
+
+import (
+	"database/sql"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+	"github.com/google/go-github/v57/github"
+)
+
+// WebhookService turns GitHub webhook deliveries into incremental jobs for the project repositories they
+// affect
+type WebhookService struct {
+	githubRepoRepo        *repositories.GitHubRepositoryRepository
+	projectRepositoryRepo *repositories.ProjectRepositoryRepository
+	githubRepoService     *GitHubRepositoryService
+	jobService            *JobService
+	projectRepo           *repositories.ProjectRepository
+}
+
+func NewWebhookService(
+	githubRepoRepo *repositories.GitHubRepositoryRepository,
+	projectRepositoryRepo *repositories.ProjectRepositoryRepository,
+	githubRepoService *GitHubRepositoryService,
+	jobService *JobService,
+	projectRepo *repositories.ProjectRepository,
+) *WebhookService {
+	return &WebhookService{
+		githubRepoRepo:        githubRepoRepo,
+		projectRepositoryRepo: projectRepositoryRepo,
+		githubRepoService:     githubRepoService,
+		jobService:            jobService,
+		projectRepo:           projectRepo,
+	}
+}
+
+// webhookDelivery is what a webhook delivery asks for: jobs for the repository with a GitHub ID, or an
+// update of the stored repository
+type webhookDelivery struct {
+	githubRepoID int64
+	jobTypes     []models.JobType
+	action       string
+	repository   *github.Repository
+}
+
+// HandleEvent handles a webhook delivery whose signature was verified and returns how many jobs it
+// created. Events other than push, pull_request, pull_request_review and repository are ignored.
+// Payloads that can't be parsed return a ValidationError; other errors are failures on our side, and
+// the jobs of the other projects are still queued before the first of them is returned.
+func (s *WebhookService) HandleEvent(eventType string, payload []byte) (int, error) {
+	delivery, err := parseWebhook(eventType, payload)
+	if err != nil || delivery == nil {
+		return 0, err
+	}
+
+	if delivery.repository != nil {
+		return 0, s.githubRepoService.UpdateFromWebhook(delivery.action, delivery.repository)
+	}
+
+	githubRepo, err := s.githubRepoRepo.GetByGithubID(delivery.githubRepoID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if !githubRepo.IsActive() {
+		return 0, nil
+	}
+
+	projectRepos, err := s.projectRepositoryRepo.GetByGithubRepoID(githubRepo.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	var firstErr error
+	for _, projectRepo := range projectRepos {
+		if !projectRepo.IsTracked {
+			continue
+		}
+		project, err := s.projectRepo.GetByID(projectRepo.ProjectID)
+		if err != nil {
+			log.Printf("Failed to get project %s for %s webhook: %v", projectRepo.ProjectID, eventType, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		// Tag pushes only fetch deployments, which the project may not sync
+		if slices.Contains(delivery.jobTypes, models.JobTypeDeployment) && !project.SyncDeployments {
+			continue
+		}
+		count, err := s.jobService.EnqueueRepositoryJobs(projectRepo.ProjectID, projectRepo.ID, delivery.jobTypes...)
+		if err != nil {
+			log.Printf("Failed to create %s webhook jobs for repository %s: %v", eventType, projectRepo.ID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		created += count
+	}
+
+	if created > 0 {
+		log.Printf("Created %d jobs for %s webhook on %s", created, eventType, githubRepo.FullName)
+	}
+	return created, firstErr
+}
+
+// parseWebhook decides what a webhook delivery asks for, or returns nil when it asks for nothing:
+//   - pushes to the default branch analyze the new commits
+//   - pushed tags fetch deployments, which read tags from the clone
+//   - pull request and review events fetch pull requests
+//   - repository events update the stored repository, following renames, transfers, archival and deletion
+func parseWebhook(eventType string, payload []byte) (*webhookDelivery, error) {
+	switch eventType {
+	case "push", "pull_request", "pull_request_review", "repository":
+	default:
+		return nil, nil
+	}
+
+	event, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		return nil, &models.ValidationError{Field: "payload", Message: "Invalid webhook payload: " + err.Error()}
+	}
+
+	switch event := event.(type) {
+	case *github.PushEvent:
+		if event.GetDeleted() {
+			return nil, nil
+		}
+		delivery := &webhookDelivery{githubRepoID: event.GetRepo().GetID()}
+		switch ref := event.GetRef(); {
+		case ref == "refs/heads/"+event.GetRepo().GetDefaultBranch():
+			delivery.jobTypes = []models.JobType{models.JobTypeClone, models.JobTypeCommit, models.JobTypeStats}
+		case strings.HasPrefix(ref, "refs/tags/"):
+			delivery.jobTypes = []models.JobType{models.JobTypeClone, models.JobTypeDeployment}
+		default:
+			return nil, nil
+		}
+		return delivery, nil
+	case *github.PullRequestEvent:
+		return &webhookDelivery{
+			githubRepoID: event.GetRepo().GetID(),
+			jobTypes:     []models.JobType{models.JobTypePullRequest, models.JobTypeStats},
+		}, nil
+	case *github.PullRequestReviewEvent:
+		return &webhookDelivery{
+			githubRepoID: event.GetRepo().GetID(),
+			jobTypes:     []models.JobType{models.JobTypePullRequest, models.JobTypeStats},
+		}, nil
+	case *github.RepositoryEvent:
+		if event.Repo == nil {
+			return nil, nil
+		}
+		return &webhookDelivery{githubRepoID: event.GetRepo().GetID(), action: event.GetAction(), repository: event.Repo}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWebhook(t *testing.T) {
+	tests := []struct {
+		file      string
+		eventType string
+		jobTypes  []models.JobType
+		action    string
+		ignored   bool
+	}{
+		{file: "push.json", eventType: "push", jobTypes: []models.JobType{models.JobTypeClone, models.JobTypeCommit, models.JobTypeStats}},
+		{file: "push_tag.json", eventType: "push", jobTypes: []models.JobType{models.JobTypeClone, models.JobTypeDeployment}},
+		{file: "push_branch.json", eventType: "push", ignored: true},
+		{file: "pull_request.json", eventType: "pull_request", jobTypes: []models.JobType{models.JobTypePullRequest, models.JobTypeStats}},
+		{file: "pull_request_review.json", eventType: "pull_request_review", jobTypes: []models.JobType{models.JobTypePullRequest, models.JobTypeStats}},
+		{file: "repository_renamed.json", eventType: "repository", action: "renamed"},
+		{file: "ping.json", eventType: "ping", ignored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			payload, err := os.ReadFile(filepath.Join("testdata", "webhooks", tt.file))
+			require.NoError(t, err)
+
+			delivery, err := parseWebhook(tt.eventType, payload)
+			require.NoError(t, err)
+			if tt.ignored {
+				assert.Nil(t, delivery)
+				return
+			}
+
+			require.NotNil(t, delivery)
+			assert.Equal(t, int64(186853002), delivery.githubRepoID)
+			assert.Equal(t, tt.jobTypes, delivery.jobTypes)
+			assert.Equal(t, tt.action, delivery.action)
+			if tt.action != "" {
+				assert.Equal(t, "octo-org/octo-service", delivery.repository.GetFullName())
+			}
+		})
+	}
+}
+
+func TestParseWebhookInvalidPayload(t *testing.T) {
+	_, err := parseWebhook("push", []byte("not json"))
+	assert.IsType(t, &models.ValidationError{}, err)
+}
@@ -0,0 +1,379 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+	"github.com/alimgiray/gscope/internal/services"
+	"github.com/google/go-github/v57/github"
+)
+
+const (
+	// ciHistory limits how far back commits and workflow runs are fetched
+	ciHistory = 90 * 24 * time.Hour
+	// ciRerunWindow is how far before the newest stored workflow run runs are listed again,
+	// so that re-runs of recent runs are picked up
+	ciRerunWindow = 7 * 24 * time.Hour
+)
+
+// CIWorker handles ci jobs, which fetch the check runs of pull request heads and default branch
+// commits and the GitHub Actions workflow runs of tracked repositories
+type CIWorker struct {
+	*BaseWorker
+	jobRepo               *repositories.JobRepository
+	githubClientPool      *services.GitHubClientPool
+	githubRepoService     *services.GitHubRepositoryService
+	projectRepositoryRepo *repositories.ProjectRepositoryRepository
+	pullRequestRepo       *repositories.PullRequestRepository
+	commitRepo            *repositories.CommitRepository
+	ciRunRepo             *repositories.CIRunRepository
+	jobGitHubStatsRepo    *repositories.JobGitHubStatsRepository
+}
+
+// NewCIWorker creates a new CI worker
+func NewCIWorker(
+	workerID string,
+	jobRepo *repositories.JobRepository,
+	githubClientPool *services.GitHubClientPool,
+	githubRepoService *services.GitHubRepositoryService,
+	projectRepositoryRepo *repositories.ProjectRepositoryRepository,
+	pullRequestRepo *repositories.PullRequestRepository,
+	commitRepo *repositories.CommitRepository,
+	ciRunRepo *repositories.CIRunRepository,
+	jobGitHubStatsRepo *repositories.JobGitHubStatsRepository,
+) *CIWorker {
+	return &CIWorker{
+		BaseWorker:            NewBaseWorker(workerID, models.JobTypeCI),
+		jobRepo:               jobRepo,
+		githubClientPool:      githubClientPool,
+		githubRepoService:     githubRepoService,
+		projectRepositoryRepo: projectRepositoryRepo,
+		pullRequestRepo:       pullRequestRepo,
+		commitRepo:            commitRepo,
+		ciRunRepo:             ciRunRepo,
+		jobGitHubStatsRepo:    jobGitHubStatsRepo,
+	}
+}
+
+// Start begins the CI worker process
+func (w *CIWorker) Start(ctx context.Context) error {
+	w.Running = true
+	log.Printf("CI worker %s started", w.WorkerID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("CI worker %s stopping due to context cancellation", w.WorkerID)
+			return ctx.Err()
+		case <-w.StopChan:
+			log.Printf("CI worker %s stopping", w.WorkerID)
+			return nil
+		default:
+			job, err := w.jobRepo.GetNextPendingJob(models.JobTypeCI, w.WorkerID)
+			if err != nil {
+				log.Printf("CI worker %s error getting job: %v", w.WorkerID, err)
+				time.Sleep(5 * time.Second)
+				continue
+			}
+
+			if job == nil {
+				time.Sleep(10 * time.Second)
+				continue
+			}
+
+			w.processCIJob(ctx, job)
+		}
+	}
+}
+
+// processCIJob runs a CI job and records its outcome
+func (w *CIWorker) processCIJob(ctx context.Context, job *models.Job) {
+	log.Printf("CI worker %s processing job %s", w.WorkerID, job.ID)
+
+	job.MarkStarted()
+	if err := w.jobRepo.Update(job); err != nil {
+		log.Printf("CI worker %s error updating job %s: %v", w.WorkerID, job.ID, err)
+		return
+	}
+
+	if err := w.ProcessJob(ctx, job); err != nil {
+		log.Printf("CI worker %s error processing job %s: %v", w.WorkerID, job.ID, err)
+		job.SetError(err.Error())
+		job.MarkFailed()
+		if err := w.jobRepo.Update(job); err != nil {
+			log.Printf("CI worker %s error marking job %s as failed: %v", w.WorkerID, job.ID, err)
+		}
+		return
+	}
+
+	job.MarkCompleted()
+	if err := w.jobRepo.Update(job); err != nil {
+		log.Printf("CI worker %s error completing job %s: %v", w.WorkerID, job.ID, err)
+		return
+	}
+
+	log.Printf("CI worker %s completed job %s", w.WorkerID, job.ID)
+}
+
+// ProcessJob fetches the CI runs of the job's repository, or of all tracked repositories of the project
+func (w *CIWorker) ProcessJob(ctx context.Context, job *models.Job) error {
+	requestStats := &services.GitHubRequestStats{}
+	client, err := w.githubClientPool.ProjectClient(job.ProjectID, requestStats)
+	if err != nil {
+		return fmt.Errorf("failed to get GitHub client for project: %s", err)
+	}
+	defer saveRequestStats(w.jobGitHubStatsRepo, job, requestStats)
+
+	var projectRepos []*models.ProjectRepository
+	if job.ProjectRepositoryID != nil {
+		projectRepo, err := w.projectRepositoryRepo.GetByID(*job.ProjectRepositoryID)
+		if err != nil {
+			return fmt.Errorf("failed to get project repository %s: %s", *job.ProjectRepositoryID, err)
+		}
+		if !projectRepo.IsTracked {
+			return fmt.Errorf("repository %s is not tracked", *job.ProjectRepositoryID)
+		}
+		projectRepos = append(projectRepos, projectRepo)
+	} else {
+		projectRepos, err = w.githubRepoService.GetProjectRepositories(job.ProjectID)
+		if err != nil {
+			return fmt.Errorf("failed to get project repositories: %s", err)
+		}
+	}
+
+	totalRuns := 0
+	for _, projectRepo := range projectRepos {
+		if !projectRepo.IsTracked {
+			continue
+		}
+
+		githubRepo, err := w.githubRepoService.GetGitHubRepository(projectRepo.GithubRepoID)
+		if err != nil {
+			return fmt.Errorf("failed to get GitHub repository %s: %s", projectRepo.GithubRepoID, err)
+		}
+		if githubRepo.IsDeleted() || githubRepo.IsInaccessible() {
+			log.Printf("Skipping CI runs of %s, it is deleted or inaccessible on GitHub", githubRepo.FullName)
+			continue
+		}
+		owner, repoName, err := parseRepoFullName(githubRepo.FullName)
+		if err != nil {
+			return fmt.Errorf("failed to parse repository name %s: %s", githubRepo.FullName, err)
+		}
+
+		log.Printf("Processing CI runs for %s/%s", owner, repoName)
+		workflowRuns, err := w.ingestWorkflowRuns(ctx, client, owner, repoName, githubRepo.ID)
+		totalRuns += workflowRuns
+		if err != nil {
+			return fmt.Errorf("failed to fetch workflow runs for %s/%s: %s", owner, repoName, err)
+		}
+		checkRuns, err := w.ingestCheckRuns(ctx, client, owner, repoName, githubRepo.ID)
+		totalRuns += checkRuns
+		if err != nil {
+			return fmt.Errorf("failed to fetch check runs for %s/%s: %s", owner, repoName, err)
+		}
+	}
+
+	log.Printf("CI job completed. Processed %d runs", totalRuns)
+	return nil
+}
+
+// ingestWorkflowRuns lists the workflow runs of a repository created since shortly before the newest stored
+// one, and fetches the earlier attempts of re-run workflows. Repositories without GitHub Actions have none.
+func (w *CIWorker) ingestWorkflowRuns(ctx context.Context, client *github.Client, owner, repo, repositoryID string) (int, error) {
+	since := time.Now().Add(-ciHistory)
+	latest, err := w.ciRunRepo.GetLatestWorkflowRunCreatedAt(repositoryID)
+	if err != nil {
+		log.Printf("Warning: failed to get latest workflow run date for repository %s: %v", repositoryID, err)
+	} else if !latest.IsZero() && latest.Add(-ciRerunWindow).After(since) {
+		since = latest.Add(-ciRerunWindow)
+	}
+
+	opts := &github.ListWorkflowRunsOptions{
+		Created:     ">=" + since.UTC().Format(time.RFC3339),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	total := 0
+	for {
+		runs, resp, err := retryGitHubRequest(ctx, func() (*github.WorkflowRuns, *github.Response, error) {
+			return client.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, opts)
+		})
+		if err != nil {
+			return total, err
+		}
+
+		for _, run := range runs.WorkflowRuns {
+			if err := w.ciRunRepo.Upsert(workflowRunToCIRun(run, repositoryID)); err != nil {
+				log.Printf("Failed to store workflow run %d: %s", run.GetID(), err)
+				continue
+			}
+			total++
+
+			for attempt := 1; attempt < run.GetRunAttempt(); attempt++ {
+				stored, err := w.ciRunRepo.HasAttempt(run.GetID(), attempt)
+				if err != nil || stored {
+					continue
+				}
+				previous, _, err := retryGitHubRequest(ctx, func() (*github.WorkflowRun, *github.Response, error) {
+					return client.Actions.GetWorkflowRunAttempt(ctx, owner, repo, run.GetID(), attempt, nil)
+				})
+				if err != nil {
+					log.Printf("Failed to fetch attempt %d of workflow run %d: %s", attempt, run.GetID(), err)
+					continue
+				}
+				if err := w.ciRunRepo.Upsert(workflowRunToCIRun(previous, repositoryID)); err != nil {
+					log.Printf("Failed to store attempt %d of workflow run %d: %s", attempt, run.GetID(), err)
+					continue
+				}
+				total++
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return total, nil
+}
+
+// ingestCheckRuns fetches all check runs, re-runs included, of the heads of pull requests and the default
+// branch commits of a repository. Commits are skipped once all their check runs were seen completed,
+// except for the heads of open pull requests.
+func (w *CIWorker) ingestCheckRuns(ctx context.Context, client *github.Client, owner, repo, repositoryID string) (int, error) {
+	settled, err := w.ciRunRepo.GetSettledCommits(repositoryID)
+	if err != nil {
+		return 0, err
+	}
+
+	since := time.Now().Add(-ciHistory)
+	var shas []string
+	seen := make(map[string]bool)
+	add := func(sha string, force bool) {
+		if sha == "" || seen[sha] || (settled[sha] && !force) {
+			return
+		}
+		seen[sha] = true
+		shas = append(shas, sha)
+	}
+
+	pullRequests, err := w.pullRequestRepo.GetByRepositoryID(repositoryID)
+	if err != nil {
+		return 0, err
+	}
+	for _, pr := range pullRequests {
+		if pr.HeadSHA == nil {
+			continue
+		}
+		open := pr.State == "open"
+		if open || (pr.GithubUpdatedAt != nil && pr.GithubUpdatedAt.After(since)) {
+			add(*pr.HeadSHA, open)
+		}
+	}
+
+	commits, err := w.commitRepo.GetByRepositoryID(repositoryID)
+	if err != nil {
+		return 0, err
+	}
+	for _, commit := range commits {
+		if commit.CommitDate.After(since) {
+			add(commit.CommitSHA, false)
+		}
+	}
+
+	total := 0
+	for _, sha := range shas {
+		pending := false
+		opts := &github.ListCheckRunsOptions{
+			Filter:      github.String("all"),
+			ListOptions: github.ListOptions{PerPage: 100},
+		}
+		failed := false
+		for {
+			result, resp, err := retryGitHubRequest(ctx, func() (*github.ListCheckRunsResults, *github.Response, error) {
+				return client.Checks.ListCheckRunsForRef(ctx, owner, repo, sha, opts)
+			})
+			if err != nil {
+				log.Printf("Failed to fetch check runs of %s: %s", sha, err)
+				failed = true
+				break
+			}
+
+			for _, checkRun := range result.CheckRuns {
+				run := checkRunToCIRun(checkRun, repositoryID)
+				if !run.Completed() {
+					pending = true
+				}
+				if err := w.ciRunRepo.Upsert(run); err != nil {
+					log.Printf("Failed to store check run %d: %s", checkRun.GetID(), err)
+					continue
+				}
+				total++
+			}
+
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+		if failed {
+			continue
+		}
+
+		if err := w.ciRunRepo.MarkCommitSynced(repositoryID, sha, pending); err != nil {
+			log.Printf("Failed to record check runs of %s as fetched: %s", sha, err)
+		}
+	}
+
+	return total, nil
+}
+
+// workflowRunToCIRun converts an attempt of a workflow run
+func workflowRunToCIRun(run *github.WorkflowRun, repositoryID string) *models.CIRun {
+	ciRun := &models.CIRun{
+		RepositoryID:    repositoryID,
+		Kind:            models.CIRunKindWorkflowRun,
+		GithubID:        run.GetID(),
+		Attempt:         run.GetRunAttempt(),
+		Name:            run.GetName(),
+		HeadSHA:         run.GetHeadSHA(),
+		HeadBranch:      run.HeadBranch,
+		Event:           run.Event,
+		Status:          run.GetStatus(),
+		Conclusion:      run.Conclusion,
+		HTMLURL:         run.HTMLURL,
+		GithubCreatedAt: timestampTime(run.CreatedAt),
+		StartedAt:       timestampTime(run.RunStartedAt),
+	}
+	// Workflow runs have no completion time; their last update is when the attempt finished
+	if ciRun.Completed() {
+		ciRun.CompletedAt = timestampTime(run.UpdatedAt)
+	}
+	return ciRun
+}
+
+// checkRunToCIRun converts a check run
+func checkRunToCIRun(run *github.CheckRun, repositoryID string) *models.CIRun {
+	ciRun := &models.CIRun{
+		RepositoryID: repositoryID,
+		Kind:         models.CIRunKindCheckRun,
+		GithubID:     run.GetID(),
+		Attempt:      1,
+		Name:         run.GetName(),
+		HeadSHA:      run.GetHeadSHA(),
+		Status:       run.GetStatus(),
+		Conclusion:   run.Conclusion,
+		HTMLURL:      run.HTMLURL,
+		StartedAt:    timestampTime(run.StartedAt),
+		CompletedAt:  timestampTime(run.CompletedAt),
+	}
+	ciRun.GithubCreatedAt = ciRun.StartedAt
+	if run.CheckSuite != nil {
+		ciRun.HeadBranch = run.CheckSuite.HeadBranch
+	}
+	return ciRun
+}
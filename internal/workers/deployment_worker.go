@@ -0,0 +1,448 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+	"github.com/alimgiray/gscope/internal/services"
+	"github.com/google/go-github/v57/github"
+)
+
+// deploymentHistory limits how far back GitHub deployments are fetched, as every deployment takes a
+// request for its status
+const deploymentHistory = 365 * 24 * time.Hour
+
+// DeploymentWorker handles deployment jobs, which fetch the GitHub releases and deployments and the git
+// tags of tracked repositories, and link them to the commits they ship
+type DeploymentWorker struct {
+	*BaseWorker
+	jobRepo               *repositories.JobRepository
+	githubClientPool      *services.GitHubClientPool
+	githubRepoService     *services.GitHubRepositoryService
+	projectRepositoryRepo *repositories.ProjectRepositoryRepository
+	deploymentRepo        *repositories.DeploymentRepository
+	jobGitHubStatsRepo    *repositories.JobGitHubStatsRepository
+}
+
+// NewDeploymentWorker creates a new deployment worker
+func NewDeploymentWorker(
+	workerID string,
+	jobRepo *repositories.JobRepository,
+	githubClientPool *services.GitHubClientPool,
+	githubRepoService *services.GitHubRepositoryService,
+	projectRepositoryRepo *repositories.ProjectRepositoryRepository,
+	deploymentRepo *repositories.DeploymentRepository,
+	jobGitHubStatsRepo *repositories.JobGitHubStatsRepository,
+) *DeploymentWorker {
+	return &DeploymentWorker{
+		BaseWorker:            NewBaseWorker(workerID, models.JobTypeDeployment),
+		jobRepo:               jobRepo,
+		githubClientPool:      githubClientPool,
+		githubRepoService:     githubRepoService,
+		projectRepositoryRepo: projectRepositoryRepo,
+		deploymentRepo:        deploymentRepo,
+		jobGitHubStatsRepo:    jobGitHubStatsRepo,
+	}
+}
+
+// Start begins the deployment worker process
+func (w *DeploymentWorker) Start(ctx context.Context) error {
+	w.Running = true
+	log.Printf("Deployment worker %s started", w.WorkerID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Deployment worker %s stopping due to context cancellation", w.WorkerID)
+			return ctx.Err()
+		case <-w.StopChan:
+			log.Printf("Deployment worker %s stopping", w.WorkerID)
+			return nil
+		default:
+			job, err := w.jobRepo.GetNextPendingJob(models.JobTypeDeployment, w.WorkerID)
+			if err != nil {
+				log.Printf("Deployment worker %s error getting job: %v", w.WorkerID, err)
+				time.Sleep(5 * time.Second)
+				continue
+			}
+
+			if job == nil {
+				time.Sleep(10 * time.Second)
+				continue
+			}
+
+			w.processDeploymentJob(ctx, job)
+		}
+	}
+}
+
+// processDeploymentJob runs a deployment job and records its outcome
+func (w *DeploymentWorker) processDeploymentJob(ctx context.Context, job *models.Job) {
+	log.Printf("Deployment worker %s processing job %s", w.WorkerID, job.ID)
+
+	job.MarkStarted()
+	if err := w.jobRepo.Update(job); err != nil {
+		log.Printf("Deployment worker %s error updating job %s: %v", w.WorkerID, job.ID, err)
+		return
+	}
+
+	if err := w.ProcessJob(ctx, job); err != nil {
+		log.Printf("Deployment worker %s error processing job %s: %v", w.WorkerID, job.ID, err)
+		job.SetError(err.Error())
+		job.MarkFailed()
+		if err := w.jobRepo.Update(job); err != nil {
+			log.Printf("Deployment worker %s error marking job %s as failed: %v", w.WorkerID, job.ID, err)
+		}
+		return
+	}
+
+	job.MarkCompleted()
+	if err := w.jobRepo.Update(job); err != nil {
+		log.Printf("Deployment worker %s error completing job %s: %v", w.WorkerID, job.ID, err)
+		return
+	}
+
+	log.Printf("Deployment worker %s completed job %s", w.WorkerID, job.ID)
+}
+
+// ProcessJob fetches the deployments of the job's repository, or of all tracked repositories of the project
+func (w *DeploymentWorker) ProcessJob(ctx context.Context, job *models.Job) error {
+	requestStats := &services.GitHubRequestStats{}
+	client, err := w.githubClientPool.ProjectClient(job.ProjectID, requestStats)
+	if err != nil {
+		return fmt.Errorf("failed to get GitHub client for project: %s", err)
+	}
+	defer saveRequestStats(w.jobGitHubStatsRepo, job, requestStats)
+
+	var projectRepos []*models.ProjectRepository
+	if job.ProjectRepositoryID != nil {
+		projectRepo, err := w.projectRepositoryRepo.GetByID(*job.ProjectRepositoryID)
+		if err != nil {
+			return fmt.Errorf("failed to get project repository %s: %s", *job.ProjectRepositoryID, err)
+		}
+		if !projectRepo.IsTracked {
+			return fmt.Errorf("repository %s is not tracked", *job.ProjectRepositoryID)
+		}
+		projectRepos = append(projectRepos, projectRepo)
+	} else {
+		projectRepos, err = w.githubRepoService.GetProjectRepositories(job.ProjectID)
+		if err != nil {
+			return fmt.Errorf("failed to get project repositories: %s", err)
+		}
+	}
+
+	total := 0
+	for _, projectRepo := range projectRepos {
+		if !projectRepo.IsTracked {
+			continue
+		}
+
+		githubRepo, err := w.githubRepoService.GetGitHubRepository(projectRepo.GithubRepoID)
+		if err != nil {
+			return fmt.Errorf("failed to get GitHub repository %s: %s", projectRepo.GithubRepoID, err)
+		}
+		if githubRepo.IsDeleted() || githubRepo.IsInaccessible() {
+			log.Printf("Skipping deployments of %s, it is deleted or inaccessible on GitHub", githubRepo.FullName)
+			continue
+		}
+		owner, repoName, err := parseRepoFullName(githubRepo.FullName)
+		if err != nil {
+			return fmt.Errorf("failed to parse repository name %s: %s", githubRepo.FullName, err)
+		}
+		// Tags and shipped commits come from the clone, which may not exist when only fetching from GitHub
+		repoPath := ""
+		if githubRepo.IsCloned && githubRepo.LocalPath != nil {
+			repoPath = *githubRepo.LocalPath
+		}
+
+		existing, err := w.deploymentRepo.GetByRepositoryID(githubRepo.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get deployments of %s: %s", githubRepo.FullName, err)
+		}
+		known := make(map[string]*models.Deployment, len(existing))
+		for _, deployment := range existing {
+			known[deployment.Source+"/"+deployment.ExternalID] = deployment
+		}
+
+		log.Printf("Processing deployments for %s/%s", owner, repoName)
+		releases, err := w.ingestReleases(ctx, client, owner, repoName, githubRepo.ID, repoPath, known)
+		total += releases
+		if err != nil {
+			return fmt.Errorf("failed to fetch releases for %s/%s: %s", owner, repoName, err)
+		}
+		deployments, err := w.ingestDeployments(ctx, client, owner, repoName, githubRepo.ID, known)
+		total += deployments
+		if err != nil {
+			return fmt.Errorf("failed to fetch deployments for %s/%s: %s", owner, repoName, err)
+		}
+		if repoPath != "" {
+			tags, err := w.ingestTags(repoPath, githubRepo.ID)
+			total += tags
+			if err != nil {
+				log.Printf("Failed to read tags of %s: %s", githubRepo.FullName, err)
+			}
+			if err := w.linkCommits(repoPath, githubRepo.ID); err != nil {
+				log.Printf("Failed to link deployments of %s to commits: %s", githubRepo.FullName, err)
+			}
+		}
+	}
+
+	log.Printf("Deployment job completed. Processed %d releases, deployments and tags", total)
+	return nil
+}
+
+// ingestReleases stores the published releases and prereleases of a repository. The commit of a release
+// is its tag's, looked up in the clone and otherwise on GitHub for releases that don't have one yet.
+func (w *DeploymentWorker) ingestReleases(ctx context.Context, client *github.Client, owner, repo, repositoryID, repoPath string, known map[string]*models.Deployment) (int, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	total := 0
+	for {
+		releases, resp, err := retryGitHubRequest(ctx, func() ([]*github.RepositoryRelease, *github.Response, error) {
+			return client.Repositories.ListReleases(ctx, owner, repo, opts)
+		})
+		if err != nil {
+			return total, err
+		}
+
+		for _, release := range releases {
+			if release.GetDraft() {
+				continue
+			}
+			externalID := strconv.FormatInt(release.GetID(), 10)
+			deployment := &models.Deployment{
+				RepositoryID: repositoryID,
+				Source:       models.DeploymentSourceRelease,
+				ExternalID:   externalID,
+				Name:         release.GetTagName(),
+				Status:       "published",
+				URL:          release.HTMLURL,
+				DeployedAt:   release.GetCreatedAt().Time,
+			}
+			if release.GetPrerelease() {
+				deployment.Status = "prerelease"
+			}
+			if release.PublishedAt != nil {
+				deployment.DeployedAt = release.PublishedAt.Time
+			}
+
+			if sha := tagCommit(repoPath, deployment.Name); sha != "" {
+				deployment.SHA = &sha
+			} else if stored := known[deployment.Source+"/"+externalID]; stored != nil && stored.SHA != nil {
+				deployment.SHA = stored.SHA
+			} else {
+				sha, _, err := client.Repositories.GetCommitSHA1(ctx, owner, repo, deployment.Name, "")
+				if err != nil {
+					log.Printf("Failed to get the commit of release %s: %s", deployment.Name, err)
+				} else {
+					deployment.SHA = &sha
+				}
+			}
+
+			if err := w.deploymentRepo.Upsert(deployment); err != nil {
+				log.Printf("Failed to store release %s: %s", deployment.Name, err)
+				continue
+			}
+			total++
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return total, nil
+}
+
+// ingestDeployments stores the GitHub deployments of a repository from the last year with their latest
+// status. Statuses are only fetched again until a deployment succeeded, failed or became inactive.
+func (w *DeploymentWorker) ingestDeployments(ctx context.Context, client *github.Client, owner, repo, repositoryID string, known map[string]*models.Deployment) (int, error) {
+	since := time.Now().Add(-deploymentHistory)
+	opts := &github.DeploymentsListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	total := 0
+	for {
+		deployments, resp, err := retryGitHubRequest(ctx, func() ([]*github.Deployment, *github.Response, error) {
+			return client.Repositories.ListDeployments(ctx, owner, repo, opts)
+		})
+		if err != nil {
+			return total, err
+		}
+
+		done := false
+		for _, githubDeployment := range deployments {
+			if githubDeployment.GetCreatedAt().Before(since) {
+				// Deployments are listed newest first
+				done = true
+				break
+			}
+
+			externalID := strconv.FormatInt(githubDeployment.GetID(), 10)
+			deployment := &models.Deployment{
+				RepositoryID: repositoryID,
+				Source:       models.DeploymentSourceDeployment,
+				ExternalID:   externalID,
+				Name:         githubDeployment.GetRef(),
+				Environment:  githubDeployment.Environment,
+				SHA:          githubDeployment.SHA,
+				Status:       "pending",
+				DeployedAt:   githubDeployment.GetCreatedAt().Time,
+			}
+
+			if stored := known[deployment.Source+"/"+externalID]; stored != nil && deploymentStatusFinal(stored.Status) {
+				deployment.Status = stored.Status
+				deployment.URL = stored.URL
+				deployment.DeployedAt = stored.DeployedAt
+			} else {
+				statuses, _, err := retryGitHubRequest(ctx, func() ([]*github.DeploymentStatus, *github.Response, error) {
+					return client.Repositories.ListDeploymentStatuses(ctx, owner, repo, githubDeployment.GetID(), &github.ListOptions{PerPage: 1})
+				})
+				if err != nil {
+					log.Printf("Failed to fetch the status of deployment %s: %s", externalID, err)
+				} else if len(statuses) > 0 {
+					// The newest status comes first; a deployment is out once it succeeded
+					status := statuses[0]
+					deployment.Status = status.GetState()
+					deployment.URL = status.EnvironmentURL
+					if deployment.Status == "success" && status.CreatedAt != nil {
+						deployment.DeployedAt = status.CreatedAt.Time
+					}
+				}
+			}
+
+			if err := w.deploymentRepo.Upsert(deployment); err != nil {
+				log.Printf("Failed to store deployment %s: %s", externalID, err)
+				continue
+			}
+			total++
+		}
+
+		if done || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return total, nil
+}
+
+// deploymentStatusFinal tells whether a deployment status won't change anymore, apart from becoming inactive
+func deploymentStatusFinal(status string) bool {
+	switch status {
+	case "success", "failure", "error", "inactive":
+		return true
+	}
+	return false
+}
+
+// ingestTags stores the tags of a cloned repository. Annotated tags are dated when they were created,
+// lightweight tags by the date of their commit.
+func (w *DeploymentWorker) ingestTags(repoPath, repositoryID string) (int, error) {
+	output, err := git(repoPath, "for-each-ref", "refs/tags",
+		"--format=%(refname:short)%09%(objectname)%09%(*objectname)%09%(creatordate:iso-strict)")
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 4 {
+			continue
+		}
+		deployedAt, err := time.Parse(time.RFC3339, parts[3])
+		if err != nil {
+			log.Printf("Failed to parse the date of tag %s: %s", parts[0], err)
+			continue
+		}
+		// Annotated tags point at a tag object, the commit is the peeled object
+		sha := parts[1]
+		if parts[2] != "" {
+			sha = parts[2]
+		}
+
+		deployment := &models.Deployment{
+			RepositoryID: repositoryID,
+			Source:       models.DeploymentSourceTag,
+			ExternalID:   parts[0],
+			Name:         parts[0],
+			SHA:          &sha,
+			Status:       "created",
+			DeployedAt:   deployedAt,
+		}
+		if err := w.deploymentRepo.Upsert(deployment); err != nil {
+			log.Printf("Failed to store tag %s: %s", parts[0], err)
+			continue
+		}
+		total++
+	}
+
+	return total, nil
+}
+
+// linkCommits links each deployment to the commits reachable from it but not from the previous deployment
+// of the same source and environment. The first deployment of each isn't linked, as it would ship the
+// whole history.
+func (w *DeploymentWorker) linkCommits(repoPath, repositoryID string) error {
+	deployments, err := w.deploymentRepo.GetByRepositoryID(repositoryID)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[string]string)
+	for _, deployment := range deployments {
+		if deployment.SHA == nil {
+			continue
+		}
+		group := deployment.Source
+		if deployment.Environment != nil {
+			group += "/" + *deployment.Environment
+		}
+		previousSHA := previous[group]
+		previous[group] = *deployment.SHA
+		if previousSHA == "" || deployment.CommitsLinked {
+			continue
+		}
+
+		output, err := git(repoPath, "rev-list", previousSHA+".."+*deployment.SHA)
+		if err != nil {
+			log.Printf("Failed to list the commits of deployment %s: %s", deployment.Name, err)
+			continue
+		}
+		shas := strings.Fields(output)
+		if err := w.deploymentRepo.ReplaceCommits(deployment.ID, shas); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tagCommit returns the commit a tag points to in a clone, or an empty string when it can't be resolved
+func tagCommit(repoPath, tag string) string {
+	if repoPath == "" || tag == "" {
+		return ""
+	}
+	output, err := git(repoPath, "rev-parse", "--verify", "--quiet", "refs/tags/"+tag+"^{commit}")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(output)
+}
+
+// git runs a git command in a repository and returns its output
+func git(repoPath string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
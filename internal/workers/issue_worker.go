@@ -0,0 +1,461 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/repositories"
+	"github.com/alimgiray/gscope/internal/services"
+	"github.com/google/go-github/v57/github"
+)
+
+// IssueWorker handles issue jobs, which sync the issues of tracked repositories with their labels,
+// assignees, close events and comments
+type IssueWorker struct {
+	*BaseWorker
+	jobRepo                    *repositories.JobRepository
+	githubClientPool           *services.GitHubClientPool
+	githubRepoService          *services.GitHubRepositoryService
+	projectRepositoryRepo      *repositories.ProjectRepositoryRepository
+	githubPersonService        *services.GithubPersonService
+	projectGithubPersonService *services.ProjectGithubPersonService
+	issueRepo                  *repositories.IssueRepository
+	issueCommentRepo           *repositories.IssueCommentRepository
+	jobGitHubStatsRepo         *repositories.JobGitHubStatsRepository
+}
+
+// NewIssueWorker creates a new issue worker
+func NewIssueWorker(
+	workerID string,
+	jobRepo *repositories.JobRepository,
+	githubClientPool *services.GitHubClientPool,
+	githubRepoService *services.GitHubRepositoryService,
+	projectRepositoryRepo *repositories.ProjectRepositoryRepository,
+	githubPersonService *services.GithubPersonService,
+	projectGithubPersonService *services.ProjectGithubPersonService,
+	issueRepo *repositories.IssueRepository,
+	issueCommentRepo *repositories.IssueCommentRepository,
+	jobGitHubStatsRepo *repositories.JobGitHubStatsRepository,
+) *IssueWorker {
+	return &IssueWorker{
+		BaseWorker:                 NewBaseWorker(workerID, models.JobTypeIssue),
+		jobRepo:                    jobRepo,
+		githubClientPool:           githubClientPool,
+		githubRepoService:          githubRepoService,
+		projectRepositoryRepo:      projectRepositoryRepo,
+		githubPersonService:        githubPersonService,
+		projectGithubPersonService: projectGithubPersonService,
+		issueRepo:                  issueRepo,
+		issueCommentRepo:           issueCommentRepo,
+		jobGitHubStatsRepo:         jobGitHubStatsRepo,
+	}
+}
+
+// Start begins the issue worker process
+func (w *IssueWorker) Start(ctx context.Context) error {
+	w.Running = true
+	log.Printf("Issue worker %s started", w.WorkerID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Issue worker %s stopping due to context cancellation", w.WorkerID)
+			return ctx.Err()
+		case <-w.StopChan:
+			log.Printf("Issue worker %s stopping", w.WorkerID)
+			return nil
+		default:
+			job, err := w.jobRepo.GetNextPendingJob(models.JobTypeIssue, w.WorkerID)
+			if err != nil {
+				log.Printf("Issue worker %s error getting job: %v", w.WorkerID, err)
+				time.Sleep(5 * time.Second)
+				continue
+			}
+
+			if job == nil {
+				time.Sleep(10 * time.Second)
+				continue
+			}
+
+			w.processIssueJob(ctx, job)
+		}
+	}
+}
+
+// processIssueJob runs an issue job and records its outcome
+func (w *IssueWorker) processIssueJob(ctx context.Context, job *models.Job) {
+	log.Printf("Issue worker %s processing job %s", w.WorkerID, job.ID)
+
+	job.MarkStarted()
+	if err := w.jobRepo.Update(job); err != nil {
+		log.Printf("Issue worker %s error updating job %s: %v", w.WorkerID, job.ID, err)
+		return
+	}
+
+	if err := w.ProcessJob(ctx, job); err != nil {
+		log.Printf("Issue worker %s error processing job %s: %v", w.WorkerID, job.ID, err)
+		job.SetError(err.Error())
+		job.MarkFailed()
+		if err := w.jobRepo.Update(job); err != nil {
+			log.Printf("Issue worker %s error marking job %s as failed: %v", w.WorkerID, job.ID, err)
+		}
+		return
+	}
+
+	job.MarkCompleted()
+	if err := w.jobRepo.Update(job); err != nil {
+		log.Printf("Issue worker %s error completing job %s: %v", w.WorkerID, job.ID, err)
+		return
+	}
+
+	log.Printf("Issue worker %s completed job %s", w.WorkerID, job.ID)
+}
+
+// ProcessJob syncs the issues of the job's repository, or of all tracked repositories of the project
+func (w *IssueWorker) ProcessJob(ctx context.Context, job *models.Job) error {
+	requestStats := &services.GitHubRequestStats{}
+	client, err := w.githubClientPool.ProjectClient(job.ProjectID, requestStats)
+	if err != nil {
+		return fmt.Errorf("failed to get GitHub client for project: %s", err)
+	}
+	defer saveRequestStats(w.jobGitHubStatsRepo, job, requestStats)
+
+	var projectRepos []*models.ProjectRepository
+	if job.ProjectRepositoryID != nil {
+		projectRepo, err := w.projectRepositoryRepo.GetByID(*job.ProjectRepositoryID)
+		if err != nil {
+			return fmt.Errorf("failed to get project repository %s: %s", *job.ProjectRepositoryID, err)
+		}
+		if !projectRepo.IsTracked {
+			return fmt.Errorf("repository %s is not tracked", *job.ProjectRepositoryID)
+		}
+		projectRepos = append(projectRepos, projectRepo)
+	} else {
+		projectRepos, err = w.githubRepoService.GetProjectRepositories(job.ProjectID)
+		if err != nil {
+			return fmt.Errorf("failed to get project repositories: %s", err)
+		}
+	}
+
+	people := make(map[int64]string)
+	var totals issueTotals
+	for _, projectRepo := range projectRepos {
+		if !projectRepo.IsTracked {
+			continue
+		}
+
+		githubRepo, err := w.githubRepoService.GetGitHubRepository(projectRepo.GithubRepoID)
+		if err != nil {
+			return fmt.Errorf("failed to get GitHub repository %s: %s", projectRepo.GithubRepoID, err)
+		}
+		if githubRepo.IsDeleted() || githubRepo.IsInaccessible() {
+			log.Printf("Skipping issues of %s, it is deleted or inaccessible on GitHub", githubRepo.FullName)
+			continue
+		}
+		owner, repoName, err := parseRepoFullName(githubRepo.FullName)
+		if err != nil {
+			return fmt.Errorf("failed to parse repository name %s: %s", githubRepo.FullName, err)
+		}
+
+		log.Printf("Processing issues for %s/%s", owner, repoName)
+		repoTotals, err := w.ingestRepository(ctx, client, owner, repoName, githubRepo.ID, job.ProjectID, people)
+		totals.Issues += repoTotals.Issues
+		totals.Comments += repoTotals.Comments
+		if err != nil {
+			return fmt.Errorf("failed to fetch issues for %s/%s: %s", owner, repoName, err)
+		}
+	}
+
+	log.Printf("Issue job completed. Processed %d issues, %d comments", totals.Issues, totals.Comments)
+	return nil
+}
+
+// issueTotals counts what was stored for a repository
+type issueTotals struct {
+	Issues   int
+	Comments int
+}
+
+// ingestRepository fetches the issues of a repository that changed since the most recently updated stored
+// one, followed by the issue comments that changed since the newest stored comment. Close events are only
+// fetched for closed issues, as they are only used to tell who closed an issue.
+func (w *IssueWorker) ingestRepository(ctx context.Context, client *github.Client, owner, repo, repositoryID, projectID string, people map[int64]string) (issueTotals, error) {
+	var totals issueTotals
+
+	since, err := w.issueRepo.GetLatestUpdatedAtByRepositoryID(repositoryID)
+	if err != nil {
+		log.Printf("Warning: failed to get latest issue date for repository %s: %v", repositoryID, err)
+		since = time.Time{}
+	}
+
+	opts := &github.IssueListByRepoOptions{
+		State:       "all",
+		Sort:        "updated",
+		Direction:   "asc",
+		Since:       since,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		issues, resp, err := retryGitHubRequest(ctx, func() ([]*github.Issue, *github.Response, error) {
+			return client.Issues.ListByRepo(ctx, owner, repo, opts)
+		})
+		if err != nil {
+			return totals, err
+		}
+
+		for _, githubIssue := range issues {
+			// Pull requests are listed as issues too and are stored by pull request jobs
+			if githubIssue.IsPullRequest() {
+				continue
+			}
+			if err := w.processIssue(ctx, client, owner, repo, githubIssue, repositoryID, projectID, people); err != nil {
+				log.Printf("Failed to process issue #%d: %s", githubIssue.GetNumber(), err)
+				continue
+			}
+			totals.Issues++
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	comments, err := w.ingestComments(ctx, client, owner, repo, repositoryID, projectID, people)
+	totals.Comments = comments
+	return totals, err
+}
+
+// processIssue stores an issue with its labels and assignees, and the close events of closed issues
+func (w *IssueWorker) processIssue(ctx context.Context, client *github.Client, owner, repo string, githubIssue *github.Issue, repositoryID, projectID string, people map[int64]string) error {
+	issue := &models.Issue{
+		RepositoryID:    repositoryID,
+		GithubIssueID:   githubIssue.GetID(),
+		Number:          githubIssue.GetNumber(),
+		Title:           githubIssue.GetTitle(),
+		Body:            githubIssue.Body,
+		State:           githubIssue.GetState(),
+		StateReason:     githubIssue.StateReason,
+		HTMLURL:         githubIssue.HTMLURL,
+		GithubCreatedAt: timestampTime(githubIssue.CreatedAt),
+		GithubUpdatedAt: timestampTime(githubIssue.UpdatedAt),
+		ClosedAt:        timestampTime(githubIssue.ClosedAt),
+	}
+	if githubIssue.User != nil {
+		if personID, err := w.personID(githubIssue.User, client, projectID, people); err != nil {
+			log.Printf("Failed to process issue author %s: %s", githubIssue.User.GetLogin(), err)
+		} else {
+			issue.AuthorID = &personID
+		}
+	}
+	if githubIssue.ClosedBy != nil {
+		if personID, err := w.personID(githubIssue.ClosedBy, client, projectID, people); err == nil {
+			issue.ClosedByID = &personID
+		}
+	}
+
+	if err := w.issueRepo.Upsert(issue); err != nil {
+		return err
+	}
+
+	labels := make([]*models.IssueLabel, 0, len(githubIssue.Labels))
+	for _, label := range githubIssue.Labels {
+		labels = append(labels, &models.IssueLabel{Name: label.GetName(), Color: label.Color})
+	}
+	if err := w.issueRepo.ReplaceLabels(issue.ID, labels); err != nil {
+		log.Printf("Failed to store labels of issue #%d: %s", issue.Number, err)
+	}
+
+	assignees := make([]string, 0, len(githubIssue.Assignees))
+	for _, assignee := range githubIssue.Assignees {
+		personID, err := w.personID(assignee, client, projectID, people)
+		if err != nil {
+			log.Printf("Failed to process assignee %s: %s", assignee.GetLogin(), err)
+			continue
+		}
+		assignees = append(assignees, personID)
+	}
+	if err := w.issueRepo.ReplaceAssignees(issue.ID, assignees); err != nil {
+		log.Printf("Failed to store assignees of issue #%d: %s", issue.Number, err)
+	}
+
+	if issue.ClosedAt != nil {
+		if err := w.ingestEvents(ctx, client, owner, repo, issue, projectID, people); err != nil {
+			log.Printf("Failed to fetch events of issue #%d: %s", issue.Number, err)
+		}
+	}
+
+	return nil
+}
+
+// ingestEvents stores the close and reopen events of an issue and records who closed it last
+func (w *IssueWorker) ingestEvents(ctx context.Context, client *github.Client, owner, repo string, issue *models.Issue, projectID string, people map[int64]string) error {
+	var closedBy string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		events, resp, err := retryGitHubRequest(ctx, func() ([]*github.IssueEvent, *github.Response, error) {
+			return client.Issues.ListIssueEvents(ctx, owner, repo, issue.Number, opts)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, githubEvent := range events {
+			kind := githubEvent.GetEvent()
+			if (kind != models.IssueEventClosed && kind != models.IssueEventReopened) || githubEvent.CreatedAt == nil {
+				continue
+			}
+			event := &models.IssueEvent{
+				RepositoryID:  issue.RepositoryID,
+				IssueID:       issue.ID,
+				GithubEventID: githubEvent.GetID(),
+				Event:         kind,
+				OccurredAt:    githubEvent.CreatedAt.Time,
+			}
+			if githubEvent.Actor != nil {
+				if personID, err := w.personID(githubEvent.Actor, client, projectID, people); err == nil {
+					event.ActorID = &personID
+					if kind == models.IssueEventClosed {
+						closedBy = personID
+					}
+				}
+			}
+			if err := w.issueRepo.CreateEvent(event); err != nil {
+				log.Printf("Failed to store %s event of issue #%d: %s", kind, issue.Number, err)
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	if closedBy == "" {
+		return nil
+	}
+	return w.issueRepo.SetClosedBy(issue.ID, closedBy)
+}
+
+// ingestComments fetches the issue comments of a repository that changed since the newest stored one.
+// Comments on pull requests are listed too and skipped, they are stored by pull request jobs.
+func (w *IssueWorker) ingestComments(ctx context.Context, client *github.Client, owner, repo, repositoryID, projectID string, people map[int64]string) (int, error) {
+	since, err := w.issueCommentRepo.GetLatestUpdatedAtByRepositoryID(repositoryID)
+	if err != nil {
+		log.Printf("Warning: failed to get latest issue comment date for repository %s: %v", repositoryID, err)
+		since = time.Time{}
+	}
+
+	opts := &github.IssueListCommentsOptions{
+		Sort:        github.String("updated"),
+		Direction:   github.String("asc"),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	if !since.IsZero() {
+		opts.Since = &since
+	}
+
+	issueIDs := make(map[int]string)
+	issueID := func(number int) string {
+		if id, ok := issueIDs[number]; ok {
+			return id
+		}
+		issue, err := w.issueRepo.GetByRepositoryAndNumber(repositoryID, number)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				log.Printf("Failed to get issue #%d: %s", number, err)
+			}
+			issueIDs[number] = ""
+			return ""
+		}
+		issueIDs[number] = issue.ID
+		return issue.ID
+	}
+
+	total := 0
+	for {
+		comments, resp, err := retryGitHubRequest(ctx, func() ([]*github.IssueComment, *github.Response, error) {
+			return client.Issues.ListComments(ctx, owner, repo, 0, opts)
+		})
+		if err != nil {
+			return total, err
+		}
+
+		for _, comment := range comments {
+			number, ok := numberFromURL(comment.GetIssueURL())
+			if !ok {
+				continue
+			}
+			id := issueID(number)
+			if id == "" {
+				continue
+			}
+			if comment.User != nil {
+				if _, err := w.personID(comment.User, client, projectID, people); err != nil {
+					log.Printf("Failed to process comment author %s: %s", comment.User.GetLogin(), err)
+				}
+			}
+			err := w.issueCommentRepo.Upsert(&models.IssueComment{
+				RepositoryID:    repositoryID,
+				IssueID:         id,
+				GithubCommentID: comment.GetID(),
+				AuthorID:        comment.GetUser().GetID(),
+				AuthorLogin:     comment.GetUser().GetLogin(),
+				Body:            comment.Body,
+				HTMLURL:         comment.HTMLURL,
+				GithubCreatedAt: timestampTime(comment.CreatedAt),
+				GithubUpdatedAt: timestampTime(comment.UpdatedAt),
+			})
+			if err != nil {
+				log.Printf("Failed to store issue comment %d: %s", comment.GetID(), err)
+				continue
+			}
+			total++
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return total, nil
+}
+
+// personID returns the ID of a GitHub user, storing them as a person of the project first if needed.
+// Known people are not updated, as the users embedded in issues carry no name.
+func (w *IssueWorker) personID(user *github.User, client *github.Client, projectID string, people map[int64]string) (string, error) {
+	if id, ok := people[user.GetID()]; ok {
+		return id, nil
+	}
+
+	person, err := w.githubPersonService.GetGithubPersonByGithubUserID(int(user.GetID()))
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+	if err == sql.ErrNoRows {
+		person = &models.GithubPerson{
+			GithubUserID: int(user.GetID()),
+			Username:     user.GetLogin(),
+			AvatarURL:    user.AvatarURL,
+			ProfileURL:   user.HTMLURL,
+			Type:         user.Type,
+		}
+		if fullUser, _, err := client.Users.Get(context.Background(), user.GetLogin()); err == nil {
+			person.DisplayName = fullUser.Name
+		}
+		if err := w.githubPersonService.UpsertGithubPerson(person); err != nil {
+			return "", err
+		}
+	}
+
+	if err := w.projectGithubPersonService.CreateProjectGithubPerson(projectID, person.ID, "issue"); err != nil {
+		return "", err
+	}
+	people[user.GetID()] = person.ID
+	return person.ID, nil
+}
@@ -29,10 +29,23 @@ type WorkerManager struct {
 	githubPersonService        *services.GithubPersonService
 	peopleStatsService         *services.PeopleStatisticsService
 	githubClient               *github.Client
-	projectRepo                *repositories.ProjectRepository
-	userRepo                   *repositories.UserRepository
+	githubClientPool           *services.GitHubClientPool
 	projectGithubPersonService *services.ProjectGithubPersonService
 	pullRequestRepo            *repositories.PullRequestRepository
+	jobGitHubStatsRepo         *repositories.JobGitHubStatsRepository
+	projectRepo                *repositories.ProjectRepository
+	prReviewCommentRepo        *repositories.PRReviewCommentRepository
+	prIssueCommentRepo         *repositories.PRIssueCommentRepository
+	prDraftEventRepo           *repositories.PRDraftEventRepository
+	prCycleMetricsService      *services.PRCycleMetricsService
+	prFileRepo                 *repositories.PRFileRepository
+	prCommitRepo               *repositories.PRCommitRepository
+	githubTeamRepo             *repositories.GithubTeamRepository
+	prReviewRequestRepo        *repositories.PRReviewRequestRepository
+	issueRepo                  *repositories.IssueRepository
+	issueCommentRepo           *repositories.IssueCommentRepository
+	ciRunRepo                  *repositories.CIRunRepository
+	deploymentRepo             *repositories.DeploymentRepository
 	wg                         sync.WaitGroup
 	ctx                        context.Context
 	cancel                     context.CancelFunc
@@ -53,10 +66,23 @@ func NewWorkerManager(
 	githubPersonService *services.GithubPersonService,
 	peopleStatsService *services.PeopleStatisticsService,
 	githubClient *github.Client,
-	projectRepo *repositories.ProjectRepository,
-	userRepo *repositories.UserRepository,
+	githubClientPool *services.GitHubClientPool,
 	projectGithubPersonService *services.ProjectGithubPersonService,
 	pullRequestRepo *repositories.PullRequestRepository,
+	jobGitHubStatsRepo *repositories.JobGitHubStatsRepository,
+	projectRepo *repositories.ProjectRepository,
+	prReviewCommentRepo *repositories.PRReviewCommentRepository,
+	prIssueCommentRepo *repositories.PRIssueCommentRepository,
+	prDraftEventRepo *repositories.PRDraftEventRepository,
+	prCycleMetricsService *services.PRCycleMetricsService,
+	prFileRepo *repositories.PRFileRepository,
+	prCommitRepo *repositories.PRCommitRepository,
+	githubTeamRepo *repositories.GithubTeamRepository,
+	prReviewRequestRepo *repositories.PRReviewRequestRepository,
+	issueRepo *repositories.IssueRepository,
+	issueCommentRepo *repositories.IssueCommentRepository,
+	ciRunRepo *repositories.CIRunRepository,
+	deploymentRepo *repositories.DeploymentRepository,
 ) *WorkerManager {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &WorkerManager{
@@ -74,10 +100,23 @@ func NewWorkerManager(
 		githubPersonService:        githubPersonService,
 		peopleStatsService:         peopleStatsService,
 		githubClient:               githubClient,
-		projectRepo:                projectRepo,
-		userRepo:                   userRepo,
+		githubClientPool:           githubClientPool,
 		projectGithubPersonService: projectGithubPersonService,
 		pullRequestRepo:            pullRequestRepo,
+		jobGitHubStatsRepo:         jobGitHubStatsRepo,
+		projectRepo:                projectRepo,
+		prReviewCommentRepo:        prReviewCommentRepo,
+		prIssueCommentRepo:         prIssueCommentRepo,
+		prDraftEventRepo:           prDraftEventRepo,
+		prCycleMetricsService:      prCycleMetricsService,
+		prFileRepo:                 prFileRepo,
+		prCommitRepo:               prCommitRepo,
+		githubTeamRepo:             githubTeamRepo,
+		prReviewRequestRepo:        prReviewRequestRepo,
+		issueRepo:                  issueRepo,
+		issueCommentRepo:           issueCommentRepo,
+		ciRunRepo:                  ciRunRepo,
+		deploymentRepo:             deploymentRepo,
 		ctx:                        ctx,
 		cancel:                     cancel,
 	}
@@ -89,10 +128,13 @@ func (wm *WorkerManager) StartAll() error {
 	cloneWorkers := wm.getWorkerCount("CLONE_WORKERS", 2)
 	commitWorkers := wm.getWorkerCount("COMMIT_WORKERS", 2)
 	pullRequestWorkers := wm.getWorkerCount("PULL_REQUEST_WORKERS", 2)
+	issueWorkers := wm.getWorkerCount("ISSUE_WORKERS", 1)
+	ciWorkers := wm.getWorkerCount("CI_WORKERS", 1)
+	deploymentWorkers := wm.getWorkerCount("DEPLOYMENT_WORKERS", 1)
 	statsWorkers := wm.getWorkerCount("STATS_WORKERS", 1)
 
-	log.Printf("Starting workers - Clone: %d, Commit: %d, PullRequest: %d, Stats: %d",
-		cloneWorkers, commitWorkers, pullRequestWorkers, statsWorkers)
+	log.Printf("Starting workers - Clone: %d, Commit: %d, PullRequest: %d, Issue: %d, CI: %d, Deployment: %d, Stats: %d",
+		cloneWorkers, commitWorkers, pullRequestWorkers, issueWorkers, ciWorkers, deploymentWorkers, statsWorkers)
 
 	// Create and start clone workers
 	for i := 0; i < cloneWorkers; i++ {
@@ -119,10 +161,68 @@ func (wm *WorkerManager) StartAll() error {
 			wm.githubPersonService,
 			wm.githubRepoService,
 			wm.projectRepositoryRepo,
+			wm.githubClientPool,
+			wm.projectGithubPersonService,
+			wm.pullRequestRepo,
+			wm.jobGitHubStatsRepo,
 			wm.projectRepo,
-			wm.userRepo,
+			wm.prReviewCommentRepo,
+			wm.prIssueCommentRepo,
+			wm.prDraftEventRepo,
+			wm.prFileRepo,
+			wm.prCommitRepo,
+			wm.githubTeamRepo,
+			wm.prReviewRequestRepo,
+		)
+		wm.workers = append(wm.workers, worker)
+		wm.startWorker(worker)
+	}
+
+	// Create and start issue workers
+	for i := 0; i < issueWorkers; i++ {
+		worker := NewIssueWorker(
+			fmt.Sprintf("issue-%d", i+1),
+			wm.jobRepo,
+			wm.githubClientPool,
+			wm.githubRepoService,
+			wm.projectRepositoryRepo,
+			wm.githubPersonService,
 			wm.projectGithubPersonService,
+			wm.issueRepo,
+			wm.issueCommentRepo,
+			wm.jobGitHubStatsRepo,
+		)
+		wm.workers = append(wm.workers, worker)
+		wm.startWorker(worker)
+	}
+
+	// Create and start CI workers
+	for i := 0; i < ciWorkers; i++ {
+		worker := NewCIWorker(
+			fmt.Sprintf("ci-%d", i+1),
+			wm.jobRepo,
+			wm.githubClientPool,
+			wm.githubRepoService,
+			wm.projectRepositoryRepo,
 			wm.pullRequestRepo,
+			wm.commitRepo,
+			wm.ciRunRepo,
+			wm.jobGitHubStatsRepo,
+		)
+		wm.workers = append(wm.workers, worker)
+		wm.startWorker(worker)
+	}
+
+	// Create and start deployment workers
+	for i := 0; i < deploymentWorkers; i++ {
+		worker := NewDeploymentWorker(
+			fmt.Sprintf("deployment-%d", i+1),
+			wm.jobRepo,
+			wm.githubClientPool,
+			wm.githubRepoService,
+			wm.projectRepositoryRepo,
+			wm.deploymentRepo,
+			wm.jobGitHubStatsRepo,
 		)
 		wm.workers = append(wm.workers, worker)
 		wm.startWorker(worker)
@@ -130,7 +230,7 @@ func (wm *WorkerManager) StartAll() error {
 
 	// Create and start stats workers
 	for i := 0; i < statsWorkers; i++ {
-		worker := NewStatsWorker(fmt.Sprintf("stats-%d", i+1), wm.jobRepo, wm.peopleStatsService, wm.projectRepositoryRepo)
+		worker := NewStatsWorker(fmt.Sprintf("stats-%d", i+1), wm.jobRepo, wm.peopleStatsService, wm.projectRepositoryRepo, wm.prCycleMetricsService)
 		wm.workers = append(wm.workers, worker)
 		wm.startWorker(worker)
 	}
@@ -193,6 +293,12 @@ func (wm *WorkerManager) GetWorkerStatus() map[string]bool {
 			status[worker.GetWorkerID()] = commitWorker.IsRunning()
 		} else if pullRequestWorker, ok := worker.(*PullRequestWorker); ok {
 			status[worker.GetWorkerID()] = pullRequestWorker.IsRunning()
+		} else if issueWorker, ok := worker.(*IssueWorker); ok {
+			status[worker.GetWorkerID()] = issueWorker.IsRunning()
+		} else if ciWorker, ok := worker.(*CIWorker); ok {
+			status[worker.GetWorkerID()] = ciWorker.IsRunning()
+		} else if deploymentWorker, ok := worker.(*DeploymentWorker); ok {
+			status[worker.GetWorkerID()] = deploymentWorker.IsRunning()
 		} else if statsWorker, ok := worker.(*StatsWorker); ok {
 			status[worker.GetWorkerID()] = statsWorker.IsRunning()
 		} else {
@@ -0,0 +1,112 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// pullRequestBackfillBatch is how many pull requests stored without their size are completed per job
+const pullRequestBackfillBatch = 100
+
+// pullRequestBackfillRetryAfter is how long a pull request whose details failed to be fetched is left out
+// of the backfill, so that pull requests failing every time don't hold up the others
+const pullRequestBackfillRetryAfter = 7 * 24 * time.Hour
+
+// needsPullRequestDetails tells whether the files, commits and timeline of a listed pull request have to be
+// fetched: it isn't stored yet, it was stored without its size, or it changed on GitHub since it was stored
+func (w *PullRequestWorker) needsPullRequestDetails(repositoryID string, pr *github.PullRequest) bool {
+	stored, err := w.pullRequestRepo.GetByRepositoryAndNumber(repositoryID, pr.GetNumber())
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Failed to get pull request #%d: %s", pr.GetNumber(), err)
+		}
+		return true
+	}
+
+	if stored.Additions == nil || stored.GithubUpdatedAt == nil || pr.UpdatedAt == nil {
+		return true
+	}
+	return !stored.GithubUpdatedAt.Equal(pr.UpdatedAt.Time)
+}
+
+// backfillPullRequestDetails fetches the size, merger and draft transitions of pull requests stored before
+// they were fetched. Those pull requests are not listed again once closed, so without it the reports on
+// size, draft time and mergers would leave out history.
+func (w *PullRequestWorker) backfillPullRequestDetails(ctx context.Context, client *github.Client, owner, repo, repositoryID, projectID string) {
+	pullRequests, err := w.pullRequestRepo.GetMissingDetailsByRepositoryID(repositoryID, time.Now().Add(-pullRequestBackfillRetryAfter), pullRequestBackfillBatch)
+	if err != nil {
+		log.Printf("Failed to get pull requests to backfill for %s/%s: %s", owner, repo, err)
+		return
+	}
+	if len(pullRequests) == 0 {
+		return
+	}
+
+	log.Printf("Backfilling size, merger and draft events of %d pull requests for %s/%s", len(pullRequests), owner, repo)
+
+	for _, pr := range pullRequests {
+		if ctx.Err() != nil {
+			return
+		}
+
+		githubPR := &github.PullRequest{Number: github.Int(pr.GithubPRNumber)}
+		if err := w.fetchPullRequestSizeREST(ctx, client, owner, repo, githubPR); err != nil {
+			log.Printf("Failed to fetch size of PR #%d: %s", pr.GithubPRNumber, err)
+			w.markPullRequestDetailsFailed(pr.ID)
+			continue
+		}
+
+		contents, err := w.fetchPullRequestContentsREST(ctx, client, owner, repo, pr.GithubPRNumber)
+		if err != nil {
+			log.Printf("Failed to fetch files and commits for PR #%d: %s", pr.GithubPRNumber, err)
+			w.markPullRequestDetailsFailed(pr.ID)
+			continue
+		}
+
+		timeline, err := w.fetchTimelineREST(ctx, client, owner, repo, pr.GithubPRNumber)
+		if err != nil {
+			log.Printf("Failed to fetch timeline for PR #%d: %s", pr.GithubPRNumber, err)
+			w.markPullRequestDetailsFailed(pr.ID)
+			continue
+		}
+
+		if pr.MergedAt != nil && pr.MergedByID == nil {
+			merger := githubPR.MergedBy
+			if merger == nil {
+				merger = mergedByFromTimeline(timeline)
+			}
+			if merger != nil {
+				personID, err := w.processGithubPerson(merger, client, projectID, "pull_request")
+				if err != nil {
+					log.Printf("Failed to process PR merger: %s", err)
+				} else {
+					pr.MergedByID = &personID
+				}
+			}
+		}
+
+		pr.Additions = githubPR.Additions
+		pr.Deletions = githubPR.Deletions
+		pr.ChangedFiles = githubPR.ChangedFiles
+		pr.CommitCount = githubPR.Commits
+		if err := w.pullRequestRepo.UpdateDetails(pr); err != nil {
+			log.Printf("Failed to store size of PR #%d: %s", pr.GithubPRNumber, err)
+			continue
+		}
+
+		w.storePullRequestContents(contents, repositoryID, pr.ID)
+		w.storeDraftEvents(draftEventsFromTimeline(timeline, repositoryID, pr.ID))
+	}
+}
+
+// markPullRequestDetailsFailed records a failed attempt to complete a pull request, which keeps it out of
+// the backfill for pullRequestBackfillRetryAfter
+func (w *PullRequestWorker) markPullRequestDetailsFailed(pullRequestID string) {
+	if err := w.pullRequestRepo.MarkDetailsFailed(pullRequestID, time.Now()); err != nil {
+		log.Printf("Failed to record failed details of pull request %s: %s", pullRequestID, err)
+	}
+}
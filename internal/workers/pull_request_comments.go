@@ -0,0 +1,266 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/go-github/v57/github"
+)
+
+// pullRequestLookup resolves pull request numbers of a repository to stored pull requests, caching the results
+type pullRequestLookup struct {
+	w            *PullRequestWorker
+	repositoryID string
+	ids          map[int]string
+}
+
+func (w *PullRequestWorker) newPullRequestLookup(repositoryID string) *pullRequestLookup {
+	return &pullRequestLookup{w: w, repositoryID: repositoryID, ids: make(map[int]string)}
+}
+
+// id returns the stored ID of a pull request, or "" when it isn't stored (e.g. the number belongs to an issue)
+func (l *pullRequestLookup) id(number int) string {
+	if id, ok := l.ids[number]; ok {
+		return id
+	}
+
+	pr, err := l.w.pullRequestRepo.GetByRepositoryAndNumber(l.repositoryID, number)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Failed to get pull request #%d: %s", number, err)
+		}
+		l.ids[number] = ""
+		return ""
+	}
+
+	l.ids[number] = pr.ID
+	return pr.ID
+}
+
+// ingestRepositoryCommentsREST fetches the review and conversation comments of a repository that
+// changed since the newest stored ones. Listing a whole repository takes a few pages instead of
+// two requests per pull request.
+func (w *PullRequestWorker) ingestRepositoryCommentsREST(ctx context.Context, client *github.Client, owner, repo, repositoryID, projectID string) (int, error) {
+	lookup := w.newPullRequestLookup(repositoryID)
+	people := make(map[int64]bool)
+	total := 0
+
+	since, err := w.prReviewCommentRepo.GetLatestUpdatedAtByRepositoryID(repositoryID)
+	if err != nil {
+		log.Printf("Warning: failed to get latest review comment date for repository %s: %v", repositoryID, err)
+		since = time.Time{}
+	}
+
+	reviewOpts := &github.PullRequestListCommentsOptions{
+		Sort:        "updated",
+		Direction:   "asc",
+		Since:       since,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		comments, resp, err := retryGitHubRequest(ctx, func() ([]*github.PullRequestComment, *github.Response, error) {
+			return client.PullRequests.ListComments(ctx, owner, repo, 0, reviewOpts)
+		})
+		if err != nil {
+			return total, err
+		}
+
+		for _, comment := range comments {
+			number, ok := numberFromURL(comment.GetPullRequestURL())
+			if !ok {
+				continue
+			}
+			pullRequestID := lookup.id(number)
+			if pullRequestID == "" {
+				continue
+			}
+			w.processCommentAuthor(comment.User, client, projectID, people)
+			if err := w.prReviewCommentRepo.Upsert(reviewCommentFromREST(comment, repositoryID, pullRequestID)); err != nil {
+				log.Printf("Failed to store review comment %d: %s", comment.GetID(), err)
+				continue
+			}
+			total++
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		reviewOpts.Page = resp.NextPage
+	}
+
+	since, err = w.prIssueCommentRepo.GetLatestUpdatedAtByRepositoryID(repositoryID)
+	if err != nil {
+		log.Printf("Warning: failed to get latest conversation comment date for repository %s: %v", repositoryID, err)
+		since = time.Time{}
+	}
+
+	issueOpts := &github.IssueListCommentsOptions{
+		Sort:        github.String("updated"),
+		Direction:   github.String("asc"),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	if !since.IsZero() {
+		issueOpts.Since = &since
+	}
+	for {
+		comments, resp, err := retryGitHubRequest(ctx, func() ([]*github.IssueComment, *github.Response, error) {
+			return client.Issues.ListComments(ctx, owner, repo, 0, issueOpts)
+		})
+		if err != nil {
+			return total, err
+		}
+
+		for _, comment := range comments {
+			// Comments on plain issues have no stored pull request and are skipped
+			number, ok := numberFromURL(comment.GetIssueURL())
+			if !ok {
+				continue
+			}
+			pullRequestID := lookup.id(number)
+			if pullRequestID == "" {
+				continue
+			}
+			w.processCommentAuthor(comment.User, client, projectID, people)
+			if err := w.prIssueCommentRepo.Upsert(issueCommentFromREST(comment, repositoryID, pullRequestID)); err != nil {
+				log.Printf("Failed to store conversation comment %d: %s", comment.GetID(), err)
+				continue
+			}
+			total++
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		issueOpts.Page = resp.NextPage
+	}
+
+	return total, nil
+}
+
+// ingestPullRequestCommentsREST fetches all review and conversation comments of a single pull request
+func (w *PullRequestWorker) ingestPullRequestCommentsREST(ctx context.Context, client *github.Client, owner, repo string, number int, repositoryID, pullRequestID, projectID string) (int, error) {
+	people := make(map[int64]bool)
+	total := 0
+
+	reviewOpts := &github.PullRequestListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := retryGitHubRequest(ctx, func() ([]*github.PullRequestComment, *github.Response, error) {
+			return client.PullRequests.ListComments(ctx, owner, repo, number, reviewOpts)
+		})
+		if err != nil {
+			return total, err
+		}
+		for _, comment := range comments {
+			w.processCommentAuthor(comment.User, client, projectID, people)
+			if err := w.prReviewCommentRepo.Upsert(reviewCommentFromREST(comment, repositoryID, pullRequestID)); err != nil {
+				log.Printf("Failed to store review comment %d: %s", comment.GetID(), err)
+				continue
+			}
+			total++
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		reviewOpts.Page = resp.NextPage
+	}
+
+	issueOpts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		comments, resp, err := retryGitHubRequest(ctx, func() ([]*github.IssueComment, *github.Response, error) {
+			return client.Issues.ListComments(ctx, owner, repo, number, issueOpts)
+		})
+		if err != nil {
+			return total, err
+		}
+		for _, comment := range comments {
+			w.processCommentAuthor(comment.User, client, projectID, people)
+			if err := w.prIssueCommentRepo.Upsert(issueCommentFromREST(comment, repositoryID, pullRequestID)); err != nil {
+				log.Printf("Failed to store conversation comment %d: %s", comment.GetID(), err)
+				continue
+			}
+			total++
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		issueOpts.Page = resp.NextPage
+	}
+
+	return total, nil
+}
+
+// processCommentAuthor stores a comment author once per run
+func (w *PullRequestWorker) processCommentAuthor(user *github.User, client *github.Client, projectID string, seen map[int64]bool) {
+	if user == nil || seen[user.GetID()] {
+		return
+	}
+	seen[user.GetID()] = true
+	if _, err := w.processGithubPerson(user, client, projectID, "pull_request"); err != nil {
+		log.Printf("Failed to process comment author %s: %s", user.GetLogin(), err)
+	}
+}
+
+// reviewCommentFromREST converts an inline review comment to our model
+func reviewCommentFromREST(comment *github.PullRequestComment, repositoryID, pullRequestID string) *models.PRReviewComment {
+	return &models.PRReviewComment{
+		RepositoryID:      repositoryID,
+		PullRequestID:     pullRequestID,
+		GithubCommentID:   comment.GetID(),
+		GithubReviewID:    comment.PullRequestReviewID,
+		InReplyToGithubID: comment.InReplyTo,
+		AuthorID:          comment.GetUser().GetID(),
+		AuthorLogin:       comment.GetUser().GetLogin(),
+		Body:              comment.Body,
+		Path:              comment.Path,
+		Line:              comment.Line,
+		OriginalLine:      comment.OriginalLine,
+		StartLine:         comment.StartLine,
+		Side:              comment.Side,
+		CommitID:          comment.CommitID,
+		HTMLURL:           comment.HTMLURL,
+		GithubCreatedAt:   timestampTime(comment.CreatedAt),
+		GithubUpdatedAt:   timestampTime(comment.UpdatedAt),
+	}
+}
+
+// issueCommentFromREST converts a conversation comment to our model
+func issueCommentFromREST(comment *github.IssueComment, repositoryID, pullRequestID string) *models.PRIssueComment {
+	return &models.PRIssueComment{
+		RepositoryID:    repositoryID,
+		PullRequestID:   pullRequestID,
+		GithubCommentID: comment.GetID(),
+		AuthorID:        comment.GetUser().GetID(),
+		AuthorLogin:     comment.GetUser().GetLogin(),
+		Body:            comment.Body,
+		HTMLURL:         comment.HTMLURL,
+		GithubCreatedAt: timestampTime(comment.CreatedAt),
+		GithubUpdatedAt: timestampTime(comment.UpdatedAt),
+	}
+}
+
+// timestampTime returns the time of a GitHub timestamp, or nil when it is missing
+func timestampTime(ts *github.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.Time
+	return &t
+}
+
+// numberFromURL returns the trailing pull request or issue number of an API URL
+func numberFromURL(url string) (int, bool) {
+	slash := strings.LastIndex(url, "/")
+	if slash == -1 {
+		return 0, false
+	}
+	number, err := strconv.Atoi(url[slash+1:])
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
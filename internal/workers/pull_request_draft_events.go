@@ -0,0 +1,78 @@
+package workers
+
+import (
+	"context"
+	"log"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/go-github/v57/github"
+)
+
+// fetchDraftEventsREST lists the transitions between draft and ready for review on the timeline of a pull request
+func (w *PullRequestWorker) fetchDraftEventsREST(ctx context.Context, client *github.Client, owner, repo string, number int, repositoryID, pullRequestID string) ([]*models.PRDraftEvent, error) {
+	timeline, err := w.fetchTimelineREST(ctx, client, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return draftEventsFromTimeline(timeline, repositoryID, pullRequestID), nil
+}
+
+// fetchTimelineREST lists the timeline of a pull request
+func (w *PullRequestWorker) fetchTimelineREST(ctx context.Context, client *github.Client, owner, repo string, number int) ([]*github.Timeline, error) {
+	var timeline []*github.Timeline
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		items, resp, err := retryGitHubRequest(ctx, func() ([]*github.Timeline, *github.Response, error) {
+			return client.Issues.ListIssueTimeline(ctx, owner, repo, number, opts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		timeline = append(timeline, items...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return timeline, nil
+}
+
+// draftEventsFromTimeline picks the transitions between draft and ready for review from a pull request timeline
+func draftEventsFromTimeline(timeline []*github.Timeline, repositoryID, pullRequestID string) []*models.PRDraftEvent {
+	var events []*models.PRDraftEvent
+	for _, item := range timeline {
+		eventType := item.GetEvent()
+		if item.CreatedAt == nil || (eventType != models.PRDraftEventReadyForReview && eventType != models.PRDraftEventConvertToDraft) {
+			continue
+		}
+		events = append(events, &models.PRDraftEvent{
+			RepositoryID:  repositoryID,
+			PullRequestID: pullRequestID,
+			EventType:     eventType,
+			OccurredAt:    item.CreatedAt.Time,
+		})
+	}
+	return events
+}
+
+// mergedByFromTimeline returns who merged a pull request, which listed pull requests don't carry
+func mergedByFromTimeline(timeline []*github.Timeline) *github.User {
+	for _, item := range timeline {
+		if item.GetEvent() == "merged" {
+			return item.Actor
+		}
+	}
+	return nil
+}
+
+// storeDraftEvents stores the draft events of a pull request, skipping the ones already stored
+func (w *PullRequestWorker) storeDraftEvents(events []*models.PRDraftEvent) {
+	for _, event := range events {
+		if err := w.prDraftEventRepo.Create(event); err != nil {
+			log.Printf("Failed to store %s event of pull request %s: %s", event.EventType, event.PullRequestID, err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package workers
+
+import (
+	"context"
+	"log"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/google/go-github/v57/github"
+)
+
+// pullRequestContents holds the changed files and commits of a pull request
+type pullRequestContents struct {
+	files   []*models.PRFile
+	commits []*models.PRCommit
+}
+
+// fetchPullRequestSizeREST sets the size of a listed pull request, and who merged it, from the pull request
+// itself. Its counts aren't capped like the lists of files and commits.
+func (w *PullRequestWorker) fetchPullRequestSizeREST(ctx context.Context, client *github.Client, owner, repo string, githubPR *github.PullRequest) error {
+	full, _, err := retryGitHubRequest(ctx, func() (*github.PullRequest, *github.Response, error) {
+		return client.PullRequests.Get(ctx, owner, repo, githubPR.GetNumber())
+	})
+	if err != nil {
+		return err
+	}
+
+	githubPR.Additions = github.Int(full.GetAdditions())
+	githubPR.Deletions = github.Int(full.GetDeletions())
+	githubPR.ChangedFiles = github.Int(full.GetChangedFiles())
+	githubPR.Commits = github.Int(full.GetCommits())
+	if githubPR.MergedBy == nil {
+		githubPR.MergedBy = full.MergedBy
+	}
+	return nil
+}
+
+// fetchPullRequestContentsREST fetches the changed files and commits of a pull request. GitHub lists at most
+// 3000 files and 250 commits.
+func (w *PullRequestWorker) fetchPullRequestContentsREST(ctx context.Context, client *github.Client, owner, repo string, number int) (*pullRequestContents, error) {
+	contents := &pullRequestContents{}
+
+	fileOpts := &github.ListOptions{PerPage: 100}
+	for {
+		files, resp, err := retryGitHubRequest(ctx, func() ([]*github.CommitFile, *github.Response, error) {
+			return client.PullRequests.ListFiles(ctx, owner, repo, number, fileOpts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			contents.files = append(contents.files, &models.PRFile{
+				Filename:         file.GetFilename(),
+				PreviousFilename: file.PreviousFilename,
+				Status:           file.GetStatus(),
+				Additions:        file.GetAdditions(),
+				Deletions:        file.GetDeletions(),
+				Changes:          file.GetChanges(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		fileOpts.Page = resp.NextPage
+	}
+
+	commitOpts := &github.ListOptions{PerPage: 100}
+	for {
+		commits, resp, err := retryGitHubRequest(ctx, func() ([]*github.RepositoryCommit, *github.Response, error) {
+			return client.PullRequests.ListCommits(ctx, owner, repo, number, commitOpts)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, commit := range commits {
+			prCommit := &models.PRCommit{CommitSHA: commit.GetSHA()}
+			if commit.Author != nil {
+				prCommit.AuthorLogin = commit.Author.Login
+			}
+			if date := commit.GetCommit().GetAuthor().Date; date != nil {
+				prCommit.AuthoredAt = &date.Time
+			}
+			contents.commits = append(contents.commits, prCommit)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		commitOpts.Page = resp.NextPage
+	}
+
+	return contents, nil
+}
+
+// storePullRequestContents replaces the stored changed files and commits of a pull request
+func (w *PullRequestWorker) storePullRequestContents(contents *pullRequestContents, repositoryID, pullRequestID string) {
+	if contents == nil {
+		return
+	}
+
+	for _, file := range contents.files {
+		file.RepositoryID = repositoryID
+		file.PullRequestID = pullRequestID
+	}
+	if err := w.prFileRepo.ReplaceByPullRequestID(pullRequestID, contents.files); err != nil {
+		log.Printf("Failed to store files of pull request %s: %s", pullRequestID, err)
+	}
+
+	for _, commit := range contents.commits {
+		commit.RepositoryID = repositoryID
+		commit.PullRequestID = pullRequestID
+	}
+	if err := w.prCommitRepo.ReplaceByPullRequestID(pullRequestID, contents.commits); err != nil {
+		log.Printf("Failed to store commits of pull request %s: %s", pullRequestID, err)
+	}
+}
@@ -0,0 +1,726 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alimgiray/gscope/internal/models"
+	"github.com/alimgiray/gscope/internal/services"
+	"github.com/google/go-github/v57/github"
+)
+
+// graphQLPullRequestPageSize keeps a page of pull requests with their nested
+// reviews, comments and commits well below GitHub's node and timeout limits
+const graphQLPullRequestPageSize = 25
+
+// pullRequestsQuery fetches a page of pull requests together with their size, changed files, commits,
+// reviews, review and conversation comments, draft transitions, requested reviewers and merger
+const pullRequestsQuery = `
+query($owner: String!, $name: String!, $states: [PullRequestState!], $first: Int!, $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequests(first: $first, after: $cursor, states: $states, orderBy: {field: CREATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes { ...pullRequest }
+    }
+  }
+}
+` + pullRequestFragments
+
+// pullRequestQuery fetches a single pull request with the same fields as pullRequestsQuery
+const pullRequestQuery = `
+query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) { ...pullRequest }
+  }
+}
+` + pullRequestFragments
+
+const pullRequestFragments = `
+fragment pullRequest on PullRequest {
+  databaseId
+  number
+  title
+  body
+  state
+  isDraft
+  additions
+  deletions
+  changedFiles
+  createdAt
+  updatedAt
+  mergedAt
+  closedAt
+  mergeCommit { oid }
+  headRefOid
+  labels(first: 20) { nodes { name color } }
+  author { ...actor }
+  mergedBy { ...actor }
+  reviewRequests(first: 20) {
+    nodes {
+      requestedReviewer {
+        __typename
+        ... on User { databaseId login name avatarUrl url }
+        ... on Team { databaseId name slug url }
+      }
+    }
+  }
+  reviews(first: 50) {
+    pageInfo { hasNextPage }
+    nodes {
+      databaseId
+      state
+      body
+      submittedAt
+      url
+      authorAssociation
+      commit { oid }
+      author { ...actor }
+      comments(first: 30) {
+        pageInfo { hasNextPage }
+        nodes {
+          databaseId
+          body
+          path
+          line
+          originalLine
+          startLine
+          url
+          createdAt
+          updatedAt
+          replyTo { databaseId }
+          commit { oid }
+          author { ...actor }
+        }
+      }
+    }
+  }
+  comments(first: 50) {
+    pageInfo { hasNextPage }
+    nodes { databaseId body url createdAt updatedAt author { ...actor } }
+  }
+  commits(first: 100) {
+    totalCount
+    pageInfo { hasNextPage }
+    nodes { commit { oid authoredDate author { user { databaseId login name avatarUrl url } } } }
+  }
+  files(first: 100) {
+    pageInfo { hasNextPage }
+    nodes { path additions deletions changeType }
+  }
+  timelineItems(first: 50, itemTypes: [READY_FOR_REVIEW_EVENT, CONVERT_TO_DRAFT_EVENT]) {
+    pageInfo { hasNextPage }
+    nodes {
+      __typename
+      ... on ReadyForReviewEvent { createdAt }
+      ... on ConvertToDraftEvent { createdAt }
+    }
+  }
+}
+
+fragment actor on Actor {
+  __typename
+  login
+  avatarUrl
+  url
+  ... on User { databaseId name }
+  ... on Bot { databaseId }
+  ... on Mannequin { databaseId }
+}`
+
+type graphQLActor struct {
+	Typename   string  `json:"__typename"`
+	DatabaseID int64   `json:"databaseId"`
+	Login      string  `json:"login"`
+	Name       *string `json:"name"`
+	AvatarURL  string  `json:"avatarUrl"`
+	URL        string  `json:"url"`
+}
+
+type graphQLReview struct {
+	DatabaseID        int64                 `json:"databaseId"`
+	State             string                `json:"state"`
+	Body              string                `json:"body"`
+	SubmittedAt       *time.Time            `json:"submittedAt"`
+	URL               string                `json:"url"`
+	AuthorAssociation string                `json:"authorAssociation"`
+	Commit            *struct{ Oid string } `json:"commit"`
+	Author            *graphQLActor         `json:"author"`
+	Comments          struct {
+		PageInfo struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Nodes []graphQLReviewComment `json:"nodes"`
+	} `json:"comments"`
+}
+
+type graphQLReviewComment struct {
+	DatabaseID   int64                       `json:"databaseId"`
+	Body         string                      `json:"body"`
+	Path         *string                     `json:"path"`
+	Line         *int                        `json:"line"`
+	OriginalLine *int                        `json:"originalLine"`
+	StartLine    *int                        `json:"startLine"`
+	URL          string                      `json:"url"`
+	CreatedAt    time.Time                   `json:"createdAt"`
+	UpdatedAt    time.Time                   `json:"updatedAt"`
+	ReplyTo      *struct{ DatabaseID int64 } `json:"replyTo"`
+	Commit       *struct{ Oid string }       `json:"commit"`
+	Author       *graphQLActor               `json:"author"`
+}
+
+type graphQLIssueComment struct {
+	DatabaseID int64         `json:"databaseId"`
+	Body       string        `json:"body"`
+	URL        string        `json:"url"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	UpdatedAt  time.Time     `json:"updatedAt"`
+	Author     *graphQLActor `json:"author"`
+}
+
+type graphQLPullRequest struct {
+	DatabaseID  int64                 `json:"databaseId"`
+	Number      int                   `json:"number"`
+	Title       string                `json:"title"`
+	Body        string                `json:"body"`
+	State       string                `json:"state"`
+	IsDraft     bool                  `json:"isDraft"`
+	CreatedAt   time.Time             `json:"createdAt"`
+	UpdatedAt   time.Time             `json:"updatedAt"`
+	MergedAt    *time.Time            `json:"mergedAt"`
+	ClosedAt    *time.Time            `json:"closedAt"`
+	MergeCommit *struct{ Oid string } `json:"mergeCommit"`
+	HeadRefOid  string                `json:"headRefOid"`
+	Labels      struct {
+		Nodes []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Author         *graphQLActor `json:"author"`
+	MergedBy       *graphQLActor `json:"mergedBy"`
+	ReviewRequests struct {
+		Nodes []struct {
+			RequestedReviewer *struct {
+				Typename   string  `json:"__typename"`
+				DatabaseID int64   `json:"databaseId"`
+				Login      string  `json:"login"`
+				Name       *string `json:"name"`
+				Slug       string  `json:"slug"`
+				AvatarURL  string  `json:"avatarUrl"`
+				URL        string  `json:"url"`
+			} `json:"requestedReviewer"`
+		} `json:"nodes"`
+	} `json:"reviewRequests"`
+	Reviews struct {
+		PageInfo struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Nodes []graphQLReview `json:"nodes"`
+	} `json:"reviews"`
+	Comments struct {
+		PageInfo struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Nodes []graphQLIssueComment `json:"nodes"`
+	} `json:"comments"`
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ChangedFiles int `json:"changedFiles"`
+	Commits      struct {
+		TotalCount int `json:"totalCount"`
+		PageInfo   struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Nodes []struct {
+			Commit struct {
+				Oid          string     `json:"oid"`
+				AuthoredDate *time.Time `json:"authoredDate"`
+				Author       struct {
+					User *graphQLActor `json:"user"`
+				} `json:"author"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+	Files struct {
+		PageInfo struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Nodes []struct {
+			Path       string `json:"path"`
+			Additions  int    `json:"additions"`
+			Deletions  int    `json:"deletions"`
+			ChangeType string `json:"changeType"`
+		} `json:"nodes"`
+	} `json:"files"`
+	TimelineItems struct {
+		PageInfo struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Nodes []struct {
+			Typename  string    `json:"__typename"`
+			CreatedAt time.Time `json:"createdAt"`
+		} `json:"nodes"`
+	} `json:"timelineItems"`
+}
+
+type pullRequestsQueryResult struct {
+	Repository *struct {
+		PullRequests struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []*graphQLPullRequest `json:"nodes"`
+		} `json:"pullRequests"`
+	} `json:"repository"`
+}
+
+type pullRequestQueryResult struct {
+	Repository *struct {
+		PullRequest *graphQLPullRequest `json:"pullRequest"`
+	} `json:"repository"`
+}
+
+// toGitHubUser converts an actor to the REST shape the worker stores. Deleted accounts are nil.
+func (a *graphQLActor) toGitHubUser() *github.User {
+	if a == nil || a.Login == "" {
+		return nil
+	}
+	user := &github.User{
+		ID:        github.Int64(a.DatabaseID),
+		Login:     github.String(a.Login),
+		Name:      a.Name,
+		AvatarURL: github.String(a.AvatarURL),
+		HTMLURL:   github.String(a.URL),
+		Type:      github.String(a.Typename),
+	}
+	return user
+}
+
+// toGitHubPullRequest converts a pull request to the REST shape so both ingestion paths store the same data
+func (pr *graphQLPullRequest) toGitHubPullRequest() *github.PullRequest {
+	// REST reports merged pull requests as closed
+	state := "open"
+	if pr.State != "OPEN" {
+		state = "closed"
+	}
+
+	githubPR := &github.PullRequest{
+		ID:           github.Int64(pr.DatabaseID),
+		Number:       github.Int(pr.Number),
+		Title:        github.String(pr.Title),
+		Body:         github.String(pr.Body),
+		State:        github.String(state),
+		Draft:        github.Bool(pr.IsDraft),
+		Additions:    github.Int(pr.Additions),
+		Deletions:    github.Int(pr.Deletions),
+		ChangedFiles: github.Int(pr.ChangedFiles),
+		Commits:      github.Int(pr.Commits.TotalCount),
+		CreatedAt:    &github.Timestamp{Time: pr.CreatedAt},
+		UpdatedAt:    &github.Timestamp{Time: pr.UpdatedAt},
+		User:         pr.Author.toGitHubUser(),
+		MergedBy:     pr.MergedBy.toGitHubUser(),
+	}
+	if pr.MergedAt != nil {
+		githubPR.MergedAt = &github.Timestamp{Time: *pr.MergedAt}
+	}
+	if pr.ClosedAt != nil {
+		githubPR.ClosedAt = &github.Timestamp{Time: *pr.ClosedAt}
+	}
+	if pr.MergeCommit != nil {
+		githubPR.MergeCommitSHA = github.String(pr.MergeCommit.Oid)
+	}
+	if pr.HeadRefOid != "" {
+		githubPR.Head = &github.PullRequestBranch{SHA: github.String(pr.HeadRefOid)}
+	}
+	for _, label := range pr.Labels.Nodes {
+		githubPR.Labels = append(githubPR.Labels, &github.Label{Name: github.String(label.Name), Color: github.String(label.Color)})
+	}
+
+	for _, node := range pr.ReviewRequests.Nodes {
+		reviewer := node.RequestedReviewer
+		if reviewer == nil {
+			continue
+		}
+		switch reviewer.Typename {
+		case "User":
+			githubPR.RequestedReviewers = append(githubPR.RequestedReviewers, &github.User{
+				ID:        github.Int64(reviewer.DatabaseID),
+				Login:     github.String(reviewer.Login),
+				Name:      reviewer.Name,
+				AvatarURL: github.String(reviewer.AvatarURL),
+				HTMLURL:   github.String(reviewer.URL),
+			})
+		case "Team":
+			githubPR.RequestedTeams = append(githubPR.RequestedTeams, &github.Team{
+				ID:      github.Int64(reviewer.DatabaseID),
+				Name:    reviewer.Name,
+				Slug:    github.String(reviewer.Slug),
+				HTMLURL: github.String(reviewer.URL),
+			})
+		}
+	}
+
+	return githubPR
+}
+
+// toGitHubReview converts a review to the REST shape
+func (r *graphQLReview) toGitHubReview() *github.PullRequestReview {
+	review := &github.PullRequestReview{
+		ID:                github.Int64(r.DatabaseID),
+		User:              r.Author.toGitHubUser(),
+		Body:              github.String(r.Body),
+		State:             github.String(r.State),
+		HTMLURL:           github.String(r.URL),
+		AuthorAssociation: github.String(r.AuthorAssociation),
+	}
+	if r.SubmittedAt != nil {
+		review.SubmittedAt = &github.Timestamp{Time: *r.SubmittedAt}
+	}
+	if r.Commit != nil {
+		review.CommitID = github.String(r.Commit.Oid)
+	}
+	return review
+}
+
+// hasMoreComments reports whether the pull request has comments beyond the pages included in the query
+func (pr *graphQLPullRequest) hasMoreComments() bool {
+	if pr.Comments.PageInfo.HasNextPage || pr.Reviews.PageInfo.HasNextPage {
+		return true
+	}
+	for _, review := range pr.Reviews.Nodes {
+		if review.Comments.PageInfo.HasNextPage {
+			return true
+		}
+	}
+	return false
+}
+
+// contents converts the changed files and commits of the pull request to our model
+func (pr *graphQLPullRequest) contents() *pullRequestContents {
+	contents := &pullRequestContents{}
+	for _, node := range pr.Files.Nodes {
+		// REST names deleted files "removed"
+		status := strings.ToLower(node.ChangeType)
+		if status == "deleted" {
+			status = "removed"
+		}
+		contents.files = append(contents.files, &models.PRFile{
+			Filename:  node.Path,
+			Status:    status,
+			Additions: node.Additions,
+			Deletions: node.Deletions,
+			Changes:   node.Additions + node.Deletions,
+		})
+	}
+	for _, node := range pr.Commits.Nodes {
+		commit := &models.PRCommit{
+			CommitSHA:  node.Commit.Oid,
+			AuthoredAt: node.Commit.AuthoredDate,
+		}
+		if user := node.Commit.Author.User; user != nil {
+			commit.AuthorLogin = github.String(user.Login)
+		}
+		contents.commits = append(contents.commits, commit)
+	}
+	return contents
+}
+
+// draftEvents converts the draft transitions of the pull request to our model
+func (pr *graphQLPullRequest) draftEvents(repositoryID, pullRequestID string) []*models.PRDraftEvent {
+	var events []*models.PRDraftEvent
+	for _, node := range pr.TimelineItems.Nodes {
+		eventType := models.PRDraftEventReadyForReview
+		if node.Typename == "ConvertToDraftEvent" {
+			eventType = models.PRDraftEventConvertToDraft
+		}
+		events = append(events, &models.PRDraftEvent{
+			RepositoryID:  repositoryID,
+			PullRequestID: pullRequestID,
+			EventType:     eventType,
+			OccurredAt:    node.CreatedAt,
+		})
+	}
+	return events
+}
+
+// toModel converts an inline review comment to our model
+func (c *graphQLReviewComment) toModel(repositoryID, pullRequestID string, reviewID int64) *models.PRReviewComment {
+	author := c.Author.toGitHubUser()
+	comment := &models.PRReviewComment{
+		RepositoryID:    repositoryID,
+		PullRequestID:   pullRequestID,
+		GithubCommentID: c.DatabaseID,
+		GithubReviewID:  &reviewID,
+		AuthorID:        author.GetID(),
+		AuthorLogin:     author.GetLogin(),
+		Body:            github.String(c.Body),
+		Path:            c.Path,
+		Line:            c.Line,
+		OriginalLine:    c.OriginalLine,
+		StartLine:       c.StartLine,
+		HTMLURL:         github.String(c.URL),
+		GithubCreatedAt: &c.CreatedAt,
+		GithubUpdatedAt: &c.UpdatedAt,
+	}
+	if c.ReplyTo != nil {
+		comment.InReplyToGithubID = &c.ReplyTo.DatabaseID
+	}
+	if c.Commit != nil {
+		comment.CommitID = github.String(c.Commit.Oid)
+	}
+	return comment
+}
+
+// toModel converts a conversation comment to our model
+func (c *graphQLIssueComment) toModel(repositoryID, pullRequestID string) *models.PRIssueComment {
+	author := c.Author.toGitHubUser()
+	return &models.PRIssueComment{
+		RepositoryID:    repositoryID,
+		PullRequestID:   pullRequestID,
+		GithubCommentID: c.DatabaseID,
+		AuthorID:        author.GetID(),
+		AuthorLogin:     author.GetLogin(),
+		Body:            github.String(c.Body),
+		HTMLURL:         github.String(c.URL),
+		GithubCreatedAt: &c.CreatedAt,
+		GithubUpdatedAt: &c.UpdatedAt,
+	}
+}
+
+// fetchPullRequestsGraphQL fetches pull requests created after the newest one stored for the
+// repository. Pages are ordered newest first, so paging stops at the first known pull request.
+func (w *PullRequestWorker) fetchPullRequestsGraphQL(ctx context.Context, client *services.GitHubGraphQLClient, owner, repo string, repositoryID string) ([]*graphQLPullRequest, error) {
+	latestPRDate, err := w.pullRequestRepo.GetLatestPRDateByRepositoryID(repositoryID)
+	if err != nil {
+		log.Printf("Warning: failed to get latest PR date for repository %s: %v", repositoryID, err)
+		latestPRDate = time.Time{}
+	}
+
+	var allPRs []*graphQLPullRequest
+	err = w.queryPullRequestsGraphQL(ctx, client, owner, repo, nil, func(pr *graphQLPullRequest) bool {
+		if !latestPRDate.IsZero() && !pr.CreatedAt.After(latestPRDate) {
+			return false
+		}
+		allPRs = append(allPRs, pr)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return allPRs, nil
+}
+
+// fetchExistingOpenPullRequestsGraphQL fetches the pull requests stored as open for the repository so they get updated,
+// including the ones merged or closed since
+func (w *PullRequestWorker) fetchExistingOpenPullRequestsGraphQL(ctx context.Context, client *services.GitHubGraphQLClient, owner, repo string, repositoryID string) ([]*graphQLPullRequest, error) {
+	existingOpenPRs, err := w.pullRequestRepo.GetOpenPRNumbersByRepositoryID(repositoryID)
+	if err != nil {
+		log.Printf("Warning: failed to get existing open PRs for repository %s: %v", repositoryID, err)
+		return nil, nil
+	}
+	if len(existingOpenPRs) == 0 {
+		return nil, nil
+	}
+
+	existing := make(map[int]bool, len(existingOpenPRs))
+	for _, number := range existingOpenPRs {
+		existing[number] = true
+	}
+
+	var allPRs []*graphQLPullRequest
+	err = w.queryPullRequestsGraphQL(ctx, client, owner, repo, []string{"OPEN"}, func(pr *graphQLPullRequest) bool {
+		if existing[pr.Number] {
+			allPRs = append(allPRs, pr)
+			delete(existing, pr.Number)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The ones left were merged or closed since they were stored and are fetched one by one
+	for _, number := range existingOpenPRs {
+		if !existing[number] {
+			continue
+		}
+		var result pullRequestQueryResult
+		variables := map[string]interface{}{"owner": owner, "name": repo, "number": number}
+		if err := client.Query(ctx, pullRequestQuery, variables, &result); err != nil {
+			log.Printf("Failed to fetch PR #%d that is no longer open: %s", number, err)
+			continue
+		}
+		if result.Repository != nil && result.Repository.PullRequest != nil {
+			allPRs = append(allPRs, result.Repository.PullRequest)
+		}
+	}
+
+	log.Printf("Found %d existing open PRs to update for repository %s", len(allPRs), repositoryID)
+	return allPRs, nil
+}
+
+// queryPullRequestsGraphQL pages through the pull requests of a repository, newest first,
+// until visit returns false or there are no more pages
+func (w *PullRequestWorker) queryPullRequestsGraphQL(ctx context.Context, client *services.GitHubGraphQLClient, owner, repo string, states []string, visit func(*graphQLPullRequest) bool) error {
+	variables := map[string]interface{}{
+		"owner":  owner,
+		"name":   repo,
+		"first":  graphQLPullRequestPageSize,
+		"states": states,
+		"cursor": nil,
+	}
+
+	for {
+		var result pullRequestsQueryResult
+		if err := client.Query(ctx, pullRequestsQuery, variables, &result); err != nil {
+			return err
+		}
+		if result.Repository == nil {
+			return nil
+		}
+
+		page := result.Repository.PullRequests
+		for _, pr := range page.Nodes {
+			if pr == nil {
+				continue
+			}
+			if !visit(pr) {
+				return nil
+			}
+		}
+
+		if !page.PageInfo.HasNextPage {
+			return nil
+		}
+		variables["cursor"] = page.PageInfo.EndCursor
+	}
+}
+
+// ingestRepositoryGraphQL fetches and stores pull requests, reviews, comments, draft transitions and the people involved
+// with a few batched GraphQL queries. Pull requests with more reviews or comments than fit in one
+// page are completed over REST.
+func (w *PullRequestWorker) ingestRepositoryGraphQL(ctx context.Context, graphQLClient *services.GitHubGraphQLClient, restClient *github.Client, owner, repo, repositoryID, projectID string, includeOpen bool) (ingestionTotals, error) {
+	var totals ingestionTotals
+
+	pullRequests, err := w.fetchPullRequestsGraphQL(ctx, graphQLClient, owner, repo, repositoryID)
+	if err != nil {
+		return totals, err
+	}
+
+	if includeOpen {
+		existingOpenPRs, err := w.fetchExistingOpenPullRequestsGraphQL(ctx, graphQLClient, owner, repo, repositoryID)
+		if err != nil {
+			log.Printf("Failed to fetch existing open PRs for %s/%s: %s", owner, repo, err)
+		}
+		pullRequests = append(pullRequests, existingOpenPRs...)
+	}
+
+	// Commit and comment authors show up on many pull requests, store each of them once per run
+	seen := make(map[int64]bool)
+	processPerson := func(user *github.User) {
+		if user == nil || seen[user.GetID()] {
+			return
+		}
+		seen[user.GetID()] = true
+		if _, err := w.processGithubPerson(user, nil, projectID, "pull_request"); err != nil {
+			log.Printf("Failed to process person %s: %s", user.GetLogin(), err)
+		}
+	}
+
+	lookup := w.newPullRequestLookup(repositoryID)
+	for _, pr := range pullRequests {
+		// GraphQL already returned names, so no client is passed for user lookups
+		if err := w.processPullRequest(ctx, nil, owner, repo, pr.toGitHubPullRequest(), repositoryID, projectID); err != nil {
+			log.Printf("Failed to process pull request #%d: %s", pr.Number, err)
+			continue
+		}
+		totals.PullRequests++
+
+		for _, commit := range pr.Commits.Nodes {
+			processPerson(commit.Commit.Author.User.toGitHubUser())
+		}
+
+		var reviews []*github.PullRequestReview
+		var reviewClient *github.Client
+		if pr.Reviews.PageInfo.HasNextPage {
+			reviews, err = w.fetchPullRequestReviews(ctx, restClient, owner, repo, pr.Number)
+			if err != nil {
+				log.Printf("Failed to fetch reviews for PR #%d: %s", pr.Number, err)
+			}
+			reviewClient = restClient
+		} else {
+			for i := range pr.Reviews.Nodes {
+				reviews = append(reviews, pr.Reviews.Nodes[i].toGitHubReview())
+			}
+		}
+
+		for _, review := range reviews {
+			if err := w.processPullRequestReview(ctx, review, repositoryID, pr.DatabaseID, reviewClient, projectID); err != nil {
+				log.Printf("Failed to process review %d: %s", review.GetID(), err)
+				continue
+			}
+			totals.Reviews++
+		}
+
+		pullRequestID := lookup.id(pr.Number)
+		if pullRequestID == "" {
+			continue
+		}
+
+		draftEvents := pr.draftEvents(repositoryID, pullRequestID)
+		if pr.TimelineItems.PageInfo.HasNextPage {
+			draftEvents, err = w.fetchDraftEventsREST(ctx, restClient, owner, repo, pr.Number, repositoryID, pullRequestID)
+			if err != nil {
+				log.Printf("Failed to fetch draft events for PR #%d: %s", pr.Number, err)
+			}
+		}
+		w.storeDraftEvents(draftEvents)
+
+		contents := pr.contents()
+		if pr.Files.PageInfo.HasNextPage || pr.Commits.PageInfo.HasNextPage {
+			// Only the lists are completed, the size reported by GraphQL is already stored
+			contents, err = w.fetchPullRequestContentsREST(ctx, restClient, owner, repo, pr.Number)
+			if err != nil {
+				log.Printf("Failed to fetch files and commits for PR #%d: %s", pr.Number, err)
+			}
+		}
+		w.storePullRequestContents(contents, repositoryID, pullRequestID)
+
+		if pr.hasMoreComments() {
+			comments, err := w.ingestPullRequestCommentsREST(ctx, restClient, owner, repo, pr.Number, repositoryID, pullRequestID, projectID)
+			if err != nil {
+				log.Printf("Failed to fetch comments for PR #%d: %s", pr.Number, err)
+			}
+			totals.Comments += comments
+			continue
+		}
+
+		for _, review := range pr.Reviews.Nodes {
+			for i := range review.Comments.Nodes {
+				comment := &review.Comments.Nodes[i]
+				processPerson(comment.Author.toGitHubUser())
+				if err := w.prReviewCommentRepo.Upsert(comment.toModel(repositoryID, pullRequestID, review.DatabaseID)); err != nil {
+					log.Printf("Failed to store review comment %d: %s", comment.DatabaseID, err)
+					continue
+				}
+				totals.Comments++
+			}
+		}
+
+		for i := range pr.Comments.Nodes {
+			comment := &pr.Comments.Nodes[i]
+			processPerson(comment.Author.toGitHubUser())
+			if err := w.prIssueCommentRepo.Upsert(comment.toModel(repositoryID, pullRequestID)); err != nil {
+				log.Printf("Failed to store conversation comment %d: %s", comment.DatabaseID, err)
+				continue
+			}
+			totals.Comments++
+		}
+	}
+
+	return totals, nil
+}
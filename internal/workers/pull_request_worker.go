@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -12,7 +13,6 @@ import (
 	"github.com/alimgiray/gscope/internal/repositories"
 	"github.com/alimgiray/gscope/internal/services"
 	"github.com/google/go-github/v57/github"
-	"golang.org/x/oauth2"
 )
 
 type PullRequestWorker struct {
@@ -24,10 +24,18 @@ type PullRequestWorker struct {
 	githubPersonService        *services.GithubPersonService
 	githubRepoService          *services.GitHubRepositoryService
 	projectRepositoryRepo      *repositories.ProjectRepositoryRepository
-	projectRepo                *repositories.ProjectRepository
-	userRepo                   *repositories.UserRepository
+	githubClientPool           *services.GitHubClientPool
 	projectGithubPersonService *services.ProjectGithubPersonService
 	pullRequestRepo            *repositories.PullRequestRepository
+	jobGitHubStatsRepo         *repositories.JobGitHubStatsRepository
+	projectRepo                *repositories.ProjectRepository
+	prReviewCommentRepo        *repositories.PRReviewCommentRepository
+	prIssueCommentRepo         *repositories.PRIssueCommentRepository
+	prDraftEventRepo           *repositories.PRDraftEventRepository
+	prFileRepo                 *repositories.PRFileRepository
+	prCommitRepo               *repositories.PRCommitRepository
+	githubTeamRepo             *repositories.GithubTeamRepository
+	prReviewRequestRepo        *repositories.PRReviewRequestRepository
 }
 
 func NewPullRequestWorker(
@@ -39,10 +47,18 @@ func NewPullRequestWorker(
 	githubPersonService *services.GithubPersonService,
 	githubRepoService *services.GitHubRepositoryService,
 	projectRepositoryRepo *repositories.ProjectRepositoryRepository,
-	projectRepo *repositories.ProjectRepository,
-	userRepo *repositories.UserRepository,
+	githubClientPool *services.GitHubClientPool,
 	projectGithubPersonService *services.ProjectGithubPersonService,
 	pullRequestRepo *repositories.PullRequestRepository,
+	jobGitHubStatsRepo *repositories.JobGitHubStatsRepository,
+	projectRepo *repositories.ProjectRepository,
+	prReviewCommentRepo *repositories.PRReviewCommentRepository,
+	prIssueCommentRepo *repositories.PRIssueCommentRepository,
+	prDraftEventRepo *repositories.PRDraftEventRepository,
+	prFileRepo *repositories.PRFileRepository,
+	prCommitRepo *repositories.PRCommitRepository,
+	githubTeamRepo *repositories.GithubTeamRepository,
+	prReviewRequestRepo *repositories.PRReviewRequestRepository,
 ) *PullRequestWorker {
 	return &PullRequestWorker{
 		BaseWorker:                 NewBaseWorker(workerID, models.JobTypePullRequest),
@@ -53,10 +69,18 @@ func NewPullRequestWorker(
 		githubPersonService:        githubPersonService,
 		githubRepoService:          githubRepoService,
 		projectRepositoryRepo:      projectRepositoryRepo,
-		projectRepo:                projectRepo,
-		userRepo:                   userRepo,
+		githubClientPool:           githubClientPool,
 		projectGithubPersonService: projectGithubPersonService,
 		pullRequestRepo:            pullRequestRepo,
+		jobGitHubStatsRepo:         jobGitHubStatsRepo,
+		projectRepo:                projectRepo,
+		prReviewCommentRepo:        prReviewCommentRepo,
+		prIssueCommentRepo:         prIssueCommentRepo,
+		prDraftEventRepo:           prDraftEventRepo,
+		prFileRepo:                 prFileRepo,
+		prCommitRepo:               prCommitRepo,
+		githubTeamRepo:             githubTeamRepo,
+		prReviewRequestRepo:        prReviewRequestRepo,
 	}
 }
 
@@ -130,20 +154,31 @@ func (w *PullRequestWorker) ProcessJob(ctx context.Context, job *models.Job) err
 		return fmt.Errorf("failed to update job status: %w", err)
 	}
 
-	// Get GitHub token for the project owner
-	user, err := w.getUserByProjectID(job.ProjectID)
+	// Create a GitHub client that rotates across the project's tokens and
+	// throttles against the rate limit budget shared by all workers
+	requestStats := &services.GitHubRequestStats{}
+	userGithubClient, err := w.githubClientPool.ProjectClient(job.ProjectID, requestStats)
 	if err != nil {
-		return fmt.Errorf("failed to get user for project: %s", err)
+		return fmt.Errorf("failed to get GitHub client for project: %s", err)
 	}
+	defer saveRequestStats(w.jobGitHubStatsRepo, job, requestStats)
 
-	// Create GitHub client with user's token
-	userGithubClient := w.githubClient
-	if user.GitHubAccessToken != "" {
-		userGithubClient = w.createAuthenticatedClient(user.GitHubAccessToken)
+	// Projects can opt into GraphQL ingestion, which batches pull requests with their
+	// reviews and people into far fewer requests than REST
+	var graphQLClient *services.GitHubGraphQLClient
+	project, err := w.projectRepo.GetByID(job.ProjectID)
+	if err != nil {
+		log.Printf("Failed to get project %s, using REST ingestion: %v", job.ProjectID, err)
+	} else if project.GitHubIngestion == models.GitHubIngestionGraphQL {
+		graphQLClient, err = w.githubClientPool.ProjectGraphQLClient(job.ProjectID, requestStats)
+		if err != nil {
+			return fmt.Errorf("failed to get GitHub GraphQL client for project: %s", err)
+		}
 	}
 
 	totalPRs := 0
 	totalReviews := 0
+	totalComments := 0
 	totalPeople := 0
 
 	// Check if this is a repository-specific job
@@ -164,6 +199,17 @@ func (w *PullRequestWorker) ProcessJob(ctx context.Context, job *models.Job) err
 			return fmt.Errorf("failed to get GitHub repository %s: %s", projectRepo.GithubRepoID, err)
 		}
 
+		// Pick up renames and transfers webhooks missed before building API paths from the full name;
+		// GitHub redirects the old paths until the daily refresh
+		if token, err := w.githubClientPool.ProjectToken(job.ProjectID); err == nil {
+			if err := w.githubRepoService.RefreshRepositoryStatusIfDue(githubRepo, token); err != nil {
+				log.Printf("Failed to refresh status of repository %s: %s", githubRepo.FullName, err)
+			}
+		}
+		if githubRepo.IsDeleted() || githubRepo.IsInaccessible() {
+			return fmt.Errorf("repository %s is deleted or inaccessible on GitHub", githubRepo.FullName)
+		}
+
 		// Parse owner and repo name from full name
 		owner, repoName, err := parseRepoFullName(githubRepo.FullName)
 		if err != nil {
@@ -172,44 +218,16 @@ func (w *PullRequestWorker) ProcessJob(ctx context.Context, job *models.Job) err
 
 		log.Printf("Processing pull requests for %s/%s", owner, repoName)
 
-		// Step 1: Fetch new pull requests from GitHub (PRs created after our last PR)
-		newPullRequests, err := w.fetchPullRequests(ctx, userGithubClient, owner, repoName, githubRepo.ID)
-		if err != nil {
-			return fmt.Errorf("failed to fetch new pull requests for %s/%s: %s", owner, repoName, err)
-		}
-
-		// Step 2: Fetch and update existing open PRs from GitHub
-		existingOpenPRs, err := w.fetchExistingOpenPullRequests(ctx, userGithubClient, owner, repoName, githubRepo.ID)
+		totals, err := w.ingestRepository(ctx, userGithubClient, graphQLClient, owner, repoName, githubRepo.ID, job.ProjectID, true)
 		if err != nil {
-			log.Printf("Failed to fetch existing open PRs for %s/%s: %s", owner, repoName, err)
+			return fmt.Errorf("failed to fetch pull requests for %s/%s: %s", owner, repoName, err)
 		}
+		totalPRs += totals.PullRequests
+		totalReviews += totals.Reviews
+		totalComments += totals.Comments
 
-		// Combine new and existing PRs
-		allPullRequests := append(newPullRequests, existingOpenPRs...)
-
-		// Process each pull request
-		for _, pr := range allPullRequests {
-			if err := w.processPullRequest(ctx, userGithubClient, owner, repoName, pr, githubRepo.ID, job.ProjectID); err != nil {
-				log.Printf("Failed to process pull request #%d: %s", pr.GetNumber(), err)
-				continue
-			}
-			totalPRs++
-
-			// Fetch and process reviews for this PR
-			reviews, err := w.fetchPullRequestReviews(ctx, userGithubClient, owner, repoName, pr.GetNumber())
-			if err != nil {
-				log.Printf("Failed to fetch reviews for PR #%d: %s", pr.GetNumber(), err)
-				continue
-			}
-
-			for _, review := range reviews {
-				if err := w.processPullRequestReview(ctx, review, githubRepo.ID, pr.GetID(), userGithubClient, job.ProjectID); err != nil {
-					log.Printf("Failed to process review %d: %s", review.GetID(), err)
-					continue
-				}
-				totalReviews++
-			}
-		}
+		// Pull requests stored before sizes were fetched get them a batch at a time
+		w.backfillPullRequestDetails(ctx, userGithubClient, owner, repoName, githubRepo.ID, job.ProjectID)
 
 		// Fetch and process repository contributors (even if no PRs exist)
 		contributors, err := w.fetchRepositoryContributors(ctx, userGithubClient, owner, repoName)
@@ -217,7 +235,7 @@ func (w *PullRequestWorker) ProcessJob(ctx context.Context, job *models.Job) err
 			log.Printf("Failed to fetch contributors for %s/%s: %s", owner, repoName, err)
 		} else {
 			for _, contributor := range contributors {
-				if err := w.processGithubPerson(contributor, userGithubClient, job.ProjectID, "contributor"); err != nil {
+				if _, err := w.processGithubPerson(contributor, userGithubClient, job.ProjectID, "contributor"); err != nil {
 					log.Printf("Failed to process contributor %s: %s", contributor.GetLogin(), err)
 					continue
 				}
@@ -253,36 +271,14 @@ func (w *PullRequestWorker) ProcessJob(ctx context.Context, job *models.Job) err
 
 			log.Printf("Processing pull requests for %s/%s", owner, repoName)
 
-			// Fetch pull requests from GitHub
-			pullRequests, err := w.fetchPullRequests(ctx, userGithubClient, owner, repoName, githubRepo.ID)
+			totals, err := w.ingestRepository(ctx, userGithubClient, graphQLClient, owner, repoName, githubRepo.ID, job.ProjectID, false)
 			if err != nil {
 				log.Printf("Failed to fetch pull requests for %s/%s: %s", owner, repoName, err)
 				continue
 			}
-
-			// Process each pull request
-			for _, pr := range pullRequests {
-				if err := w.processPullRequest(ctx, userGithubClient, owner, repoName, pr, githubRepo.ID, job.ProjectID); err != nil {
-					log.Printf("Failed to process pull request #%d: %s", pr.GetNumber(), err)
-					continue
-				}
-				totalPRs++
-
-				// Fetch and process reviews for this PR
-				reviews, err := w.fetchPullRequestReviews(ctx, userGithubClient, owner, repoName, pr.GetNumber())
-				if err != nil {
-					log.Printf("Failed to fetch reviews for PR #%d: %s", pr.GetNumber(), err)
-					continue
-				}
-
-				for _, review := range reviews {
-					if err := w.processPullRequestReview(ctx, review, githubRepo.ID, pr.GetID(), userGithubClient, job.ProjectID); err != nil {
-						log.Printf("Failed to process review %d: %s", review.GetID(), err)
-						continue
-					}
-					totalReviews++
-				}
-			}
+			totalPRs += totals.PullRequests
+			totalReviews += totals.Reviews
+			totalComments += totals.Comments
 		}
 	}
 
@@ -302,10 +298,136 @@ func (w *PullRequestWorker) ProcessJob(ctx context.Context, job *models.Job) err
 		}
 	}
 
-	log.Printf("Pull request job completed. Processed %d PRs, %d reviews, %d people", totalPRs, totalReviews, totalPeople)
+	log.Printf("Pull request job completed. Processed %d PRs, %d reviews, %d comments, %d people", totalPRs, totalReviews, totalComments, totalPeople)
 	return nil
 }
 
+// ingestionTotals counts what was stored for a repository
+type ingestionTotals struct {
+	PullRequests int
+	Reviews      int
+	Comments     int
+}
+
+// ingestRepository fetches and stores the pull requests and reviews of a repository with GraphQL
+// when a client for it is given, falling back to REST when the GraphQL queries fail
+func (w *PullRequestWorker) ingestRepository(ctx context.Context, restClient *github.Client, graphQLClient *services.GitHubGraphQLClient, owner, repo, repositoryID, projectID string, includeOpen bool) (ingestionTotals, error) {
+	if graphQLClient != nil {
+		totals, err := w.ingestRepositoryGraphQL(ctx, graphQLClient, restClient, owner, repo, repositoryID, projectID, includeOpen)
+		if err == nil {
+			return totals, nil
+		}
+		if ctx.Err() != nil {
+			return ingestionTotals{}, ctx.Err()
+		}
+		log.Printf("GraphQL ingestion failed for %s/%s, falling back to REST: %s", owner, repo, err)
+	}
+
+	return w.ingestRepositoryREST(ctx, restClient, owner, repo, repositoryID, projectID, includeOpen)
+}
+
+// ingestRepositoryREST fetches pull requests page by page and their files, commits, draft transitions and
+// reviews one pull request at a time, followed by the comments of the whole repository
+func (w *PullRequestWorker) ingestRepositoryREST(ctx context.Context, client *github.Client, owner, repo, repositoryID, projectID string, includeOpen bool) (ingestionTotals, error) {
+	var totals ingestionTotals
+
+	// Fetch new pull requests from GitHub (PRs created after our last PR)
+	pullRequests, err := w.fetchPullRequests(ctx, client, owner, repo, repositoryID)
+	if err != nil {
+		return totals, err
+	}
+
+	// Fetch and update existing open PRs from GitHub
+	if includeOpen {
+		existingOpenPRs, err := w.fetchExistingOpenPullRequests(ctx, client, owner, repo, repositoryID)
+		if err != nil {
+			log.Printf("Failed to fetch existing open PRs for %s/%s: %s", owner, repo, err)
+		}
+		pullRequests = append(pullRequests, existingOpenPRs...)
+	}
+
+	// Process each pull request
+	lookup := w.newPullRequestLookup(repositoryID)
+	for _, pr := range pullRequests {
+		// Listed pull requests carry no size or merger, they are read from the pull request itself. The
+		// timeline has the draft transitions. They are only fetched again, along with the files and commits,
+		// when the pull request changed since it was stored.
+		var contents *pullRequestContents
+		var timeline []*github.Timeline
+		if w.needsPullRequestDetails(repositoryID, pr) {
+			if err := w.fetchPullRequestSizeREST(ctx, client, owner, repo, pr); err != nil {
+				log.Printf("Failed to fetch size of PR #%d: %s", pr.GetNumber(), err)
+			}
+
+			contents, err = w.fetchPullRequestContentsREST(ctx, client, owner, repo, pr.GetNumber())
+			if err != nil {
+				log.Printf("Failed to fetch files and commits for PR #%d: %s", pr.GetNumber(), err)
+			}
+
+			timeline, err = w.fetchTimelineREST(ctx, client, owner, repo, pr.GetNumber())
+			if err != nil {
+				log.Printf("Failed to fetch timeline for PR #%d: %s", pr.GetNumber(), err)
+			}
+			if pr.MergedBy == nil {
+				pr.MergedBy = mergedByFromTimeline(timeline)
+			}
+		}
+
+		if err := w.processPullRequest(ctx, client, owner, repo, pr, repositoryID, projectID); err != nil {
+			log.Printf("Failed to process pull request #%d: %s", pr.GetNumber(), err)
+			continue
+		}
+		totals.PullRequests++
+
+		if pullRequestID := lookup.id(pr.GetNumber()); pullRequestID != "" {
+			w.storePullRequestContents(contents, repositoryID, pullRequestID)
+			w.storeDraftEvents(draftEventsFromTimeline(timeline, repositoryID, pullRequestID))
+		}
+
+		// Fetch and process reviews for this PR
+		reviews, err := w.fetchPullRequestReviews(ctx, client, owner, repo, pr.GetNumber())
+		if err != nil {
+			log.Printf("Failed to fetch reviews for PR #%d: %s", pr.GetNumber(), err)
+			continue
+		}
+
+		for _, review := range reviews {
+			if err := w.processPullRequestReview(ctx, review, repositoryID, pr.GetID(), client, projectID); err != nil {
+				log.Printf("Failed to process review %d: %s", review.GetID(), err)
+				continue
+			}
+			totals.Reviews++
+		}
+	}
+
+	comments, err := w.ingestRepositoryCommentsREST(ctx, client, owner, repo, repositoryID, projectID)
+	if err != nil {
+		log.Printf("Failed to fetch comments for %s/%s: %s", owner, repo, err)
+	}
+	totals.Comments = comments
+
+	return totals, nil
+}
+
+// saveRequestStats records how many GitHub requests a job made and how many the cache answered
+func saveRequestStats(jobGitHubStatsRepo *repositories.JobGitHubStatsRepository, job *models.Job, requestStats *services.GitHubRequestStats) {
+	stats := &models.JobGitHubStats{
+		JobID:               job.ID,
+		ProjectID:           job.ProjectID,
+		ProjectRepositoryID: job.ProjectRepositoryID,
+		Requests:            requestStats.Requests(),
+		CacheHits:           requestStats.CacheHits(),
+		CreatedAt:           time.Now(),
+	}
+
+	log.Printf("%s job %s made %d GitHub requests, %d answered from cache (%.1f%%)",
+		job.JobType, job.ID, stats.Requests, stats.CacheHits, stats.CacheHitRate())
+
+	if err := jobGitHubStatsRepo.Upsert(stats); err != nil {
+		log.Printf("Warning: failed to save GitHub request stats for job %s: %v", job.ID, err)
+	}
+}
+
 func (w *PullRequestWorker) fetchPullRequests(ctx context.Context, client *github.Client, owner, repo string, repositoryID string) ([]*github.PullRequest, error) {
 	// Get the latest PR date from the database for this repository (any PR, not just open ones)
 	latestPRDate, err := w.pullRequestRepo.GetLatestPRDateByRepositoryID(repositoryID)
@@ -358,7 +480,8 @@ func (w *PullRequestWorker) fetchPullRequests(ctx context.Context, client *githu
 	return allPRs, nil
 }
 
-// fetchExistingOpenPullRequests fetches all open PRs from GitHub to update existing ones
+// fetchExistingOpenPullRequests fetches the PRs stored as open from GitHub to update them, including the ones
+// merged or closed since
 func (w *PullRequestWorker) fetchExistingOpenPullRequests(ctx context.Context, client *github.Client, owner, repo string, repositoryID string) ([]*github.PullRequest, error) {
 	// Get existing open PR numbers from our database
 	existingOpenPRs, err := w.pullRequestRepo.GetOpenPRNumbersByRepositoryID(repositoryID)
@@ -372,6 +495,11 @@ func (w *PullRequestWorker) fetchExistingOpenPullRequests(ctx context.Context, c
 		return nil, nil
 	}
 
+	existing := make(map[int]bool, len(existingOpenPRs))
+	for _, number := range existingOpenPRs {
+		existing[number] = true
+	}
+
 	var allPRs []*github.PullRequest
 	opts := &github.PullRequestListOptions{
 		State: "open", // Only get open PRs
@@ -390,11 +518,9 @@ func (w *PullRequestWorker) fetchExistingOpenPullRequests(ctx context.Context, c
 
 		// Only include PRs that exist in our database
 		for _, pr := range prs {
-			for _, existingPRNumber := range existingOpenPRs {
-				if pr.GetNumber() == existingPRNumber {
-					allPRs = append(allPRs, pr)
-					break
-				}
+			if existing[pr.GetNumber()] {
+				allPRs = append(allPRs, pr)
+				delete(existing, pr.GetNumber())
 			}
 		}
 
@@ -404,6 +530,21 @@ func (w *PullRequestWorker) fetchExistingOpenPullRequests(ctx context.Context, c
 		opts.Page = resp.NextPage
 	}
 
+	// The ones left were merged or closed since they were stored and are fetched one by one
+	for _, number := range existingOpenPRs {
+		if !existing[number] {
+			continue
+		}
+		pr, _, err := retryGitHubRequest(ctx, func() (*github.PullRequest, *github.Response, error) {
+			return client.PullRequests.Get(ctx, owner, repo, number)
+		})
+		if err != nil {
+			log.Printf("Failed to fetch PR #%d that is no longer open: %s", number, err)
+			continue
+		}
+		allPRs = append(allPRs, pr)
+	}
+
 	log.Printf("Found %d existing open PRs to update for repository %s", len(allPRs), repositoryID)
 	return allPRs, nil
 }
@@ -468,9 +609,24 @@ func (w *PullRequestWorker) fetchRepositoryContributors(ctx context.Context, cli
 
 func (w *PullRequestWorker) processPullRequest(ctx context.Context, client *github.Client, owner, repo string, githubPR *github.PullRequest, repositoryID string, projectID string) error {
 	// Process the PR author
+	var authorID *string
 	if githubPR.User != nil {
-		if err := w.processGithubPerson(githubPR.User, client, projectID, "pull_request"); err != nil {
+		personID, err := w.processGithubPerson(githubPR.User, client, projectID, "pull_request")
+		if err != nil {
 			log.Printf("Failed to process PR author: %s", err)
+		} else {
+			authorID = &personID
+		}
+	}
+
+	// Process who merged the PR
+	var mergedByID *string
+	if githubPR.MergedBy != nil {
+		personID, err := w.processGithubPerson(githubPR.MergedBy, client, projectID, "pull_request")
+		if err != nil {
+			log.Printf("Failed to process PR merger: %s", err)
+		} else {
+			mergedByID = &personID
 		}
 	}
 
@@ -482,6 +638,12 @@ func (w *PullRequestWorker) processPullRequest(ctx context.Context, client *gith
 		Title:          githubPR.GetTitle(),
 		State:          githubPR.GetState(),
 		Draft:          githubPR.GetDraft(),
+		Additions:      githubPR.Additions,
+		Deletions:      githubPR.Deletions,
+		ChangedFiles:   githubPR.ChangedFiles,
+		CommitCount:    githubPR.Commits,
+		AuthorID:       authorID,
+		MergedByID:     mergedByID,
 	}
 
 	// Handle GitHub timestamps
@@ -504,6 +666,10 @@ func (w *PullRequestWorker) processPullRequest(ctx context.Context, client *gith
 		sha := githubPR.GetMergeCommitSHA()
 		pr.MergeCommitSHA = &sha
 	}
+	if githubPR.Head != nil && githubPR.Head.SHA != nil {
+		sha := githubPR.Head.GetSHA()
+		pr.HeadSHA = &sha
+	}
 	if githubPR.ClosedAt != nil {
 		pr.ClosedAt = &githubPR.ClosedAt.Time
 	}
@@ -536,13 +702,80 @@ func (w *PullRequestWorker) processPullRequest(ctx context.Context, client *gith
 	}
 
 	// Upsert the pull request
-	return w.pullRequestService.UpsertPullRequest(pr)
+	if err := w.pullRequestService.UpsertPullRequest(pr); err != nil {
+		return err
+	}
+
+	labels := make([]*models.PRLabel, 0, len(githubPR.Labels))
+	for _, label := range githubPR.Labels {
+		labels = append(labels, &models.PRLabel{Name: label.GetName(), Color: label.Color})
+	}
+	if err := w.pullRequestRepo.ReplaceLabels(pr.ID, labels); err != nil {
+		log.Printf("Failed to store labels of PR #%d: %s", pr.GithubPRNumber, err)
+	}
+
+	w.processReviewRequests(githubPR, client, repositoryID, pr.ID, projectID)
+	return nil
+}
+
+// processReviewRequests stores the people and teams currently asked to review a pull request. Requests
+// stored by earlier fetches are kept, as GitHub drops a request once the reviewer submits a review.
+func (w *PullRequestWorker) processReviewRequests(githubPR *github.PullRequest, client *github.Client, repositoryID, pullRequestID, projectID string) {
+	for _, reviewer := range githubPR.RequestedReviewers {
+		personID, err := w.requestedReviewerID(reviewer, client, projectID)
+		if err != nil {
+			log.Printf("Failed to process requested reviewer %s: %s", reviewer.GetLogin(), err)
+			continue
+		}
+		request := &models.PRRequestedReviewer{
+			RepositoryID:   repositoryID,
+			PullRequestID:  pullRequestID,
+			GithubPersonID: personID,
+		}
+		if err := w.prReviewRequestRepo.CreateRequestedReviewer(request); err != nil {
+			log.Printf("Failed to store review request for %s: %s", reviewer.GetLogin(), err)
+		}
+	}
+
+	for _, githubTeam := range githubPR.RequestedTeams {
+		team := &models.GithubTeam{
+			GithubTeamID: githubTeam.GetID(),
+			Slug:         githubTeam.GetSlug(),
+			Name:         githubTeam.Name,
+			HTMLURL:      githubTeam.HTMLURL,
+		}
+		if err := w.githubTeamRepo.Upsert(team); err != nil {
+			log.Printf("Failed to process requested team %s: %s", githubTeam.GetSlug(), err)
+			continue
+		}
+		request := &models.PRRequestedTeam{
+			RepositoryID:  repositoryID,
+			PullRequestID: pullRequestID,
+			GithubTeamID:  team.ID,
+		}
+		if err := w.prReviewRequestRepo.CreateRequestedTeam(request); err != nil {
+			log.Printf("Failed to store review request for team %s: %s", githubTeam.GetSlug(), err)
+		}
+	}
+}
+
+// requestedReviewerID returns the ID of a requested reviewer, storing them as a project person first
+// if needed. Reviewer objects carry no name, so known people are not updated from them.
+func (w *PullRequestWorker) requestedReviewerID(reviewer *github.User, client *github.Client, projectID string) (string, error) {
+	person, err := w.githubPersonService.GetGithubPersonByGithubUserID(int(reviewer.GetID()))
+	if err == sql.ErrNoRows {
+		return w.processGithubPerson(reviewer, client, projectID, "pull_request")
+	}
+	if err != nil {
+		return "", err
+	}
+	return person.ID, w.projectGithubPersonService.CreateProjectGithubPerson(projectID, person.ID, "pull_request")
 }
 
 func (w *PullRequestWorker) processPullRequestReview(ctx context.Context, githubReview *github.PullRequestReview, repositoryID string, pullRequestID int64, client *github.Client, projectID string) error {
 	// Process the reviewer
 	if githubReview.User != nil {
-		if err := w.processGithubPerson(githubReview.User, client, projectID, "pull_request"); err != nil {
+		if _, err := w.processGithubPerson(githubReview.User, client, projectID, "pull_request"); err != nil {
 			log.Printf("Failed to process review author: %s", err)
 		}
 	}
@@ -603,7 +836,8 @@ func (w *PullRequestWorker) processPullRequestReview(ctx context.Context, github
 	return w.prReviewService.UpsertPRReview(review)
 }
 
-func (w *PullRequestWorker) processGithubPerson(githubUser *github.User, client *github.Client, projectID, sourceType string) error {
+// processGithubPerson stores a GitHub user as a person of the project and returns their ID
+func (w *PullRequestWorker) processGithubPerson(githubUser *github.User, client *github.Client, projectID, sourceType string) (string, error) {
 	person := &models.GithubPerson{
 		GithubUserID: int(githubUser.GetID()),
 		Username:     githubUser.GetLogin(),
@@ -613,7 +847,7 @@ func (w *PullRequestWorker) processGithubPerson(githubUser *github.User, client
 	if githubUser.Name != nil {
 		displayName := githubUser.GetName()
 		person.DisplayName = &displayName
-	} else {
+	} else if client != nil {
 		// Try to fetch full user details to get the name using the authenticated client
 		if fullUser, err := w.fetchFullUserDetails(githubUser.GetLogin(), client); err == nil && fullUser.Name != nil {
 			displayName := fullUser.GetName()
@@ -635,11 +869,11 @@ func (w *PullRequestWorker) processGithubPerson(githubUser *github.User, client
 
 	// Upsert the person
 	if err := w.githubPersonService.UpsertGithubPerson(person); err != nil {
-		return err
+		return "", err
 	}
 
 	// Create project-github person relationship
-	return w.projectGithubPersonService.CreateProjectGithubPerson(projectID, person.ID, sourceType)
+	return person.ID, w.projectGithubPersonService.CreateProjectGithubPerson(projectID, person.ID, sourceType)
 }
 
 // fetchFullUserDetails fetches complete user details from GitHub API
@@ -679,45 +913,19 @@ func parseRepoFullName(fullName string) (owner, repo string, err error) {
 	return owner, repo, nil
 }
 
-// getUserByProjectID gets the user who owns the project
-func (w *PullRequestWorker) getUserByProjectID(projectID string) (*models.User, error) {
-	// Get the project to find the owner
-	project, err := w.getProjectByID(projectID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get project: %w", err)
-	}
-
-	// Get the user by ID
-	user, err := w.getUserByID(project.OwnerID.String())
-	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
-	}
-
-	return user, nil
-}
-
-// getProjectByID gets a project by ID
-func (w *PullRequestWorker) getProjectByID(projectID string) (*models.Project, error) {
-	return w.projectRepo.GetByID(projectID)
-}
-
-// getUserByID gets a user by ID
-func (w *PullRequestWorker) getUserByID(userID string) (*models.User, error) {
-	return w.userRepo.GetByID(userID)
+// makeGitHubRequestWithRetry performs a GitHub API request with rate limit handling and exponential backoff
+func (w *PullRequestWorker) makeGitHubRequestWithRetry(ctx context.Context, requestFunc func() ([]*github.PullRequest, *github.Response, error)) ([]*github.PullRequest, *github.Response, error) {
+	return retryGitHubRequest(ctx, requestFunc)
 }
 
-// createAuthenticatedClient creates a GitHub client with the provided token
-func (w *PullRequestWorker) createAuthenticatedClient(token string) *github.Client {
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
-	return github.NewClient(tc)
+// makeGitHubRequestWithRetryReviews performs a GitHub API request for reviews with rate limit handling and exponential backoff
+func (w *PullRequestWorker) makeGitHubRequestWithRetryReviews(ctx context.Context, requestFunc func() ([]*github.PullRequestReview, *github.Response, error)) ([]*github.PullRequestReview, *github.Response, error) {
+	return retryGitHubRequest(ctx, requestFunc)
 }
 
-// makeGitHubRequestWithRetry performs a GitHub API request with rate limit handling and exponential backoff
-func (w *PullRequestWorker) makeGitHubRequestWithRetry(ctx context.Context, requestFunc func() ([]*github.PullRequest, *github.Response, error)) ([]*github.PullRequest, *github.Response, error) {
+// retryGitHubRequest performs a GitHub API request, retrying failures after retryDelay
+func retryGitHubRequest[T any](ctx context.Context, requestFunc func() (T, *github.Response, error)) (T, *github.Response, error) {
+	var zero T
 	maxRetries := 5
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
@@ -727,143 +935,55 @@ func (w *PullRequestWorker) makeGitHubRequestWithRetry(ctx context.Context, requ
 			return result, resp, nil
 		}
 
-		// Check if it's a rate limit error
-		if resp != nil && resp.StatusCode == 403 {
-			// Check if we have rate limit info in headers
-			if resetTime := resp.Header.Get("X-RateLimit-Reset"); resetTime != "" {
-				if resetUnix, parseErr := time.Parse("2006-01-02 15:04:05", resetTime); parseErr == nil {
-					waitTime := time.Until(resetUnix)
-					if waitTime > 0 {
-						log.Printf("Rate limit exceeded, waiting until %s (%.0f seconds)", resetUnix.Format("2006-01-02 15:04:05"), waitTime.Seconds())
-						time.Sleep(waitTime)
-						continue
-					}
-				}
-			}
-
-			// If we can't parse the reset time, use gradual backoff: 1min, 3min, 6min, 10min, 15min
-			var waitTime time.Duration
-			switch attempt {
-			case 1:
-				waitTime = 1 * time.Minute
-			case 2:
-				waitTime = 3 * time.Minute
-			case 3:
-				waitTime = 6 * time.Minute
-			case 4:
-				waitTime = 10 * time.Minute
-			case 5:
-				waitTime = 15 * time.Minute
-			default:
-				waitTime = 15 * time.Minute
-			}
-			log.Printf("Rate limit exceeded (attempt %d/%d), waiting %v before retry", attempt, maxRetries, waitTime)
-			time.Sleep(waitTime)
-			continue
+		if attempt == maxRetries {
+			return result, resp, err
 		}
 
-		// For other errors, use exponential backoff
-		if attempt < maxRetries {
-			var waitTime time.Duration
-			switch attempt {
-			case 1:
-				waitTime = 1 * time.Minute
-			case 2:
-				waitTime = 3 * time.Minute
-			case 3:
-				waitTime = 6 * time.Minute
-			case 4:
-				waitTime = 10 * time.Minute
-			case 5:
-				waitTime = 15 * time.Minute
-			default:
-				waitTime = 15 * time.Minute
-			}
-			log.Printf("GitHub API request failed (attempt %d/%d): %v, waiting %v before retry", attempt, maxRetries, err, waitTime)
-			time.Sleep(waitTime)
-			continue
+		waitTime := retryDelay(resp, err, attempt)
+		log.Printf("GitHub API request failed (attempt %d/%d): %v, waiting %v before retry", attempt, maxRetries, err, waitTime.Round(time.Second))
+		select {
+		case <-ctx.Done():
+			return zero, resp, ctx.Err()
+		case <-time.After(waitTime):
 		}
-
-		// Last attempt failed
-		return result, resp, err
 	}
 
 	// This should never be reached, but just in case
-	return nil, nil, fmt.Errorf("all retry attempts failed")
+	return zero, nil, fmt.Errorf("all retry attempts failed")
 }
 
-// makeGitHubRequestWithRetryReviews performs a GitHub API request for reviews with rate limit handling and exponential backoff
-func (w *PullRequestWorker) makeGitHubRequestWithRetryReviews(ctx context.Context, requestFunc func() ([]*github.PullRequestReview, *github.Response, error)) ([]*github.PullRequestReview, *github.Response, error) {
-	maxRetries := 5
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		result, resp, err := requestFunc()
-
-		if err == nil {
-			return result, resp, nil
+// retryDelay returns how long to wait before retrying a failed GitHub request.
+// Rate limit errors wait until the window in resp.Rate resets, other errors back off gradually.
+func retryDelay(resp *github.Response, err error, attempt int) time.Duration {
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		if waitTime := time.Until(rateLimitErr.Rate.Reset.Time); waitTime > 0 {
+			return waitTime + time.Second
 		}
+	}
 
-		// Check if it's a rate limit error
-		if resp != nil && resp.StatusCode == 403 {
-			// Check if we have rate limit info in headers
-			if resetTime := resp.Header.Get("X-RateLimit-Reset"); resetTime != "" {
-				if resetUnix, parseErr := time.Parse("2006-01-02 15:04:05", resetTime); parseErr == nil {
-					waitTime := time.Until(resetUnix)
-					if waitTime > 0 {
-						log.Printf("Rate limit exceeded, waiting until %s (%.0f seconds)", resetUnix.Format("2006-01-02 15:04:05"), waitTime.Seconds())
-						time.Sleep(waitTime)
-						continue
-					}
-				}
-			}
-
-			// If we can't parse the reset time, use gradual backoff: 1min, 3min, 6min, 10min, 15min
-			var waitTime time.Duration
-			switch attempt {
-			case 1:
-				waitTime = 1 * time.Minute
-			case 2:
-				waitTime = 3 * time.Minute
-			case 3:
-				waitTime = 6 * time.Minute
-			case 4:
-				waitTime = 10 * time.Minute
-			case 5:
-				waitTime = 15 * time.Minute
-			default:
-				waitTime = 15 * time.Minute
-			}
-			log.Printf("Rate limit exceeded (attempt %d/%d), waiting %v before retry", attempt, maxRetries, waitTime)
-			time.Sleep(waitTime)
-			continue
-		}
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) && abuseErr.RetryAfter != nil {
+		return *abuseErr.RetryAfter
+	}
 
-		// For other errors, use exponential backoff
-		if attempt < maxRetries {
-			var waitTime time.Duration
-			switch attempt {
-			case 1:
-				waitTime = 1 * time.Minute
-			case 2:
-				waitTime = 3 * time.Minute
-			case 3:
-				waitTime = 6 * time.Minute
-			case 4:
-				waitTime = 10 * time.Minute
-			case 5:
-				waitTime = 15 * time.Minute
-			default:
-				waitTime = 15 * time.Minute
-			}
-			log.Printf("GitHub API request failed (attempt %d/%d): %v, waiting %v before retry", attempt, maxRetries, err, waitTime)
-			time.Sleep(waitTime)
-			continue
+	if resp != nil && resp.Rate.Remaining == 0 {
+		if waitTime := time.Until(resp.Rate.Reset.Time); waitTime > 0 {
+			return waitTime + time.Second
 		}
-
-		// Last attempt failed
-		return result, resp, err
 	}
 
-	// This should never be reached, but just in case
-	return nil, nil, fmt.Errorf("all retry attempts failed")
+	// Gradual backoff: 1min, 3min, 6min, 10min, 15min
+	switch attempt {
+	case 1:
+		return 1 * time.Minute
+	case 2:
+		return 3 * time.Minute
+	case 3:
+		return 6 * time.Minute
+	case 4:
+		return 10 * time.Minute
+	default:
+		return 15 * time.Minute
+	}
 }
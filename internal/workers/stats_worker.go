@@ -17,6 +17,7 @@ type StatsWorker struct {
 	jobRepo               *repositories.JobRepository
 	peopleStatsService    *services.PeopleStatisticsService
 	projectRepositoryRepo *repositories.ProjectRepositoryRepository
+	prCycleMetricsService *services.PRCycleMetricsService
 }
 
 // NewStatsWorker creates a new stats worker
@@ -25,12 +26,14 @@ func NewStatsWorker(
 	jobRepo *repositories.JobRepository,
 	peopleStatsService *services.PeopleStatisticsService,
 	projectRepositoryRepo *repositories.ProjectRepositoryRepository,
+	prCycleMetricsService *services.PRCycleMetricsService,
 ) *StatsWorker {
 	return &StatsWorker{
 		BaseWorker:            NewBaseWorker(workerID, models.JobTypeStats),
 		jobRepo:               jobRepo,
 		peopleStatsService:    peopleStatsService,
 		projectRepositoryRepo: projectRepositoryRepo,
+		prCycleMetricsService: prCycleMetricsService,
 	}
 }
 
@@ -148,6 +151,7 @@ func (w *StatsWorker) ProcessJob(ctx context.Context, job *models.Job) error {
 		if err != nil {
 			return err
 		}
+		w.calculateCycleMetrics(projectRepo)
 
 		// Mark repository as analyzed after successful stats calculation
 		now := time.Now()
@@ -180,6 +184,7 @@ func (w *StatsWorker) ProcessJob(ctx context.Context, job *models.Job) error {
 				}).WithError(err).Error("Error calculating statistics for repository")
 				continue
 			}
+			w.calculateCycleMetrics(projectRepo)
 
 			// Mark repository as analyzed after successful stats calculation
 			now := time.Now()
@@ -193,3 +198,14 @@ func (w *StatsWorker) ProcessJob(ctx context.Context, job *models.Job) error {
 		return nil
 	}
 }
+
+// calculateCycleMetrics derives the pull request cycle-time metrics of a repository. A failure is
+// logged only, as the people statistics are already stored.
+func (w *StatsWorker) calculateCycleMetrics(projectRepo *models.ProjectRepository) {
+	logger.WithField("repository_id", projectRepo.ID).Info("Calculating pull request cycle-time metrics for repository")
+	if err := w.prCycleMetricsService.CalculateForRepository(projectRepo.GithubRepoID); err != nil {
+		logger.WithFields(logrus.Fields{
+			"repository_id": projectRepo.ID,
+		}).WithError(err).Error("Error calculating pull request cycle-time metrics for repository")
+	}
+}
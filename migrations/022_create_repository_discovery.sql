@@ -0,0 +1,53 @@
+-- Migration: Organization-scoped repository discovery
+-- Date: 2025-08-10
+
+-- Repository attributes used by discovery rules
+ALTER TABLE github_repositories ADD COLUMN is_archived BOOLEAN DEFAULT FALSE;
+ALTER TABLE github_repositories ADD COLUMN is_fork BOOLEAN DEFAULT FALSE;
+ALTER TABLE github_repositories ADD COLUMN topics TEXT; -- JSON array of topic names
+
+-- Organizations or teams a project discovers repositories from
+CREATE TABLE IF NOT EXISTS repository_discovery_sources (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    organization TEXT NOT NULL,
+    team_slug TEXT, -- NULL means the whole organization
+    auto_track BOOLEAN DEFAULT TRUE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_repository_discovery_sources_project_id ON repository_discovery_sources(project_id);
+CREATE INDEX IF NOT EXISTS idx_repository_discovery_sources_deleted_at ON repository_discovery_sources(deleted_at);
+
+CREATE TRIGGER IF NOT EXISTS update_repository_discovery_sources_updated_at
+    AFTER UPDATE ON repository_discovery_sources
+    FOR EACH ROW
+BEGIN
+    UPDATE repository_discovery_sources SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- Include/exclude rules applied to discovered repositories
+CREATE TABLE IF NOT EXISTS repository_discovery_rules (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('include', 'exclude')),
+    field TEXT NOT NULL CHECK (field IN ('name', 'topic', 'language', 'archived', 'fork')),
+    value TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_repository_discovery_rules_project_id ON repository_discovery_rules(project_id);
+CREATE INDEX IF NOT EXISTS idx_repository_discovery_rules_deleted_at ON repository_discovery_rules(deleted_at);
+
+CREATE TRIGGER IF NOT EXISTS update_repository_discovery_rules_updated_at
+    AFTER UPDATE ON repository_discovery_rules
+    FOR EACH ROW
+BEGIN
+    UPDATE repository_discovery_rules SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,10 @@
+-- Migration: Track repository renames, transfers and deletion on GitHub
+-- Date: 2025-08-11
+
+-- Set when GitHub no longer returns the repository (deleted or access revoked)
+ALTER TABLE github_repositories ADD COLUMN github_deleted_at DATETIME;
+
+-- Full name before the most recent rename or transfer
+ALTER TABLE github_repositories ADD COLUMN previous_full_name TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_github_repositories_github_deleted_at ON github_repositories(github_deleted_at);
@@ -0,0 +1,28 @@
+-- Migration: Map GitHub App installations to projects
+-- Date: 2025-08-12
+
+CREATE TABLE IF NOT EXISTS project_github_installations (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    installation_id INTEGER NOT NULL,
+    account_login TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_github_installations_project_id ON project_github_installations(project_id);
+CREATE INDEX IF NOT EXISTS idx_project_github_installations_installation_id ON project_github_installations(installation_id);
+
+-- A project uses at most one installation at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_github_installations_unique
+    ON project_github_installations(project_id)
+    WHERE deleted_at IS NULL;
+
+CREATE TRIGGER IF NOT EXISTS update_project_github_installations_updated_at
+    AFTER UPDATE ON project_github_installations
+    FOR EACH ROW
+BEGIN
+    UPDATE project_github_installations SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
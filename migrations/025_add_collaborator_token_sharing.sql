@@ -0,0 +1,4 @@
+-- Migration: Let collaborators share their GitHub token with background jobs
+-- Date: 2025-08-14
+
+ALTER TABLE project_collaborators ADD COLUMN share_github_token BOOLEAN NOT NULL DEFAULT 0;
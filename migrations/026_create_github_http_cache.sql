@@ -0,0 +1,28 @@
+-- Migration: Cache GitHub API responses for conditional requests and record request stats per job
+-- Date: 2025-08-15
+
+CREATE TABLE IF NOT EXISTS github_http_cache (
+    cache_key TEXT PRIMARY KEY,
+    url TEXT NOT NULL,
+    etag TEXT,
+    last_modified TEXT,
+    header TEXT,
+    body BLOB,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_http_cache_updated_at ON github_http_cache(updated_at);
+
+CREATE TABLE IF NOT EXISTS job_github_stats (
+    job_id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    project_repository_id TEXT,
+    requests INTEGER NOT NULL DEFAULT 0,
+    cache_hits INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_github_stats_project_repository_id ON job_github_stats(project_repository_id);
@@ -0,0 +1,4 @@
+-- Migration: Let projects choose between REST and GraphQL pull request ingestion
+-- Date: 2025-08-16
+
+ALTER TABLE projects ADD COLUMN github_ingestion TEXT NOT NULL DEFAULT 'rest';
@@ -0,0 +1,71 @@
+-- Migration: Store pull request review comments and conversation comments
+-- Date: 2025-08-17
+
+-- Inline review comments anchored to a file and line of the diff. Replies point
+-- to the first comment of their thread through in_reply_to_github_id.
+CREATE TABLE IF NOT EXISTS pr_review_comments (
+    id TEXT PRIMARY KEY,
+    repository_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    github_comment_id INTEGER UNIQUE NOT NULL,
+    github_review_id INTEGER,
+    in_reply_to_github_id INTEGER,
+    author_id INTEGER NOT NULL,
+    author_login TEXT NOT NULL,
+    body TEXT,
+    path TEXT,
+    line INTEGER,
+    original_line INTEGER,
+    start_line INTEGER,
+    side TEXT, -- "LEFT", "RIGHT"
+    commit_id TEXT,
+    html_url TEXT,
+    github_created_at DATETIME,
+    github_updated_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (id)
+);
+
+-- Comments on the conversation tab of a pull request
+CREATE TABLE IF NOT EXISTS pr_issue_comments (
+    id TEXT PRIMARY KEY,
+    repository_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    github_comment_id INTEGER UNIQUE NOT NULL,
+    author_id INTEGER NOT NULL,
+    author_login TEXT NOT NULL,
+    body TEXT,
+    html_url TEXT,
+    github_created_at DATETIME,
+    github_updated_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_review_comments_repository_id ON pr_review_comments(repository_id);
+CREATE INDEX IF NOT EXISTS idx_pr_review_comments_pull_request_id ON pr_review_comments(pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pr_review_comments_in_reply_to ON pr_review_comments(in_reply_to_github_id);
+CREATE INDEX IF NOT EXISTS idx_pr_issue_comments_repository_id ON pr_issue_comments(repository_id);
+CREATE INDEX IF NOT EXISTS idx_pr_issue_comments_pull_request_id ON pr_issue_comments(pull_request_id);
+
+CREATE TRIGGER IF NOT EXISTS update_pr_review_comments_updated_at
+    AFTER UPDATE ON pr_review_comments
+    FOR EACH ROW
+BEGIN
+    UPDATE pr_review_comments SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_pr_issue_comments_updated_at
+    AFTER UPDATE ON pr_issue_comments
+    FOR EACH ROW
+BEGIN
+    UPDATE pr_issue_comments SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- Separate score weights for inline review comments and conversation comments
+ALTER TABLE score_settings ADD COLUMN review_comments INTEGER DEFAULT 20;
+ALTER TABLE score_settings ADD COLUMN issue_comments INTEGER DEFAULT 20;
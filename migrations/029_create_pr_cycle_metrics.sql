@@ -0,0 +1,43 @@
+-- Migration: Track pull request draft periods and derived cycle-time metrics
+-- Date: 2025-08-18
+
+-- Transitions between draft and ready for review taken from the pull request timeline
+CREATE TABLE IF NOT EXISTS pr_draft_events (
+    id TEXT PRIMARY KEY,
+    repository_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    event_type TEXT NOT NULL, -- "ready_for_review", "convert_to_draft"
+    occurred_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (pull_request_id, event_type, occurred_at),
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (id)
+);
+
+-- Lifecycle metrics derived from pull requests, reviews and draft events by the stats job.
+-- Durations are in seconds and exclude the time a pull request spent as draft.
+CREATE TABLE IF NOT EXISTS pr_cycle_metrics (
+    id TEXT PRIMARY KEY,
+    pull_request_id TEXT UNIQUE NOT NULL,
+    repository_id TEXT NOT NULL,
+    author_login TEXT NOT NULL,
+    opened_at DATETIME NOT NULL,
+    first_review_at DATETIME,
+    first_approval_at DATETIME,
+    last_approval_at DATETIME,
+    merged_at DATETIME,
+    closed_at DATETIME,
+    review_rounds INTEGER NOT NULL DEFAULT 0,
+    draft_seconds INTEGER NOT NULL DEFAULT 0,
+    time_to_first_review INTEGER,
+    time_to_approval INTEGER,
+    approval_to_merge INTEGER,
+    cycle_time INTEGER,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_draft_events_repository_id ON pr_draft_events(repository_id);
+CREATE INDEX IF NOT EXISTS idx_pr_cycle_metrics_repository_id ON pr_cycle_metrics(repository_id);
@@ -0,0 +1,49 @@
+-- Migration: Store pull request size, changed files and commits
+-- Date: 2025-08-19
+
+-- Size of the pull request diff, NULL until it has been fetched
+ALTER TABLE pull_requests ADD COLUMN additions INTEGER;
+ALTER TABLE pull_requests ADD COLUMN deletions INTEGER;
+ALTER TABLE pull_requests ADD COLUMN changed_files INTEGER;
+ALTER TABLE pull_requests ADD COLUMN commit_count INTEGER;
+
+-- Files changed by a pull request
+CREATE TABLE IF NOT EXISTS pr_files (
+    id TEXT PRIMARY KEY,
+    repository_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    filename TEXT NOT NULL,
+    previous_filename TEXT,
+    status TEXT NOT NULL, -- "added", "modified", "removed", "renamed", ...
+    additions INTEGER DEFAULT 0,
+    deletions INTEGER DEFAULT 0,
+    changes INTEGER DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (pull_request_id, filename),
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (id)
+);
+
+-- Commits of a pull request, linked to the commits table by SHA once the repository is analyzed
+CREATE TABLE IF NOT EXISTS pr_commits (
+    id TEXT PRIMARY KEY,
+    repository_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    commit_sha TEXT NOT NULL,
+    author_login TEXT,
+    authored_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (pull_request_id, commit_sha),
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_files_pull_request_id ON pr_files(pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pr_commits_pull_request_id ON pr_commits(pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_pr_commits_commit_sha ON pr_commits(commit_sha);
+
+-- Size of the pull request when its cycle-time metrics were derived
+ALTER TABLE pr_cycle_metrics ADD COLUMN additions INTEGER;
+ALTER TABLE pr_cycle_metrics ADD COLUMN deletions INTEGER;
+ALTER TABLE pr_cycle_metrics ADD COLUMN changed_files INTEGER;
+ALTER TABLE pr_cycle_metrics ADD COLUMN commit_count INTEGER;
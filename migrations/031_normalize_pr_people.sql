@@ -0,0 +1,103 @@
+-- Migration: Store pull request authors and requested reviewers and teams as references
+-- Date: 2025-08-20
+
+-- Author of the pull request. The raw user JSON is kept as received from GitHub.
+ALTER TABLE pull_requests ADD COLUMN author_id TEXT REFERENCES github_people (id);
+
+CREATE TABLE IF NOT EXISTS github_teams (
+    id TEXT PRIMARY KEY,
+    github_team_id INTEGER UNIQUE NOT NULL,
+    slug TEXT NOT NULL,
+    name TEXT,
+    html_url TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- GitHub drops a review request once the reviewer submits a review, so every request seen on a
+-- fetch is kept
+CREATE TABLE IF NOT EXISTS pr_requested_reviewers (
+    id TEXT PRIMARY KEY,
+    repository_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    github_person_id TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (pull_request_id, github_person_id),
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (id),
+    FOREIGN KEY (github_person_id) REFERENCES github_people (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS pr_requested_teams (
+    id TEXT PRIMARY KEY,
+    repository_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    github_team_id TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (pull_request_id, github_team_id),
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (id),
+    FOREIGN KEY (github_team_id) REFERENCES github_teams (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_id ON pull_requests(author_id);
+CREATE INDEX IF NOT EXISTS idx_pr_requested_reviewers_person ON pr_requested_reviewers(github_person_id);
+CREATE INDEX IF NOT EXISTS idx_pr_requested_reviewers_repository_id ON pr_requested_reviewers(repository_id);
+CREATE INDEX IF NOT EXISTS idx_pr_requested_teams_team ON pr_requested_teams(github_team_id);
+
+CREATE TRIGGER IF NOT EXISTS update_github_teams_updated_at
+    AFTER UPDATE ON github_teams
+    FOR EACH ROW
+BEGIN
+    UPDATE github_teams SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- Migrate the existing JSON columns. Requested reviewers were never stored as people, so the ones
+-- missing are created from the JSON first.
+INSERT OR IGNORE INTO github_people (id, github_user_id, username, display_name, avatar_url, profile_url, type)
+SELECT lower(substr(h, 1, 8) || '-' || substr(h, 9, 4) || '-' || substr(h, 13, 4) || '-' || substr(h, 17, 4) || '-' || substr(h, 21, 12)),
+       github_user_id, username, display_name, avatar_url, profile_url, type
+FROM (
+    SELECT hex(randomblob(16)) AS h,
+           json_extract(r.value, '$.id') AS github_user_id,
+           json_extract(r.value, '$.login') AS username,
+           json_extract(r.value, '$.name') AS display_name,
+           json_extract(r.value, '$.avatar_url') AS avatar_url,
+           json_extract(r.value, '$.html_url') AS profile_url,
+           json_extract(r.value, '$.type') AS type
+    FROM pull_requests pr, json_each(pr.requested_reviewers) r
+    WHERE json_valid(pr.requested_reviewers) AND json_extract(r.value, '$.id') IS NOT NULL
+    GROUP BY json_extract(r.value, '$.id')
+);
+
+UPDATE pull_requests
+SET author_id = (
+    SELECT gp.id FROM github_people gp WHERE gp.github_user_id = json_extract(pull_requests.user, '$.id')
+)
+WHERE json_valid(user);
+
+INSERT OR IGNORE INTO pr_requested_reviewers (id, repository_id, pull_request_id, github_person_id)
+SELECT lower(hex(randomblob(16))), pr.repository_id, pr.id, gp.id
+FROM pull_requests pr, json_each(pr.requested_reviewers) r
+JOIN github_people gp ON gp.github_user_id = json_extract(r.value, '$.id')
+WHERE json_valid(pr.requested_reviewers);
+
+INSERT OR IGNORE INTO github_teams (id, github_team_id, slug, name, html_url)
+SELECT lower(substr(h, 1, 8) || '-' || substr(h, 9, 4) || '-' || substr(h, 13, 4) || '-' || substr(h, 17, 4) || '-' || substr(h, 21, 12)),
+       github_team_id, slug, name, html_url
+FROM (
+    SELECT hex(randomblob(16)) AS h,
+           json_extract(t.value, '$.id') AS github_team_id,
+           json_extract(t.value, '$.slug') AS slug,
+           json_extract(t.value, '$.name') AS name,
+           json_extract(t.value, '$.html_url') AS html_url
+    FROM pull_requests pr, json_each(pr.requested_teams) t
+    WHERE json_valid(pr.requested_teams) AND json_extract(t.value, '$.id') IS NOT NULL
+    GROUP BY json_extract(t.value, '$.id')
+);
+
+INSERT OR IGNORE INTO pr_requested_teams (id, repository_id, pull_request_id, github_team_id)
+SELECT lower(hex(randomblob(16))), pr.repository_id, pr.id, gt.id
+FROM pull_requests pr, json_each(pr.requested_teams) t
+JOIN github_teams gt ON gt.github_team_id = json_extract(t.value, '$.id')
+WHERE json_valid(pr.requested_teams);
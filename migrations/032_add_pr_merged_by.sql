@@ -0,0 +1,9 @@
+-- Migration: Store who merged a pull request for review coverage reports
+-- Date: 2025-08-21
+
+-- NULL for pull requests that aren't merged or were fetched before this column existed
+ALTER TABLE pull_requests ADD COLUMN merged_by_id TEXT REFERENCES github_people (id);
+
+-- Commits of the default branch are linked to pull requests by merge commit and pull request commit SHA
+CREATE INDEX IF NOT EXISTS idx_pull_requests_merge_commit_sha ON pull_requests(merge_commit_sha);
+CREATE INDEX IF NOT EXISTS idx_pr_commits_commit_sha ON pr_commits(commit_sha);
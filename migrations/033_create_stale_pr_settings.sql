@@ -0,0 +1,21 @@
+-- Migration: Create stale pull request settings table
+-- Date: 2025-08-22
+
+-- Thresholds after which an open pull request is reported as stale or at risk
+CREATE TABLE IF NOT EXISTS stale_pr_settings (
+    id TEXT PRIMARY KEY,
+    project_id TEXT UNIQUE NOT NULL,
+    max_age_days INTEGER NOT NULL DEFAULT 14 CHECK (max_age_days > 0),
+    review_sla_hours INTEGER NOT NULL DEFAULT 24 CHECK (review_sla_hours > 0),
+    draft_idle_days INTEGER NOT NULL DEFAULT 7 CHECK (draft_idle_days > 0),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER IF NOT EXISTS update_stale_pr_settings_updated_at
+    AFTER UPDATE ON stale_pr_settings
+    FOR EACH ROW
+BEGIN
+    UPDATE stale_pr_settings SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
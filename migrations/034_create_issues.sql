@@ -0,0 +1,146 @@
+-- Migration: Store GitHub issues with their labels, assignees, close events and comments
+-- Date: 2025-08-23
+
+-- Issues of tracked repositories. Pull requests, which GitHub also lists as issues, are not stored here.
+CREATE TABLE IF NOT EXISTS issues (
+    id TEXT PRIMARY KEY,
+    repository_id TEXT NOT NULL,
+    github_issue_id INTEGER UNIQUE NOT NULL,
+    number INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT,
+    state TEXT NOT NULL, -- "open", "closed"
+    state_reason TEXT, -- "completed", "not_planned", "reopened"
+    author_id TEXT REFERENCES github_people (id),
+    closed_by_id TEXT REFERENCES github_people (id),
+    html_url TEXT,
+    github_created_at DATETIME,
+    github_updated_at DATETIME,
+    closed_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (repository_id, number),
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id)
+);
+
+-- Current labels and assignees of an issue, replaced on every fetch
+CREATE TABLE IF NOT EXISTS issue_labels (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    color TEXT,
+    UNIQUE (issue_id, name),
+    FOREIGN KEY (issue_id) REFERENCES issues (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS issue_assignees (
+    id TEXT PRIMARY KEY,
+    issue_id TEXT NOT NULL,
+    github_person_id TEXT NOT NULL,
+    UNIQUE (issue_id, github_person_id),
+    FOREIGN KEY (issue_id) REFERENCES issues (id) ON DELETE CASCADE,
+    FOREIGN KEY (github_person_id) REFERENCES github_people (id) ON DELETE CASCADE
+);
+
+-- Close and reopen events, from which the person who closed an issue is taken
+CREATE TABLE IF NOT EXISTS issue_events (
+    id TEXT PRIMARY KEY,
+    repository_id TEXT NOT NULL,
+    issue_id TEXT NOT NULL,
+    github_event_id INTEGER UNIQUE NOT NULL,
+    event TEXT NOT NULL, -- "closed", "reopened"
+    actor_id TEXT REFERENCES github_people (id),
+    occurred_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id),
+    FOREIGN KEY (issue_id) REFERENCES issues (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS issue_comments (
+    id TEXT PRIMARY KEY,
+    repository_id TEXT NOT NULL,
+    issue_id TEXT NOT NULL,
+    github_comment_id INTEGER UNIQUE NOT NULL,
+    author_id INTEGER NOT NULL,
+    author_login TEXT NOT NULL,
+    body TEXT,
+    html_url TEXT,
+    github_created_at DATETIME,
+    github_updated_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id),
+    FOREIGN KEY (issue_id) REFERENCES issues (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_issues_repository_id ON issues(repository_id);
+CREATE INDEX IF NOT EXISTS idx_issues_author_id ON issues(author_id);
+CREATE INDEX IF NOT EXISTS idx_issue_labels_issue_id ON issue_labels(issue_id);
+CREATE INDEX IF NOT EXISTS idx_issue_assignees_issue_id ON issue_assignees(issue_id);
+CREATE INDEX IF NOT EXISTS idx_issue_events_issue_id ON issue_events(issue_id);
+CREATE INDEX IF NOT EXISTS idx_issue_comments_repository_id ON issue_comments(repository_id);
+CREATE INDEX IF NOT EXISTS idx_issue_comments_issue_id ON issue_comments(issue_id);
+
+CREATE TRIGGER IF NOT EXISTS update_issues_updated_at
+    AFTER UPDATE ON issues
+    FOR EACH ROW
+BEGIN
+    UPDATE issues SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_issue_comments_updated_at
+    AFTER UPDATE ON issue_comments
+    FOR EACH ROW
+BEGIN
+    UPDATE issue_comments SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- Optional score weights for opening and closing issues, off by default
+ALTER TABLE score_settings ADD COLUMN issues_opened INTEGER DEFAULT 0;
+ALTER TABLE score_settings ADD COLUMN issues_closed INTEGER DEFAULT 0;
+
+-- SQLite can't change the job type CHECK constraint in place, so the jobs table is recreated
+-- with the issue job type allowed
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE jobs_new (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    project_repository_id TEXT,
+    job_type TEXT NOT NULL CHECK (job_type IN ('clone', 'commit', 'pull_request', 'issue', 'stats')),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'in-progress', 'completed', 'failed')),
+    error_message TEXT,
+    depends_on TEXT,
+    started_at DATETIME,
+    completed_at DATETIME,
+    worker_id TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_repository_id) REFERENCES project_repositories(id) ON DELETE CASCADE
+);
+
+INSERT INTO jobs_new (id, project_id, project_repository_id, job_type, status, error_message, depends_on, started_at, completed_at, worker_id, created_at, updated_at)
+SELECT id, project_id, project_repository_id, job_type, status, error_message, depends_on, started_at, completed_at, worker_id, created_at, updated_at
+FROM jobs;
+
+DROP TABLE jobs;
+
+ALTER TABLE jobs_new RENAME TO jobs;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_project_id ON jobs(project_id);
+CREATE INDEX IF NOT EXISTS idx_jobs_job_type ON jobs(job_type);
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status);
+CREATE INDEX IF NOT EXISTS idx_jobs_created_at ON jobs(created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_status_created_at ON jobs(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_depends_on ON jobs(depends_on);
+CREATE INDEX IF NOT EXISTS idx_jobs_worker_id ON jobs(worker_id);
+
+CREATE TRIGGER IF NOT EXISTS update_jobs_updated_at
+    AFTER UPDATE ON jobs
+    FOR EACH ROW
+BEGIN
+    UPDATE jobs SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+PRAGMA foreign_keys = ON;
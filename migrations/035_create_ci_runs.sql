@@ -0,0 +1,97 @@
+-- Migration: Store CI check runs and workflow runs for pull request heads and default branch commits
+-- Date: 2025-08-24
+
+-- NULL for pull requests fetched before this column existed, until they are fetched again
+ALTER TABLE pull_requests ADD COLUMN head_sha TEXT;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_head_sha ON pull_requests(head_sha);
+
+-- Check runs and GitHub Actions workflow runs. A check run that is re-run gets a new ID, while a
+-- re-run workflow keeps its ID and gets a new attempt, so every attempt is stored as its own row.
+CREATE TABLE IF NOT EXISTS ci_runs (
+    id TEXT PRIMARY KEY,
+    repository_id TEXT NOT NULL,
+    kind TEXT NOT NULL, -- "check_run", "workflow_run"
+    github_id INTEGER NOT NULL,
+    attempt INTEGER NOT NULL DEFAULT 1,
+    name TEXT NOT NULL,
+    head_sha TEXT NOT NULL,
+    head_branch TEXT,
+    event TEXT,
+    status TEXT NOT NULL, -- "queued", "in_progress", "completed", ...
+    conclusion TEXT, -- "success", "failure", "timed_out", ... once completed
+    html_url TEXT,
+    github_created_at DATETIME,
+    started_at DATETIME,
+    completed_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(kind, github_id, attempt),
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_ci_runs_repository_id ON ci_runs(repository_id);
+CREATE INDEX IF NOT EXISTS idx_ci_runs_head_sha ON ci_runs(repository_id, head_sha);
+
+CREATE TRIGGER IF NOT EXISTS update_ci_runs_updated_at
+    AFTER UPDATE ON ci_runs
+    FOR EACH ROW
+BEGIN
+    UPDATE ci_runs SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- Commits whose check runs were fetched. Commits are fetched again while any of their check runs
+-- was still running, and heads of open pull requests on every sync.
+CREATE TABLE IF NOT EXISTS ci_synced_commits (
+    repository_id TEXT NOT NULL,
+    sha TEXT NOT NULL,
+    pending BOOLEAN NOT NULL DEFAULT 0,
+    synced_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (repository_id, sha),
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id) ON DELETE CASCADE
+);
+
+-- SQLite can't change the job type CHECK constraint in place, so the jobs table is recreated
+-- with the ci job type allowed
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE jobs_new (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    project_repository_id TEXT,
+    job_type TEXT NOT NULL CHECK (job_type IN ('clone', 'commit', 'pull_request', 'issue', 'ci', 'stats')),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'in-progress', 'completed', 'failed')),
+    error_message TEXT,
+    depends_on TEXT,
+    started_at DATETIME,
+    completed_at DATETIME,
+    worker_id TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_repository_id) REFERENCES project_repositories(id) ON DELETE CASCADE
+);
+
+INSERT INTO jobs_new (id, project_id, project_repository_id, job_type, status, error_message, depends_on, started_at, completed_at, worker_id, created_at, updated_at)
+SELECT id, project_id, project_repository_id, job_type, status, error_message, depends_on, started_at, completed_at, worker_id, created_at, updated_at
+FROM jobs;
+
+DROP TABLE jobs;
+
+ALTER TABLE jobs_new RENAME TO jobs;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_project_id ON jobs(project_id);
+CREATE INDEX IF NOT EXISTS idx_jobs_job_type ON jobs(job_type);
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status);
+CREATE INDEX IF NOT EXISTS idx_jobs_created_at ON jobs(created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_status_created_at ON jobs(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_depends_on ON jobs(depends_on);
+CREATE INDEX IF NOT EXISTS idx_jobs_worker_id ON jobs(worker_id);
+
+CREATE TRIGGER IF NOT EXISTS update_jobs_updated_at
+    AFTER UPDATE ON jobs
+    FOR EACH ROW
+BEGIN
+    UPDATE jobs SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1,121 @@
+-- Migration: Create deployments, pull request labels and DORA settings
+-- Date: 2025-08-25
+
+-- Current labels of a pull request, replaced on every fetch
+CREATE TABLE IF NOT EXISTS pr_labels (
+    id TEXT PRIMARY KEY,
+    pull_request_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    color TEXT,
+    UNIQUE (pull_request_id, name),
+    FOREIGN KEY (pull_request_id) REFERENCES pull_requests (id) ON DELETE CASCADE
+);
+
+-- GitHub releases, GitHub deployments and git tags of a repository. Which of them count as deploys is
+-- decided by the DORA settings of a project when reporting.
+CREATE TABLE IF NOT EXISTS deployments (
+    id TEXT PRIMARY KEY,
+    repository_id TEXT NOT NULL,
+    source TEXT NOT NULL CHECK (source IN ('release', 'deployment', 'tag')),
+    external_id TEXT NOT NULL, -- GitHub ID of releases and deployments, name of tags
+    name TEXT NOT NULL,
+    environment TEXT,
+    sha TEXT,
+    status TEXT NOT NULL,
+    url TEXT,
+    deployed_at DATETIME NOT NULL,
+    commits_linked BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (repository_id, source, external_id),
+    FOREIGN KEY (repository_id) REFERENCES github_repositories (id) ON DELETE CASCADE
+);
+
+-- Commits shipped by a deployment: the ones reachable from it but not from the previous deployment
+-- of the same source and environment
+CREATE TABLE IF NOT EXISTS deployment_commits (
+    deployment_id TEXT NOT NULL,
+    commit_sha TEXT NOT NULL,
+    PRIMARY KEY (deployment_id, commit_sha),
+    FOREIGN KEY (deployment_id) REFERENCES deployments (id) ON DELETE CASCADE
+);
+
+-- What counts as a deploy and as a failed change when computing the DORA metrics of a project
+CREATE TABLE IF NOT EXISTS dora_settings (
+    id TEXT PRIMARY KEY,
+    project_id TEXT UNIQUE NOT NULL,
+    deploy_source TEXT NOT NULL DEFAULT 'release' CHECK (deploy_source IN ('release', 'deployment', 'tag')),
+    environment TEXT NOT NULL DEFAULT 'production',
+    tag_pattern TEXT NOT NULL DEFAULT '',
+    revert_is_failure BOOLEAN NOT NULL DEFAULT 1,
+    failure_labels TEXT NOT NULL DEFAULT 'hotfix',
+    failed_status_is_failure BOOLEAN NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_labels_pull_request_id ON pr_labels(pull_request_id);
+CREATE INDEX IF NOT EXISTS idx_deployments_repository_id ON deployments(repository_id);
+CREATE INDEX IF NOT EXISTS idx_deployments_deployed_at ON deployments(deployed_at);
+CREATE INDEX IF NOT EXISTS idx_deployment_commits_commit_sha ON deployment_commits(commit_sha);
+
+CREATE TRIGGER IF NOT EXISTS update_deployments_updated_at
+    AFTER UPDATE ON deployments
+    FOR EACH ROW
+BEGIN
+    UPDATE deployments SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS update_dora_settings_updated_at
+    AFTER UPDATE ON dora_settings
+    FOR EACH ROW
+BEGIN
+    UPDATE dora_settings SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+-- SQLite can't change the job type CHECK constraint in place, so the jobs table is recreated
+-- with the deployment job type allowed
+PRAGMA foreign_keys = OFF;
+
+CREATE TABLE jobs_new (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    project_repository_id TEXT,
+    job_type TEXT NOT NULL CHECK (job_type IN ('clone', 'commit', 'pull_request', 'issue', 'ci', 'deployment', 'stats')),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'in-progress', 'completed', 'failed')),
+    error_message TEXT,
+    depends_on TEXT,
+    started_at DATETIME,
+    completed_at DATETIME,
+    worker_id TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (project_repository_id) REFERENCES project_repositories(id) ON DELETE CASCADE
+);
+
+INSERT INTO jobs_new (id, project_id, project_repository_id, job_type, status, error_message, depends_on, started_at, completed_at, worker_id, created_at, updated_at)
+SELECT id, project_id, project_repository_id, job_type, status, error_message, depends_on, started_at, completed_at, worker_id, created_at, updated_at
+FROM jobs;
+
+DROP TABLE jobs;
+
+ALTER TABLE jobs_new RENAME TO jobs;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_project_id ON jobs(project_id);
+CREATE INDEX IF NOT EXISTS idx_jobs_job_type ON jobs(job_type);
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status);
+CREATE INDEX IF NOT EXISTS idx_jobs_created_at ON jobs(created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_status_created_at ON jobs(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_jobs_depends_on ON jobs(depends_on);
+CREATE INDEX IF NOT EXISTS idx_jobs_worker_id ON jobs(worker_id);
+
+CREATE TRIGGER IF NOT EXISTS update_jobs_updated_at
+    AFTER UPDATE ON jobs
+    FOR EACH ROW
+BEGIN
+    UPDATE jobs SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+
+PRAGMA foreign_keys = ON;
@@ -0,0 +1,20 @@
+-- Migration: Create bot settings table
+-- Date: 2025-08-26
+
+-- How a project treats bot and automation accounts in people lists and scores
+CREATE TABLE IF NOT EXISTS bot_settings (
+    id TEXT PRIMARY KEY,
+    project_id TEXT UNIQUE NOT NULL,
+    policy TEXT NOT NULL DEFAULT 'unscored' CHECK (policy IN ('hide', 'unscored', 'separate')),
+    patterns TEXT NOT NULL DEFAULT '', -- Extra glob patterns, one per line, matched against usernames, author names and emails
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER IF NOT EXISTS update_bot_settings_updated_at
+    AFTER UPDATE ON bot_settings
+    FOR EACH ROW
+BEGIN
+    UPDATE bot_settings SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
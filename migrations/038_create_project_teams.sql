@@ -0,0 +1,37 @@
+-- Migration: Create project teams synced from GitHub organization teams
+-- Date: 2025-08-27
+
+-- Teams seen only as requested reviewers have no organization until they are synced
+ALTER TABLE github_teams ADD COLUMN organization TEXT;
+
+CREATE TABLE IF NOT EXISTS github_team_members (
+    github_team_id TEXT NOT NULL,
+    github_person_id TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (github_team_id, github_person_id),
+    FOREIGN KEY (github_team_id) REFERENCES github_teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (github_person_id) REFERENCES github_people (id) ON DELETE CASCADE
+);
+
+-- The teams a project reports on
+CREATE TABLE IF NOT EXISTS project_teams (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    github_team_id TEXT,
+    name TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (project_id, github_team_id),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+    FOREIGN KEY (github_team_id) REFERENCES github_teams (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_github_team_members_person ON github_team_members(github_person_id);
+CREATE INDEX IF NOT EXISTS idx_project_teams_project_id ON project_teams(project_id);
+
+CREATE TRIGGER IF NOT EXISTS update_project_teams_updated_at
+    AFTER UPDATE ON project_teams
+    FOR EACH ROW
+BEGIN
+    UPDATE project_teams SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
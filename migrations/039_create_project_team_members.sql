@@ -0,0 +1,26 @@
+-- Migration: Create members of teams defined in a project
+-- Date: 2025-08-28
+
+-- Members of the project teams that are not synced from GitHub. A person moving between teams gets
+-- one row per stint; open-ended dates mean since always or until now.
+CREATE TABLE IF NOT EXISTS project_team_members (
+    id TEXT PRIMARY KEY,
+    project_team_id TEXT NOT NULL,
+    github_person_id TEXT NOT NULL,
+    start_date DATE,
+    end_date DATE,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_team_id) REFERENCES project_teams (id) ON DELETE CASCADE,
+    FOREIGN KEY (github_person_id) REFERENCES github_people (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_team_members_team ON project_team_members(project_team_id);
+CREATE INDEX IF NOT EXISTS idx_project_team_members_person ON project_team_members(github_person_id);
+
+CREATE TRIGGER IF NOT EXISTS update_project_team_members_updated_at
+    AFTER UPDATE ON project_team_members
+    FOR EACH ROW
+BEGIN
+    UPDATE project_team_members SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
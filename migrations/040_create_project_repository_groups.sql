@@ -0,0 +1,16 @@
+-- Migration: Create repository groups of project repositories and scope scheduled updates by group
+-- Date: 2025-08-29
+
+-- Named groups (tags) of the repositories of a project, a repository can be in several groups
+CREATE TABLE IF NOT EXISTS project_repository_groups (
+    project_repository_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_repository_id, name),
+    FOREIGN KEY (project_repository_id) REFERENCES project_repositories (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_repository_groups_name ON project_repository_groups(name);
+
+-- Scheduled updates cover the tracked repositories of this group, or all of them when NULL
+ALTER TABLE project_update_settings ADD COLUMN repository_group TEXT;
@@ -0,0 +1,30 @@
+-- Migration: Create repository score settings table
+-- Date: 2025-08-30
+
+-- Per-repository score weighting: a multiplier on the score of each daily row of a repository and,
+-- optionally, weights used instead of the project's score settings
+CREATE TABLE IF NOT EXISTS repository_score_settings (
+    id TEXT PRIMARY KEY,
+    project_repository_id TEXT UNIQUE NOT NULL,
+    multiplier REAL NOT NULL DEFAULT 1 CHECK (multiplier >= 0),
+    override_weights BOOLEAN NOT NULL DEFAULT FALSE,
+    additions INTEGER NOT NULL DEFAULT 0,
+    deletions INTEGER NOT NULL DEFAULT 0,
+    commits INTEGER NOT NULL DEFAULT 0,
+    pull_requests INTEGER NOT NULL DEFAULT 0,
+    comments INTEGER NOT NULL DEFAULT 0,
+    review_comments INTEGER NOT NULL DEFAULT 0,
+    issue_comments INTEGER NOT NULL DEFAULT 0,
+    issues_opened INTEGER NOT NULL DEFAULT 0,
+    issues_closed INTEGER NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (project_repository_id) REFERENCES project_repositories(id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER IF NOT EXISTS update_repository_score_settings_updated_at
+    AFTER UPDATE ON repository_score_settings
+    FOR EACH ROW
+BEGIN
+    UPDATE repository_score_settings SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
@@ -0,0 +1,22 @@
+-- Migration: Create score formulas table
+-- Date: 2025-08-31
+
+-- Versions of the expression-based score formula of a project. At most one version is active; projects
+-- without an active formula are scored with the weights of their score settings.
+CREATE TABLE IF NOT EXISTS score_formulas (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    expression TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (project_id, version),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_score_formulas_active
+    ON score_formulas(project_id)
+    WHERE is_active;
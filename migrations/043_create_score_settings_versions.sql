@@ -0,0 +1,45 @@
+-- Migration: Create score settings versions table
+-- Date: 2025-09-01
+
+-- Every change to the score settings of a project is kept as a version with who made it. A version
+-- scores the days from its effective date on, or all of history when it has none, until a later
+-- version takes over; score_settings holds the weights of the latest version. The stats job that
+-- recalculated the project after a change is kept to show its progress.
+CREATE TABLE IF NOT EXISTS score_settings_versions (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    effective_from DATE,
+    additions INTEGER NOT NULL DEFAULT 0,
+    deletions INTEGER NOT NULL DEFAULT 0,
+    commits INTEGER NOT NULL DEFAULT 0,
+    pull_requests INTEGER NOT NULL DEFAULT 0,
+    comments INTEGER NOT NULL DEFAULT 0,
+    review_comments INTEGER NOT NULL DEFAULT 0,
+    issue_comments INTEGER NOT NULL DEFAULT 0,
+    issues_opened INTEGER NOT NULL DEFAULT 0,
+    issues_closed INTEGER NOT NULL DEFAULT 0,
+    changed_by TEXT,
+    recalculation_job_id TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (project_id, version),
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    FOREIGN KEY (changed_by) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (recalculation_job_id) REFERENCES jobs(id) ON DELETE SET NULL
+);
+
+-- The current settings of existing projects become their first version, covering all of history
+INSERT INTO score_settings_versions (id, project_id, version, additions, deletions, commits, pull_requests, comments,
+                                     review_comments, issue_comments, issues_opened, issues_closed, created_at)
+SELECT lower(substr(h, 1, 8) || '-' || substr(h, 9, 4) || '-' || substr(h, 13, 4) || '-' || substr(h, 17, 4) || '-' || substr(h, 21, 12)),
+       project_id, 1, additions, deletions, commits, pull_requests, comments,
+       review_comments, issue_comments, issues_opened, issues_closed, created_at
+FROM (
+    SELECT hex(randomblob(16)) AS h, project_id,
+           COALESCE(additions, 0) AS additions, COALESCE(deletions, 0) AS deletions,
+           COALESCE(commits, 0) AS commits, COALESCE(pull_requests, 0) AS pull_requests,
+           COALESCE(comments, 0) AS comments, COALESCE(review_comments, 0) AS review_comments,
+           COALESCE(issue_comments, 0) AS issue_comments, COALESCE(issues_opened, 0) AS issues_opened,
+           COALESCE(issues_closed, 0) AS issues_closed, created_at
+    FROM score_settings
+);
@@ -0,0 +1,6 @@
+-- Migration: Let projects choose whether issues are fetched
+-- Date: 2025-09-02
+
+-- Issue jobs add a job per repository to every update; projects that don't use the issue reports can
+-- turn them off. They stay on for existing projects, which already fetch them.
+ALTER TABLE projects ADD COLUMN sync_issues INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+-- Migration: Let projects choose whether CI runs are fetched
+-- Date: 2025-09-03
+
+-- CI jobs add a job per repository to every update; projects that don't use the CI reports can turn
+-- them off. They stay on for existing projects, which already fetch them.
+ALTER TABLE projects ADD COLUMN sync_ci INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,6 @@
+-- Migration: Let projects choose whether deployments are fetched
+-- Date: 2025-09-04
+
+-- Deployment jobs add a job per repository to every update; projects that don't use the DORA metrics can
+-- turn them off. They stay on for existing projects, which already fetch them.
+ALTER TABLE projects ADD COLUMN sync_deployments INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,28 @@
+-- Migration: Give the review requests backfilled by migration 031 dashed UUIDs
+-- Date: 2025-09-05
+
+-- Migration 031 stored the IDs of the review requests it copied from the JSON columns as 32 plain hex
+-- digits; every other ID is a dashed UUID. Nothing references these IDs, so they are replaced. The
+-- random hex of each new ID is stored first: SQLite would otherwise evaluate randomblob once per substr.
+CREATE TEMP TABLE pr_request_new_ids AS
+SELECT id AS old_id, hex(randomblob(16)) AS h FROM pr_requested_reviewers
+WHERE length(id) = 32 AND instr(id, '-') = 0
+UNION ALL
+SELECT id, hex(randomblob(16)) FROM pr_requested_teams
+WHERE length(id) = 32 AND instr(id, '-') = 0;
+
+UPDATE pr_requested_reviewers
+SET id = (
+    SELECT lower(substr(h, 1, 8) || '-' || substr(h, 9, 4) || '-' || substr(h, 13, 4) || '-' || substr(h, 17, 4) || '-' || substr(h, 21, 12))
+    FROM pr_request_new_ids WHERE old_id = pr_requested_reviewers.id
+)
+WHERE id IN (SELECT old_id FROM pr_request_new_ids);
+
+UPDATE pr_requested_teams
+SET id = (
+    SELECT lower(substr(h, 1, 8) || '-' || substr(h, 9, 4) || '-' || substr(h, 13, 4) || '-' || substr(h, 17, 4) || '-' || substr(h, 21, 12))
+    FROM pr_request_new_ids WHERE old_id = pr_requested_teams.id
+)
+WHERE id IN (SELECT old_id FROM pr_request_new_ids);
+
+DROP TABLE pr_request_new_ids;
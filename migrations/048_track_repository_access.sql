@@ -0,0 +1,14 @@
+-- Migration: Tell repositories GitHub no longer shows apart from deleted ones and record status checks
+-- Date: 2025-09-06
+
+-- Set when GitHub answers 404 for the repository: it was deleted or the token lost access, and GitHub
+-- doesn't say which. github_deleted_at is kept for repositories known to be deleted.
+ALTER TABLE github_repositories ADD COLUMN github_inaccessible_at DATETIME;
+
+-- When the status of the repository was last refreshed from GitHub
+ALTER TABLE github_repositories ADD COLUMN status_checked_at DATETIME;
+
+-- Repositories marked deleted so far were mostly marked on a 404, so they count as inaccessible until
+-- GitHub is asked again
+UPDATE github_repositories SET github_inaccessible_at = github_deleted_at, github_deleted_at = NULL
+WHERE github_deleted_at IS NOT NULL;
@@ -0,0 +1,6 @@
+-- Migration: Record when completing a pull request stored without its size failed
+-- Date: 2025-09-07
+
+-- Pull requests that fail every time (deleted head, 404) are skipped by the backfill for a while
+-- instead of being picked first by every pull request job
+ALTER TABLE pull_requests ADD COLUMN details_failed_at DATETIME;
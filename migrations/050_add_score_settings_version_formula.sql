@@ -0,0 +1,13 @@
+-- Migration: Add score formula to score settings versions
+-- Date: 2025-09-08
+
+-- A version scores its days with a score formula instead of its weights when it has one, so that
+-- activating or deactivating a formula keeps the scores of earlier days like any other change. Projects
+-- with an active formula were scored with it over all of history, which all their versions now record.
+ALTER TABLE score_settings_versions ADD COLUMN score_formula_id TEXT REFERENCES score_formulas(id) ON DELETE SET NULL;
+
+UPDATE score_settings_versions
+SET score_formula_id = (
+    SELECT sf.id FROM score_formulas sf
+    WHERE sf.project_id = score_settings_versions.project_id AND sf.is_active
+);
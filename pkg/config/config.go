@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/alimgiray/gscope/pkg/logger"
 	"github.com/joho/godotenv"
@@ -31,6 +32,21 @@ type GitHubConfig struct {
 	ClientID     string
 	ClientSecret string
 	CallbackURL  string
+
+	// GitHub App used for background jobs. OAuth above is only used for login
+	// when the app is configured.
+	AppID         int64
+	AppSlug       string
+	AppPrivateKey string
+
+	// Requests per token and hour background jobs leave untouched
+	RateLimitReserve int
+
+	// Days a cached GitHub response is kept without being revalidated. Pruning is off when 0.
+	CacheRetentionDays int
+
+	// Secret GitHub signs webhook deliveries with. Webhooks are rejected when empty.
+	WebhookSecret string
 }
 
 type SessionConfig struct {
@@ -57,9 +73,15 @@ func Load() error {
 			Path: getEnv("DB_PATH", "./gscope.db"),
 		},
 		GitHub: GitHubConfig{
-			ClientID:     getEnv("GITHUB_CLIENT_ID", ""),
-			ClientSecret: getEnv("GITHUB_CLIENT_SECRET", ""),
-			CallbackURL:  getEnv("GITHUB_CALLBACK_URL", "http://localhost:8080/auth/github/callback"),
+			ClientID:           getEnv("GITHUB_CLIENT_ID", ""),
+			ClientSecret:       getEnv("GITHUB_CLIENT_SECRET", ""),
+			CallbackURL:        getEnv("GITHUB_CALLBACK_URL", "http://localhost:8080/auth/github/callback"),
+			AppID:              int64(getEnvAsInt("GITHUB_APP_ID", 0)),
+			AppSlug:            getEnv("GITHUB_APP_SLUG", ""),
+			AppPrivateKey:      getAppPrivateKey(),
+			RateLimitReserve:   getEnvAsInt("GITHUB_RATE_LIMIT_RESERVE", 50),
+			CacheRetentionDays: getEnvAsInt("GITHUB_CACHE_RETENTION_DAYS", 30),
+			WebhookSecret:      getEnv("GITHUB_WEBHOOK_SECRET", ""),
 		},
 		Session: SessionConfig{
 			Secret: getEnv("SESSION_SECRET", "default-secret-key-change-in-production"),
@@ -74,6 +96,7 @@ func Load() error {
 		"database_path":    AppConfig.Database.Path,
 		"github_client_id": maskString(AppConfig.GitHub.ClientID),
 		"github_callback":  AppConfig.GitHub.CallbackURL,
+		"github_app_id":    AppConfig.GitHub.AppID,
 	}).Info("Application configuration")
 
 	return nil
@@ -87,6 +110,27 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getAppPrivateKey reads the GitHub App private key either inline from
+// GITHUB_APP_PRIVATE_KEY or from the file at GITHUB_APP_PRIVATE_KEY_PATH
+func getAppPrivateKey() string {
+	if key := getEnv("GITHUB_APP_PRIVATE_KEY", ""); key != "" {
+		// Allow single-line keys with escaped newlines in .env files
+		return strings.ReplaceAll(key, `\n`, "\n")
+	}
+
+	path := getEnv("GITHUB_APP_PRIVATE_KEY_PATH", "")
+	if path == "" {
+		return ""
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.WithError(err).Error("Failed to read GitHub App private key")
+		return ""
+	}
+	return string(data)
+}
+
 // getEnvAsInt gets an environment variable as integer or returns a default value
 func getEnvAsInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
@@ -1,7 +1,9 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
@@ -103,8 +105,21 @@ func Close() error {
 	return nil
 }
 
-// RunSQLScripts reads and executes SQL scripts from the directory
+// RunSQLScripts reads and executes SQL scripts from the directory.
+// Applied scripts are recorded in schema_migrations so that non-idempotent
+// statements (such as ALTER TABLE ... ADD COLUMN) only run once.
 func RunSQLScripts() error {
+	// Make sure the migration bookkeeping table exists
+	_, err := DB.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			name TEXT PRIMARY KEY,
+			applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
 	// Read all SQL files from directory
 	sqlDir := "migrations"
 	files, err := os.ReadDir(sqlDir)
@@ -114,15 +129,21 @@ func RunSQLScripts() error {
 
 	for _, file := range files {
 		if filepath.Ext(file.Name()) == ".sql" {
+			applied, err := isMigrationApplied(file.Name())
+			if err != nil {
+				return err
+			}
+			if applied {
+				continue
+			}
+
 			sqlPath := filepath.Join(sqlDir, file.Name())
 			sqlContent, err := os.ReadFile(sqlPath)
 			if err != nil {
 				return err
 			}
 
-			// Execute the SQL script
-			_, err = DB.Exec(string(sqlContent))
-			if err != nil {
+			if err := applyMigration(file.Name(), string(sqlContent)); err != nil {
 				return err
 			}
 
@@ -133,3 +154,45 @@ func RunSQLScripts() error {
 	logger.Info("All SQL scripts executed successfully")
 	return nil
 }
+
+// applyMigration executes a migration script and records it in schema_migrations in one transaction,
+// so that a crash can't leave a script applied but unrecorded. Scripts that rebuild tables turn
+// foreign keys off, which SQLite ignores inside a transaction, so the script runs on its own
+// connection with foreign keys off until it's committed.
+func applyMigration(name, script string) error {
+	ctx := context.Background()
+	conn, err := DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(script); err != nil {
+		return fmt.Errorf("migration %s: %w", name, err)
+	}
+	if _, err := tx.Exec("INSERT INTO schema_migrations (name) VALUES (?)", name); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// isMigrationApplied checks whether a migration script has already been executed
+func isMigrationApplied(name string) (bool, error) {
+	var count int
+	err := DB.QueryRow("SELECT COUNT(*) FROM schema_migrations WHERE name = ?", name).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}